@@ -39,6 +39,14 @@ var (
 	// This error is returned by WaitDeployed if contract creation leaves an
 	// empty contract behind.
 	ErrNoCodeAfterDeploy = errors.New("no contract code after deployment")
+
+	// This error is raised when attempting to follow events on a backend that
+	// doesn't implement ContractFollower.
+	ErrNoFollowing = errors.New("backend does not support following events")
+
+	// This error is returned by a LogFollower if the chain is reorganised below
+	// the oldest block it remembers, or below the checkpoint it resumed from.
+	ErrReorgTooDeep = errors.New("chain reorganised below the followed blocks")
 )
 
 // ContractCaller defines the methods needed to allow operating with contract on a read
@@ -62,6 +70,26 @@ type PendingContractCaller interface {
 	PendingCallContract(ctx context.Context, call cpchain.CallMsg) ([]byte, error)
 }
 
+// BatchContractCaller defines the methods needed to execute several contract calls
+// in a single request. BatchCall will try to discover this interface and falls back
+// to one CallContract per call if the backend does not implement it.
+type BatchContractCaller interface {
+	// BatchCallContract executes the calls, in order, on the state of the given
+	// block. It returns the output or the error of each call, the error being
+	// only set if the batch could not be executed at all.
+	BatchCallContract(ctx context.Context, calls []cpchain.CallMsg, blockNumber *big.Int) ([][]byte, []error, error)
+}
+
+// ChainHeadReader defines the method needed to run the calls of a batch on the
+// same block. BatchCall will try to discover this interface to resolve the
+// latest block once for all its calls, if the backend does not implement it
+// each call runs on the latest block at the time.
+type ChainHeadReader interface {
+	// HeaderByNumber returns a block header of the canonical chain, the latest
+	// one if number is nil.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
 // ContractTransactor defines the methods needed to allow operating with contract
 // on a write only basis. Beside the transacting method, the remainder are helpers
 // used when the user does not provide some needed values, but rather leaves it up
@@ -98,6 +126,17 @@ type ContractFilterer interface {
 	SubscribeFilterLogs(ctx context.Context, query cpchain.FilterQuery, ch chan<- types.Log) (cpchain.Subscription, error)
 }
 
+// ContractFollower defines the methods needed to follow contract events across
+// chain reorganisations, polling the logs of the new blocks and checking that the
+// ones delivered are still canonical.
+type ContractFollower interface {
+	ContractFilterer
+
+	// HeaderByNumber returns a block header from the current canonical chain. If
+	// number is nil, the latest known header is returned.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
 // DeployBackend wraps the operations needed by WaitMined and WaitDeployed.
 type DeployBackend interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
@@ -406,7 +406,8 @@ func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, query cpchai
 	}), nil
 }
 
-// AdjustTime adds a time shift to the simulated clock.
+// AdjustTime adds a time shift to the simulated clock. Block times are in
+// milliseconds, so is the precision of the shift.
 func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -418,7 +419,7 @@ func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTx(tx)
 		}
-		block.OffsetTime(int64(adjustment.Seconds()))
+		block.OffsetTime(int64(adjustment / time.Millisecond))
 	})
 	statedb, _ := b.blockchain.State()
 
@@ -433,7 +434,12 @@ func (b *SimulatedBackend) Blockchain() *core.BlockChain {
 	return b.blockchain
 }
 
+// HeaderByNumber returns a block header from the current canonical chain. If number is
+// nil, the latest known header is returned.
 func (b *SimulatedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return b.blockchain.CurrentHeader(), nil
+	}
 	return b.blockchain.GetHeaderByNumber(number.Uint64()), nil
 }
 
@@ -0,0 +1,50 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/core"
+)
+
+func TestAdjustTime(t *testing.T) {
+	backend := NewDporSimulatedBackend(core.GenesisAlloc{})
+
+	// Blocks without adjustment are made at the fixed block time
+	backend.Commit()
+	parent := backend.Blockchain().CurrentBlock().Time()
+	backend.Commit()
+	step := new(big.Int).Sub(backend.Blockchain().CurrentBlock().Time(), parent)
+
+	// Block times are in milliseconds, so is the shift
+	for _, adjustment := range []time.Duration{time.Hour, 1500 * time.Millisecond} {
+		parent := backend.Blockchain().CurrentBlock().Time()
+		if err := backend.AdjustTime(adjustment); err != nil {
+			t.Fatalf("failed to adjust time by %v: %v", adjustment, err)
+		}
+		backend.Commit()
+
+		have := new(big.Int).Sub(backend.Blockchain().CurrentBlock().Time(), parent)
+		want := new(big.Int).Add(step, big.NewInt(int64(adjustment/time.Millisecond)))
+		if have.Cmp(want) != 0 {
+			t.Errorf("block time shift mismatch for %v: have %v ms, want %v ms", adjustment, have, want)
+		}
+	}
+}
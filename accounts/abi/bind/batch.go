@@ -0,0 +1,170 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BatchCall gathers constant calls of bound contracts to execute them together,
+// in a single request if the backend implements BatchContractCaller. All calls
+// run on the block of the options, else on the latest block resolved once for
+// the whole batch if the backend implements ChainHeadReader. The results of the
+// calls are only set once the batch is executed.
+type BatchCall struct {
+	opts   CallOpts
+	caller ContractCaller
+	calls  []*batchedCall
+}
+
+// batchedCall is a call gathered in a batch.
+type batchedCall struct {
+	contract *BoundContract
+	method   string
+	msg      cpchain.CallMsg
+	result   interface{}
+}
+
+// NewBatchCall creates an empty batch of calls executed through caller.
+func NewBatchCall(opts *CallOpts, caller ContractCaller) *BatchCall {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	return &BatchCall{opts: *opts, caller: caller}
+}
+
+// Len returns the number of calls gathered in the batch.
+func (b *BatchCall) Len() int {
+	return len(b.calls)
+}
+
+// BatchCall adds a call of the (constant) contract method with params as input
+// values to batch. The output is unpacked into result once the batch is executed,
+// like for Call.
+func (c *BoundContract) BatchCall(batch *BatchCall, result interface{}, method string, params ...interface{}) error {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	batch.calls = append(batch.calls, &batchedCall{
+		contract: c,
+		method:   method,
+		msg:      cpchain.CallMsg{From: batch.opts.From, To: &c.address, Data: input},
+		result:   result,
+	})
+	return nil
+}
+
+// Execute executes the calls of the batch and unpacks their results, stopping at
+// the first failed call.
+func (b *BatchCall) Execute() error {
+	if len(b.calls) == 0 {
+		return nil
+	}
+	ctx := ensureContext(b.opts.Context)
+	number, err := b.blockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	outputs, errs, err := b.execute(ctx, number)
+	if err != nil {
+		return err
+	}
+	for i, call := range b.calls {
+		if errs[i] == nil && len(outputs[i]) == 0 {
+			// Make sure we have a contract to operate on, and bail out otherwise.
+			errs[i] = b.checkCode(ctx, call.contract.address, number)
+		}
+		if errs[i] != nil {
+			return fmt.Errorf("%s: %v", call.method, errs[i])
+		}
+		if err := call.contract.abi.Unpack(call.result, call.method, outputs[i]); err != nil {
+			return fmt.Errorf("%s: %v", call.method, err)
+		}
+	}
+	return nil
+}
+
+// blockNumber returns the block the calls run on, nil for the latest block at
+// the time of each call.
+func (b *BatchCall) blockNumber(ctx context.Context) (*big.Int, error) {
+	if b.opts.Pending || b.opts.BlockNumber != nil {
+		return b.opts.BlockNumber, nil
+	}
+	hr, ok := b.caller.(ChainHeadReader)
+	if !ok {
+		return nil, nil
+	}
+	head, err := hr.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return head.Number, nil
+}
+
+// execute runs the calls on the backend, in a single request if it supports it.
+func (b *BatchCall) execute(ctx context.Context, number *big.Int) ([][]byte, []error, error) {
+	msgs := make([]cpchain.CallMsg, len(b.calls))
+	for i, call := range b.calls {
+		msgs[i] = call.msg
+	}
+	if b.opts.Pending {
+		pb, ok := b.caller.(PendingContractCaller)
+		if !ok {
+			return nil, nil, ErrNoPendingState
+		}
+		outputs, errs := make([][]byte, len(msgs)), make([]error, len(msgs))
+		for i, msg := range msgs {
+			outputs[i], errs[i] = pb.PendingCallContract(ctx, msg)
+		}
+		return outputs, errs, nil
+	}
+	if bc, ok := b.caller.(BatchContractCaller); ok {
+		return bc.BatchCallContract(ctx, msgs, number)
+	}
+	outputs, errs := make([][]byte, len(msgs)), make([]error, len(msgs))
+	for i, msg := range msgs {
+		outputs[i], errs[i] = b.caller.CallContract(ctx, msg, number)
+	}
+	return outputs, errs, nil
+}
+
+// checkCode returns ErrNoCode if there is no contract at addr.
+func (b *BatchCall) checkCode(ctx context.Context, addr common.Address, number *big.Int) error {
+	var (
+		code []byte
+		err  error
+	)
+	if b.opts.Pending {
+		code, err = b.caller.(PendingContractCaller).PendingCodeAt(ctx, addr)
+	} else {
+		code, err = b.caller.CodeAt(ctx, addr, number)
+	}
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return ErrNoCode
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const counterABI = `[{"constant":true,"inputs":[],"name":"count","outputs":[{"name":"","type":"uint256"}],"type":"function"}]`
+
+// blockCaller records the blocks the calls run on, the head moving on with
+// every call.
+type blockCaller struct {
+	head   int64
+	blocks []*big.Int
+}
+
+func (c *blockCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *blockCaller) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.blocks = append(c.blocks, blockNumber)
+	c.head++
+	return common.LeftPadBytes(big.NewInt(c.head).Bytes(), 32), nil
+}
+
+// headCaller is a blockCaller able to tell the chain head.
+type headCaller struct {
+	blockCaller
+}
+
+func (c *headCaller) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(c.head)}, nil
+}
+
+func TestBatchCallBlock(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(counterABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := func(opts *bind.CallOpts, caller bind.ContractCaller) {
+		var (
+			contract = bind.NewBoundContract(common.Address{1}, parsed, caller, nil, nil)
+			batch    = bind.NewBatchCall(opts, caller)
+			counts   = make([]*big.Int, 3)
+		)
+		for i := range counts {
+			if err := contract.BatchCall(batch, &counts[i], "count"); err != nil {
+				t.Fatalf("failed to batch call: %v", err)
+			}
+		}
+		if err := batch.Execute(); err != nil {
+			t.Fatalf("failed to execute batch: %v", err)
+		}
+	}
+
+	// The latest block is resolved once for all calls
+	caller := &headCaller{blockCaller{head: 7}}
+	batch(nil, caller)
+	for i, block := range caller.blocks {
+		if block == nil || block.Int64() != 7 {
+			t.Errorf("call %d: block mismatch: have %v, want 7", i, block)
+		}
+	}
+	// The block of the options is used as is
+	caller = &headCaller{blockCaller{head: 7}}
+	batch(&bind.CallOpts{BlockNumber: big.NewInt(3)}, caller)
+	for i, block := range caller.blocks {
+		if block == nil || block.Int64() != 3 {
+			t.Errorf("call %d: block mismatch: have %v, want 3", i, block)
+		}
+	}
+	// Backends without the head run each call on the latest block
+	plain := &blockCaller{head: 7}
+	batch(nil, plain)
+	for i, block := range plain.blocks {
+		if block != nil {
+			t.Errorf("call %d: block mismatch: have %v, want latest", i, block)
+		}
+	}
+}
@@ -284,6 +284,17 @@ var bindTests = []struct {
 			} else if str != "Hi" || num.Cmp(big.NewInt(1)) != 0 {
 				t.Fatalf("Retrieved value mismatch: have %v/%v, want %v/%v", str, num, "Hi", 1)
 			}
+			// Execute the same call in a batch
+			batch := bind.NewBatchCall(nil, sim)
+			str, num, _, err := getter.BatchGetter(batch)
+			if err != nil {
+				t.Fatalf("Failed to batch anonymous field retriever: %v", err)
+			}
+			if err := batch.Execute(); err != nil {
+				t.Fatalf("Failed to execute batch: %v", err)
+			} else if *str != "Hi" || (*num).Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("Batched value mismatch: have %v/%v, want %v/%v", *str, *num, "Hi", 1)
+			}
 		`,
 	},
 	// Tests that tuples can be properly returned and deserialized
@@ -316,6 +327,24 @@ var bindTests = []struct {
 			} else if res.A != "Hi" || res.B.Cmp(big.NewInt(1)) != 0 {
 				t.Fatalf("Retrieved value mismatch: have %v/%v, want %v/%v", res.A, res.B, "Hi", 1)
 			}
+			// Execute the same call twice in a batch
+			batch := bind.NewBatchCall(nil, sim)
+			first, err := tupler.BatchTuple(batch)
+			if err != nil {
+				t.Fatalf("Failed to batch structure retriever: %v", err)
+			}
+			second, _ := tupler.BatchTuple(batch)
+			if batch.Len() != 2 {
+				t.Fatalf("Batch length mismatch: have %d, want %d", batch.Len(), 2)
+			}
+			if err := batch.Execute(); err != nil {
+				t.Fatalf("Failed to execute batch: %v", err)
+			}
+			for _, res := range []*struct{ A string; B *big.Int; C [32]byte }{first, second} {
+				if res.A != "Hi" || res.B.Cmp(big.NewInt(1)) != 0 {
+					t.Fatalf("Batched value mismatch: have %v/%v, want %v/%v", res.A, res.B, "Hi", 1)
+				}
+			}
 		`,
 	},
 	// Tests that arrays/slices can be properly returned and deserialized.
@@ -740,6 +769,69 @@ var bindTests = []struct {
 				t.Fatalf("unsubscribed simple event arrived: %v", event)
 			case <-time.After(250 * time.Millisecond):
 			}
+			// Test following events from past blocks on
+			fit, err := eventer.FollowSimpleEvent(&bind.FollowOpts{Poll: time.Millisecond}, []common.Address{{3}, {255}}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to follow simple events: %v", err)
+			}
+			defer fit.Close()
+
+			for _, want := range []uint64{33, 255} {
+				if !fit.Next() {
+					t.Fatalf("followed simple event %d not found: %v", want, fit.Error())
+				}
+				if fit.Event.Value.Uint64() != want || fit.Event.Raw.Removed {
+					t.Errorf("followed simple log mismatch: have %v, want %d", fit.Event, want)
+				}
+			}
+			forked, stale := fit.Event.Raw.BlockNumber-1, fit.Checkpoint()
+			if stale.Number != sim.Blockchain().CurrentBlock().NumberU64() {
+				t.Fatalf("checkpoint mismatch: have %d, want %d", stale.Number, sim.Blockchain().CurrentBlock().NumberU64())
+			}
+			// Reorganise the followed events away and raise another one instead
+			if err := sim.Blockchain().SetHead(forked); err != nil {
+				t.Fatalf("failed to rewind chain: %v", err)
+			}
+			sim.Rollback()
+			if _, err := eventer.RaiseSimpleEvent(auth, common.Address{3}, [32]byte{3}, true, big.NewInt(77)); err != nil {
+				t.Fatalf("failed to raise followed simple event: %v", err)
+			}
+			sim.Commit()
+
+			if !fit.Next() || fit.Event.Value.Uint64() != 255 || !fit.Event.Raw.Removed {
+				t.Fatalf("reorganised simple event not removed: %v, %v", fit.Event, fit.Error())
+			}
+			if !fit.Next() || fit.Event.Value.Uint64() != 77 || fit.Event.Raw.Removed {
+				t.Fatalf("new simple event not followed: %v, %v", fit.Event, fit.Error())
+			}
+			checkpoint := fit.Checkpoint()
+			fit.Close()
+
+			if fit.Next() {
+				t.Fatalf("closed follower delivered an event: %v", fit.Event)
+			}
+			// Resume following from the checkpoint, and fail from the reorganised one
+			rit, err := eventer.FollowSimpleEvent(&bind.FollowOpts{Checkpoint: &checkpoint, Poll: time.Millisecond}, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to resume following simple events: %v", err)
+			}
+			defer rit.Close()
+
+			if _, err := eventer.RaiseSimpleEvent(auth, common.Address{4}, [32]byte{4}, true, big.NewInt(88)); err != nil {
+				t.Fatalf("failed to raise followed simple event: %v", err)
+			}
+			sim.Commit()
+
+			if !rit.Next() || rit.Event.Value.Uint64() != 88 {
+				t.Fatalf("resumed simple event mismatch: %v, %v", rit.Event, rit.Error())
+			}
+			xit, err := eventer.FollowSimpleEvent(&bind.FollowOpts{Checkpoint: &stale, Poll: time.Millisecond}, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to resume following simple events: %v", err)
+			}
+			if xit.Next() || xit.Error() != bind.ErrReorgTooDeep {
+				t.Fatalf("stale checkpoint error mismatch: have %v, want %v", xit.Error(), bind.ErrReorgTooDeep)
+			}
 		`,
 	},
 	{
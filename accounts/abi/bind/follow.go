@@ -0,0 +1,289 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// followWindow is the number of blocks below the head whose delivered events
+	// are remembered, to be removed if the blocks are reorganised away.
+	followWindow = 128
+
+	// maxFollowRange is the maximum number of blocks whose logs are read at once.
+	maxFollowRange = 2048
+
+	// defaultFollowPoll is the interval the backend is polled at for new blocks
+	// if none is set.
+	defaultFollowPoll = time.Second
+)
+
+// FollowOpts is the collection of options to fine tune following events within
+// a bound contract.
+type FollowOpts struct {
+	Start         uint64        // First block to follow if no checkpoint is given
+	Checkpoint    *Checkpoint   // Checkpoint to resume following after (nil or zero = from Start)
+	Confirmations uint64        // Number of blocks to wait for on top of a block before delivering its events
+	Poll          time.Duration // Interval to poll the backend for new blocks at (0 = 1 second)
+
+	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+}
+
+// Checkpoint is the position of a LogFollower, the last block whose events were
+// all delivered. Following can be resumed from a persisted checkpoint as long as
+// its block is still canonical.
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// followedBlock is a block whose events were delivered.
+type followedBlock struct {
+	number uint64
+	hash   common.Hash
+	logs   []types.Log
+}
+
+// followedItem is either a log to deliver or the checkpoint reached once the
+// logs queued before it are delivered.
+type followedItem struct {
+	log        types.Log
+	checkpoint *Checkpoint
+}
+
+// LogFollower follows the logs of a contract matching a query, block after block,
+// across chain reorganisations: the logs of the blocks reorganised away are
+// delivered again with their Removed flag set, newest first, before the logs of
+// the new canonical blocks.
+type LogFollower struct {
+	backend       ContractFollower
+	query         cpchain.FilterQuery
+	confirmations uint64
+	poll          time.Duration
+	ctx           context.Context
+
+	blocks     []*followedBlock // Blocks delivered in the follow window, oldest first
+	next       uint64           // Number of the next block to read the logs of
+	queue      []followedItem   // Logs and checkpoints to deliver
+	log        types.Log        // Log delivered last
+	checkpoint Checkpoint       // Checkpoint of the logs delivered so far
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	fail      error
+}
+
+// FollowLogs follows the contract logs of past and future blocks, returning the
+// follower to construct a strongly typed bound iterator on top of it.
+func (c *BoundContract) FollowLogs(opts *FollowOpts, name string, query ...[]interface{}) (*LogFollower, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FollowOpts)
+	}
+	backend, ok := c.filterer.(ContractFollower)
+	if !ok {
+		return nil, ErrNoFollowing
+	}
+	// Append the event selector to the query parameters and construct the topic set
+	query = append([][]interface{}{{c.abi.Events[name].Id()}}, query...)
+
+	topics, err := makeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+	f := &LogFollower{
+		backend:       backend,
+		query:         cpchain.FilterQuery{Addresses: []common.Address{c.address}, Topics: topics},
+		confirmations: opts.Confirmations,
+		poll:          opts.Poll,
+		ctx:           ensureContext(opts.Context),
+		next:          opts.Start,
+		quit:          make(chan struct{}),
+	}
+	if f.poll == 0 {
+		f.poll = defaultFollowPoll
+	}
+	if opts.Checkpoint != nil && *opts.Checkpoint != (Checkpoint{}) {
+		f.checkpoint = *opts.Checkpoint
+		f.blocks = []*followedBlock{{number: opts.Checkpoint.Number, hash: opts.Checkpoint.Hash}}
+		f.next = opts.Checkpoint.Number + 1
+	}
+	return f, nil
+}
+
+// Next waits for the subsequent log, returning false if the follower failed or
+// was closed. Error() can then be queried for the exact failure.
+func (f *LogFollower) Next() bool {
+	for f.fail == nil {
+		if len(f.queue) > 0 {
+			f.log, f.queue = f.queue[0].log, f.queue[1:]
+			f.advance()
+			return true
+		}
+		queued, err := f.update()
+		if err != nil {
+			f.fail = err
+			break
+		}
+		if queued {
+			f.advance()
+			continue
+		}
+		select {
+		case <-time.After(f.poll):
+		case <-f.ctx.Done():
+			f.fail = f.ctx.Err()
+		case <-f.quit:
+			return false
+		}
+	}
+	return false
+}
+
+// Log returns the log delivered last.
+func (f *LogFollower) Log() types.Log {
+	return f.log
+}
+
+// Checkpoint returns the checkpoint to resume following after once the logs
+// delivered so far are handled, the zero Checkpoint if no block was followed yet.
+func (f *LogFollower) Checkpoint() Checkpoint {
+	return f.checkpoint
+}
+
+// Error returns any retrieval error occurred during following.
+func (f *LogFollower) Error() error {
+	return f.fail
+}
+
+// Close stops the follower, releasing a pending Next.
+func (f *LogFollower) Close() error {
+	f.closeOnce.Do(func() { close(f.quit) })
+	return nil
+}
+
+// advance moves the checkpoint past the checkpoints at the front of the queue.
+func (f *LogFollower) advance() {
+	for len(f.queue) > 0 && f.queue[0].checkpoint != nil {
+		f.checkpoint, f.queue = *f.queue[0].checkpoint, f.queue[1:]
+	}
+}
+
+// update unwinds the blocks reorganised away and queues the logs of the new
+// blocks, returning whether anything was queued.
+func (f *LogFollower) update() (bool, error) {
+	unwound, err := f.unwind()
+	if err != nil {
+		return false, err
+	}
+	head, err := f.backend.HeaderByNumber(f.ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if head.Number.Uint64() < f.next+f.confirmations {
+		return unwound, nil
+	}
+	last := head.Number.Uint64() - f.confirmations
+	if last-f.next >= maxFollowRange {
+		last = f.next + maxFollowRange - 1
+	}
+	target, err := f.header(last)
+	if err != nil || target == nil {
+		return unwound, err
+	}
+	query := f.query
+	query.FromBlock, query.ToBlock = new(big.Int).SetUint64(f.next), new(big.Int).SetUint64(last)
+
+	logs, err := f.backend.FilterLogs(f.ctx, query)
+	if err != nil {
+		return false, err
+	}
+	// The logs may belong to another chain if a reorg happened meanwhile
+	if again, err := f.header(last); err != nil || again == nil || again.Hash() != target.Hash() {
+		return unwound, err
+	}
+	for i := 0; i < len(logs); {
+		block := &followedBlock{number: logs[i].BlockNumber, hash: logs[i].BlockHash}
+		for ; i < len(logs) && logs[i].BlockHash == block.hash; i++ {
+			block.logs = append(block.logs, logs[i])
+			f.queue = append(f.queue, followedItem{log: logs[i]})
+		}
+		f.blocks = append(f.blocks, block)
+		f.queue = append(f.queue, followedItem{checkpoint: &Checkpoint{Number: block.number, Hash: block.hash}})
+	}
+	if len(f.blocks) == 0 || f.blocks[len(f.blocks)-1].number != last {
+		f.blocks = append(f.blocks, &followedBlock{number: last, hash: target.Hash()})
+		f.queue = append(f.queue, followedItem{checkpoint: &Checkpoint{Number: last, Hash: target.Hash()}})
+	}
+	f.next = last + 1
+
+	// Forget the blocks out of the follow window, but the newest of them
+	for len(f.blocks) > 1 && f.blocks[1].number+followWindow <= last {
+		f.blocks = f.blocks[1:]
+	}
+	return len(f.queue) > 0, nil
+}
+
+// unwind queues the removal of the logs of the delivered blocks which are no
+// longer canonical, returning whether anything was queued.
+func (f *LogFollower) unwind() (bool, error) {
+	unwound := false
+	for len(f.blocks) > 0 {
+		block := f.blocks[len(f.blocks)-1]
+		header, err := f.header(block.number)
+		if err != nil {
+			return false, err
+		}
+		if header != nil && header.Hash() == block.hash {
+			break
+		}
+		for i := len(block.logs) - 1; i >= 0; i-- {
+			log := block.logs[i]
+			log.Removed = true
+			f.queue = append(f.queue, followedItem{log: log})
+		}
+		f.blocks = f.blocks[:len(f.blocks)-1]
+		unwound = true
+	}
+	if !unwound {
+		return false, nil
+	}
+	if len(f.blocks) == 0 {
+		return false, ErrReorgTooDeep
+	}
+	fork := f.blocks[len(f.blocks)-1]
+	f.queue = append(f.queue, followedItem{checkpoint: &Checkpoint{Number: fork.number, Hash: fork.hash}})
+	f.next = fork.number + 1
+	return true, nil
+}
+
+// header returns the canonical header at number, nil if there is none.
+func (f *LogFollower) header(number uint64) (*types.Header, error) {
+	header, err := f.backend.HeaderByNumber(f.ctx, new(big.Int).SetUint64(number))
+	if err == cpchain.NotFound {
+		return nil, nil
+	}
+	return header, err
+}
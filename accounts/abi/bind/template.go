@@ -247,6 +247,26 @@ package {{.Package}}
 		func (_{{$contract.Type}} *{{$contract.Type}}CallerSession) {{.Normalized.Name}}({{range $i, $_ := .Normalized.Inputs}}{{if ne $i 0}},{{end}} {{.Name}} {{bindtype .Type}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type}};{{end}} }, {{else}} {{range .Normalized.Outputs}}{{bindtype .Type}},{{end}} {{end}} error) {
 		  return _{{$contract.Type}}.Contract.{{.Normalized.Name}}(&_{{$contract.Type}}.CallOpts {{range .Normalized.Inputs}}, {{.Name}}{{end}})
 		}
+
+		// Batch{{.Normalized.Name}} adds a call of the contract method 0x{{printf "%x" .Original.Id}} to batch, the
+		// returned results being set once the batch is executed.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Caller) Batch{{.Normalized.Name}}(batch *bind.BatchCall {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type}} {{end}}) ({{if .Structured}}*struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type}};{{end}} },{{else}}{{range .Normalized.Outputs}}*{{bindtype .Type}},{{end}}{{end}} error) {
+			{{if .Structured}}ret := new(struct{
+				{{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type}}
+				{{end}}
+			}){{else}}var (
+				{{range $i, $_ := .Normalized.Outputs}}ret{{$i}} = new({{bindtype .Type}})
+				{{end}}
+			){{end}}
+			out := {{if .Structured}}ret{{else}}{{if eq (len .Normalized.Outputs) 1}}ret0{{else}}&[]interface{}{
+				{{range $i, $_ := .Normalized.Outputs}}ret{{$i}},
+				{{end}}
+			}{{end}}{{end}}
+			err := _{{$contract.Type}}.contract.BatchCall(batch, out, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			return {{if .Structured}}ret,{{else}}{{range $i, $_ := .Normalized.Outputs}}ret{{$i}},{{end}}{{end}} err
+		}
 	{{end}}
 
 	{{range .Transacts}}
@@ -401,6 +421,67 @@ package {{.Package}}
 				}
 			}), nil
 		}
+
+		// {{$contract.Type}}{{.Normalized.Name}}Follower is returned from Follow{{.Normalized.Name}} and is used to follow the unpacked data for {{.Normalized.Name}} events raised by the {{$contract.Type}} contract across chain reorganisations.
+		type {{$contract.Type}}{{.Normalized.Name}}Follower struct {
+			Event *{{$contract.Type}}{{.Normalized.Name}} // Event containing the contract specifics and raw log, removed if reorganised away
+
+			contract *bind.BoundContract // Generic contract to use for unpacking event data
+			event    string              // Event name to use for unpacking event data
+
+			follower *bind.LogFollower // Follower delivering the raw logs
+			fail     error             // Occurred error to stop following
+		}
+		// Next waits for the subsequent event, returning false if following failed or
+		// was closed. In case of a retrieval or parsing error, Error() can be queried
+		// for the exact failure.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Follower) Next() bool {
+			if it.fail != nil || !it.follower.Next() {
+				return false
+			}
+			log := it.follower.Log()
+			it.Event = new({{$contract.Type}}{{.Normalized.Name}})
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+		}
+		// Checkpoint returns the checkpoint to resume following after once the events
+		// delivered so far are handled.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Follower) Checkpoint() bind.Checkpoint {
+			return it.follower.Checkpoint()
+		}
+		// Error returns any retrieval or parsing error occurred during following.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Follower) Error() error {
+			if it.fail != nil {
+				return it.fail
+			}
+			return it.follower.Error()
+		}
+		// Close terminates the following process, releasing a pending Next.
+		func (it *{{$contract.Type}}{{.Normalized.Name}}Follower) Close() error {
+			return it.follower.Close()
+		}
+
+		// Follow{{.Normalized.Name}} is a free log following operation binding the contract event 0x{{printf "%x" .Original.Id}},
+		// delivering again the events of the blocks reorganised away as removed.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Filterer) Follow{{.Normalized.Name}}(opts *bind.FollowOpts{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}} []{{bindtype .Type}}{{end}}{{end}}) (*{{$contract.Type}}{{.Normalized.Name}}Follower, error) {
+			{{range .Normalized.Inputs}}
+			{{if .Indexed}}var {{.Name}}Rule []interface{}
+			for _, {{.Name}}Item := range {{.Name}} {
+				{{.Name}}Rule = append({{.Name}}Rule, {{.Name}}Item)
+			}{{end}}{{end}}
+
+			follower, err := _{{$contract.Type}}.contract.FollowLogs(opts, "{{.Original.Name}}"{{range .Normalized.Inputs}}{{if .Indexed}}, {{.Name}}Rule{{end}}{{end}})
+			if err != nil {
+				return nil, err
+			}
+			return &{{$contract.Type}}{{.Normalized.Name}}Follower{contract: _{{$contract.Type}}.contract, event: "{{.Original.Name}}", follower: follower}, nil
+		}
  	{{end}}
 {{end}}
 `
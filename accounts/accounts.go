@@ -132,6 +132,19 @@ type Wallet interface {
 	PublicKey(account Account) ([]byte, error)
 }
 
+// SealSigner is implemented by wallets that sign the dpor consensus messages
+// from their content instead of a bare hash. The wallet derives the signed hash
+// itself, so it can apply stricter approval rules to arbitrary hashes.
+type SealSigner interface {
+	// SignSeal requests the wallet to sign a block header, as the proposer seal
+	// and validator commit or, if prepare is set, as the validator prepare.
+	SignSeal(account Account, header *types.Header, prepare bool) ([]byte, error)
+
+	// SignMac requests the wallet to sign the message authentication code
+	// dpor peers exchange in their handshake.
+	SignMac(account Account, mac string) ([]byte, error)
+}
+
 // Backend is a "wallet provider" that may contain a batch of accounts they can
 // sign transactions with and upon request, do so.
 type Backend interface {
@@ -0,0 +1,287 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package external implements an account backend forwarding all signing
+// requests to an external signer process over JSON-RPC, so that the keys never
+// have to reside on the node host.
+package external
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ExternalBackendType is the reflect type of an external signer backend.
+var ExternalBackendType = reflect.TypeOf(&ExternalBackend{})
+
+// errBadSignature is returned if the signer answers with a signature from a
+// different account or for different content than requested.
+var errBadSignature = errors.New("external signer returned a mismatching signature")
+
+// ExternalBackend is an account backend with a single wallet, the external
+// signer it is connected to.
+type ExternalBackend struct {
+	signers []accounts.Wallet
+}
+
+// NewExternalBackend connects to the signer listening on endpoint, which may
+// be an IPC path or an HTTP URL.
+func NewExternalBackend(endpoint string) (*ExternalBackend, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewExternalBackendWithClient(endpoint, client)
+}
+
+// NewExternalBackendWithClient creates a backend talking to the signer over an
+// established RPC client.
+func NewExternalBackendWithClient(endpoint string, client *rpc.Client) (*ExternalBackend, error) {
+	signer := &ExternalSigner{
+		client: client,
+		url:    accounts.URL{Scheme: "extapi", Path: endpoint},
+	}
+	if err := signer.refresh(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list accounts of external signer: %v", err)
+	}
+	return &ExternalBackend{signers: []accounts.Wallet{signer}}, nil
+}
+
+// Wallets implements accounts.Backend, returning the external signer.
+func (b *ExternalBackend) Wallets() []accounts.Wallet {
+	return b.signers
+}
+
+// Subscribe implements accounts.Backend. The set of signers never changes, so
+// no events are ever sent.
+func (b *ExternalBackend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// ExternalSigner is a wallet whose accounts are held by an external signer.
+// Every signature it returns is checked against the requesting account before
+// being handed out.
+type ExternalSigner struct {
+	client *rpc.Client
+	url    accounts.URL
+
+	mu       sync.RWMutex
+	accounts []accounts.Account
+}
+
+// refresh reloads the list of accounts the signer exposes.
+func (s *ExternalSigner) refresh() error {
+	var addrs []common.Address
+	if err := s.client.Call(&addrs, "account_list"); err != nil {
+		return err
+	}
+	accs := make([]accounts.Account, len(addrs))
+	for i, addr := range addrs {
+		accs[i] = accounts.Account{Address: addr, URL: s.url}
+	}
+	s.mu.Lock()
+	s.accounts = accs
+	s.mu.Unlock()
+	return nil
+}
+
+// URL implements accounts.Wallet, returning the endpoint of the signer.
+func (s *ExternalSigner) URL() accounts.URL {
+	return s.url
+}
+
+// Status implements accounts.Wallet, returning the version of the signer.
+func (s *ExternalSigner) Status() (string, error) {
+	var version string
+	if err := s.client.Call(&version, "account_version"); err != nil {
+		return "Unreachable", err
+	}
+	return fmt.Sprintf("Connected, version %s", version), nil
+}
+
+// Open implements accounts.Wallet, reloading the account list of the signer.
+func (s *ExternalSigner) Open(passphrase string) error {
+	return s.refresh()
+}
+
+// Close implements accounts.Wallet, but is a noop since the connection is
+// shared for the lifetime of the backend.
+func (s *ExternalSigner) Close() error { return nil }
+
+// Accounts implements accounts.Wallet, returning the accounts the signer
+// exposes.
+func (s *ExternalSigner) Accounts() []accounts.Account {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]accounts.Account(nil), s.accounts...)
+}
+
+// Contains implements accounts.Wallet, returning whether the signer exposes
+// the account.
+func (s *ExternalSigner) Contains(account accounts.Account) bool {
+	if account.URL != (accounts.URL{}) && account.URL != s.url {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, acc := range s.accounts {
+		if acc.Address == account.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// Derive implements accounts.Wallet, but is not supported by external signers.
+func (s *ExternalSigner) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// SelfDerive implements accounts.Wallet, but is a noop for external signers.
+func (s *ExternalSigner) SelfDerive(base accounts.DerivationPath, chain cpchain.ChainStateReader) {}
+
+// SignHash implements accounts.Wallet, requesting the signer to sign an
+// arbitrary hash.
+func (s *ExternalSigner) SignHash(account accounts.Account, hash []byte) ([]byte, error) {
+	return s.sign(account, hash, "account_signHash", account.Address, hexutil.Bytes(hash))
+}
+
+// SignSeal implements accounts.SealSigner, sending the header to the signer
+// which derives the seal hash itself. Signers may thus approve seals under
+// other rules than arbitrary hashes.
+func (s *ExternalSigner) SignSeal(account accounts.Account, header *types.Header, prepare bool) ([]byte, error) {
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(account, sealHash(header, prepare), "account_signSeal", account.Address, hexutil.Bytes(raw), prepare)
+}
+
+// SignMac implements accounts.SealSigner, sending the handshake message to the
+// signer which checks and hashes it itself.
+func (s *ExternalSigner) SignMac(account accounts.Account, mac string) ([]byte, error) {
+	return s.sign(account, macHash(mac), "account_signMac", account.Address, mac)
+}
+
+// sign requests a signature with the given method and arguments and verifies
+// it was made over hash by the account.
+func (s *ExternalSigner) sign(account accounts.Account, hash []byte, method string, args ...interface{}) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var sig hexutil.Bytes
+	if err := s.client.Call(&sig, method, args...); err != nil {
+		return nil, err
+	}
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pubkey) != account.Address {
+		return nil, errBadSignature
+	}
+	return sig, nil
+}
+
+// SignTx implements accounts.Wallet, requesting the signer to sign the
+// transaction.
+func (s *ExternalSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var res hexutil.Bytes
+	if err := s.client.Call(&res, "account_signTransaction", account.Address, hexutil.Bytes(raw), (*hexutil.Big)(chainID)); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(res, signed); err != nil {
+		return nil, err
+	}
+	// Make sure the signer signed what we asked for, with the right key
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewCep1Signer(chainID)
+	}
+	if signer.Hash(signed) != signer.Hash(tx) || !bytes.Equal(signed.Data(), tx.Data()) {
+		return nil, errBadSignature
+	}
+	if from, err := types.Sender(signer, signed); err != nil || from != account.Address {
+		return nil, errBadSignature
+	}
+	return signed, nil
+}
+
+// SignHashWithPassphrase implements accounts.Wallet, but is not supported since
+// passphrases never leave the signer host.
+func (s *ExternalSigner) SignHashWithPassphrase(account accounts.Account, passphrase string, hash []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// SignTxWithPassphrase implements accounts.Wallet, but is not supported since
+// passphrases never leave the signer host.
+func (s *ExternalSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// DecryptWithEcies implements accounts.Wallet, but is not supported by
+// external signers.
+func (s *ExternalSigner) DecryptWithEcies(account accounts.Account, cipherText []byte) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+// PublicKey implements accounts.Wallet, returning the uncompressed public key
+// of the account.
+func (s *ExternalSigner) PublicKey(account accounts.Account) ([]byte, error) {
+	if !s.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	var pubkey hexutil.Bytes
+	if err := s.client.Call(&pubkey, "account_publicKey", account.Address); err != nil {
+		return nil, err
+	}
+	key, err := crypto.UnmarshalPubkey(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*key) != account.Address {
+		return nil, errBadSignature
+	}
+	return pubkey, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestSigner(t *testing.T, rules *Rules) (*ExternalSigner, accounts.Account, func()) {
+	dir, err := ioutil.TempDir("", "external-signer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	key, _ := crypto.GenerateKey()
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", NewSignerAPI(ks, rules, nil)); err != nil {
+		t.Fatal(err)
+	}
+	backend, err := NewExternalBackendWithClient("inproc", rpc.DialInProc(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend.Wallets()[0].(*ExternalSigner), accounts.Account{Address: account.Address}, func() { os.RemoveAll(dir) }
+}
+
+func TestExternalSignerHashes(t *testing.T) {
+	signer, account, cleanup := newTestSigner(t, &Rules{AllowSeal: true})
+	defer cleanup()
+
+	if accs := signer.Accounts(); len(accs) != 1 || accs[0].Address != account.Address || !signer.Contains(account) {
+		t.Fatalf("account list mismatch: %v", accs)
+	}
+	header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1000), Extra: []byte("seal")}
+	for _, prepare := range []bool{false, true} {
+		sig, err := signer.SignSeal(account, header, prepare)
+		if err != nil {
+			t.Fatalf("failed to sign seal (prepare %v): %v", prepare, err)
+		}
+		if pubkey, err := crypto.SigToPub(sealHash(header, prepare), sig); err != nil || crypto.PubkeyToAddress(*pubkey) != account.Address {
+			t.Fatalf("seal signed by wrong key or over wrong hash (prepare %v)", prepare)
+		}
+	}
+	if _, err := signer.SignSeal(account, &types.Header{Number: big.NewInt(0), Time: big.NewInt(0)}, false); err == nil || err.Error() != errInvalidSeal.Error() {
+		t.Fatalf("genesis seal not denied: %v", err)
+	}
+	mac := "cpchain|" + time.Now().Format(time.RFC3339)
+	sig, err := signer.SignMac(account, mac)
+	if err != nil {
+		t.Fatalf("failed to sign mac: %v", err)
+	}
+	if pubkey, err := crypto.SigToPub(crypto.Keccak256([]byte(mac)), sig); err != nil || crypto.PubkeyToAddress(*pubkey) != account.Address {
+		t.Fatalf("mac signed by wrong key")
+	}
+	for _, mac := range []string{"cpchain|yesterday", "other|" + time.Now().Format(time.RFC3339), "cpchain|" + time.Now().Add(-time.Hour).Format(time.RFC3339)} {
+		if _, err := signer.SignMac(account, mac); err == nil || err.Error() != errInvalidMac.Error() {
+			t.Fatalf("mac %q not denied: %v", mac, err)
+		}
+	}
+	hash := crypto.Keccak256([]byte("seal"))
+	if _, err := signer.SignHash(account, hash); err == nil || err.Error() != ErrRequestDenied.Error() {
+		t.Fatalf("hash signing not denied: %v", err)
+	}
+	if _, err := signer.SignSeal(accounts.Account{Address: common.Address{0x01}}, header, false); err != accounts.ErrUnknownAccount {
+		t.Fatalf("unknown account error mismatch: %v", err)
+	}
+	if _, err := signer.PublicKey(account); err != nil {
+		t.Fatalf("failed to retrieve public key: %v", err)
+	}
+}
+
+func TestExternalSignerDefaultRules(t *testing.T) {
+	signer, account, cleanup := newTestSigner(t, new(Rules))
+	defer cleanup()
+
+	header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1000)}
+	if _, err := signer.SignSeal(account, header, false); err == nil || err.Error() != ErrRequestDenied.Error() {
+		t.Fatalf("seal signing not denied: %v", err)
+	}
+	if _, err := signer.SignMac(account, "cpchain|"+time.Now().Format(time.RFC3339)); err == nil || err.Error() != ErrRequestDenied.Error() {
+		t.Fatalf("mac signing not denied: %v", err)
+	}
+}
+
+func TestExternalSignerTransactions(t *testing.T) {
+	to := common.Address{0x02}
+	signer, account, cleanup := newTestSigner(t, &Rules{
+		AllowTx:   true,
+		AllowedTo: []common.Address{to},
+		MaxValue:  (*hexutil.Big)(big.NewInt(100)),
+	})
+	defer cleanup()
+
+	chainID := big.NewInt(42)
+	tx := types.NewTransaction(0, to, big.NewInt(100), 21000, big.NewInt(1), nil)
+	signed, err := signer.SignTx(account, tx, chainID)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if from, err := types.Sender(types.NewCep1Signer(chainID), signed); err != nil || from != account.Address {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", from, err, account.Address)
+	}
+	denied := []*types.Transaction{
+		types.NewTransaction(0, to, big.NewInt(101), 21000, big.NewInt(1), nil),
+		types.NewTransaction(0, common.Address{0x03}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), []byte{0x00}),
+	}
+	for i, tx := range denied {
+		if _, err := signer.SignTx(account, tx, chainID); err == nil || err.Error() != ErrRequestDenied.Error() {
+			t.Errorf("transaction %d: not denied: %v", i, err)
+		}
+	}
+	if _, err := signer.SignTxWithPassphrase(account, "", tx, chainID); err != accounts.ErrNotSupported {
+		t.Fatalf("passphrase signing error mismatch: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SignerVersion is the version of the signer protocol served by SignerAPI.
+const SignerVersion = "1.0.0"
+
+// macMaxSkew is the maximum distance of a handshake mac timestamp from the
+// clock of the signer.
+const macMaxSkew = 5 * time.Minute
+
+var (
+	// ErrRequestDenied is returned if a signing request is rejected by the rules
+	// or the approval callback of the signer.
+	ErrRequestDenied = errors.New("request denied")
+
+	// errInvalidSeal is returned if a seal request carries no valid header.
+	errInvalidSeal = errors.New("invalid seal header")
+
+	// errInvalidMac is returned if a mac request is not a recent dpor
+	// handshake message.
+	errInvalidMac = errors.New("invalid handshake mac")
+)
+
+// Request kinds passed to the approval callback.
+const (
+	RequestSeal        = "seal"
+	RequestMac         = "mac"
+	RequestHash        = "hash"
+	RequestTransaction = "transaction"
+)
+
+// Rules are the static approval rules of a signer. Requests passing them are
+// handed to the approval callback, if any.
+type Rules struct {
+	Accounts    []common.Address `json:"accounts"`    // Accounts exposed to the node, all keystore accounts if empty
+	AllowSeal   bool             `json:"allowSeal"`   // Whether DPoR seals and handshake macs may be signed
+	AllowHash   bool             `json:"allowHash"`   // Whether arbitrary hashes may be signed
+	AllowTx     bool             `json:"allowTx"`     // Whether transactions may be signed
+	AllowCreate bool             `json:"allowCreate"` // Whether contract creations may be signed
+	AllowedTo   []common.Address `json:"allowedTo"`   // Permitted transaction recipients, any if empty
+	MaxValue    *hexutil.Big     `json:"maxValue"`    // Maximum value of a transaction, unlimited if nil
+}
+
+// LoadRules reads signer rules from a JSON file.
+func LoadRules(path string) (*Rules, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := new(Rules)
+	if err := json.Unmarshal(blob, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Request is a signing request that passed the static rules.
+type Request struct {
+	Kind    string             // One of RequestSeal, RequestMac, RequestHash or RequestTransaction
+	Account common.Address     // Account asked to sign
+	Hash    []byte             // Hash to sign, nil for transactions
+	Header  *types.Header      // Header to seal, nil unless a seal
+	Mac     string             // Handshake message, empty unless a mac
+	Tx      *types.Transaction // Transaction to sign, nil for hashes
+}
+
+// ApproveFn decides on a signing request that passed the static rules.
+type ApproveFn func(req *Request) bool
+
+// SignerAPI is the JSON-RPC service of an external signer, to be registered
+// under the "account" namespace. It signs with unlocked keystore accounts.
+type SignerAPI struct {
+	ks      *keystore.KeyStore
+	rules   Rules
+	approve ApproveFn
+}
+
+// NewSignerAPI creates a signer service. The approval callback may be nil, in
+// which case all requests passing the rules are approved.
+func NewSignerAPI(ks *keystore.KeyStore, rules *Rules, approve ApproveFn) *SignerAPI {
+	return &SignerAPI{ks: ks, rules: *rules, approve: approve}
+}
+
+// Version returns the version of the signer protocol.
+func (api *SignerAPI) Version() string {
+	return SignerVersion
+}
+
+// List returns the addresses of the accounts exposed by the signer.
+func (api *SignerAPI) List() []common.Address {
+	var addrs []common.Address
+	for _, account := range api.ks.Accounts() {
+		if api.exposed(account.Address) {
+			addrs = append(addrs, account.Address)
+		}
+	}
+	return addrs
+}
+
+// SignHash signs an arbitrary hash with the account.
+func (api *SignerAPI) SignHash(addr common.Address, hash hexutil.Bytes) (hexutil.Bytes, error) {
+	if err := api.check(&Request{Kind: RequestHash, Account: addr, Hash: hash}, api.rules.AllowHash); err != nil {
+		return nil, err
+	}
+	return api.ks.SignHash(accounts.Account{Address: addr}, hash)
+}
+
+// SignSeal signs an RLP encoded block header as a DPoR seal, or as a validator
+// prepare if requested. The signed hash is derived from the header here, so the
+// node cannot pass off other hashes as seals.
+func (api *SignerAPI) SignSeal(addr common.Address, raw hexutil.Bytes, prepare bool) (hexutil.Bytes, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(raw, header); err != nil {
+		return nil, err
+	}
+	if header.Number == nil || header.Number.Sign() <= 0 || header.Time == nil {
+		return nil, errInvalidSeal
+	}
+	hash := sealHash(header, prepare)
+	if err := api.check(&Request{Kind: RequestSeal, Account: addr, Hash: hash, Header: header}, api.rules.AllowSeal); err != nil {
+		return nil, err
+	}
+	return api.ks.SignHash(accounts.Account{Address: addr}, hash)
+}
+
+// SignMac signs a dpor handshake message. Only well formed messages stamped
+// close to the local time are signed.
+func (api *SignerAPI) SignMac(addr common.Address, mac string) (hexutil.Bytes, error) {
+	parts := strings.Split(mac, "|")
+	if len(parts) != 2 || parts[0] != "cpchain" {
+		return nil, errInvalidMac
+	}
+	stamp, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return nil, errInvalidMac
+	}
+	if skew := time.Since(stamp); skew > macMaxSkew || skew < -macMaxSkew {
+		return nil, errInvalidMac
+	}
+	hash := macHash(mac)
+	if err := api.check(&Request{Kind: RequestMac, Account: addr, Hash: hash, Mac: mac}, api.rules.AllowSeal); err != nil {
+		return nil, err
+	}
+	return api.ks.SignHash(accounts.Account{Address: addr}, hash)
+}
+
+// SignTransaction signs an RLP encoded transaction with the account and
+// returns the signed transaction in RLP encoding.
+func (api *SignerAPI) SignTransaction(addr common.Address, raw hexutil.Bytes, chainID *hexutil.Big) (hexutil.Bytes, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return nil, err
+	}
+	allowed := api.rules.AllowTx && api.txAllowed(tx)
+	if err := api.check(&Request{Kind: RequestTransaction, Account: addr, Tx: tx}, allowed); err != nil {
+		return nil, err
+	}
+	signed, err := api.ks.SignTx(accounts.Account{Address: addr}, tx, (*big.Int)(chainID))
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(signed)
+}
+
+// PublicKey returns the uncompressed public key of the account.
+func (api *SignerAPI) PublicKey(addr common.Address) (hexutil.Bytes, error) {
+	if !api.exposed(addr) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	return api.ks.EcdsaPublicKey(accounts.Account{Address: addr})
+}
+
+// exposed returns whether the rules expose the account to the node.
+func (api *SignerAPI) exposed(addr common.Address) bool {
+	if len(api.rules.Accounts) == 0 {
+		return api.ks.HasAddress(addr)
+	}
+	for _, account := range api.rules.Accounts {
+		if account == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// txAllowed checks the transaction against the recipient and value rules.
+func (api *SignerAPI) txAllowed(tx *types.Transaction) bool {
+	if tx.To() == nil {
+		return api.rules.AllowCreate
+	}
+	if api.rules.MaxValue != nil && tx.Value().Cmp(api.rules.MaxValue.ToInt()) > 0 {
+		return false
+	}
+	if len(api.rules.AllowedTo) == 0 {
+		return true
+	}
+	for _, to := range api.rules.AllowedTo {
+		if to == *tx.To() {
+			return true
+		}
+	}
+	return false
+}
+
+// check applies the rules and the approval callback to a request and logs the
+// decision.
+func (api *SignerAPI) check(req *Request, allowed bool) error {
+	if !api.exposed(req.Account) {
+		log.Warn("Rejected signing request for unknown account", "kind", req.Kind, "account", req.Account)
+		return accounts.ErrUnknownAccount
+	}
+	if !allowed || (api.approve != nil && !api.approve(req)) {
+		log.Warn("Rejected signing request", "kind", req.Kind, "account", req.Account)
+		return ErrRequestDenied
+	}
+	log.Info("Approved signing request", "kind", req.Kind, "account", req.Account)
+	return nil
+}
+
+// sealHash returns the hash dpor signs a header with: the header hash for the
+// proposer seal and validator commits, prefixed and rehashed for prepares.
+func sealHash(header *types.Header, prepare bool) []byte {
+	hash := header.Hash().Bytes()
+	if prepare {
+		return crypto.Keccak256([]byte("Prepare"), hash)
+	}
+	return hash
+}
+
+// macHash returns the hash dpor signs a handshake message with.
+func macHash(mac string) []byte {
+	return crypto.Keccak256([]byte(mac))
+}
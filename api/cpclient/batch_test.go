@@ -0,0 +1,86 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallTestService echoes the data of the calls, failing the ones without data.
+type CallTestService struct {
+	mu     sync.Mutex
+	blocks []string // Blocks the calls were executed at
+}
+
+func (s *CallTestService) Call(args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	s.mu.Lock()
+	s.blocks = append(s.blocks, block)
+	s.mu.Unlock()
+
+	data, _ := args["data"].(string)
+	if data == "" {
+		return nil, errors.New("execution reverted")
+	}
+	return hexutil.Decode(data)
+}
+
+func TestBatchCallContract(t *testing.T) {
+	service := new(CallTestService)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	client := cpclient.NewClient(rpc.DialInProc(server))
+
+	to := common.HexToAddress("0x01")
+	msgs := []cpchain.CallMsg{
+		{To: &to, Data: []byte{0x01, 0x02}},
+		{To: &to},
+		{To: &to, Data: []byte{0x03}},
+	}
+	outputs, errs, err := client.BatchCallContract(context.Background(), msgs, nil)
+	if err != nil {
+		t.Fatalf("failed to execute batch: %v", err)
+	}
+	if len(outputs) != len(msgs) || len(errs) != len(msgs) {
+		t.Fatalf("result count mismatch: have %d/%d, want %d", len(outputs), len(errs), len(msgs))
+	}
+	if errs[0] != nil || !bytes.Equal(outputs[0], []byte{0x01, 0x02}) {
+		t.Errorf("call 0 mismatch: have %x, %v", outputs[0], errs[0])
+	}
+	if errs[1] == nil {
+		t.Errorf("failed call 1 returned no error")
+	}
+	if errs[2] != nil || !bytes.Equal(outputs[2], []byte{0x03}) {
+		t.Errorf("call 2 mismatch: have %x, %v", outputs[2], errs[2])
+	}
+	for i, block := range service.blocks {
+		if block != "latest" {
+			t.Errorf("call %d block mismatch: have %s, want latest", i, block)
+		}
+	}
+}
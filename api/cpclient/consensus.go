@@ -0,0 +1,329 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/consensus/dpor/finality"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	// ErrUnknownBlock is returned if the node does not know the requested block.
+	ErrUnknownBlock = errors.New("unknown block")
+
+	// ErrMethodNotAvailable is returned if the node does not serve the requested
+	// method, e.g. because its namespace is not exposed on the endpoint.
+	ErrMethodNotAvailable = errors.New("method not available")
+)
+
+// methodNotFoundCode is the JSON-RPC error code for unknown methods.
+const methodNotFoundCode = -32601
+
+// CallError is returned by the typed consensus helpers if a call fails. Err is
+// ErrUnknownBlock or ErrMethodNotAvailable if the failure is recognised, and
+// the original error otherwise.
+type CallError struct {
+	Method string // RPC method that failed
+	Code   int    // JSON-RPC error code, zero if the call did not reach the node
+	Err    error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Method, e.Err)
+}
+
+// wrapCallError converts an error returned by the RPC client into a *CallError.
+func wrapCallError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	callErr := &CallError{Method: method, Err: err}
+	if rpcErr, ok := err.(rpc.Error); ok {
+		callErr.Code = rpcErr.ErrorCode()
+		switch {
+		case callErr.Code == methodNotFoundCode:
+			callErr.Err = ErrMethodNotAvailable
+		case err.Error() == ErrUnknownBlock.Error():
+			callErr.Err = ErrUnknownBlock
+		}
+	}
+	return callErr
+}
+
+// call invokes method and wraps its error.
+func (c *Client) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return wrapCallError(method, c.c.CallContext(ctx, result, method, args...))
+}
+
+// Snapshot is the DPoR consensus snapshot at a block.
+type Snapshot struct {
+	Mode             uint                        `json:"mode"`
+	Number           uint64                      `json:"number"`     // Block number where the snapshot was created
+	Hash             common.Hash                 `json:"hash"`       // Block hash where the snapshot was created
+	Candidates       []common.Address            `json:"candidates"` // Candidates read from the campaign contract
+	RecentProposers  map[uint64][]common.Address `json:"proposers"`  // Proposers of recent terms
+	RecentValidators map[uint64][]common.Address `json:"validators"` // Validators of recent terms
+}
+
+// SnapshotAt returns the DPoR snapshot at the given block. The block number
+// can be nil, in which case the snapshot at the latest block is returned.
+func (c *Client) SnapshotAt(ctx context.Context, blockNumber *big.Int) (*Snapshot, error) {
+	var snap *Snapshot
+	if err := c.call(ctx, &snap, "dpor_getSnapshot", toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		return nil, cpchain.NotFound
+	}
+	return snap, nil
+}
+
+// SnapshotAtHash returns the DPoR snapshot at the block with the given hash.
+func (c *Client) SnapshotAtHash(ctx context.Context, hash common.Hash) (*Snapshot, error) {
+	var snap *Snapshot
+	if err := c.call(ctx, &snap, "dpor_getSnapshotAtHash", hash); err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		return nil, cpchain.NotFound
+	}
+	return snap, nil
+}
+
+// ProposersAt returns the proposers of the term of the given block, as
+// recorded in its header. The block number can be nil for the latest block.
+func (c *Client) ProposersAt(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	var result []common.Address
+	err := c.call(ctx, &result, "dpor_getProposers", toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// ValidatorsAt returns the validators of the term of the given block. The
+// block number can be nil for the latest block.
+func (c *Client) ValidatorsAt(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	var result []common.Address
+	err := c.call(ctx, &result, "dpor_getValidators", toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// FinalityProof returns the proof that the given block is final, along the
+// committee hand-overs since the given checkpoint. The block number can be nil
+// for the latest block. Proofs are checked with finality.Verify, trusting the
+// committee of the checkpoint rather than the node.
+func (c *Client) FinalityProof(ctx context.Context, blockNumber *big.Int, checkpoint uint64) (*finality.Proof, error) {
+	var proof *finality.Proof
+	if err := c.call(ctx, &proof, "dpor_getFinalityProof", toBlockNumArg(blockNumber), hexutil.Uint64(checkpoint)); err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		return nil, cpchain.NotFound
+	}
+	return proof, nil
+}
+
+// RNodeAddresses returns the addresses of the current RNodes.
+func (c *Client) RNodeAddresses(ctx context.Context) ([]common.Address, error) {
+	var result []common.Address
+	err := c.call(ctx, &result, "dpor_getRNodes")
+	return result, err
+}
+
+// CandidatesAt returns the campaign candidates recorded in the DPoR snapshot
+// at the given block. The block number can be nil for the latest block.
+func (c *Client) CandidatesAt(ctx context.Context, blockNumber *big.Int) ([]common.Address, error) {
+	snap, err := c.SnapshotAt(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Candidates, nil
+}
+
+// AdmissionStatus is the state of the node's admission control.
+type AdmissionStatus uint32
+
+const (
+	// AdmissionIdle means no campaign proof work is running.
+	AdmissionIdle AdmissionStatus = iota + 1
+	// AdmissionRunning means the campaign proof work is running.
+	AdmissionRunning
+)
+
+func (s AdmissionStatus) String() string {
+	switch s {
+	case AdmissionIdle:
+		return "idle"
+	case AdmissionRunning:
+		return "running"
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(s))
+}
+
+// AdmissionResult is the outcome of one campaign proof work.
+type AdmissionResult struct {
+	BlockNumber int64  `json:"block_number"`
+	Nonce       uint64 `json:"nonce"`
+	Success     bool   `json:"success"`
+}
+
+// AdmissionStatus returns the state of the node's admission control.
+func (c *Client) AdmissionStatus(ctx context.Context) (AdmissionStatus, error) {
+	var result AdmissionStatus
+	err := c.call(ctx, &result, "admission_getStatus")
+	return result, err
+}
+
+// AdmissionResults returns the results of the last campaign proof works, keyed
+// by proof work type.
+func (c *Client) AdmissionResults(ctx context.Context) (map[string]AdmissionResult, error) {
+	var result map[string]AdmissionResult
+	err := c.call(ctx, &result, "admission_getResult")
+	return result, err
+}
+
+// IsRNode reports whether the node's account is an RNode.
+func (c *Client) IsRNode(ctx context.Context) (bool, error) {
+	var result bool
+	err := c.call(ctx, &result, "admission_isRNode")
+	return result, err
+}
+
+// FundForRNode deposits the RNode threshold from the node's account.
+func (c *Client) FundForRNode(ctx context.Context) error {
+	return c.call(ctx, nil, "admission_fundForRNode")
+}
+
+// AbortCampaign stops the running campaign proof work of the node.
+func (c *Client) AbortCampaign(ctx context.Context) error {
+	return c.call(ctx, nil, "admission_abort")
+}
+
+// PrivateTransactionReceipt returns the receipt of a private transaction. The
+// receipt is only available on nodes participating in the transaction.
+func (c *Client) PrivateTransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	supported, err := c.SupportPrivateTx(ctx)
+	if err != nil {
+		return nil, wrapCallError("eth_supportPrivateTx", err)
+	}
+	if !supported {
+		return nil, &CallError{Method: "eth_supportPrivateTx", Err: types.ErrNotSupportedTxType}
+	}
+	var r *types.Receipt
+	if err := c.call(ctx, &r, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, cpchain.NotFound
+	}
+	return r, nil
+}
+
+// TermEvent is sent when the chain enters a new term.
+type TermEvent struct {
+	Term       uint64           // Index of the new term
+	Header     *types.Header    // First header seen in the term
+	Proposers  []common.Address // Proposers of the term
+	Validators []common.Address // Validators of the term
+}
+
+// SubscribeNewTerm subscribes to notifications about the chain entering a new
+// term. The first notification is sent for the term of the first new head.
+func (c *Client) SubscribeNewTerm(ctx context.Context, ch chan<- *TermEvent) (cpchain.Subscription, error) {
+	cfg, err := c.ChainConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Dpor == nil || cfg.Dpor.TermLen == 0 || cfg.Dpor.ViewLen == 0 {
+		return nil, errors.New("chain is not running dpor")
+	}
+	blocksPerTerm := cfg.Dpor.TermLen * cfg.Dpor.ViewLen
+
+	var last *uint64
+	return c.subscribeHeads(ctx, func(header *types.Header, quit <-chan struct{}) bool {
+		number := header.Number.Uint64()
+		if number == 0 {
+			return true
+		}
+		term := (number - 1) / blocksPerTerm
+		if last != nil && *last == term {
+			return true
+		}
+		last = &term
+		event := &TermEvent{
+			Term:       term,
+			Header:     header,
+			Proposers:  header.Dpor.Proposers,
+			Validators: header.Dpor.Validators,
+		}
+		select {
+		case ch <- event:
+			return true
+		case <-quit:
+			return false
+		}
+	})
+}
+
+// SubscribeImpeachBlocks subscribes to notifications about impeach blocks, the
+// empty blocks validators produce for a faulty proposer.
+func (c *Client) SubscribeImpeachBlocks(ctx context.Context, ch chan<- *types.Header) (cpchain.Subscription, error) {
+	return c.subscribeHeads(ctx, func(header *types.Header, quit <-chan struct{}) bool {
+		if !header.Impeachment() {
+			return true
+		}
+		select {
+		case ch <- header:
+			return true
+		case <-quit:
+			return false
+		}
+	})
+}
+
+// subscribeHeads subscribes to new heads and feeds them to handle until the
+// subscription ends or handle returns false.
+func (c *Client) subscribeHeads(ctx context.Context, handle func(header *types.Header, quit <-chan struct{}) bool) (cpchain.Subscription, error) {
+	heads := make(chan *types.Header, 16)
+	sub, err := c.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, wrapCallError("eth_subscribe", err)
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case header := <-heads:
+				if !handle(header, quit) {
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/consensus/dpor/finality"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DporTestService mimics the dpor namespace of a node at block 10.
+type DporTestService struct{}
+
+func (s *DporTestService) GetSnapshot(number rpc.BlockNumber) (map[string]interface{}, error) {
+	if number > 10 {
+		return nil, errors.New("unknown block")
+	}
+	return map[string]interface{}{
+		"mode":       0,
+		"number":     10,
+		"hash":       common.HexToHash("0x0a"),
+		"candidates": []common.Address{common.HexToAddress("0x01")},
+		"proposers":  map[uint64][]common.Address{0: {common.HexToAddress("0x02")}},
+		"validators": map[uint64][]common.Address{0: {common.HexToAddress("0x03")}},
+	}, nil
+}
+
+func (s *DporTestService) GetFinalityProof(number rpc.BlockNumber, checkpoint *rpc.BlockNumber) (*finality.Proof, error) {
+	if number > 10 {
+		return nil, errors.New("unknown block")
+	}
+	header := &types.Header{Number: big.NewInt(10), Time: big.NewInt(0)}
+	return &finality.Proof{Header: header, Validators: []common.Address{common.HexToAddress("0x03")}, Checkpoint: uint64(*checkpoint)}, nil
+}
+
+// AdmissionTestService mimics the admission namespace of a campaigning node.
+type AdmissionTestService struct{}
+
+func (s *AdmissionTestService) GetStatus() (uint32, error) { return 2, nil }
+
+func (s *AdmissionTestService) GetResult() map[string]cpclient.AdmissionResult {
+	return map[string]cpclient.AdmissionResult{"cpu": {BlockNumber: 9, Nonce: 42, Success: true}}
+}
+
+func newConsensusTestClient(t *testing.T) *cpclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("dpor", new(DporTestService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	if err := server.RegisterName("admission", new(AdmissionTestService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	return cpclient.NewClient(rpc.DialInProc(server))
+}
+
+func TestSnapshotAt(t *testing.T) {
+	client := newConsensusTestClient(t)
+
+	snap, err := client.SnapshotAt(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve snapshot: %v", err)
+	}
+	if snap.Number != 10 || len(snap.Candidates) != 1 || snap.RecentValidators[0][0] != common.HexToAddress("0x03") {
+		t.Fatalf("snapshot mismatch: %+v", snap)
+	}
+	_, err = client.SnapshotAt(context.Background(), big.NewInt(11))
+	if callErr, ok := err.(*cpclient.CallError); !ok || callErr.Err != cpclient.ErrUnknownBlock || callErr.Method != "dpor_getSnapshot" {
+		t.Fatalf("unknown block error mismatch: have %v", err)
+	}
+	_, err = client.SnapshotAtHash(context.Background(), common.Hash{})
+	if callErr, ok := err.(*cpclient.CallError); !ok || callErr.Err != cpclient.ErrMethodNotAvailable {
+		t.Fatalf("missing method error mismatch: have %v", err)
+	}
+}
+
+func TestFinalityProof(t *testing.T) {
+	client := newConsensusTestClient(t)
+
+	proof, err := client.FinalityProof(context.Background(), big.NewInt(10), 4)
+	if err != nil {
+		t.Fatalf("failed to retrieve finality proof: %v", err)
+	}
+	if proof.Header.Number.Uint64() != 10 || proof.Checkpoint != 4 || len(proof.Validators) != 1 {
+		t.Fatalf("finality proof mismatch: %+v", proof)
+	}
+	_, err = client.FinalityProof(context.Background(), big.NewInt(11), 0)
+	if callErr, ok := err.(*cpclient.CallError); !ok || callErr.Err != cpclient.ErrUnknownBlock {
+		t.Fatalf("unknown block error mismatch: have %v", err)
+	}
+}
+
+func TestAdmissionHelpers(t *testing.T) {
+	client := newConsensusTestClient(t)
+
+	status, err := client.AdmissionStatus(context.Background())
+	if err != nil || status != cpclient.AdmissionRunning {
+		t.Fatalf("admission status mismatch: have %v (%v), want %v", status, err, cpclient.AdmissionRunning)
+	}
+	results, err := client.AdmissionResults(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve admission results: %v", err)
+	}
+	if result := results["cpu"]; !result.Success || result.Nonce != 42 {
+		t.Fatalf("admission result mismatch: %+v", result)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/api/proof"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
@@ -95,6 +96,11 @@ func (c *Client) getBlock(ctx context.Context, method string, args ...interface{
 	} else if len(raw) == 0 {
 		return nil, cpchain.NotFound
 	}
+	return decodeBlock(raw)
+}
+
+// decodeBlock decodes the RPC representation of a block with full transactions.
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
 	// Decode header and transactions.
 	var head *types.Header
 	var body rpcBlock
@@ -274,6 +280,17 @@ func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*t
 	return r, err
 }
 
+// ReceiptProof returns the receipt of a transaction along its merkle proof.
+// Proofs are checked against the receipts root of the block with proof.VerifyReceipt.
+func (c *Client) ReceiptProof(ctx context.Context, txHash common.Hash) (*proof.ReceiptProof, error) {
+	var result *proof.ReceiptProof
+	err := c.c.CallContext(ctx, &result, "eth_getReceiptProof", txHash)
+	if err == nil && result == nil {
+		return nil, cpchain.NotFound
+	}
+	return result, err
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -445,6 +462,18 @@ func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber
 	return result, err
 }
 
+// ProofAt returns the merkle proof of the given account and storage slots.
+// The block number can be nil, in which case the proof is taken from the latest known block.
+// Proofs are checked against the state root of the block with proof.VerifyAccount.
+func (c *Client) ProofAt(ctx context.Context, account common.Address, keys []common.Hash, blockNumber *big.Int) (*proof.AccountResult, error) {
+	var result *proof.AccountResult
+	err := c.c.CallContext(ctx, &result, "eth_getProof", account, keys, toBlockNumArg(blockNumber))
+	if err == nil && result == nil {
+		return nil, cpchain.NotFound
+	}
+	return result, err
+}
+
 // NonceAt returns the account nonce of the given account.
 // The block number can be nil, in which case the nonce is taken from the latest known block.
 func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
@@ -462,6 +491,18 @@ func (c *Client) FilterLogs(ctx context.Context, q cpchain.FilterQuery) ([]types
 	return result, err
 }
 
+// FilterLogsPage executes a filter query returning up to limit logs, resuming
+// from the token of the previous page if not empty. The returned token is empty
+// on the last page.
+func (c *Client) FilterLogsPage(ctx context.Context, q cpchain.FilterQuery, limit uint, token string) ([]types.Log, string, error) {
+	var result struct {
+		Logs []types.Log `json:"logs"`
+		Next string      `json:"next"`
+	}
+	err := c.c.CallContext(ctx, &result, "eth_getLogsPage", toFilterArg(q), hexutil.Uint(limit), token)
+	return result.Logs, result.Next, err
+}
+
 // SubscribeFilterLogs subscribes to the results of a streaming filter query.
 func (c *Client) SubscribeFilterLogs(ctx context.Context, q cpchain.FilterQuery, ch chan<- types.Log) (cpchain.Subscription, error) {
 	return c.c.EthSubscribe(ctx, ch, "logs", toFilterArg(q))
@@ -537,6 +578,30 @@ func (c *Client) CallContract(ctx context.Context, msg cpchain.CallMsg, blockNum
 	return hex, nil
 }
 
+// BatchCallContract executes several message calls in a single batch request, on
+// the state of the given block. It returns the output or the error of each call.
+func (c *Client) BatchCallContract(ctx context.Context, msgs []cpchain.CallMsg, blockNumber *big.Int) ([][]byte, []error, error) {
+	var (
+		hexes = make([]hexutil.Bytes, len(msgs))
+		batch = make([]rpc.BatchElem, len(msgs))
+	)
+	for i, msg := range msgs {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), toBlockNumArg(blockNumber)},
+			Result: &hexes[i],
+		}
+	}
+	if err := c.c.BatchCallContext(ctx, batch); err != nil {
+		return nil, nil, err
+	}
+	outputs, errs := make([][]byte, len(msgs)), make([]error, len(msgs))
+	for i := range batch {
+		outputs[i], errs[i] = hexes[i], batch[i].Error
+	}
+	return outputs, errs, nil
+}
+
 // PendingCallContract executes a message call transaction using the EVM.
 // The state seen by the contract call is the pending state.
 func (c *Client) PendingCallContract(ctx context.Context, msg cpchain.CallMsg) ([]byte, error) {
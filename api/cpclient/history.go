@@ -0,0 +1,79 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AddressTransaction is a transaction involving an address, as recorded in the
+// address index of a node.
+type AddressTransaction struct {
+	BlockNumber      uint64
+	BlockHash        common.Hash
+	TransactionIndex uint64
+	Hash             common.Hash
+	Direction        string // "sent", "received", "self" or "created"
+}
+
+// AddressTransactions is a page of the transaction history of an address.
+type AddressTransactions struct {
+	Transactions []*AddressTransaction
+	Page         uint
+	HasMore      bool // Whether later pages hold more transactions
+}
+
+type rpcAddressTransactions struct {
+	Transactions []struct {
+		BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+		BlockHash        common.Hash    `json:"blockHash"`
+		TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+		Hash             common.Hash    `json:"hash"`
+		Direction        string         `json:"direction"`
+	} `json:"transactions"`
+	Page    hexutil.Uint `json:"page"`
+	HasMore bool         `json:"hasMore"`
+}
+
+// TransactionsByAddress returns a page of the canonical transactions involving
+// addr between the blocks from and to inclusive, oldest first. A nil from
+// starts at the genesis block and a nil to ends at the latest block. The node
+// must maintain the address index.
+func (c *Client) TransactionsByAddress(ctx context.Context, addr common.Address, from, to *big.Int, page uint) (*AddressTransactions, error) {
+	if from == nil {
+		from = new(big.Int)
+	}
+	var raw rpcAddressTransactions
+	if err := c.call(ctx, &raw, "eth_getTransactionsByAddress", addr, toBlockNumArg(from), toBlockNumArg(to), hexutil.Uint(page)); err != nil {
+		return nil, err
+	}
+	result := &AddressTransactions{Page: uint(raw.Page), HasMore: raw.HasMore}
+	for _, tx := range raw.Transactions {
+		result.Transactions = append(result.Transactions, &AddressTransaction{
+			BlockNumber:      uint64(tx.BlockNumber),
+			BlockHash:        tx.BlockHash,
+			TransactionIndex: uint64(tx.TransactionIndex),
+			Hash:             tx.Hash,
+			Direction:        tx.Direction,
+		})
+	}
+	return result, nil
+}
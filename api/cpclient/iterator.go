@@ -0,0 +1,284 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	cpchain "bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errReorgTooDeep is returned by the block iterator if the chain reorganised
+// beyond the blocks it remembers.
+var errReorgTooDeep = errors.New("chain reorganisation deeper than iterator history")
+
+// BlockEvent is a block yielded by a BlockIterator, together with its
+// receipts. Removed is set if a previously yielded block left the canonical
+// chain; removed blocks are yielded newest first.
+type BlockEvent struct {
+	Block    *types.Block
+	Receipts types.Receipts
+	Removed  bool
+}
+
+// Checkpoint identifies the last block a consumer has processed. An iterator
+// resumed from a checkpoint continues with the next block and detects a
+// reorganisation of the checkpoint block itself.
+type Checkpoint struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// IteratorOptions configures a BlockIterator.
+type IteratorOptions struct {
+	Range         RangeOptions  // Options of the underlying range requests
+	BatchBlocks   uint64        // Maximum number of blocks fetched at once
+	Confirmations uint64        // Number of blocks to stay behind the head
+	History       int           // Number of yielded blocks remembered for reorg handling
+	PollInterval  time.Duration // Waiting time before polling again at the head
+}
+
+// DefaultIteratorOptions contains the default settings of a BlockIterator.
+var DefaultIteratorOptions = IteratorOptions{
+	Range:        DefaultRangeOptions,
+	BatchBlocks:  1024,
+	History:      128,
+	PollInterval: 2 * time.Second,
+}
+
+// yieldedBlock is a canonical block the iterator has handed out.
+type yieldedBlock struct {
+	number uint64
+	hash   common.Hash
+	event  *BlockEvent // Nil for a block only known from a checkpoint
+}
+
+// BlockIterator yields canonical blocks with their receipts in order. It
+// follows the head of the chain and reports blocks that were reorganised out
+// after being yielded.
+type BlockIterator struct {
+	client *Client
+	opts   IteratorOptions
+
+	next    uint64          // Number of the next block to fetch
+	history []*yieldedBlock // Recently queued canonical blocks, oldest first
+	queue   []*BlockEvent   // Fetched events waiting to be yielded
+
+	current    *BlockEvent
+	checkpoint Checkpoint
+	err        error
+}
+
+// NewBlockIterator creates an iterator starting at block from.
+func (c *Client) NewBlockIterator(from uint64, opts *IteratorOptions) *BlockIterator {
+	it := &BlockIterator{
+		client: c,
+		opts:   opts.sanitize(),
+		next:   from,
+	}
+	if from > 0 {
+		it.checkpoint = Checkpoint{Number: from - 1}
+	}
+	return it
+}
+
+// ResumeBlockIterator creates an iterator continuing after the checkpoint.
+func (c *Client) ResumeBlockIterator(cp Checkpoint, opts *IteratorOptions) *BlockIterator {
+	it := c.NewBlockIterator(cp.Number+1, opts)
+	it.checkpoint = cp
+	if cp.Hash != (common.Hash{}) {
+		it.history = append(it.history, &yieldedBlock{number: cp.Number, hash: cp.Hash})
+	}
+	return it
+}
+
+// sanitize fills the unset fields of the options with the default values.
+func (opts *IteratorOptions) sanitize() IteratorOptions {
+	if opts == nil {
+		return DefaultIteratorOptions
+	}
+	conf := *opts
+	conf.Range = conf.Range.sanitize()
+	if conf.BatchBlocks == 0 {
+		conf.BatchBlocks = DefaultIteratorOptions.BatchBlocks
+	}
+	if conf.History <= 0 {
+		conf.History = DefaultIteratorOptions.History
+	}
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = DefaultIteratorOptions.PollInterval
+	}
+	return conf
+}
+
+// Next advances the iterator to the next event, waiting for new blocks at the
+// head of the chain. It returns false if ctx is done or an error occurred.
+func (it *BlockIterator) Next(ctx context.Context) bool {
+	for len(it.queue) == 0 {
+		if it.err != nil {
+			return false
+		}
+		if err := it.fill(ctx); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.queue) > 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			it.err = ctx.Err()
+			return false
+		case <-time.After(it.opts.PollInterval):
+		}
+	}
+	it.current, it.queue = it.queue[0], it.queue[1:]
+
+	header := it.current.Block.Header()
+	if it.current.Removed {
+		it.checkpoint = Checkpoint{Number: header.Number.Uint64() - 1, Hash: header.ParentHash}
+	} else {
+		it.checkpoint = Checkpoint{Number: header.Number.Uint64(), Hash: it.current.Block.Hash()}
+	}
+	return true
+}
+
+// Event returns the current event.
+func (it *BlockIterator) Event() *BlockEvent {
+	return it.current
+}
+
+// Checkpoint returns the position after the current event, which can be
+// persisted to resume the iteration later.
+func (it *BlockIterator) Checkpoint() Checkpoint {
+	return it.checkpoint
+}
+
+// Err returns the error that stopped the iterator, if any.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
+
+// fill fetches the next blocks up to the confirmed head, queueing removal
+// events first if the chain was reorganised.
+func (it *BlockIterator) fill(ctx context.Context) error {
+	head, err := it.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	number := head.Number.Uint64()
+	if number < it.opts.Confirmations {
+		return nil
+	}
+	last := number - it.opts.Confirmations
+	if it.next > last {
+		// Nothing new, but make sure the remembered tip is still canonical
+		if len(it.history) > 0 {
+			tip := it.history[len(it.history)-1]
+			header, err := it.client.HeaderByNumber(ctx, new(big.Int).SetUint64(tip.number))
+			if err != nil && err != cpchain.NotFound {
+				return err
+			}
+			if header == nil || header.Hash() != tip.hash {
+				return it.rewind(ctx)
+			}
+		}
+		return nil
+	}
+	if last-it.next >= it.opts.BatchBlocks {
+		last = it.next + it.opts.BatchBlocks - 1
+	}
+	blocks, err := it.client.BlocksByRange(ctx, it.next, last, &it.opts.Range)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	receipts, err := it.client.ReceiptsByRange(ctx, it.next, it.next+uint64(len(blocks))-1, &it.opts.Range)
+	if err != nil {
+		return err
+	}
+	// Check the new blocks extend the remembered chain
+	if len(it.history) > 0 && blocks[0].ParentHash() != it.history[len(it.history)-1].hash {
+		return it.rewind(ctx)
+	}
+	for i, block := range blocks {
+		if i > 0 && block.ParentHash() != blocks[i-1].Hash() {
+			break // reorganised while fetching, the rest is picked up next time
+		}
+		if i >= len(receipts) || len(receipts[i]) != len(block.Transactions()) {
+			break // receipts raced with a reorganisation, retry later
+		}
+		if len(receipts[i]) > 0 && receipts[i][0].TxHash != block.Transactions()[0].Hash() {
+			break
+		}
+		event := &BlockEvent{Block: block, Receipts: receipts[i]}
+		it.queue = append(it.queue, event)
+		it.remember(&yieldedBlock{number: block.NumberU64(), hash: block.Hash(), event: event})
+		it.next++
+	}
+	return nil
+}
+
+// remember appends a queued block to the history, dropping the oldest entries
+// beyond the configured depth.
+func (it *BlockIterator) remember(block *yieldedBlock) {
+	it.history = append(it.history, block)
+	if over := len(it.history) - it.opts.History; over > 0 {
+		it.history = append(it.history[:0], it.history[over:]...)
+	}
+}
+
+// rewind finds the newest remembered block that is still canonical, queues
+// removal events for the blocks above it and restarts fetching after it. If
+// the history runs out, the reorganised chain is followed through its parent
+// hashes, up to the configured history depth.
+func (it *BlockIterator) rewind(ctx context.Context) error {
+	for depth := 0; len(it.history) > 0 && depth < it.opts.History; depth++ {
+		tip := it.history[len(it.history)-1]
+		header, err := it.client.HeaderByNumber(ctx, new(big.Int).SetUint64(tip.number))
+		if err != nil && err != cpchain.NotFound {
+			return err
+		}
+		if header != nil && header.Hash() == tip.hash {
+			it.next = tip.number + 1
+			return nil
+		}
+		event := tip.event
+		if event == nil {
+			block, err := it.client.BlockByHash(ctx, tip.hash)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve reorganised block %x: %v", tip.hash, err)
+			}
+			event = &BlockEvent{Block: block}
+		}
+		it.queue = append(it.queue, &BlockEvent{Block: event.Block, Receipts: event.Receipts, Removed: true})
+		it.history = it.history[:len(it.history)-1]
+
+		if len(it.history) == 0 && tip.number > 0 {
+			it.history = append(it.history, &yieldedBlock{number: tip.number - 1, hash: event.Block.ParentHash()})
+		}
+	}
+	return errReorgTooDeep
+}
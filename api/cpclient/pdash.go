@@ -0,0 +1,135 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PdashOrder is an order of the pdash contract, as indexed by a node.
+type PdashOrder struct {
+	ID             uint64
+	DescHash       common.Hash
+	BuyerRSAPubkey []byte
+	Buyer          common.Address
+	Seller         common.Address
+	Proxy          common.Address
+	SecondaryProxy common.Address
+	OfferedPrice   *big.Int
+	ProxyFee       *big.Int
+	DeliverHash    common.Hash
+	EndTime        uint64
+	Status         string // "created", "sellerConfirmed", ..., "disputed" or "withdrawn"
+	DisputeID      uint64
+}
+
+// PdashOrderFilter selects the orders matching all of its set criteria.
+type PdashOrderFilter struct {
+	Buyer  *common.Address
+	Seller *common.Address
+	Proxy  *common.Address // Either the proxy or the secondary proxy
+	Status string          // Any status if empty
+	Page   uint
+}
+
+// PdashOrders is a page of the orders matching a filter.
+type PdashOrders struct {
+	Orders      []*PdashOrder
+	Page        uint
+	HasMore     bool   // Whether later pages hold more orders
+	BlockNumber uint64 // Block the orders are indexed at
+}
+
+type rpcPdashOrder struct {
+	ID             hexutil.Uint64 `json:"id"`
+	DescHash       common.Hash    `json:"descHash"`
+	BuyerRSAPubkey hexutil.Bytes  `json:"buyerRSAPubkey"`
+	Buyer          common.Address `json:"buyer"`
+	Seller         common.Address `json:"seller"`
+	Proxy          common.Address `json:"proxy"`
+	SecondaryProxy common.Address `json:"secondaryProxy"`
+	OfferedPrice   *hexutil.Big   `json:"offeredPrice"`
+	ProxyFee       *hexutil.Big   `json:"proxyFee"`
+	DeliverHash    common.Hash    `json:"deliverHash"`
+	EndTime        hexutil.Uint64 `json:"endTime"`
+	Status         string         `json:"status"`
+	DisputeID      hexutil.Uint64 `json:"disputeId"`
+}
+
+func (o *rpcPdashOrder) toOrder() *PdashOrder {
+	return &PdashOrder{
+		ID:             uint64(o.ID),
+		DescHash:       o.DescHash,
+		BuyerRSAPubkey: o.BuyerRSAPubkey,
+		Buyer:          o.Buyer,
+		Seller:         o.Seller,
+		Proxy:          o.Proxy,
+		SecondaryProxy: o.SecondaryProxy,
+		OfferedPrice:   o.OfferedPrice.ToInt(),
+		ProxyFee:       o.ProxyFee.ToInt(),
+		DeliverHash:    o.DeliverHash,
+		EndTime:        uint64(o.EndTime),
+		Status:         o.Status,
+		DisputeID:      uint64(o.DisputeID),
+	}
+}
+
+// PdashOrders returns a page of the pdash orders matching filter, ordered by
+// id. The node must index the pdash contract.
+func (c *Client) PdashOrders(ctx context.Context, filter PdashOrderFilter) (*PdashOrders, error) {
+	arg := map[string]interface{}{"page": hexutil.Uint(filter.Page)}
+	if filter.Buyer != nil {
+		arg["buyer"] = filter.Buyer
+	}
+	if filter.Seller != nil {
+		arg["seller"] = filter.Seller
+	}
+	if filter.Proxy != nil {
+		arg["proxy"] = filter.Proxy
+	}
+	if filter.Status != "" {
+		arg["status"] = filter.Status
+	}
+	var raw struct {
+		Orders      []*rpcPdashOrder `json:"orders"`
+		Page        hexutil.Uint     `json:"page"`
+		HasMore     bool             `json:"hasMore"`
+		BlockNumber hexutil.Uint64   `json:"blockNumber"`
+	}
+	if err := c.call(ctx, &raw, "pdash_getOrders", arg); err != nil {
+		return nil, err
+	}
+	result := &PdashOrders{Page: uint(raw.Page), HasMore: raw.HasMore, BlockNumber: uint64(raw.BlockNumber)}
+	for _, order := range raw.Orders {
+		result.Orders = append(result.Orders, order.toOrder())
+	}
+	return result, nil
+}
+
+// PdashOrder returns the pdash order with the given id. The node must index
+// the pdash contract.
+func (c *Client) PdashOrder(ctx context.Context, id uint64) (*PdashOrder, error) {
+	var raw rpcPdashOrder
+	if err := c.call(ctx, &raw, "pdash_getOrder", hexutil.Uint64(id)); err != nil {
+		return nil, err
+	}
+	return raw.toOrder(), nil
+}
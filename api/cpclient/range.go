@@ -0,0 +1,171 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// errInvalidRange is returned if a range ends before it starts.
+var errInvalidRange = errors.New("invalid block range")
+
+// RangeOptions controls how range requests are split and issued.
+type RangeOptions struct {
+	Span        uint64 // Number of blocks requested per call, must not exceed the server's limit
+	BatchSize   int    // Number of calls sent in one batch request
+	Concurrency int    // Number of batch requests in flight at the same time
+}
+
+// DefaultRangeOptions matches the limits of the server side range APIs.
+var DefaultRangeOptions = RangeOptions{
+	Span:        128,
+	BatchSize:   4,
+	Concurrency: 4,
+}
+
+// sanitize fills the unset fields of the options with the default values.
+func (opts *RangeOptions) sanitize() RangeOptions {
+	if opts == nil {
+		return DefaultRangeOptions
+	}
+	conf := *opts
+	if conf.Span == 0 {
+		conf.Span = DefaultRangeOptions.Span
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = DefaultRangeOptions.BatchSize
+	}
+	if conf.Concurrency <= 0 {
+		conf.Concurrency = DefaultRangeOptions.Concurrency
+	}
+	return conf
+}
+
+// BlocksByRange returns the canonical blocks from from to to inclusive, with
+// full transactions. The result stops at the server's head, so it may be
+// shorter than requested.
+func (c *Client) BlocksByRange(ctx context.Context, from, to uint64, opts *RangeOptions) ([]*types.Block, error) {
+	raws, err := c.rangeCall(ctx, "eth_getBlocksByRange", from, to, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]*types.Block, len(raws))
+	for i, raw := range raws {
+		if blocks[i], err = decodeBlock(raw); err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// ReceiptsByRange returns the receipts of the canonical blocks from from to to
+// inclusive, one list per block. The result stops at the server's head, so it
+// may be shorter than requested.
+func (c *Client) ReceiptsByRange(ctx context.Context, from, to uint64, opts *RangeOptions) ([]types.Receipts, error) {
+	raws, err := c.rangeCall(ctx, "eth_getReceiptsByRange", from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	receipts := make([]types.Receipts, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &receipts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// rangeCall splits [from, to] into spans, requests them with batches of method
+// calls and returns the per-block results in order. Results following a short
+// span are dropped, so that the returned blocks are always contiguous.
+func (c *Client) rangeCall(ctx context.Context, method string, from, to uint64, opts *RangeOptions, extra ...interface{}) ([]json.RawMessage, error) {
+	if to < from {
+		return nil, errInvalidRange
+	}
+	conf := opts.sanitize()
+
+	// Split the range into spans and the spans into batches
+	var batches [][]rpc.BatchElem
+	for start := from; start <= to; start += conf.Span {
+		end := start + conf.Span - 1
+		if end > to || end < start {
+			end = to
+		}
+		args := append([]interface{}{hexutil.Uint64(start), hexutil.Uint64(end)}, extra...)
+		elem := rpc.BatchElem{Method: method, Args: args, Result: new([]json.RawMessage)}
+
+		if n := len(batches); n == 0 || len(batches[n-1]) == conf.BatchSize {
+			batches = append(batches, make([]rpc.BatchElem, 0, conf.BatchSize))
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], elem)
+
+		if end == to {
+			break
+		}
+	}
+	// Issue the batches with limited concurrency
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		fail  error
+		slots = make(chan struct{}, conf.Concurrency)
+	)
+	for _, batch := range batches {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(batch []rpc.BatchElem) {
+			defer func() { <-slots; wg.Done() }()
+
+			err := c.c.BatchCallContext(ctx, batch)
+			for i := 0; err == nil && i < len(batch); i++ {
+				err = batch[i].Error
+			}
+			if err != nil {
+				mu.Lock()
+				if fail == nil {
+					fail = err
+				}
+				mu.Unlock()
+			}
+		}(batch)
+	}
+	wg.Wait()
+	if fail != nil {
+		return nil, fail
+	}
+	// Flatten the results, stopping at the first incomplete span
+	var results []json.RawMessage
+	for _, batch := range batches {
+		for _, elem := range batch {
+			span := *elem.Result.(*[]json.RawMessage)
+			results = append(results, span...)
+
+			start, end := uint64(elem.Args[0].(hexutil.Uint64)), uint64(elem.Args[1].(hexutil.Uint64))
+			if uint64(len(span)) < end-start+1 {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RangeTestService serves a fake canonical chain of empty blocks.
+type RangeTestService struct {
+	mu      sync.Mutex
+	headers []*types.Header
+	known   map[common.Hash]*types.Header // All blocks ever created, including side forks
+}
+
+// extend appends n blocks to the chain, marking them with the given extra data
+// so that forks get different hashes.
+func (s *RangeTestService) extend(n int, mark byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			Number:   big.NewInt(int64(len(s.headers))),
+			Time:     big.NewInt(0),
+			TxsRoot:  types.EmptyRootHash,
+			Extra:    []byte{mark},
+			GasLimit: 1,
+		}
+		if len(s.headers) > 0 {
+			header.ParentHash = s.headers[len(s.headers)-1].Hash()
+		}
+		s.headers = append(s.headers, header)
+
+		if s.known == nil {
+			s.known = make(map[common.Hash]*types.Header)
+		}
+		s.known[header.Hash()] = header
+	}
+}
+
+// rewind drops the blocks above number.
+func (s *RangeTestService) rewind(number int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = s.headers[:number+1]
+}
+
+func marshalTestBlock(header *types.Header) map[string]interface{} {
+	blob, _ := json.Marshal(header)
+	fields := make(map[string]interface{})
+	json.Unmarshal(blob, &fields)
+	fields["transactions"] = []interface{}{}
+	return fields
+}
+
+func (s *RangeTestService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if number == rpc.LatestBlockNumber {
+		number = rpc.BlockNumber(len(s.headers) - 1)
+	}
+	if int(number) >= len(s.headers) {
+		return nil
+	}
+	return marshalTestBlock(s.headers[number])
+}
+
+func (s *RangeTestService) GetBlockByHash(hash common.Hash, fullTx bool) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if header, ok := s.known[hash]; ok {
+		return marshalTestBlock(header)
+	}
+	return nil
+}
+
+func (s *RangeTestService) GetBlocksByRange(from, to rpc.BlockNumber, fullTx bool) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blocks := []map[string]interface{}{}
+	for n := int(from); n <= int(to) && n < len(s.headers); n++ {
+		blocks = append(blocks, marshalTestBlock(s.headers[n]))
+	}
+	return blocks
+}
+
+func (s *RangeTestService) GetReceiptsByRange(from, to rpc.BlockNumber) [][]map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	receipts := [][]map[string]interface{}{}
+	for n := int(from); n <= int(to) && n < len(s.headers); n++ {
+		receipts = append(receipts, []map[string]interface{}{})
+	}
+	return receipts
+}
+
+func newRangeTestClient(t *testing.T, service *RangeTestService) *cpclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	return cpclient.NewClient(rpc.DialInProc(server))
+}
+
+func TestBlocksByRange(t *testing.T) {
+	service := new(RangeTestService)
+	service.extend(100, 0)
+	client := newRangeTestClient(t, service)
+
+	opts := &cpclient.RangeOptions{Span: 7, BatchSize: 3, Concurrency: 2}
+	blocks, err := client.BlocksByRange(context.Background(), 5, 120, opts)
+	if err != nil {
+		t.Fatalf("failed to retrieve blocks: %v", err)
+	}
+	if len(blocks) != 95 {
+		t.Fatalf("block count mismatch: have %d, want %d", len(blocks), 95)
+	}
+	for i, block := range blocks {
+		if block.NumberU64() != uint64(5+i) {
+			t.Fatalf("block %d: number mismatch: have %d, want %d", i, block.NumberU64(), 5+i)
+		}
+	}
+	receipts, err := client.ReceiptsByRange(context.Background(), 0, 9, opts)
+	if err != nil {
+		t.Fatalf("failed to retrieve receipts: %v", err)
+	}
+	if len(receipts) != 10 {
+		t.Fatalf("receipt list count mismatch: have %d, want %d", len(receipts), 10)
+	}
+	if _, err := client.BlocksByRange(context.Background(), 10, 9, opts); err == nil {
+		t.Fatalf("inverted range accepted")
+	}
+}
+
+func TestBlockIteratorReorg(t *testing.T) {
+	service := new(RangeTestService)
+	service.extend(20, 0)
+	client := newRangeTestClient(t, service)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := &cpclient.IteratorOptions{BatchBlocks: 8, PollInterval: 10 * time.Millisecond}
+	it := client.NewBlockIterator(0, opts)
+	for i := 0; i < 20; i++ {
+		if !it.Next(ctx) {
+			t.Fatalf("iterator stopped: %v", it.Err())
+		}
+		if ev := it.Event(); ev.Removed || ev.Block.NumberU64() != uint64(i) {
+			t.Fatalf("event %d mismatch: number %d, removed %v", i, ev.Block.NumberU64(), ev.Removed)
+		}
+	}
+	old := it.Checkpoint()
+
+	// Replace the last three blocks with a longer fork
+	service.rewind(16)
+	service.extend(5, 1)
+
+	var removed, added []uint64
+	for len(added) < 5 {
+		if !it.Next(ctx) {
+			t.Fatalf("iterator stopped: %v", it.Err())
+		}
+		if ev := it.Event(); ev.Removed {
+			removed = append(removed, ev.Block.NumberU64())
+		} else {
+			added = append(added, ev.Block.NumberU64())
+		}
+	}
+	if want := []uint64{19, 18, 17}; !equalNumbers(removed, want) {
+		t.Fatalf("removed blocks mismatch: have %v, want %v", removed, want)
+	}
+	if want := []uint64{17, 18, 19, 20, 21}; !equalNumbers(added, want) {
+		t.Fatalf("added blocks mismatch: have %v, want %v", added, want)
+	}
+	if cp := it.Checkpoint(); cp.Number != 21 || cp.Hash == old.Hash {
+		t.Fatalf("checkpoint mismatch: have %+v", cp)
+	}
+
+	// A resumed iterator detects the reorganisation of its checkpoint
+	resumed := client.ResumeBlockIterator(cpclient.Checkpoint{Number: 19, Hash: old.Hash}, opts)
+	if !resumed.Next(ctx) {
+		t.Fatalf("resumed iterator stopped: %v", resumed.Err())
+	}
+	if resumed.Err() != nil || !resumed.Event().Removed {
+		t.Fatalf("resumed iterator missed the reorganisation")
+	}
+}
+
+func equalNumbers(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RangeTestService) GetTransactionsByAddress(addr common.Address, from, to rpc.BlockNumber, page hexutil.Uint) map[string]interface{} {
+	return map[string]interface{}{
+		"transactions": []map[string]interface{}{{
+			"blockNumber":      hexutil.Uint64(from),
+			"blockHash":        common.Hash{0x01},
+			"transactionIndex": hexutil.Uint64(page),
+			"hash":             common.Hash{0x02},
+			"direction":        "sent",
+		}},
+		"page":    page,
+		"hasMore": to == rpc.LatestBlockNumber,
+	}
+}
+
+func TestTransactionsByAddress(t *testing.T) {
+	client := newRangeTestClient(t, new(RangeTestService))
+
+	history, err := client.TransactionsByAddress(context.Background(), common.Address{0x01}, nil, nil, 3)
+	if err != nil {
+		t.Fatalf("failed to retrieve address history: %v", err)
+	}
+	if !history.HasMore || history.Page != 3 || len(history.Transactions) != 1 {
+		t.Fatalf("history mismatch: %+v", history)
+	}
+	if tx := history.Transactions[0]; tx.BlockNumber != 0 || tx.TransactionIndex != 3 || tx.Direction != "sent" || tx.Hash != (common.Hash{0x02}) {
+		t.Fatalf("transaction mismatch: %+v", tx)
+	}
+}
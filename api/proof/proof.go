@@ -0,0 +1,191 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package proof defines the merkle proofs of accounts, storage slots and
+// receipts served by eth_getProof and eth_getReceiptProof, and verifies them
+// against the roots of a header.
+//
+// A verifier only needs a header it trusts, e.g. one proven final, to check
+// the balance of an account or the events of a transaction without trusting
+// the node the proof comes from.
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// ErrAccountMismatch is returned if the account fields of a proof are not
+	// the proven ones.
+	ErrAccountMismatch = errors.New("account not proven")
+
+	// ErrStorageMismatch is returned if a storage value is not the proven one.
+	ErrStorageMismatch = errors.New("storage value not proven")
+
+	// ErrReceiptMismatch is returned if a receipt is not the proven one.
+	ErrReceiptMismatch = errors.New("receipt not proven")
+
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+)
+
+// AccountResult is the proof of an account and of some of its storage slots.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the proof of a storage slot.
+type StorageResult struct {
+	Key   common.Hash     `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// ReceiptProof is the proof of the receipt of a transaction.
+type ReceiptProof struct {
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	ReceiptsRoot     common.Hash     `json:"receiptsRoot"`
+	Receipt          hexutil.Bytes   `json:"receipt"` // Consensus encoding of the receipt
+	Proof            []hexutil.Bytes `json:"proof"`
+}
+
+// account is the consensus encoding of an account in the state trie.
+type account struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// VerifyAccount checks the account and its storage slots against the state
+// root of a header. Accounts missing from the state are proven empty.
+func VerifyAccount(stateRoot common.Hash, result *AccountResult) error {
+	value, err := verify(stateRoot, crypto.Keccak256(result.Address[:]), result.AccountProof)
+	if err != nil {
+		return err
+	}
+	want := account{Balance: new(big.Int), Root: types.EmptyRootHash, CodeHash: emptyCodeHash[:]}
+	if len(value) > 0 {
+		if err := rlp.DecodeBytes(value, &want); err != nil {
+			return err
+		}
+	}
+	if result.Balance == nil || result.Balance.ToInt().Cmp(want.Balance) != 0 || uint64(result.Nonce) != want.Nonce ||
+		result.StorageHash != want.Root || !bytes.Equal(result.CodeHash[:], want.CodeHash) {
+		return ErrAccountMismatch
+	}
+	for i := range result.StorageProof {
+		if err := VerifyStorage(result.StorageHash, &result.StorageProof[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyStorage checks a storage slot against the storage root of its
+// account. Slots missing from the storage are proven zero.
+func VerifyStorage(storageHash common.Hash, result *StorageResult) error {
+	value, err := verify(storageHash, crypto.Keccak256(result.Key[:]), result.Proof)
+	if err != nil {
+		return err
+	}
+	want := new(big.Int)
+	if len(value) > 0 {
+		var content []byte
+		if err := rlp.DecodeBytes(value, &content); err != nil {
+			return err
+		}
+		want.SetBytes(content)
+	}
+	if result.Value == nil || result.Value.ToInt().Cmp(want) != 0 {
+		return ErrStorageMismatch
+	}
+	return nil
+}
+
+// VerifyReceipt checks the receipt against the receipts root of a header and
+// returns it decoded.
+func VerifyReceipt(receiptsRoot common.Hash, proof *ReceiptProof) (*types.Receipt, error) {
+	key, _ := rlp.EncodeToBytes(uint(proof.TransactionIndex))
+	value, err := verify(receiptsRoot, key, proof.Proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 || !bytes.Equal(value, proof.Receipt) {
+		return nil, ErrReceiptMismatch
+	}
+	receipt := new(types.Receipt)
+	if err := rlp.DecodeBytes(value, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// ProveReceipt proves the receipt with the given index against the receipts
+// root derived from the receipts of a block.
+func ProveReceipt(receipts types.Receipts, index uint64) ([]hexutil.Bytes, error) {
+	tr := new(trie.Trie)
+	for i := range receipts {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, receipts.GetRlp(i))
+	}
+	key, _ := rlp.EncodeToBytes(uint(index))
+	var proof proofList
+	if err := tr.Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// proofList collects the nodes of a merkle proof from the root down, as the
+// tries prove them.
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+// verify returns the value proven at the key in the trie with the given root,
+// or nil if the key is proven absent.
+func verify(root common.Hash, key []byte, proof []hexutil.Bytes) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, nil
+	}
+	db := database.NewMemDatabase()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	value, _, err := trie.VerifyProof(root, key, db)
+	return value, err
+}
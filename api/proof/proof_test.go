@@ -0,0 +1,157 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package proof
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// proveAccount assembles the proof of an account the way eth_getProof does.
+func proveAccount(t *testing.T, statedb *state.StateDB, addr common.Address, keys ...common.Hash) *AccountResult {
+	nodes, err := statedb.GetProof(addr)
+	if err != nil {
+		t.Fatalf("failed to prove account: %v", err)
+	}
+	result := &AccountResult{
+		Address:      addr,
+		AccountProof: toHex(nodes),
+		Balance:      (*hexutil.Big)(statedb.GetBalance(addr)),
+		CodeHash:     emptyCodeHash,
+		Nonce:        hexutil.Uint64(statedb.GetNonce(addr)),
+		StorageHash:  types.EmptyRootHash,
+	}
+	if tr := statedb.StorageTrie(addr); tr != nil {
+		result.CodeHash = statedb.GetCodeHash(addr)
+		result.StorageHash = tr.Hash()
+	}
+	for _, key := range keys {
+		nodes, err := statedb.GetStorageProof(addr, key)
+		if err != nil {
+			t.Fatalf("failed to prove storage: %v", err)
+		}
+		result.StorageProof = append(result.StorageProof, StorageResult{
+			Key:   key,
+			Value: (*hexutil.Big)(statedb.GetState(addr, key).Big()),
+			Proof: toHex(nodes),
+		})
+	}
+	return result
+}
+
+func toHex(nodes [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(nodes))
+	for i, node := range nodes {
+		result[i] = node
+	}
+	return result
+}
+
+func TestVerifyAccount(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+		contract   = common.Address{0x01}
+		holder     = common.Address{0x02}
+		missing    = common.Address{0x03}
+		slot       = common.Hash{0x01}
+	)
+	statedb.SetBalance(holder, big.NewInt(1000))
+	statedb.SetNonce(holder, 3)
+	statedb.SetCode(contract, []byte{0x60, 0x00})
+	statedb.SetState(contract, slot, common.BigToHash(big.NewInt(42)))
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	for _, result := range []*AccountResult{
+		proveAccount(t, statedb, holder),
+		proveAccount(t, statedb, contract, slot, common.Hash{0x02}),
+		proveAccount(t, statedb, missing),
+	} {
+		if err := VerifyAccount(root, result); err != nil {
+			t.Fatalf("valid proof of %x rejected: %v", result.Address, err)
+		}
+	}
+
+	// Tampered fields are rejected
+	result := proveAccount(t, statedb, holder)
+	result.Balance = (*hexutil.Big)(big.NewInt(1001))
+	if err := VerifyAccount(root, result); err != ErrAccountMismatch {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrAccountMismatch)
+	}
+	result = proveAccount(t, statedb, missing)
+	result.Nonce = 1
+	if err := VerifyAccount(root, result); err != ErrAccountMismatch {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrAccountMismatch)
+	}
+	result = proveAccount(t, statedb, contract, slot)
+	result.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(43))
+	if err := VerifyAccount(root, result); err != ErrStorageMismatch {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrStorageMismatch)
+	}
+	// Proofs of another account or against another root are rejected
+	result = proveAccount(t, statedb, holder)
+	result.Address = contract
+	if err := VerifyAccount(root, result); err == nil {
+		t.Errorf("proof of another account accepted")
+	}
+	if err := VerifyAccount(common.Hash{0x01}, proveAccount(t, statedb, holder)); err == nil {
+		t.Errorf("proof against another root accepted")
+	}
+}
+
+func TestVerifyReceipt(t *testing.T) {
+	receipts := make(types.Receipts, 3)
+	for i := range receipts {
+		receipts[i] = types.NewReceipt(nil, i == 1, uint64(21000*(i+1)))
+		receipts[i].Logs = []*types.Log{{Address: common.Address{byte(i)}, Data: []byte{byte(i)}}}
+		receipts[i].Bloom = types.CreateBloom(types.Receipts{receipts[i]})
+	}
+	root := types.DeriveSha(receipts)
+
+	nodes, err := ProveReceipt(receipts, 1)
+	if err != nil {
+		t.Fatalf("failed to prove receipt: %v", err)
+	}
+	proof := &ReceiptProof{TransactionIndex: 1, ReceiptsRoot: root, Receipt: receipts.GetRlp(1), Proof: nodes}
+	receipt, err := VerifyReceipt(root, proof)
+	if err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed || receipt.CumulativeGasUsed != 42000 || len(receipt.Logs) != 1 || receipt.Logs[0].Data[0] != 1 {
+		t.Fatalf("receipt mismatch: %+v", receipt)
+	}
+
+	proof.Receipt = receipts.GetRlp(2)
+	if _, err := VerifyReceipt(root, proof); err != ErrReceiptMismatch {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrReceiptMismatch)
+	}
+	proof.Receipt, proof.TransactionIndex = receipts.GetRlp(1), 2
+	if _, err := VerifyReceipt(root, proof); err == nil {
+		t.Errorf("proof accepted for another index")
+	}
+	proof.TransactionIndex = 3
+	if _, err := VerifyReceipt(root, proof); err == nil {
+		t.Errorf("proof accepted past the receipts")
+	}
+}
@@ -19,7 +19,9 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -34,13 +36,13 @@ import (
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/contracts/dpor/primitive_register"
 	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
 	"bitbucket.org/cpchain/chain/core/state"
 	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/naoina/toml"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/urfave/cli"
 )
 
@@ -166,9 +168,85 @@ Use "cpchain chain dump 0" to dump the genesis block.`,
 
 			Description: "Example: ./cpchain chain delete dpor- --datadir ~/.cpchain",
 		},
+		{
+			Action: freezeChain,
+			Name:   "freeze",
+			Usage:  "Move old canonical blocks from the database to the ancient store",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+				flags.GetByName(flags.AncientDepthFlagName),
+			}, flags.LogFlags...),
+			Description: fmt.Sprintf(`The freeze command moves the headers, bodies and receipts of the canonical blocks
+deeper than --%v (default %d) to the append-only ancient store in
+datadir/cpchain/chaindata/ancient. The node must not be running.`, flags.AncientDepthFlagName, defaultAncientDepth),
+		},
+		{
+			Action: checkAncients,
+			Name:   "check-ancient",
+			Usage:  "Check the integrity of the ancient store",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+			}, flags.LogFlags...),
+			Description: `The check-ancient command verifies the hashes, parent links, transaction roots and
+receipt roots of all the blocks of the ancient store.`,
+		},
+		{
+			Action: verifyChain,
+			Name:   "verify",
+			Usage:  "Check the integrity of the chain data",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+				cli.Uint64Flag{
+					Name:  "from",
+					Usage: "First block to check",
+				},
+				cli.Uint64Flag{
+					Name:  "to",
+					Usage: "Last block to check (default = head block)",
+					Value: math.MaxUint64,
+				},
+				cli.Uint64Flag{
+					Name:  "state.interval",
+					Usage: "Check the state of the blocks with a number multiple of this (0 = last block only)",
+				},
+				cli.BoolTFlag{
+					Name:  "seals",
+					Usage: "Check the seals and validator signatures against the DPoR snapshots",
+				},
+				cli.BoolFlag{
+					Name:  "repair",
+					Usage: "Rewrite broken hash to number mappings and transaction lookup entries",
+				},
+			}, flags.LogFlags...),
+			Description: `The verify command walks the canonical chain and checks the header and body
+linkage, the receipt roots, the transaction lookup entries, the DPoR seals and
+signatures and the presence of the state at sampled heights. It then looks for
+hash to number mappings left without a header.
+
+Every problem is printed as a JSON object on its own line, followed by a summary
+object. The command fails if problems are left unrepaired. Nodes pruning the
+state only keep the state of recent blocks.`,
+		},
+		{
+			Action:    migrateDB,
+			Name:      "migrate-db",
+			Usage:     "Copy the chain database to another key-value store backend",
+			ArgsUsage: "<" + strings.Join(database.Backends, "|") + ">",
+			Flags: append([]cli.Flag{
+				flags.GetByName(flags.DataDirFlagName),
+			}, flags.LogFlags...),
+			Description: `The migrate-db command copies all the content of datadir/cpchain/chaindata into a
+database of the given backend, which then replaces it. The previous database is
+kept aside and can be removed once the node runs fine. The node must not be running,
+and must not be configured to use the previous backend explicitly.`,
+		},
 	},
 }
 
+// defaultAncientDepth is the number of recent blocks kept in the database by
+// the freeze command if no depth is given.
+const defaultAncientDepth = 90000
+
 // initChain creates a genesis block from a toml format file
 func initChain(ctx *cli.Context) error {
 	// Make sure we have a valid genesis TOML and run with suitable runmode.
@@ -281,19 +359,8 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*database.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		log.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
-
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		log.Fatalf("Failed to read database iostats: %v", err)
-	}
-	fmt.Println(ioStats)
+	db := rawdb.KeyValueStore(chainDb)
+	printDatabaseStats(db)
 
 	fmt.Printf("Trie cache misses:  %d\n", trie.CacheMisses())
 	fmt.Printf("Trie cache unloads: %d\n\n", trie.CacheUnloads())
@@ -312,26 +379,39 @@ func importChain(ctx *cli.Context) error {
 	}
 
 	// Compact the entire database to more accurately measure disk io and print the stats
+	compacter, ok := db.(database.Compacter)
+	if !ok {
+		return nil
+	}
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
+	if err := compacter.Compact(nil, nil); err != nil {
 		log.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 
-	stats, err = db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		log.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
+	printDatabaseStats(db)
+	return nil
+}
 
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		log.Fatalf("Failed to read database iostats: %v", err)
-	}
-	fmt.Println(ioStats)
+// printDatabaseStats prints the statistics of the database backend.
+func printDatabaseStats(db database.Database) {
+	switch db := db.(type) {
+	case *database.LDBDatabase:
+		stats, err := db.LDB().GetProperty("leveldb.stats")
+		if err != nil {
+			log.Fatalf("Failed to read database stats: %v", err)
+		}
+		fmt.Println(stats)
 
-	return nil
+		ioStats, err := db.LDB().GetProperty("leveldb.iostats")
+		if err != nil {
+			log.Fatalf("Failed to read database iostats: %v", err)
+		}
+		fmt.Println(ioStats)
+	case *database.LogDatabase:
+		fmt.Println(db.Stats())
+	}
 }
 
 func exportChain(ctx *cli.Context) error {
@@ -375,7 +455,7 @@ func importPreimages(ctx *cli.Context) error {
 		log.Fatalf("This command requires an argument.")
 	}
 	cfg, stack := newConfigNode(ctx)
-	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache).(*database.LDBDatabase)
+	diskdb := rawdb.KeyValueStore(commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache))
 
 	start := time.Now()
 	if err := commons.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -391,7 +471,7 @@ func exportPreimages(ctx *cli.Context) error {
 		log.Fatal("This command requires an argument.")
 	}
 	cfg, stack := newConfigNode(ctx)
-	diskdb := commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache).(*database.LDBDatabase)
+	diskdb := rawdb.KeyValueStore(commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache)).(database.Iteratee)
 
 	start := time.Now()
 	if err := commons.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -441,9 +521,9 @@ func compactDB(ctx *cli.Context) (err error) {
 	_, chainDb := commons.OpenChain(ctx, stack, &cfg.Cpc)
 	defer chainDb.Close()
 
-	if db, ok := chainDb.(*database.LDBDatabase); ok {
+	if db, ok := rawdb.KeyValueStore(chainDb).(database.Compacter); ok {
 		log.Warn("This requires a few minutes to finish, please do not interrupt!")
-		err = db.LDB().CompactRange(util.Range{})
+		err = db.Compact(nil, nil)
 		if err == nil {
 			log.Warn("Successfully compacted the underlying database!")
 		}
@@ -461,8 +541,9 @@ func deletePattern(ctx *cli.Context) (err error) {
 	_, chainDb := commons.OpenChain(ctx, stack, &cfg.Cpc)
 	defer chainDb.Close()
 
-	if db, ok := chainDb.(*database.LDBDatabase); ok {
-		iter := db.LDB().NewIterator(nil, nil)
+	db := rawdb.KeyValueStore(chainDb)
+	if iteratee, ok := db.(database.Iteratee); ok {
+		iter := iteratee.NewPrefixIterator(nil)
 
 		log.Warn("This requires a few minutes to finish, please do not interrupt!")
 
@@ -486,6 +567,207 @@ func deletePattern(ctx *cli.Context) (err error) {
 	return err
 }
 
+// freezeChain moves the canonical blocks deeper than the given depth to the
+// ancient store, creating it if needed.
+func freezeChain(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		log.Fatal("This command requires no argument.")
+	}
+	depth := uint64(defaultAncientDepth)
+	if ctx.IsSet(flags.AncientDepthFlagName) {
+		depth = ctx.Uint64(flags.AncientDepthFlagName)
+	}
+	cfg, stack := newConfigNode(ctx)
+	chainDb := commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache)
+	if rawdb.AncientStore(chainDb) == nil {
+		path := stack.ResolvePath(configs.DatabaseName)
+		if path == "" {
+			log.Fatal("The ancient store requires a data directory.")
+		}
+		fdb, err := rawdb.NewDatabaseWithFreezer(chainDb, rawdb.AncientPath(path))
+		if err != nil {
+			log.Fatalf("Could not open ancient store: %v", err)
+		}
+		chainDb = fdb
+	}
+	defer chainDb.Close()
+
+	head := rawdb.ReadHeadBlockHash(chainDb)
+	number := rawdb.ReadHeaderNumber(chainDb, head)
+	if number == nil {
+		log.Fatal("No head block found, nothing to freeze.")
+	}
+	if *number < depth {
+		fmt.Printf("Chain head %d is not deeper than %d, nothing to freeze.\n", *number, depth)
+		return nil
+	}
+	log.Warn("This may take a long time to finish, please do not interrupt!")
+
+	start := time.Now()
+	moved, err := rawdb.FreezeBlocks(chainDb, *number+1-depth)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Moved %d blocks to the ancient store in %v, %d ancient blocks in total.\n", moved, time.Since(start), rawdb.AncientStore(chainDb).Ancients())
+	return nil
+}
+
+// checkAncients verifies the integrity of the ancient store.
+func checkAncients(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		log.Fatal("This command requires no argument.")
+	}
+	cfg, stack := newConfigNode(ctx)
+	chainDb := commons.MakeChainDatabase(ctx, stack, cfg.Cpc.DatabaseCache)
+	defer chainDb.Close()
+
+	ancients := rawdb.AncientStore(chainDb)
+	if ancients == nil {
+		fmt.Println("No ancient store found.")
+		return nil
+	}
+	start := time.Now()
+	if err := rawdb.VerifyAncients(chainDb); err != nil {
+		return fmt.Errorf("ancient store corrupted: %v", err)
+	}
+	fmt.Printf("Checked %d ancient blocks in %v, no error found.\n", ancients.Ancients(), time.Since(start))
+	return nil
+}
+
+// verifyChain checks the chain data, printing the problems found as JSON.
+func verifyChain(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		log.Fatal("This command requires no argument.")
+	}
+	cfg, stack := newConfigNode(ctx)
+	chain, chainDb := commons.OpenChain(ctx, stack, &cfg.Cpc)
+	defer chainDb.Close()
+	defer chain.Stop()
+
+	var (
+		encoder = json.NewEncoder(os.Stdout)
+		found   int
+		fixed   int
+	)
+	verifyCfg := &core.VerifyConfig{
+		From:          ctx.Uint64("from"),
+		To:            ctx.Uint64("to"),
+		StateInterval: ctx.Uint64("state.interval"),
+		Seals:         ctx.BoolT("seals"),
+		Repair:        ctx.Bool("repair"),
+	}
+	start := time.Now()
+	checked, err := core.VerifyChainData(chain, verifyCfg, func(issue *core.ChainIssue) {
+		if found++; issue.Repaired {
+			fixed++
+		}
+		encoder.Encode(issue)
+	})
+	if err != nil {
+		return err
+	}
+	encoder.Encode(map[string]interface{}{
+		"checked":  checked,
+		"issues":   found,
+		"repaired": fixed,
+		"elapsed":  time.Since(start).String(),
+	})
+	if found > fixed {
+		return fmt.Errorf("found %d unrepaired problems", found-fixed)
+	}
+	return nil
+}
+
+// migrateDB copies the chain database to a database of another backend and
+// swaps them.
+func migrateDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		log.Fatal("This command requires argument <backend>.")
+	}
+	target := ctx.Args().First()
+
+	cfg, stack := newConfigNode(ctx)
+	path := stack.ResolvePath(configs.DatabaseName)
+	if path == "" {
+		log.Fatal("The migration requires a data directory.")
+	}
+	source := database.DetectBackend(path)
+	switch {
+	case source == "":
+		log.Fatalf("No database found in %s.", path)
+	case source == target:
+		fmt.Printf("The database already uses the %s backend.\n", target)
+		return nil
+	}
+	handles := cfg.Cpc.DatabaseHandles
+	src, err := database.Open(source, path, cfg.Cpc.DatabaseCache, handles)
+	if err != nil {
+		log.Fatalf("Could not open database: %v", err)
+	}
+
+	// Copy everything into a fresh database next to the current one
+	newPath := path + "." + target
+	if err := os.RemoveAll(newPath); err != nil {
+		return err
+	}
+	dst, err := database.Open(target, newPath, cfg.Cpc.DatabaseCache, handles)
+	if err != nil {
+		return err
+	}
+	log.Warn("This may take a long time to finish, please do not interrupt!")
+
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		copied int
+		batch  = dst.NewBatch()
+		iter   = src.(database.Iteratee).NewPrefixIterator(nil)
+	)
+	for iter.Next() {
+		if err = batch.Put(iter.Key(), iter.Value()); err != nil {
+			break
+		}
+		if batch.ValueSize() >= database.IdealBatchSize {
+			if err = batch.Write(); err != nil {
+				break
+			}
+			batch.Reset()
+		}
+		if copied++; time.Since(logged) > 8*time.Second {
+			log.Info("Copying database", "entries", copied, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	iter.Release()
+	if err == nil {
+		err = iter.Error()
+	}
+	if err == nil {
+		err = batch.Write()
+	}
+	dst.Close()
+	src.Close()
+	if err != nil {
+		os.RemoveAll(newPath)
+		return fmt.Errorf("copy failed: %v", err)
+	}
+	// Move the ancient store along and swap the databases
+	if _, err := os.Stat(rawdb.AncientPath(path)); err == nil {
+		if err := os.Rename(rawdb.AncientPath(path), rawdb.AncientPath(newPath)); err != nil {
+			return err
+		}
+	}
+	oldPath := path + "." + source + ".old"
+	if err := os.Rename(path, oldPath); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, path); err != nil {
+		return err
+	}
+	fmt.Printf("Copied %d entries to the %s backend in %v.\nThe previous database is kept in %s.\n", copied, target, time.Since(start), oldPath)
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -0,0 +1,457 @@
+// Copyright 2018 The cpchain authors
+// This file is part of cpchain.
+//
+// cpchain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// cpchain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with cpchain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/node"
+	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/naoina/toml"
+	"github.com/urfave/cli"
+)
+
+var networkCommand = cli.Command{
+	Name:  "network",
+	Usage: "Manage private networks",
+	Subcommands: []cli.Command{
+		{
+			Name:   "init",
+			Usage:  "Generate keys, genesis and node configs of a new private network",
+			Action: initNetwork,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "output",
+					Usage: "Directory to write the network to",
+					Value: "network",
+				},
+			},
+			ArgsUsage: "<spec.toml>",
+			Description: `The init command generates a private network from a spec file:
+
+    runMode    = "dev"        # run mode whose chain id, term and view lengths the network takes
+    period     = 1000         # block period in milliseconds, that of the run mode if 0
+    proposers  = 4            # number of proposer nodes
+    validators = 4            # number of validator nodes, 3f+1 to tolerate f faulty ones
+    host       = "127.0.0.1"  # host all nodes listen on
+    port       = 30310        # p2p port of the first node, the following nodes count up
+    rpcPort    = 8501         # rpc port of the first node, the following nodes count up
+    password   = "password"   # password of all generated accounts
+    balance    = 300000       # cpc each generated account is funded with
+    lightKdf   = false        # whether to encrypt the keystores with the lightweight kdf
+
+    [[prefund]]               # extra accounts funded in the genesis
+    address = "0x..."
+    balance = 1000000
+
+Each node gets a data dir with an account, a node key and a config.toml that
+embeds the genesis and lists all nodes as static nodes. The system contracts are predeployed by an
+admin account, whose keystore is written to admin/keystore.`,
+		},
+	},
+}
+
+// networkContracts lists the system contracts predeployed in the genesis of
+// a generated network, in the order the admin deploys them. The proxy
+// contract register is deployed right after them.
+var networkContracts = append(append([]string{}, configs.DeveloperContracts...), configs.ContractValidators)
+
+const (
+	defaultNetworkHost    = "127.0.0.1"
+	defaultNetworkPort    = 30310
+	defaultNetworkRPCPort = 8501
+	defaultNetworkBalance = 300000
+	networkAdminBalance   = 800000000
+)
+
+// networkSpec describes a private network to generate
+type networkSpec struct {
+	RunMode    string `toml:"runMode"`
+	Period     uint64 `toml:"period"`
+	Proposers  int    `toml:"proposers"`
+	Validators int    `toml:"validators"`
+	Host       string `toml:"host"`
+	Port       int    `toml:"port"`
+	RPCPort    int    `toml:"rpcPort"`
+	Password   string `toml:"password"`
+	Balance    uint64 `toml:"balance"`
+	LightKdf   bool   `toml:"lightKdf"`
+
+	Prefund []struct {
+		Address common.Address `toml:"address"`
+		Balance uint64         `toml:"balance"`
+	} `toml:"prefund"`
+}
+
+// networkNode is a generated node of a network
+type networkNode struct {
+	name      string
+	dir       string
+	validator bool
+	key       *ecdsa.PrivateKey
+	nodeKey   *ecdsa.PrivateKey
+	port      int
+	rpcPort   int
+	enode     *discover.Node
+}
+
+func (n *networkNode) address() common.Address {
+	return crypto.PubkeyToAddress(n.key.PublicKey)
+}
+
+func initNetwork(ctx *cli.Context) error {
+	specPath := ctx.Args().First()
+	if len(specPath) == 0 {
+		log.Fatal("Must supply path to the network spec file")
+	}
+	file, err := os.Open(specPath)
+	if err != nil {
+		log.Fatalf("Failed to read network spec file: %v", err)
+	}
+	defer file.Close()
+
+	spec := new(networkSpec)
+	if err := toml.NewDecoder(file).Decode(spec); err != nil {
+		log.Fatalf("Invalid network spec file: %v", err)
+	}
+
+	nodes, err := generateNetwork(spec, ctx.String("output"))
+	if err != nil {
+		log.Fatalf("Failed to generate network: %v", err)
+	}
+	for _, n := range nodes {
+		role := "--mine"
+		if n.validator {
+			role = "--validator"
+		}
+		log.Infof("Start %v with: cpchain --config %v run --runmode %v --unlock %v --password %v %v",
+			n.name, filepath.Join(n.dir, "config.toml"), spec.RunMode, n.address().Hex(),
+			filepath.Join(ctx.String("output"), "password"), role)
+	}
+	return nil
+}
+
+// generateNetwork writes the keys, genesis and node configs of the network
+// described by spec to dir, and returns the generated nodes, proposers first.
+func generateNetwork(spec *networkSpec, dir string) ([]*networkNode, error) {
+	if spec.RunMode == "" {
+		spec.RunMode = string(configs.Dev)
+	}
+	if spec.Host == "" {
+		spec.Host = defaultNetworkHost
+	}
+	if spec.Port == 0 {
+		spec.Port = defaultNetworkPort
+	}
+	if spec.RPCPort == 0 {
+		spec.RPCPort = defaultNetworkRPCPort
+	}
+	if spec.Balance == 0 {
+		spec.Balance = defaultNetworkBalance
+	}
+	if spec.Proposers <= 0 {
+		return nil, errors.New("network needs at least one proposer")
+	}
+	if spec.Validators <= 0 || (spec.Validators-1)%3 != 0 {
+		return nil, fmt.Errorf("number of validators must be 3f+1, got %d", spec.Validators)
+	}
+	ip := net.ParseIP(spec.Host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid host ip: %v", spec.Host)
+	}
+	if err := configs.SetRunMode(configs.RunMode(spec.RunMode)); err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("output directory already exists: %v", dir)
+	}
+
+	// generate the accounts and node keys
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if spec.LightKdf {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	adminKey, err := newNetworkAccount(filepath.Join(dir, "admin", "keystore"), spec.Password, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*networkNode
+	for i := 0; i < spec.Proposers+spec.Validators; i++ {
+		validator := i >= spec.Proposers
+		name := fmt.Sprintf("proposer%d", i+1)
+		if validator {
+			name = fmt.Sprintf("validator%d", i-spec.Proposers+1)
+		}
+		n := &networkNode{
+			name:      name,
+			dir:       filepath.Join(dir, name),
+			validator: validator,
+			port:      spec.Port + i,
+			rpcPort:   spec.RPCPort + i,
+		}
+		if n.key, err = newNetworkAccount(filepath.Join(n.dir, "keystore"), spec.Password, scryptN, scryptP); err != nil {
+			return nil, err
+		}
+		if n.nodeKey, err = crypto.GenerateKey(); err != nil {
+			return nil, err
+		}
+		n.enode = discover.NewNode(discover.PubkeyID(&n.nodeKey.PublicKey), ip, uint16(n.port), uint16(n.port))
+		nodes = append(nodes, n)
+	}
+	proposers, validatorNodes := nodes[:spec.Proposers], nodes[spec.Proposers:]
+
+	// the genesis funds all accounts and carries the predeployed contracts
+	balance := func(amount uint64) *big.Int {
+		return new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(configs.Cpc))
+	}
+	alloc := core.GenesisAlloc{
+		crypto.PubkeyToAddress(adminKey.PublicKey): {Balance: balance(networkAdminBalance)},
+	}
+	for _, n := range nodes {
+		alloc[n.address()] = core.GenesisAccount{Balance: balance(spec.Balance)}
+	}
+	for _, account := range spec.Prefund {
+		alloc[account.Address] = core.GenesisAccount{Balance: balance(account.Balance)}
+	}
+	contracts, proxyContractRegister, err := predeployContracts(adminKey, validatorNodes, alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	// the chain config is that of the run mode with the contracts wired in
+	chainConfig := *configs.ChainConfigInfo()
+	dporConfig := *chainConfig.Dpor
+	dporConfig.Contracts = contracts
+	dporConfig.ProxyContractRegister = proxyContractRegister
+	dporConfig.FaultyNumber = uint64(spec.Validators-1) / 3
+	if spec.Period != 0 {
+		dporConfig.Period = spec.Period
+	}
+	chainConfig.Dpor = &dporConfig
+
+	genesis := &core.Genesis{
+		Config:     &chainConfig,
+		Timestamp:  1492009146000,
+		ExtraData:  make([]byte, common.HashLength),
+		GasLimit:   configs.DefaultGasLimitPerBlock,
+		Difficulty: big.NewInt(1),
+		Alloc:      alloc,
+		Dpor: types.DporSnap{
+			Proposers:  make([]common.Address, chainConfig.Dpor.TermLen),
+			Sigs:       make([]types.DporSignature, spec.Validators),
+			Validators: make([]common.Address, 0, spec.Validators),
+		},
+	}
+	for i := range genesis.Dpor.Proposers {
+		genesis.Dpor.Proposers[i] = proposers[i%len(proposers)].address()
+	}
+	for _, n := range validatorNodes {
+		genesis.Dpor.Validators = append(genesis.Dpor.Validators, n.address())
+	}
+
+	// write the network out, the encoder only applies the field overrides of
+	// the genesis when nested
+	enc, err := genesis.MarshalTOML()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTOML(filepath.Join(dir, "genesis.toml"), enc); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "password"), []byte(spec.Password+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	staticNodes := make([]*discover.Node, 0, len(nodes))
+	for _, n := range nodes {
+		staticNodes = append(staticNodes, n.enode)
+	}
+	enodes, err := json.MarshalIndent(staticNodes, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "static-nodes.json"), enodes, 0644); err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		if err := writeNetworkNode(n, genesis, spec.Host, staticNodes); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info("Generated network", "dir", dir, "proposers", spec.Proposers, "validators", spec.Validators,
+		"genesis", genesis.ToBlock(nil).Hash().Hex())
+	return nodes, nil
+}
+
+// newNetworkAccount generates an account into the keystore of given dir
+func newNetworkAccount(keydir, password string, scryptN, scryptP int) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := keystore.NewKeyStore(keydir, scryptN, scryptP).ImportECDSA(key, password); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// predeployContracts deploys the system contracts by the admin on a simulated
+// chain and copies their code and storage into alloc. The validators are
+// registered in the validator registry along with their enode urls. It returns
+// the addresses of the contracts and of the proxy contract register.
+func predeployContracts(adminKey *ecdsa.PrivateKey, validatorNodes []*networkNode, alloc core.GenesisAlloc) (map[string]common.Address, common.Address, error) {
+	// the simulated chain is a mainnet one
+	runMode := configs.GetRunMode()
+	configs.SetRunMode(configs.Mainnet)
+	defer configs.SetRunMode(runMode)
+
+	var (
+		admin    = crypto.PubkeyToAddress(adminKey.PublicKey)
+		backend  = backends.NewDporSimulatedBackend(alloc)
+		auth     = bind.NewKeyedTransactor(adminKey)
+		deployed = make(map[string]common.Address)
+	)
+	for _, name := range networkContracts {
+		addr, tx, err := deploySystemContract(auth, backend, name, deployed)
+		if err = waitSimulated(backend, tx, err); err != nil {
+			return nil, common.Address{}, fmt.Errorf("failed to deploy %v contract: %v", name, err)
+		}
+		deployed[name] = addr
+	}
+	proxyContractRegister, tx, _, err := proxy.DeployProxyContractRegister(auth, backend)
+	if err = waitSimulated(backend, tx, err); err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to deploy proxy contract register: %v", err)
+	}
+
+	registry, err := validators.NewValidatorRegistry(deployed[configs.ContractValidators], backend)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	for _, n := range validatorNodes {
+		tx, err := registry.AddValidator(auth, n.address(), n.enode.String())
+		if err = waitSimulated(backend, tx, err); err != nil {
+			return nil, common.Address{}, fmt.Errorf("failed to register validator %v: %v", n.address().Hex(), err)
+		}
+	}
+
+	statedb, err := backend.Blockchain().State()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	contracts := []common.Address{proxyContractRegister}
+	for _, addr := range deployed {
+		contracts = append(contracts, addr)
+	}
+	for _, addr := range contracts {
+		account := core.GenesisAccount{
+			Code:    statedb.GetCode(addr),
+			Storage: make(map[common.Hash]common.Hash),
+			Balance: statedb.GetBalance(addr),
+			Nonce:   statedb.GetNonce(addr),
+		}
+		// storage values are read again as the iterator yields them rlp encoded
+		statedb.ForEachStorage(addr, func(key, _ common.Hash) bool {
+			account.Storage[key] = statedb.GetState(addr, key)
+			return true
+		})
+		alloc[addr] = account
+	}
+	// later contracts of the admin must not collide with the predeployed ones
+	adminAccount := alloc[admin]
+	adminAccount.Nonce = statedb.GetNonce(admin)
+	alloc[admin] = adminAccount
+
+	return deployed, proxyContractRegister, nil
+}
+
+// waitSimulated seals the transaction into a block of the simulated chain and
+// checks it succeeded.
+func waitSimulated(backend *backends.SimulatedBackend, tx *types.Transaction, err error) error {
+	if err != nil {
+		return err
+	}
+	backend.Commit()
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %v failed", tx.Hash().Hex())
+	}
+	return nil
+}
+
+// writeNetworkNode writes the node key and config of a node. The static nodes
+// are set in the config, as a list there overrides the one in the data dir.
+func writeNetworkNode(n *networkNode, genesis *core.Genesis, host string, staticNodes []*discover.Node) error {
+	cfg := config{
+		Cpc:  cpc.DefaultConfig,
+		Node: node.DefaultConfig,
+	}
+	cfg.Cpc.Genesis = genesis
+	cfg.Cpc.Cpcbase = n.address()
+	cfg.Node.DataDir = n.dir
+	cfg.Node.IPCPath = configs.ClientIdentifier + ".ipc"
+	cfg.Node.P2P.StaticNodes = staticNodes
+	cfg.Node.P2P.ListenAddr = fmt.Sprintf(":%d", n.port)
+	cfg.Node.HTTPHost = host
+	cfg.Node.HTTPPort = n.rpcPort
+
+	instanceDir := filepath.Join(n.dir, cfg.Node.Name)
+	if err := os.MkdirAll(instanceDir, 0700); err != nil {
+		return err
+	}
+	if err := crypto.SaveECDSA(filepath.Join(instanceDir, "nodekey"), n.nodeKey); err != nil {
+		return err
+	}
+	return writeTOML(filepath.Join(n.dir, "config.toml"), cfg)
+}
+
+func writeTOML(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(v)
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 The cpchain authors
+
+package main
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/node"
+	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/naoina/toml"
+)
+
+func TestGenerateNetwork(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpchain-network")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spec := &networkSpec{Proposers: 2, Validators: 4, Password: "password", LightKdf: true}
+	nodes, err := generateNetwork(spec, filepath.Join(dir, "network"))
+	if err != nil {
+		t.Fatalf("failed to generate network: %v", err)
+	}
+	if len(nodes) != 6 {
+		t.Fatalf("nodes mismatch: have %d, want 6", len(nodes))
+	}
+	if _, err := generateNetwork(&networkSpec{Proposers: 1, Validators: 2}, filepath.Join(dir, "invalid")); err == nil {
+		t.Fatalf("network with 2 validators generated")
+	}
+
+	// every node loads the genesis from its config
+	var genesis *core.Genesis
+	for _, n := range nodes {
+		f, err := os.Open(filepath.Join(n.dir, "config.toml"))
+		if err != nil {
+			t.Fatalf("config of %v missing: %v", n.name, err)
+		}
+		cfg := config{Cpc: cpc.DefaultConfig, Node: node.DefaultConfig}
+		err = toml.NewDecoder(f).Decode(&cfg)
+		f.Close()
+		if err != nil {
+			t.Fatalf("invalid config of %v: %v", n.name, err)
+		}
+		if cfg.Cpc.Genesis == nil || cfg.Node.DataDir != n.dir {
+			t.Fatalf("config of %v mismatch", n.name)
+		}
+		genesis = cfg.Cpc.Genesis
+		if _, err := os.Stat(filepath.Join(n.dir, configs.ClientIdentifier, "nodekey")); err != nil {
+			t.Fatalf("node key of %v missing: %v", n.name, err)
+		}
+	}
+	if faulty := genesis.Config.Dpor.FaultyNumber; faulty != 1 {
+		t.Fatalf("faulty number mismatch: have %d, want 1", faulty)
+	}
+	for i, proposer := range genesis.Dpor.Proposers {
+		if proposer != nodes[i%spec.Proposers].address() {
+			t.Fatalf("proposer %d mismatch", i)
+		}
+	}
+
+	// the predeployed contracts are alive in the genesis state
+	for _, name := range networkContracts {
+		addr := genesis.Config.Dpor.Contracts[name]
+		if len(genesis.Alloc[addr].Code) == 0 {
+			t.Fatalf("%v contract missing in genesis", name)
+		}
+	}
+	backend := backends.NewDporSimulatedBackend(genesis.Alloc)
+	registry, err := validators.NewValidatorRegistry(genesis.Config.Dpor.Contracts[configs.ContractValidators], backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered, err := registry.GetValidators(nil)
+	if err != nil {
+		t.Fatalf("failed to read validator registry: %v", err)
+	}
+	if len(registered) != spec.Validators {
+		t.Fatalf("registered validators mismatch: have %d, want %d", len(registered), spec.Validators)
+	}
+	for i, v := range registered {
+		n := nodes[spec.Proposers+i]
+		if v != n.address() || genesis.Dpor.Validators[i] != v {
+			t.Fatalf("validator %d mismatch", i)
+		}
+		if enode, err := registry.EnodeOf(nil, v); err != nil || enode != n.enode.String() {
+			t.Fatalf("enode of validator %d mismatch: have %v, want %v", i, enode, n.enode)
+		}
+	}
+}
+
+func TestGenerateNetworkSeal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpchain-network")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	spec := &networkSpec{Proposers: 1, Validators: 4, Password: "password", LightKdf: true}
+	nodes, err := generateNetwork(spec, filepath.Join(dir, "network"))
+	if err != nil {
+		t.Fatalf("failed to generate network: %v", err)
+	}
+	f, err := os.Open(filepath.Join(nodes[0].dir, "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{Cpc: cpc.DefaultConfig, Node: node.DefaultConfig}
+	err = toml.NewDecoder(f).Decode(&cfg)
+	f.Close()
+	if err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	genesis := cfg.Cpc.Genesis
+
+	db := database.NewMemDatabase()
+	genesis.MustCommit(db)
+	engine := func(n *networkNode) *dpor.Dpor {
+		d := dpor.New(genesis.Config.Dpor, db)
+		d.Authorize(n.address(), func(_ accounts.Account, hash []byte) ([]byte, error) {
+			return crypto.Sign(hash, n.key)
+		})
+		return d
+	}
+	proposer := engine(nodes[0])
+	chain, err := core.NewBlockChain(db, nil, genesis.Config, proposer, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// the proposer of the genesis seals the first block
+	header := &types.Header{
+		ParentHash: chain.Genesis().Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   genesis.GasLimit,
+		Coinbase:   nodes[0].address(),
+	}
+	if err := proposer.PrepareBlock(chain, header); err != nil {
+		t.Fatalf("failed to prepare block: %v", err)
+	}
+	block, err := proposer.Seal(chain, types.NewBlockWithHeader(header), nil)
+	if err != nil || block == nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	header = block.Header()
+
+	// the validators of the genesis sign it
+	header.Dpor.Sigs = make([]types.DporSignature, spec.Validators)
+	for i, n := range nodes[spec.Proposers:] {
+		signed := types.CopyHeader(block.Header())
+		validator := engine(n)
+		validator.SetChain(chain)
+		if err := validator.SignHeader(signed, consensus.Commit); err != nil {
+			t.Fatalf("validator %d failed to sign block: %v", i, err)
+		}
+		header.Dpor.Sigs[i] = signed.Dpor.Sigs[i]
+	}
+
+	verifier := dpor.New(genesis.Config.Dpor, db)
+	if err := verifier.VerifySeal(chain, header, nil); err != nil {
+		t.Fatalf("failed to verify seal: %v", err)
+	}
+	if err := verifier.VerifyHeader(chain, header, true, nil); err != nil {
+		t.Fatalf("failed to verify block: %v", err)
+	}
+}
@@ -17,6 +17,7 @@
 package main
 
 import (
+	"crypto/ecdsa"
 	"io/ioutil"
 	"os"
 	"os/signal"
@@ -33,9 +34,13 @@ import (
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus/dpor"
 	"bitbucket.org/cpchain/chain/contracts/dpor/primitive_register"
+	example_register "bitbucket.org/cpchain/chain/contracts/primitives_example/primitive_register"
+	"bitbucket.org/cpchain/chain/core/vm"
 	"bitbucket.org/cpchain/chain/internal/profile"
 	"bitbucket.org/cpchain/chain/node"
 	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
+	"bitbucket.org/cpchain/chain/protocols/lcpc"
 	"github.com/urfave/cli"
 )
 
@@ -111,15 +116,24 @@ func init() {
 
 func run(ctx *cli.Context) error {
 	isRunningChain = true
-	err := times.InvalidSystemClock()
-	if err != nil {
-		log.Warn(InvalidSystemClockWarn)
-		log.Fatalf("system clock need to be synchronized.there is more than %v seconds gap between ntp and this server", times.MaxGapDuration)
+	// a developer chain has nobody to agree on time with
+	if !ctx.Bool(flags.DevFlagName) {
+		err := times.InvalidSystemClock()
+		if err != nil {
+			log.Warn(InvalidSystemClockWarn)
+			log.Fatalf("system clock need to be synchronized.there is more than %v seconds gap between ntp and this server", times.MaxGapDuration)
+		}
 	}
 
 	if ctx.IsSet(flags.MineFlagName) && ctx.IsSet(flags.ValidatorFlagName) {
 		log.Fatalf("A node cannot be both miner and validator.")
 	}
+	if ctx.IsSet(flags.LightFlagName) && (ctx.IsSet(flags.MineFlagName) || ctx.IsSet(flags.ValidatorFlagName)) {
+		log.Fatalf("A light client can be neither miner nor validator.")
+	}
+	if ctx.IsSet(flags.DevFlagName) && (ctx.IsSet(flags.ValidatorFlagName) || ctx.IsSet(flags.LightFlagName)) {
+		log.Fatalf("A developer chain seals blocks alone, it can be neither validator nor light client.")
+	}
 
 	n := createNode(ctx)
 	bootstrap(ctx, n)
@@ -129,15 +143,26 @@ func run(ctx *cli.Context) error {
 
 // Register chain services for a *full* node.
 func registerChainService(cfg *cpc.Config, n *node.Node, cliCtx *cli.Context) {
+	if cfg.SyncMode == syncer.LightSync {
+		registerLightService(cfg, n)
+		return
+	}
 	err := n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
 		fullNode, err := cpc.New(ctx, cfg)
 		if err != nil {
 			return nil, err
 		}
+		if cfg.LightServ {
+			fullNode.AddLesServer(lcpc.NewLightServer(fullNode.BlockChain(), fullNode.TxPool(), fullNode.NetVersion()))
+		}
 
 		primitive_register.RegisterPrimitiveContracts()
+		example_register.RegisterPrimitiveContracts()
+		if err := vm.CheckPrimitives(fullNode.BlockChain().Config()); err != nil {
+			return nil, err
+		}
 
-		if cliCtx.Bool(flags.MineFlagName) {
+		if cliCtx.Bool(flags.MineFlagName) || cliCtx.Bool(flags.DevFlagName) {
 			fullNode.SetAsMiner(true)
 		}
 
@@ -151,6 +176,16 @@ func registerChainService(cfg *cpc.Config, n *node.Node, cliCtx *cli.Context) {
 	}
 }
 
+// Register the chain service for a *light* node.
+func registerLightService(cfg *cpc.Config, n *node.Node) {
+	err := n.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return lcpc.New(ctx, cfg)
+	})
+	if err != nil {
+		log.Fatalf("Failed to register the light chain service: %v", err)
+	}
+}
+
 // Creates a node with chain services registered
 func createNode(ctx *cli.Context) *node.Node {
 	cfg, n := newConfigNode(ctx)
@@ -268,7 +303,7 @@ func setupMining(ctx *cli.Context, n *node.Node, key *keystore.Key) {
 		cpchainService.AdmissionApiBackend.IgnoreNetworkCheck()
 	}
 
-	if ctx.Bool(flags.MineFlagName) {
+	if ctx.Bool(flags.MineFlagName) || ctx.Bool(flags.DevFlagName) {
 		if err := cpchainService.StartMining(true); err != nil {
 			log.Fatalf("Failed to start mining: %v", err)
 		}
@@ -281,22 +316,42 @@ func setupMining(ctx *cli.Context, n *node.Node, key *keystore.Key) {
 	}
 }
 
+// startRewards starts the reward service, driving the reward contract with key
+// if scheduled to.
+func startRewards(n *node.Node, key *keystore.Key) {
+	var cpchainService *cpc.CpchainService
+	if err := n.Service(&cpchainService); err != nil {
+		log.Fatalf("CPChain service not running: %v", err)
+	}
+	rpcClient, err := n.Attach()
+	if err != nil {
+		log.Fatalf("Failed to attach to self: %v", err)
+	}
+	var privateKey *ecdsa.PrivateKey
+	if key != nil {
+		privateKey = key.PrivateKey
+	}
+	if err := cpchainService.StartRewards(cpclient.NewClient(rpcClient), privateKey); err != nil {
+		log.Error("Failed to start reward service", "err", err)
+	}
+}
+
 func handleInterrupt(n *node.Node) {
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
 	defer signal.Stop(sigc)
 
+	// Light clients run no full service, they propose no block either
 	var cpchainService *cpc.CpchainService
-	if err := n.Service(&cpchainService); err != nil {
-		log.Fatalf("CPChain service not running: %v", err)
-	}
+	n.Service(&cpchainService)
 
 WaitSignal:
 	signal := <-sigc
 	log.Info("Got interrupt")
 	// Warn to not to stop if local coinbase is a current or future proposer!
-	if coinbase, err := cpchainService.Coinbase(); err == nil && signal != syscall.SIGTERM {
-		if cpchainService.Engine().(*dpor.Dpor).IsCurrentOrFutureProposer(coinbase) {
+	if cpchainService != nil && signal != syscall.SIGTERM {
+		coinbase, err := cpchainService.Coinbase()
+		if err == nil && cpchainService.Engine().(*dpor.Dpor).IsCurrentOrFutureProposer(coinbase) {
 			log.Warn(BusyWarning)
 			goto WaitSignal
 		}
@@ -334,9 +389,18 @@ func bootstrap(ctx *cli.Context, n *node.Node) {
 
 	startNode(n)
 	key := unlockAccounts(ctx, n)
-	cpc.StartSyncerLoop <- "startLoop"
+	if ctx.Bool(flags.DevFlagName) {
+		key = unlockDeveloper(ctx, n)
+	}
 	handleWallet(n)
-	setupMining(ctx, n, key)
+	if !ctx.IsSet(flags.LightFlagName) {
+		cpc.StartSyncerLoop <- "startLoop"
+		setupMining(ctx, n, key)
+		startRewards(n, key)
+	}
+	if ctx.Bool(flags.DevFlagName) {
+		go deployDeveloperContracts(n, key)
+	}
 	// handle user interrupt
 	go handleInterrupt(n)
 }
@@ -48,7 +48,7 @@ const (
 )
 
 // MakeChainDatabase open an LevelDB using the flags passed to the client and will hard crash if it fails.
-// It creates a new one if the database doesn't exist.
+// It creates a new one if the database doesn't exist. The ancient store is opened along if there is one.
 func MakeChainDatabase(ctx *cli.Context, n *node.Node, databaseCache int) database.Database {
 	// TODO hardcoded name
 	name := "chaindata"
@@ -58,6 +58,14 @@ func MakeChainDatabase(ctx *cli.Context, n *node.Node, databaseCache int) databa
 	if err != nil {
 		log.Fatalf("Could not open database: %v", err)
 	}
+	if path := n.ResolvePath(name); path != "" {
+		if _, err := os.Stat(rawdb.AncientPath(path)); err == nil {
+			chainDb, err = rawdb.NewDatabaseWithFreezer(chainDb, rawdb.AncientPath(path))
+			if err != nil {
+				log.Fatalf("Could not open ancient store: %v", err)
+			}
+		}
+	}
 	return chainDb
 }
 
@@ -244,7 +252,7 @@ func ExportChainN(blockchain *core.BlockChain, fn string, first, last uint64) er
 }
 
 // ImportPreimages imports a batch of exported hash preimages into the database.
-func ImportPreimages(db *database.LDBDatabase, fn string) error {
+func ImportPreimages(db database.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
 
 	// Open the file handle and potentially unwrap the gzip stream
@@ -291,7 +299,7 @@ func ImportPreimages(db *database.LDBDatabase, fn string) error {
 
 // ExportPreimages exports all known hash preimages into the specified file,
 // truncating any data already present in the file.
-func ExportPreimages(db *database.LDBDatabase, fn string) error {
+func ExportPreimages(db database.Iteratee, fn string) error {
 	log.Info("Exporting preimages", "file", fn)
 
 	// Open the file handle and potentially wrap with a gzip stream
@@ -307,7 +315,8 @@ func ExportPreimages(db *database.LDBDatabase, fn string) error {
 		defer writer.(*gzip.Writer).Close()
 	}
 	// Iterate over the preimages and export them
-	it := db.NewIteratorWithPrefix([]byte("secure-key-"))
+	it := db.NewPrefixIterator([]byte("secure-key-"))
+	defer it.Release()
 	for it.Next() {
 		if err := rlp.Encode(writer, it.Value()); err != nil {
 			return err
@@ -117,7 +117,7 @@ func updateP2pConfig(ctx *cli.Context, cfg *p2p.Config) {
 		cfg.ListenAddr = fmt.Sprintf(":%d", ctx.Int(flags.PortFlagName))
 	}
 
-	if isRunningChain {
+	if isRunningChain && !ctx.Bool(flags.DevFlagName) {
 		updateBootstrapNodes(ctx, cfg)
 		updateValidatorNodes(ctx)
 	}
@@ -210,6 +210,12 @@ func updateNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(flags.LightKdfFlagName) {
 		cfg.UseLightweightKDF = ctx.Bool(flags.LightKdfFlagName)
 	}
+	if ctx.IsSet(flags.SignerFlagName) {
+		cfg.ExternalSigner = ctx.String(flags.SignerFlagName)
+	}
+	if ctx.IsSet(flags.DBEngineFlagName) {
+		cfg.DatabaseBackend = ctx.String(flags.DBEngineFlagName)
+	}
 }
 
 // begin chain configs
@@ -287,6 +293,25 @@ func updateChainConfig(ctx *cli.Context, cfg *cpc.Config, n *node.Node) {
 	// setGPO(ctx, &cfg.GPO)
 	updateTxPool(ctx, &cfg.TxPool)
 	updateDatabaseCache(ctx, cfg)
+	if ctx.IsSet(flags.AddrIndexFlagName) {
+		cfg.AddressIndex = ctx.Bool(flags.AddrIndexFlagName)
+	}
+	if ctx.IsSet(flags.LogIndexFlagName) {
+		cfg.LogIndex = ctx.Bool(flags.LogIndexFlagName)
+	}
+	if ctx.IsSet(flags.AncientDepthFlagName) {
+		cfg.AncientDepth = ctx.Uint64(flags.AncientDepthFlagName)
+	}
+	if ctx.IsSet(flags.RewardScheduleFlagName) {
+		cfg.RewardSchedule = ctx.Bool(flags.RewardScheduleFlagName)
+	}
+	if ctx.IsSet(flags.PdashContractFlagName) {
+		addr := ctx.String(flags.PdashContractFlagName)
+		if !common.IsHexAddress(addr) {
+			log.Fatalf("Invalid pdash contract address: %v", addr)
+		}
+		cfg.PdashContract = common.HexToAddress(addr)
+	}
 	updateTrieCache(ctx, cfg)
 }
 
@@ -309,6 +334,15 @@ func updateSyncModeFlag(ctx *cli.Context, cfg *cpc.Config) {
 	if ctx.IsSet(flags.FastSyncFlagName) {
 		cfg.SyncMode = syncer.FastSync
 	}
+	if ctx.IsSet(flags.SnapSyncFlagName) {
+		cfg.SyncMode = syncer.SnapSync
+	}
+	if ctx.IsSet(flags.LightFlagName) {
+		cfg.SyncMode = syncer.LightSync
+	}
+	if ctx.IsSet(flags.LightServFlagName) {
+		cfg.LightServ = true
+	}
 }
 
 // Updates config from --config file
@@ -361,6 +395,10 @@ func newConfigNode(ctx *cli.Context) (config, *node.Node) {
 
 	updateSyncModeFlag(ctx, &cfg.Cpc)
 
+	if ctx.Bool(flags.DevFlagName) {
+		updateDeveloperNodeConfig(ctx, &cfg.Node)
+	}
+
 	// create node
 	n, err := node.New(&cfg.Node)
 	if err != nil {
@@ -369,6 +407,9 @@ func newConfigNode(ctx *cli.Context) (config, *node.Node) {
 
 	// update chain config
 	updateChainConfig(ctx, &cfg.Cpc, n)
+	if ctx.Bool(flags.DevFlagName) {
+		updateDeveloperChainConfig(ctx, &cfg.Cpc, n)
+	}
 
 	return cfg, n
 }
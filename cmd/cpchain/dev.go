@@ -0,0 +1,204 @@
+// Copyright 2018 The cpchain authors
+// This file is part of cpchain.
+//
+// cpchain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// cpchain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with cpchain. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/cmd/cpchain/flags"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/admission"
+	"bitbucket.org/cpchain/chain/contracts/dpor/campaign"
+	"bitbucket.org/cpchain/chain/contracts/dpor/network"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	rptContract "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/node"
+	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+// admission parameters of developer chains and generated networks, the same as
+// tools/smartcontract deploys
+const (
+	devCpuDifficulty     = 12
+	devMemoryDifficulty  = 6
+	devCpuWorkTimeout    = 5
+	devMemoryWorkTimeout = 5
+)
+
+// updateDeveloperNodeConfig keeps a developer chain in memory and away from
+// any peer. Only the keystore of a given data dir is used.
+func updateDeveloperNodeConfig(ctx *cli.Context, cfg *node.Config) {
+	configs.SetRunMode(configs.Dev)
+
+	if ctx.IsSet(flags.DataDirFlagName) && cfg.KeyStoreDir == "" {
+		cfg.KeyStoreDir = filepath.Join(cfg.DataDir, "keystore")
+	}
+	cfg.DataDir = ""
+
+	cfg.P2P.NoDiscovery = true
+	cfg.P2P.MaxPeers = 0
+	cfg.P2P.ListenAddr = ""
+	cfg.P2P.NAT = nil
+	cfg.P2P.BootstrapNodes = nil
+}
+
+// updateDeveloperChainConfig sets up the genesis of a developer chain. The
+// coinbase, or a new account if there is none, proposes and validates all
+// blocks. All accounts in the keystore are funded.
+func updateDeveloperChainConfig(ctx *cli.Context, cfg *cpc.Config, n *node.Node) {
+	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	if cfg.Cpcbase == (common.Address{}) {
+		events := make(chan accounts.WalletEvent, 16)
+		sub := n.AccountManager().Subscribe(events)
+		defer sub.Unsubscribe()
+
+		account, err := ks.NewAccount(developerPassword(ctx))
+		if err != nil {
+			log.Fatalf("Failed to create developer account: %v", err)
+		}
+		cfg.Cpcbase = account.Address
+
+		// the account manager finds the account once it announces its wallet
+		for event := range events {
+			if event.Kind == accounts.WalletArrived && event.Wallet.Contains(account) {
+				break
+			}
+		}
+	}
+
+	var faucets []common.Address
+	for _, account := range ks.Accounts() {
+		faucets = append(faucets, account.Address)
+	}
+
+	cfg.Dev = true
+	cfg.Genesis = core.DeveloperGenesisBlock(ctx.Uint64(flags.DevPeriodFlagName), cfg.Cpcbase, faucets...)
+	log.Info("Using developer account", "address", cfg.Cpcbase.Hex(), "period", ctx.Uint64(flags.DevPeriodFlagName))
+}
+
+// developerPassword returns the password of the developer account, the first
+// line of the --password file or empty.
+func developerPassword(ctx *cli.Context) string {
+	if passwords := makePasswordList(ctx); len(passwords) > 0 {
+		return passwords[0]
+	}
+	return ""
+}
+
+// unlockDeveloper unlocks the developer account and returns its key
+func unlockDeveloper(ctx *cli.Context, n *node.Node) *keystore.Key {
+	var cpchainService *cpc.CpchainService
+	if err := n.Service(&cpchainService); err != nil {
+		log.Fatalf("CPChain service not running: %v", err)
+	}
+	developer, err := cpchainService.Coinbase()
+	if err != nil {
+		log.Fatalf("Developer account missing: %v", err)
+	}
+
+	ks := n.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	_, key, err := unlockAccountWithPassword(ks, developer.Hex(), developerPassword(ctx))
+	if err != nil || key == nil {
+		log.Fatalf("Failed to unlock developer account %v: %v", developer.Hex(), err)
+	}
+	return key
+}
+
+// deployDeveloperContracts deploys the system contracts of a developer chain
+// in the order their addresses in the chain config are derived from.
+func deployDeveloperContracts(n *node.Node, key *keystore.Key) {
+	var cpchainService *cpc.CpchainService
+	if err := n.Service(&cpchainService); err != nil {
+		log.Fatalf("CPChain service not running: %v", err)
+	}
+	rpcClient, err := n.Attach()
+	if err != nil {
+		log.Fatalf("Failed to attach to self: %v", err)
+	}
+
+	var (
+		client   = cpclient.NewClient(rpcClient)
+		config   = cpchainService.BlockChain().Config().Dpor
+		auth     = bind.NewKeyedTransactor(key.PrivateKey)
+		deployed = make(map[string]common.Address)
+	)
+	for _, name := range configs.DeveloperContracts {
+		addr, tx, err := deploySystemContract(auth, client, name, deployed)
+		waitDeveloperContract(client, name, config.Contracts[name], addr, tx, err)
+		deployed[name] = addr
+	}
+
+	addr, tx, _, err := proxy.DeployProxyContractRegister(auth, client)
+	waitDeveloperContract(client, "proxyContractRegister", config.ProxyContractRegister, addr, tx, err)
+
+	log.Info("Developer chain is ready", "contracts", len(deployed)+1)
+}
+
+// waitDeveloperContract waits for a system contract to be deployed at the
+// address the chain config expects.
+func waitDeveloperContract(client *cpclient.Client, name string, want common.Address, addr common.Address, tx *types.Transaction, err error) {
+	if err != nil {
+		log.Fatalf("Failed to deploy %v contract: %v", name, err)
+	}
+	if addr != want {
+		log.Fatalf("Contract %v deployed at %v instead of %v", name, addr.Hex(), want.Hex())
+	}
+	if _, err := bind.WaitDeployed(context.Background(), client, tx); err != nil {
+		log.Fatalf("Failed to deploy %v contract: %v", name, err)
+	}
+	log.Info("Deployed system contract", "name", name, "address", addr.Hex())
+}
+
+// deploySystemContract deploys the system contract of given name, the campaign
+// contract refers to the admission and rnode contracts deployed before it.
+func deploySystemContract(auth *bind.TransactOpts, backend bind.ContractBackend, name string, deployed map[string]common.Address) (addr common.Address, tx *types.Transaction, err error) {
+	switch name {
+	case configs.ContractRnode:
+		addr, tx, _, err = rnode.DeployRnode(auth, backend)
+	case configs.ContractAdmission:
+		addr, tx, _, err = admission.DeployAdmission(auth, backend, big.NewInt(devCpuDifficulty), big.NewInt(devMemoryDifficulty),
+			big.NewInt(devCpuWorkTimeout), big.NewInt(devMemoryWorkTimeout))
+	case configs.ContractCampaign:
+		addr, tx, _, err = campaign.DeployCampaign(auth, backend, deployed[configs.ContractAdmission], deployed[configs.ContractRnode])
+	case configs.ContractRpt:
+		addr, tx, _, err = rptContract.DeployRpt(auth, backend)
+	case configs.ContractNetwork:
+		addr, tx, _, err = network.DeployNetwork(auth, backend)
+	case configs.ContractReward:
+		addr, tx, _, err = reward.DeployReward(auth, backend)
+	case configs.ContractValidators:
+		addr, tx, _, err = validators.DeployValidatorRegistry(auth, backend)
+	default:
+		err = fmt.Errorf("unknown system contract: %v", name)
+	}
+	return addr, tx, err
+}
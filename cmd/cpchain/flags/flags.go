@@ -97,6 +97,7 @@ const (
 	PasswordFlagName = "password"
 	LightKdfFlagName = "lightkdf"
 	UnlockFlagName   = "unlock"
+	SignerFlagName   = "signer"
 )
 
 var AccountFlags = []cli.Flag{
@@ -115,16 +116,27 @@ var AccountFlags = []cli.Flag{
 		Usage: "Comma separated list of accounts to unlock",
 		Value: "",
 	},
+	cli.StringFlag{
+		Name:  SignerFlagName,
+		Usage: "IPC path or HTTP URL of an external signer holding the account keys",
+		Value: "",
+	},
 }
 
 const (
-	NetworkIDFlagName     = "networkid"
-	NoCompactionFlagName  = "nocompaction"
-	CacheFlagName         = "cache"
-	CacheDatabaseFlagName = "cache.database"
-	CacheGCFlagName       = "cache.gc"
-	MaxTxMapSizeFlagName  = "txpoolsize"
-	FifoTxPoolQueue       = "fifotxpool"
+	NetworkIDFlagName      = "networkid"
+	NoCompactionFlagName   = "nocompaction"
+	CacheFlagName          = "cache"
+	CacheDatabaseFlagName  = "cache.database"
+	CacheGCFlagName        = "cache.gc"
+	MaxTxMapSizeFlagName   = "txpoolsize"
+	FifoTxPoolQueue        = "fifotxpool"
+	AddrIndexFlagName      = "addrindex"
+	LogIndexFlagName       = "logindex"
+	AncientDepthFlagName   = "ancient.depth"
+	DBEngineFlagName       = "db.engine"
+	RewardScheduleFlagName = "reward.schedule"
+	PdashContractFlagName  = "pdash.contract"
 )
 
 var ChainFlags = []cli.Flag{
@@ -164,11 +176,37 @@ var ChainFlags = []cli.Flag{
 		Name:  FifoTxPoolQueue,
 		Usage: "Use FIFO tx pool queue",
 	},
+	cli.BoolFlag{
+		Name:  AddrIndexFlagName,
+		Usage: "Maintain an address to transaction history index for eth_getTransactionsByAddress",
+	},
+	cli.BoolFlag{
+		Name:  LogIndexFlagName,
+		Usage: "Maintain an exact log index by address and first topic for eth_getLogs",
+	},
+	cli.Uint64Flag{
+		Name:  AncientDepthFlagName,
+		Usage: "Move canonical blocks deeper than this to the ancient store (0 = keep all blocks in the database)",
+	},
+	cli.StringFlag{
+		Name:  DBEngineFlagName,
+		Usage: "Key-value store backend of new databases (leveldb, logdb), existing ones keep theirs",
+	},
+	cli.BoolFlag{
+		Name:  RewardScheduleFlagName,
+		Usage: "Drive the period transitions of the reward contract with the unlocked account owning it",
+	},
+	cli.StringFlag{
+		Name:  PdashContractFlagName,
+		Usage: "Address of the pdash contract whose orders to index for the pdash API",
+	},
 }
 
 const (
 	MineFlagName      = "mine"
 	ValidatorFlagName = "validator"
+	DevFlagName       = "dev"
+	DevPeriodFlagName = "dev.period"
 )
 
 var MinerFlags = []cli.Flag{
@@ -180,10 +218,21 @@ var MinerFlags = []cli.Flag{
 		Name:  ValidatorFlagName,
 		Usage: "Enable validator",
 	},
+	cli.BoolFlag{
+		Name:  DevFlagName,
+		Usage: "Run an ephemeral developer chain with funded accounts and system contracts, sealing all blocks alone",
+	},
+	cli.Uint64Flag{
+		Name:  DevPeriodFlagName,
+		Usage: "Block period of the developer chain in milliseconds, 0 to seal blocks on transactions only",
+	},
 }
 
 const (
-	FastSyncFlagName = "fast"
+	FastSyncFlagName  = "fast"
+	SnapSyncFlagName  = "snap"
+	LightFlagName     = "light"
+	LightServFlagName = "lightserv"
 )
 
 var SyncFlags = []cli.Flag{
@@ -191,6 +240,18 @@ var SyncFlags = []cli.Flag{
 		Name:  FastSyncFlagName,
 		Usage: "Enable fast sync",
 	},
+	cli.BoolFlag{
+		Name:  SnapSyncFlagName,
+		Usage: "Enable fast sync downloading the state as account and storage ranges",
+	},
+	cli.BoolFlag{
+		Name:  LightFlagName,
+		Usage: "Run as a light client, following the headers and retrieving the rest on demand",
+	},
+	cli.BoolFlag{
+		Name:  LightServFlagName,
+		Usage: "Serve the light clients",
+	},
 }
 
 const (
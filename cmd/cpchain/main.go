@@ -51,6 +51,7 @@ func newApp() *cli.App {
 		dumpConfigCommand,
 		chainCommand,
 		campaignCommand,
+		networkCommand,
 	}
 
 	// global flags
@@ -32,7 +32,8 @@ var (
 		common.HexToAddress("0x22a672eab2b1a3ff3ed91563205a56ca5a560e08"), // #6
 	}
 	devChainConfig = &ChainConfig{
-		ChainID: big.NewInt(DevChainId),
+		ChainID:                big.NewInt(DevChainId),
+		ImpeachPunishmentBlock: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber),
 		Dpor: &DporConfig{
 			Period:                DefaultBlockPeriod,
 			TermLen:               4,
@@ -0,0 +1,59 @@
+// Copyright 2018 The cphain authors
+
+package configs
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// developer configuration, a single node chain run by `cpchain run --dev`
+const (
+	DeveloperFaultyValidatorsNumber = 0
+	DeveloperValidatorsNumber       = DeveloperFaultyValidatorsNumber*3 + 1
+)
+
+// DeveloperContracts lists the system contracts of a developer chain in the
+// order the developer deploys them, starting from nonce 0. The proxy contract
+// register is deployed right after them.
+var DeveloperContracts = []string{
+	ContractRnode,
+	ContractAdmission,
+	ContractCampaign,
+	ContractRpt,
+	ContractNetwork,
+	ContractReward,
+}
+
+// DeveloperChainConfig returns the chain config of a developer chain in which
+// the developer proposes and validates all blocks. A block is sealed every
+// period milliseconds, or as soon as transactions arrive if period is 0.
+//
+// Term and view lengths are the dev ones, as some packages read them from the
+// config of the run mode.
+func DeveloperChainConfig(developer common.Address, period uint64) *ChainConfig {
+	contracts := make(map[string]common.Address, len(DeveloperContracts))
+	for nonce, name := range DeveloperContracts {
+		contracts[name] = crypto.CreateAddress(developer, uint64(nonce))
+	}
+	proxyContractRegister := crypto.CreateAddress(developer, uint64(len(DeveloperContracts)))
+
+	return &ChainConfig{
+		ChainID:                big.NewInt(DevChainId),
+		ImpeachPunishmentBlock: big.NewInt(0),
+		ProxyContractBlock:     big.NewInt(0),
+		Dpor: &DporConfig{
+			Period:                period,
+			TermLen:               devChainConfig.Dpor.TermLen,
+			ViewLen:               devChainConfig.Dpor.ViewLen,
+			FaultyNumber:          DeveloperFaultyValidatorsNumber,
+			MaxInitBlockNumber:    DefaultDevMaxInitBlockNumber,
+			ProxyContractRegister: proxyContractRegister,
+			Contracts:             contracts,
+			ImpeachTimeout:        time.Millisecond * DefaultBlockPeriod,
+		},
+	}
+}
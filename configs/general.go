@@ -21,11 +21,6 @@ const (
 	DatabaseName     = "chaindata"
 )
 
-// EnableProxyContract is used for enable proxy contract in evm
-const (
-	EnableProxyContract = false
-)
-
 // IgnoreNetworkStatusCheck is used for ignore network status check before campaign
 // this is not a hard restriction, set to true to ignore the check
 const (
@@ -92,8 +87,8 @@ const (
 	DefaultTestMainnetMaxInitBlockNumber = 180
 )
 
-// NewRptWithImpeachPunishmentPivotBlockNumber is a block number used to denote the
-// start point from which the new rpt method is used to do elections.
+// NewRptWithImpeachPunishmentPivotBlockNumber is the ImpeachPunishmentBlock of the
+// chains the fork was rolled out on before it was part of the chain configs.
 // The new rpt calc method considers to punish those impeached reputation nodes.
 const (
 	NewRptWithImpeachPunishmentPivotBlockNumber = 1244472
@@ -118,8 +113,30 @@ const (
 	ContractAdmission = "admission" // address of admission
 	ContractRnode     = "rnode"     // address of rnode
 	ContractNetwork   = "network"   // address of network
+	ContractReward    = "reward"    // address of reward,manages the fundraising of investors
+
+	ContractValidators = "validators" // address of validator registry,governs the validator committee
 )
 
+// Names of the primitive contract implementations chain configs can enable.
+const (
+	PrimitiveCpuPowValidate = "cpuPowValidate" // validates the cpu proof of work of a campaign
+	PrimitiveMemPowValidate = "memPowValidate" // validates the memory proof of work of a campaign
+	PrimitiveRank           = "rank"           // rank of an address by rpt
+	PrimitiveMaintenance    = "maintenance"    // maintenance score of an address
+	PrimitiveProxyCount     = "proxyCount"     // number of proxy transactions of an address
+	PrimitiveUploadReward   = "uploadReward"   // upload reward of an address
+	PrimitiveTxVolume       = "txVolume"       // transaction volume of an address
+	PrimitiveIsProxy        = "isProxy"        // whether an address is a proxy
+)
+
+// DefaultPrimitives are the primitive contracts of the chain configs declaring
+// none, enabled from the genesis block on.
+var DefaultPrimitives = []PrimitiveConfig{
+	{Name: PrimitiveCpuPowValidate, Address: common.BytesToAddress([]byte{106})},
+	{Name: PrimitiveMemPowValidate, Address: common.BytesToAddress([]byte{107})},
+}
+
 // some version numbers
 const (
 	RnodeVersion    = 2
@@ -178,7 +195,7 @@ var (
 
 var (
 	// just for test
-	TestChainConfig = &ChainConfig{big.NewInt(DevChainId), &DporConfig{Period: 0, TermLen: 4}}
+	TestChainConfig = &ChainConfig{ChainID: big.NewInt(DevChainId), Dpor: &DporConfig{Period: 0, TermLen: 4}}
 )
 
 // this contains all the changes we have made to the cpchain protocol.
@@ -296,6 +313,23 @@ type ChainConfig struct {
 
 	// Various consensus engines
 	Dpor *DporConfig `json:"dpor,omitempty" toml:"dpor,omitempty"`
+
+	// Primitive contracts enabled on top of the built-in ones at fixed addresses,
+	// nil for DefaultPrimitives. A non-nil list replaces DefaultPrimitives.
+	Primitives []PrimitiveConfig `json:"primitives,omitempty" toml:"primitives,omitempty"`
+
+	// Hard forks, activated from the given block on, nil for never
+	ImpeachPunishmentBlock *big.Int `json:"impeachPunishmentBlock,omitempty" toml:"impeachPunishmentBlock,omitempty"` // Rpt punishes impeached proposers, nil for NewRptWithImpeachPunishmentPivotBlockNumber
+	ProxyContractBlock     *big.Int `json:"proxyContractBlock,omitempty"     toml:"proxyContractBlock,omitempty"`     // Calls of registered proxy contracts are redirected
+	ValidatorRegistryBlock *big.Int `json:"validatorRegistryBlock,omitempty" toml:"validatorRegistryBlock,omitempty"` // Validator committee is governed by the validator registry
+}
+
+// PrimitiveConfig enables a primitive contract at an address from a block on.
+type PrimitiveConfig struct {
+	Name    string         `json:"name"          toml:"name"`          // Name the implementation is registered under
+	Address common.Address `json:"address"       toml:"address"`       // Address the contract is called at
+	Block   *big.Int       `json:"block"         toml:"block"`         // Activation block, nil for genesis
+	Gas     uint64         `json:"gas,omitempty" toml:"gas,omitempty"` // Flat gas cost of a call, zero for the implementation's own
 }
 
 // DporConfig is the consensus engine configs for proof-of-authority based sealing.
@@ -374,6 +408,140 @@ func (c *ChainConfig) IsCpchain() bool {
 	return c.ChainID.Uint64() == MainnetChainId
 }
 
+// IsImpeachPunishment returns whether num is either equal to the impeach
+// punishment fork block or greater.
+func (c *ChainConfig) IsImpeachPunishment(num *big.Int) bool {
+	return isForked(c.impeachPunishmentBlock(), num)
+}
+
+// impeachPunishmentBlock returns the impeach punishment fork block, the block
+// the fork was hard coded at for the configs predating it.
+func (c *ChainConfig) impeachPunishmentBlock() *big.Int {
+	if c.ImpeachPunishmentBlock == nil {
+		return big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber)
+	}
+	return c.ImpeachPunishmentBlock
+}
+
+// IsProxyContract returns whether num is either equal to the proxy contract
+// fork block or greater.
+func (c *ChainConfig) IsProxyContract(num *big.Int) bool {
+	return isForked(c.ProxyContractBlock, num)
+}
+
+// IsValidatorRegistry returns whether num is either equal to the validator
+// registry fork block or greater.
+func (c *ChainConfig) IsValidatorRegistry(num *big.Int) bool {
+	return isForked(c.ValidatorRegistryBlock, num)
+}
+
+// ActivePrimitives returns the primitive contracts enabled in the given block,
+// the default ones if the config declares none.
+func (c *ChainConfig) ActivePrimitives(num *big.Int) []PrimitiveConfig {
+	var active []PrimitiveConfig
+	for _, primitive := range c.primitives() {
+		if primitive.Block == nil || primitive.Block.Sign() == 0 || isForked(primitive.Block, num) {
+			active = append(active, primitive)
+		}
+	}
+	return active
+}
+
+func (c *ChainConfig) primitives() []PrimitiveConfig {
+	if c.Primitives == nil {
+		return DefaultPrimitives
+	}
+	return c.Primitives
+}
+
+// CheckCompatible checks whether scheduled fork transitions have been imported
+// with a mismatching chain configuration.
+func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
+	bhead := new(big.Int).SetUint64(height)
+
+	// Iterate checkCompatible to find the lowest conflict.
+	var lasterr *ConfigCompatError
+	for {
+		err := c.checkCompatible(newcfg, bhead)
+		if err == nil || (lasterr != nil && err.RewindTo == lasterr.RewindTo) {
+			break
+		}
+		lasterr = err
+		bhead.SetUint64(err.RewindTo)
+	}
+	return lasterr
+}
+
+func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *ConfigCompatError {
+	if isForkIncompatible(c.impeachPunishmentBlock(), newcfg.impeachPunishmentBlock(), head) {
+		return newCompatError("impeach punishment fork block", c.impeachPunishmentBlock(), newcfg.impeachPunishmentBlock())
+	}
+	if isForkIncompatible(c.ProxyContractBlock, newcfg.ProxyContractBlock, head) {
+		return newCompatError("proxy contract fork block", c.ProxyContractBlock, newcfg.ProxyContractBlock)
+	}
+	if isForkIncompatible(c.ValidatorRegistryBlock, newcfg.ValidatorRegistryBlock, head) {
+		return newCompatError("validator registry fork block", c.ValidatorRegistryBlock, newcfg.ValidatorRegistryBlock)
+	}
+	stored, updated := primitiveBlocks(c.primitives()), primitiveBlocks(newcfg.primitives())
+	for primitive, block := range stored {
+		if isForkIncompatible(block, updated[primitive], head) {
+			return newCompatError("primitive "+primitive.Name+" activation block", block, updated[primitive])
+		}
+	}
+	for primitive, block := range updated {
+		if _, ok := stored[primitive]; !ok && isForkIncompatible(nil, block, head) {
+			return newCompatError("primitive "+primitive.Name+" activation block", nil, block)
+		}
+	}
+	return nil
+}
+
+// primitiveKey identifies a primitive contract across chain configs. The gas is
+// part of it, as changing it changes the execution once the contract is active.
+type primitiveKey struct {
+	Name    string
+	Address common.Address
+	Gas     uint64
+}
+
+// primitiveBlocks returns the activation blocks of the primitive contracts,
+// the genesis ones activated at block zero.
+func primitiveBlocks(primitives []PrimitiveConfig) map[primitiveKey]*big.Int {
+	blocks := make(map[primitiveKey]*big.Int, len(primitives))
+	for _, primitive := range primitives {
+		block := primitive.Block
+		if block == nil {
+			block = new(big.Int)
+		}
+		blocks[primitiveKey{primitive.Name, primitive.Address, primitive.Gas}] = block
+	}
+	return blocks
+}
+
+// isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
+// block s2 because head is already past the fork.
+func isForkIncompatible(s1, s2, head *big.Int) bool {
+	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
+
+func configNumEqual(x, y *big.Int) bool {
+	if x == nil {
+		return y == nil
+	}
+	if y == nil {
+		return x == nil
+	}
+	return x.Cmp(y) == 0
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -395,6 +563,23 @@ type ConfigCompatError struct {
 	RewindTo uint64
 }
 
+func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedblock == nil:
+		rew = newblock
+	case newblock == nil || storedblock.Cmp(newblock) < 0:
+		rew = storedblock
+	default:
+		rew = newblock
+	}
+	err := &ConfigCompatError{what, storedblock, newblock, 0}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindTo = rew.Uint64() - 1
+	}
+	return err
+}
+
 func (err *ConfigCompatError) Error() string {
 	return fmt.Sprintf("mismatching %s in database (have %d, want %d, rewindto %d)", err.What, err.StoredConfig, err.NewConfig, err.RewindTo)
 }
@@ -407,6 +592,8 @@ func (err *ConfigCompatError) Error() string {
 type Rules struct {
 	ChainID   *big.Int
 	IsCpchain bool
+
+	IsImpeachPunishment, IsProxyContract, IsValidatorRegistry bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -415,5 +602,11 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainID == nil {
 		chainID = new(big.Int)
 	}
-	return Rules{ChainID: new(big.Int).Set(chainID), IsCpchain: c.IsCpchain()}
+	return Rules{
+		ChainID:             new(big.Int).Set(chainID),
+		IsCpchain:           c.IsCpchain(),
+		IsImpeachPunishment: c.IsImpeachPunishment(num),
+		IsProxyContract:     c.IsProxyContract(num),
+		IsValidatorRegistry: c.IsValidatorRegistry(num),
+	}
 }
@@ -206,3 +206,124 @@ func TestResolveUrl(t *testing.T) {
 		t.Skip("skip if no hosts mapping")
 	}
 }
+
+func TestForkPredicates(t *testing.T) {
+	cc := ChainConfig{ChainID: big.NewInt(10), ImpeachPunishmentBlock: big.NewInt(5)}
+	assert.False(t, cc.IsImpeachPunishment(big.NewInt(4)))
+	assert.True(t, cc.IsImpeachPunishment(big.NewInt(5)))
+	assert.False(t, cc.IsImpeachPunishment(nil))
+	assert.False(t, cc.IsProxyContract(big.NewInt(1<<40)))
+	assert.False(t, cc.IsValidatorRegistry(big.NewInt(1<<40)))
+	assert.True(t, cc.Rules(big.NewInt(6)).IsImpeachPunishment)
+
+	// Configs predating the impeach punishment fork switch at the legacy pivot
+	legacy := ChainConfig{ChainID: big.NewInt(10)}
+	assert.False(t, legacy.IsImpeachPunishment(big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber-1)))
+	assert.True(t, legacy.IsImpeachPunishment(big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber)))
+}
+
+func TestCheckCompatible(t *testing.T) {
+	type test struct {
+		stored, new *ChainConfig
+		head        uint64
+		wantErr     *ConfigCompatError
+	}
+	tests := []test{
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 0, wantErr: nil},
+		{stored: &ChainConfig{}, new: &ChainConfig{}, head: 100, wantErr: nil},
+		{
+			stored:  &ChainConfig{ImpeachPunishmentBlock: big.NewInt(10)},
+			new:     &ChainConfig{ImpeachPunishmentBlock: big.NewInt(20)},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{ImpeachPunishmentBlock: big.NewInt(10)},
+			new:    &ChainConfig{ImpeachPunishmentBlock: big.NewInt(20)},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "impeach punishment fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{ProxyContractBlock: big.NewInt(20)},
+			head:   25,
+			wantErr: &ConfigCompatError{
+				What:         "proxy contract fork block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
+		{
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{ImpeachPunishmentBlock: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber)},
+			head:    NewRptWithImpeachPunishmentPivotBlockNumber + 10,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{ImpeachPunishmentBlock: big.NewInt(20)},
+			head:   30,
+			wantErr: &ConfigCompatError{
+				What:         "impeach punishment fork block",
+				StoredConfig: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     19,
+			},
+		},
+		{
+			stored: &ChainConfig{ImpeachPunishmentBlock: big.NewInt(30), ProxyContractBlock: big.NewInt(10)},
+			new:    &ChainConfig{ImpeachPunishmentBlock: big.NewInt(25), ProxyContractBlock: big.NewInt(20)},
+			head:   40,
+			wantErr: &ConfigCompatError{
+				What:         "proxy contract fork block",
+				StoredConfig: big.NewInt(10),
+				NewConfig:    big.NewInt(20),
+				RewindTo:     9,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{Primitives: append([]PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50)}}, DefaultPrimitives...)},
+			head:   60,
+			wantErr: &ConfigCompatError{
+				What:         "primitive rank activation block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(50),
+				RewindTo:     49,
+			},
+		},
+		{
+			stored:  &ChainConfig{},
+			new:     &ChainConfig{Primitives: append([]PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50)}}, DefaultPrimitives...)},
+			head:    40,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{Primitives: []PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50)}}},
+			new:    &ChainConfig{Primitives: []PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50), Gas: 100}}},
+			head:   60,
+			wantErr: &ConfigCompatError{
+				What:         "primitive rank activation block",
+				StoredConfig: big.NewInt(50),
+				NewConfig:    nil,
+				RewindTo:     49,
+			},
+		},
+		{
+			stored:  &ChainConfig{Primitives: []PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50)}}},
+			new:     &ChainConfig{Primitives: []PrimitiveConfig{{Name: PrimitiveRank, Address: common.BytesToAddress([]byte{100}), Block: big.NewInt(50), Gas: 100}}},
+			head:    40,
+			wantErr: nil,
+		},
+	}
+	for i, tt := range tests {
+		err := tt.stored.CheckCompatible(tt.new, tt.head)
+		assert.Equal(t, tt.wantErr, err, "test %d", i)
+	}
+}
@@ -39,7 +39,8 @@ var (
 		common.HexToAddress("0xfaf2a2cdc4da310b52ad7d8d86e8c1bd5d4c0bd0"), // #19
 	}
 	mainnetChainConfig = &ChainConfig{
-		ChainID: big.NewInt(MainnetChainId),
+		ChainID:                big.NewInt(MainnetChainId),
+		ImpeachPunishmentBlock: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber),
 		Dpor: &DporConfig{
 			Period:                MainnetBlockPeriod,
 			TermLen:               12,
@@ -40,7 +40,8 @@ var (
 		common.HexToAddress("0xcc9cd266776b331fd424ea14dc30fc8561bec628"), // #19
 	}
 	testMainnetChainConfig = &ChainConfig{
-		ChainID: big.NewInt(TestMainnetChainId),
+		ChainID:                big.NewInt(TestMainnetChainId),
+		ImpeachPunishmentBlock: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber),
 		Dpor: &DporConfig{
 			Period:                TestMainnetBlockPeriod,
 			TermLen:               12,
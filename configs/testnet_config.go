@@ -32,7 +32,8 @@ var (
 		common.HexToAddress("0x2661177788fe63888e93cf18b5e4e31306a01170"), // #6
 	}
 	testnetChainConfig = &ChainConfig{
-		ChainID: big.NewInt(TestnetChainId),
+		ChainID:                big.NewInt(TestnetChainId),
+		ImpeachPunishmentBlock: big.NewInt(NewRptWithImpeachPunishmentPivotBlockNumber),
 		Dpor: &DporConfig{
 			Period:                TestnetBlockPeriod,
 			TermLen:               4,
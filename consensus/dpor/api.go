@@ -1,12 +1,25 @@
 package dpor
 
 import (
+	"errors"
+
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor/finality"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var (
+	// errInvalidCheckpoint is returned if a finality proof is requested from
+	// a checkpoint past the block.
+	errInvalidCheckpoint = errors.New("checkpoint past the block")
+
+	// errCommitteeNotHandedOver is returned if the committee of a block was not
+	// handed over in the headers since the checkpoint.
+	errCommitteeNotHandedOver = errors.New("committee not handed over on chain")
+)
+
 // API is a user facing RPC API to allow controlling the signer and voting
 // mechanisms of the proof-of-authority scheme.
 type API struct {
@@ -58,6 +71,14 @@ func (api *API) GetProposers(number rpc.BlockNumber) ([]common.Address, error) {
 
 // GetValidators retrieves the Validators at a given block.
 func (api *API) GetValidators(number rpc.BlockNumber) ([]common.Address, error) {
+	// Retrieve the requested block number (or current if none requested)
+	if number < 0 {
+		header := api.chain.CurrentHeader()
+		if header == nil {
+			return nil, errUnknownBlock
+		}
+		number = rpc.BlockNumber(header.Number.Int64())
+	}
 	return api.dpor.ValidatorsOf(uint64(number))
 }
 
@@ -65,3 +86,76 @@ func (api *API) GetValidators(number rpc.BlockNumber) ([]common.Address, error)
 func (api *API) GetRNodes() ([]common.Address, error) {
 	return api.dpor.GetRNodes()
 }
+
+// GetFinalityProof returns a self-contained proof that the block with the given
+// number is final, along the committee hand-overs since the given checkpoint,
+// the genesis if none. Proofs are checked with the finality package.
+func (api *API) GetFinalityProof(number rpc.BlockNumber, checkpoint *rpc.BlockNumber) (*finality.Proof, error) {
+	var header *types.Header
+	if number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	var from uint64
+	if checkpoint != nil {
+		if *checkpoint < 0 || uint64(*checkpoint) > header.Number.Uint64() {
+			return nil, errInvalidCheckpoint
+		}
+		from = uint64(*checkpoint)
+	}
+	start := api.chain.GetHeaderByNumber(from)
+	if start == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.dpor.dh.snapshot(api.dpor, api.chain, from, start.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		params    = finality.Params{TermLen: api.dpor.config.TermLen, ViewLen: api.dpor.config.ViewLen, HandoverDelay: ValidatorsHandoverDelay}
+		committee = snap.ValidatorsOf(from)
+		handovers []*types.Header
+	)
+	// Hand-overs count from the term they take effect in, after the delay
+	for term := params.TermOf(from); term+1+params.HandoverDelay <= params.TermOf(header.Number.Uint64()); term++ {
+		last := api.chain.GetHeaderByNumber(snap.StartBlockNumberOfTerm(term + 1))
+		if last == nil {
+			return nil, errUnknownBlock
+		}
+		if len(last.Dpor.Validators) != 0 && !equalAddresses(last.Dpor.Validators, committee) {
+			handovers = append(handovers, last)
+			committee = last.Dpor.CopyValidators()
+		}
+	}
+	// The proof only holds if the committee the node verified the block with
+	// is the one handed over on chain
+	snap, err = api.dpor.dh.snapshot(api.dpor, api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !equalAddresses(snap.ValidatorsOf(header.Number.Uint64()), committee) {
+		return nil, errCommitteeNotHandedOver
+	}
+	return &finality.Proof{
+		Header:     header,
+		Validators: committee,
+		Checkpoint: from,
+		Handovers:  handovers,
+	}, nil
+}
+
+func equalAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -200,13 +200,20 @@ func (d *Dialer) UpdateRemoteProposers(term uint64, proposers []common.Address)
 	return nil
 }
 
-// dialAllRemoteValidators tries to dial all remote validators
+// dialAllRemoteValidators tries to dial all remote validators, the default ones
+// and those the validator registry hands the committee over to
 func (d *Dialer) dialAllRemoteValidators(term uint64) {
 
 	validators := d.ValidatorsOfTerm(term)
 
+	enodes := make([]string, 0, len(d.defaultValidators))
+	enodes = append(enodes, d.defaultValidators...)
+	if d.dpor != nil {
+		enodes = append(enodes, d.dpor.ValidatorEnodesOf(term)...)
+	}
+
 	// dial validators not connected yet
-	for _, validatorID := range d.defaultValidators {
+	for _, validatorID := range enodes {
 		node, err := discover.ParseNode(validatorID)
 		if err != nil {
 			continue
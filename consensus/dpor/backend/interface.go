@@ -207,6 +207,10 @@ type DporService interface {
 	// ProposerOf returns the proposer of the specified block number by rpt and election calculation
 	ProposerOf(number uint64) (common.Address, error)
 
+	// ValidatorEnodesOf returns enode urls of validators of the given term and the terms scheduled after it
+	// registered in the validator registry
+	ValidatorEnodesOf(term uint64) []string
+
 	// ValidatorsOfTerm returns the list of validators in committee for the specified term
 	ValidatorsOfTerm(term uint64) ([]common.Address, error)
 
@@ -22,6 +22,7 @@ import (
 	"math/big"
 	"time"
 
+	"bitbucket.org/cpchain/chain/accounts"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
@@ -93,11 +94,6 @@ var (
 	// errVerifyUncleNotAllowed is returned when verify uncle block.
 	errVerifyUncleNotAllowed = errors.New("uncles not allowed")
 
-	// errWaitTransactions is returned if an empty block is attempted to be sealed
-	// on an instant chain (0 second period). It's important to refuse these as the
-	// block reward is zero, so an empty block just bloats the chain... fast.
-	errWaitTransactions = errors.New("waiting for transactions")
-
 	errInvalidStateForSign = errors.New("the state is unexpected for signing header")
 )
 
@@ -166,6 +162,9 @@ func (d *Dpor) PrepareBlock(chain consensus.ChainReader, header *types.Header) e
 		header.Dpor.Proposers = append(header.Dpor.Proposers, proposer)
 	}
 
+	// Hand the validators committee in the validator registry over at checkpoints
+	header.Dpor.Validators = d.handoverOf(chain, snap, header)
+
 	log.Debug("prepare a block", "number", header.Number.Uint64(), "proposers", header.Dpor.ProposersFormatText(),
 		"validators", header.Dpor.ValidatorsFormatText())
 
@@ -277,9 +276,23 @@ func (d *Dpor) Finalize(chain consensus.ChainReader, header *types.Header, state
 // Authorize injects a private key into the consensus engine to mint new blocks
 // with.
 func (d *Dpor) Authorize(signer common.Address, signFn backend.SignFn) {
+	d.authorize(signer, signFn, nil)
+}
+
+// AuthorizeWallet is like Authorize, signing with the wallet. Headers and
+// handshake macs are handed to wallets supporting accounts.SealSigner as they
+// are, so that the wallet can check what it signs.
+func (d *Dpor) AuthorizeWallet(signer common.Address, wallet accounts.Wallet) {
+	sealer, _ := wallet.(accounts.SealSigner)
+	d.authorize(signer, wallet.SignHash, sealer)
+}
+
+// authorize sets the signer fields and starts the handler of the signer.
+func (d *Dpor) authorize(signer common.Address, signFn backend.SignFn, sealer accounts.SealSigner) {
 	d.coinbaseLock.Lock()
 	d.coinbase = signer
 	d.signFn = signFn
+	d.sealer = sealer
 	d.coinbaseLock.Unlock()
 
 	if d.handler == nil {
@@ -299,7 +312,6 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 		number = header.Number.Uint64()
 
 		coinbase = d.Coinbase()
-		signFn   = d.SignHash
 	)
 
 	// Sealing the genesis block is not supported
@@ -309,7 +321,7 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 
 	// For 0-period chains, refuse to seal empty blocks (no reward but would spin sealing)
 	if d.config.Period == 0 && len(block.Transactions()) == 0 {
-		return nil, errWaitTransactions
+		return nil, consensus.ErrWaitTransactions
 	}
 
 	// Bail out if we're unauthorized to sign a block
@@ -346,7 +358,7 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 	}
 
 	// Proposer seals the block with signature
-	sighash, err := signFn(d.dh.sigHash(header).Bytes())
+	sighash, err := d.signHeaderHash(header, consensus.Commit)
 	if err != nil {
 		return nil, err
 	}
@@ -354,6 +366,13 @@ func (d *Dpor) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan
 
 	// Create a signature space for validators
 	header.Dpor.Sigs = make([]types.DporSignature, len(header.Dpor.Validators))
+
+	// The dev sealer is the whole validators committee, sign it right away
+	if d.Mode() == DevMode {
+		if err := d.dh.signHeader(d, chain, header, consensus.Commit); err != nil {
+			return nil, err
+		}
+	}
 	log.Debug("sealed the block", "hash", header.Hash().Hex(), "number", header.Number)
 
 	// Update dpor current snapshot
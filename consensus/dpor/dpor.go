@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/admission"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
@@ -16,6 +17,7 @@ import (
 	"bitbucket.org/cpchain/chain/consensus/dpor/campaign"
 	"bitbucket.org/cpchain/chain/consensus/dpor/rnode"
 	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"bitbucket.org/cpchain/chain/consensus/dpor/validators"
 	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -36,6 +38,10 @@ type SyncFromPeerFn func(p *p2p.Peer)
 // SyncFromBestPeerFn tries to sync blocks from best peer
 type SyncFromBestPeerFn func()
 
+// StateCallerFn returns a contract caller executing calls on the state of the
+// block with given hash and number
+type StateCallerFn func(hash common.Hash, number uint64) (bind.ContractCaller, error)
+
 const (
 	inMemorySnapshots  = 200 // Number of recent vote snapshots to keep in memory
 	inMemorySignatures = 100 // Number of recent block signatures to keep in memory
@@ -50,6 +56,7 @@ const (
 	FakeMode
 	DoNothingFakeMode
 	PbftFakeMode
+	DevMode
 )
 
 // Dpor is the proof-of-reputation consensus engine proposed to support the
@@ -68,9 +75,10 @@ type Dpor struct {
 	currentSnap     *DporSnapshot // Current snapshot
 	currentSnapLock sync.RWMutex
 
-	coinbase     common.Address // Coinbase of the miner(proposer or validator)
-	signFn       backend.SignFn // Sign function to authorize hashes with
-	coinbaseLock sync.RWMutex   // Protects the signer fields
+	coinbase     common.Address      // Coinbase of the miner(proposer or validator)
+	signFn       backend.SignFn      // Sign function to authorize hashes with
+	sealer       accounts.SealSigner // Wallet signing headers and macs from their content, if supported
+	coinbaseLock sync.RWMutex        // Protects the signer fields
 
 	handler *backend.Handler
 
@@ -89,9 +97,11 @@ type Dpor struct {
 
 	ac admission.ApiBackend
 
-	rNodeBackend    rnode.RNodeService
-	rptBackend      rpt.RptService
-	campaignBackend campaign.CandidateService
+	rNodeBackend     rnode.RNodeService
+	rptBackend       rpt.RptService
+	campaignBackend  campaign.CandidateService
+	validatorBackend validators.ValidatorService
+	stateCaller      StateCallerFn
 
 	chain consensus.ChainReadWriter
 
@@ -122,6 +132,25 @@ func (d *Dpor) SignHash(hash []byte) ([]byte, error) {
 	return d.signFn(account, hash)
 }
 
+// signHeaderHash signs the seal hash of a header, prefixed as required by the
+// given validator state. Wallets that sign consensus messages from their
+// content are handed the header instead of the hash.
+func (d *Dpor) signHeaderHash(header *types.Header, state consensus.State) ([]byte, error) {
+	d.coinbaseLock.Lock()
+	account, sealer := accounts.Account{Address: d.coinbase}, d.sealer
+	d.coinbaseLock.Unlock()
+
+	if sealer != nil {
+		prepare := state == consensus.Prepare || state == consensus.ImpeachPrepare
+		return sealer.SignSeal(account, header, prepare)
+	}
+	hash, err := hashBytesWithState(d.dh.sigHash(header).Bytes(), state)
+	if err != nil {
+		return nil, err
+	}
+	return d.SignHash(hash)
+}
+
 // IsMiner returns if local coinbase is a miner(proposer or validator)
 func (d *Dpor) IsMiner() bool {
 	d.isMinerLock.RLock()
@@ -259,6 +288,14 @@ func NewPbftFaker(config *configs.DporConfig, db database.Database) *Dpor {
 	return d
 }
 
+// NewDevSealer creates a dpor sealing blocks alone for a developer chain, the
+// coinbase is the only proposer and validator and signs blocks on sealing.
+func NewDevSealer(config *configs.DporConfig, db database.Database) *Dpor {
+	d := New(config, db)
+	d.mode = DevMode
+	return d
+}
+
 // SetHandler sets dpor.handler
 func (d *Dpor) SetHandler(handler *backend.Handler) error {
 	d.handler = handler
@@ -324,6 +361,14 @@ func (d *Dpor) initMinerAndValidator(blockchain consensus.ChainReadWriter, serve
 	d.pmSyncFromPeerFn = pmSyncFromPeerFn
 	d.pmSyncFromBestPeerFn = pmSyncFromBestPeerFn
 
+	// The dev sealer signs its blocks itself, no validator to talk to
+	if d.Mode() == DevMode {
+		header := d.chain.CurrentHeader()
+		snap, _ := d.dh.snapshot(d, d.chain, header.Number.Uint64(), header.Hash(), nil)
+		d.SetCurrentSnap(snap)
+		return
+	}
+
 	var (
 		faulty  = d.config.FaultyNumber
 		handler = d.handler
@@ -365,6 +410,11 @@ func (d *Dpor) StopMining() {
 	}
 	atomic.StoreInt32(&d.runningMiner, 0)
 
+	// the handler of the dev sealer was never started
+	if d.Mode() == DevMode {
+		return
+	}
+
 	d.handler.Stop()
 	return
 }
@@ -407,8 +457,8 @@ func (d *Dpor) SetupAdmission(ac admission.ApiBackend) {
 	d.ac = ac
 }
 
-func (d *Dpor) SetRptBackend(rptContract common.Address, backend backend.ClientBackend) {
-	d.rptBackend, _ = rpt.NewRptService(rptContract, backend)
+func (d *Dpor) SetRptBackend(config *configs.ChainConfig, rptContract common.Address, backend backend.ClientBackend) {
+	d.rptBackend, _ = rpt.NewRptService(config, rptContract, backend)
 }
 
 func (d *Dpor) GetRptBackend() rpt.RptService {
@@ -423,6 +473,52 @@ func (d *Dpor) GetCandidateBackend() campaign.CandidateService {
 	return d.campaignBackend
 }
 
+// SetValidatorBackend setups the validator registry, read from the states
+// stateCaller executes calls on.
+func (d *Dpor) SetValidatorBackend(validatorsContract common.Address, backend backend.ClientBackend, stateCaller StateCallerFn) {
+	d.validatorBackend, _ = validators.NewValidatorService(validatorsContract, backend)
+	d.stateCaller = stateCaller
+}
+
+func (d *Dpor) GetValidatorBackend() validators.ValidatorService {
+	return d.validatorBackend
+}
+
+// handoverOf returns the validators committee in the validator registry the
+// header hands over, nil if it is not a checkpoint or the committee is unchanged.
+// The registry is read on the state of the parent block, the same for every
+// node whatever its own head.
+func (d *Dpor) handoverOf(chain consensus.ChainReader, snap *DporSnapshot, header *types.Header) []common.Address {
+	if d.validatorBackend == nil || d.stateCaller == nil || snap == nil {
+		return nil
+	}
+	if config := chain.Config(); config == nil || !config.IsValidatorRegistry(header.Number) {
+		return nil
+	}
+	if header.Impeachment() || !backend.IsCheckPoint(header.Number.Uint64(), d.config.TermLen, d.config.ViewLen) {
+		return nil
+	}
+
+	caller, err := d.stateCaller(header.ParentHash, header.Number.Uint64()-1)
+	if err != nil {
+		log.Warn("failed to read the state of the parent block", "number", header.Number, "parent", header.ParentHash, "err", err)
+		return nil
+	}
+	validators, err := d.validatorBackend.ValidatorsAt(caller)
+	if err != nil {
+		log.Warn("failed to read validators from validator registry", "err", err)
+		return nil
+	}
+
+	handover := types.CopyHeader(header)
+	handover.Dpor.Validators = validators
+	if err := snap.verifyHandover(handover); err != nil {
+		log.Debug("validators in validator registry can not be handed over", "number", header.Number, "err", err)
+		return nil
+	}
+	return validators
+}
+
 func (d *Dpor) SetRNodeBackend(rNodeContract common.Address, backend backend.ClientBackend) {
 	d.rNodeBackend, _ = rnode.NewRNodeService(rNodeContract, backend)
 }
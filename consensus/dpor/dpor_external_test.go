@@ -160,7 +160,7 @@ func Test_SetContractBackends(t *testing.T) {
 	if err != nil {
 		log.Fatal(err.Error())
 	}
-	d.SetRptBackend(rptAddr, backend)
+	d.SetRptBackend(blockchain.Config(), rptAddr, backend)
 	rptBackend := d.GetRptBackend()
 	equalSigner := reflect.DeepEqual(nil, rptBackend)
 	if equalSigner {
@@ -254,7 +254,7 @@ func TestUpdateRpts(t *testing.T) {
 	snapshot.Candidates = recentC
 	accounts := generateABatchAccounts(8)
 	contractAddr, _, backend := newBlockchainWithDb(60, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts, err := snapshot.UpdateRpts(rptInstance)
 	if err != nil {
 		t.Error("UpdateRpts has some problems...", err)
@@ -298,7 +298,7 @@ func TestDporSnapshot_updateProposersTermlen4(t *testing.T) {
 
 	accounts := generateABatchAccounts(8)
 	contractAddr, _, backend := newBlockchainWithDb(60, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts := rptInstance.CalcRptInfoList(accounts, 6)
 
 	term := snapshot.FutureTermOf(snapshot.Number)
@@ -320,7 +320,7 @@ func TestDporSnapshot_updateProposersTermlen12(t *testing.T) {
 	//account num >=8
 	accounts := generateABatchAccounts(3)
 	contractAddr, _, backend := newBlockchainWithDb(60, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts := rptInstance.CalcRptInfoList(accounts, 6)
 
 	term := snapshot.FutureTermOf(snapshot.Number)
@@ -349,7 +349,7 @@ func TestSnapshot_applyHeader(t *testing.T) {
 
 	accounts := generateABatchAccounts(20)
 	rptAddr, campaignAddr, backend := newBlockchainWithDb(100, accounts)
-	rptInstance, _ := rpt.NewRptService(rptAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, rptAddr, backend)
 	campaignInstance, _ := campaign.NewCampaignService(campaignAddr, backend)
 	ifUpdateCommittee := true
 	snapshot.ApplyHeader(header, ifUpdateCommittee, campaignInstance, rptInstance)
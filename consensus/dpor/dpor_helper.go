@@ -23,6 +23,7 @@ import (
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor/backend"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -51,9 +52,17 @@ type defaultDporHelper struct {
 // validateBlock checks basic fields in a block, this is called only by validators
 func (dh *defaultDporHelper) validateBlock(c *Dpor, chain consensus.ChainReader, block *types.Block, verifySigs bool, verifyProposers bool) error {
 
-	// verify the `validators` field in the header is empty
-	if len(block.Header().Dpor.Validators) != 0 {
-		return consensus.ErrorInvalidValidatorsList
+	// verify the `validators` field in the header hands over the committee read
+	// from the validator registry if a handover is due, and is empty otherwise
+	validators := block.Header().Dpor.Validators
+	if len(validators) != 0 || backend.IsCheckPoint(block.NumberU64(), c.config.TermLen, c.config.ViewLen) {
+		snap, err := dh.snapshot(c, chain, block.NumberU64()-1, block.ParentHash(), nil)
+		if err != nil {
+			return err
+		}
+		if !equalAddresses(validators, c.handoverOf(chain, snap, block.Header())) {
+			return consensus.ErrorInvalidValidatorsList
+		}
 	}
 
 	// verify the block header according to Dpor Protocol
@@ -202,6 +211,21 @@ func (dh *defaultDporHelper) verifyBasic(dpor *Dpor, chain consensus.ChainReader
 		return ErrInvalidGasLimit
 	}
 
+	// Ensure that the validators committee handed over is valid
+	if len(header.Dpor.Validators) != 0 {
+		if config := chain.Config(); config == nil || !config.IsValidatorRegistry(header.Number) {
+			return consensus.ErrorInvalidValidatorsList
+		}
+		snap, err := dh.snapshot(dpor, chain, number-1, header.ParentHash, parents)
+		if err != nil {
+			return err
+		}
+		if err := snap.verifyHandover(header); err != nil {
+			log.Debug("invalid validators handover", "number", number, "hash", hash, "err", err)
+			return consensus.ErrorInvalidValidatorsList
+		}
+	}
+
 	if isImpeach {
 		return dh.verifyBasicImpeach(dpor, chain, header, parent)
 	}
@@ -401,13 +425,26 @@ func (dh *defaultDporHelper) snapshot(dpor *Dpor, chain consensus.ChainReader, n
 				validators = genesis.Dpor.CopyValidators()
 			}
 			snap = newSnapshot(dpor.config, 0, genesis.Hash(), proposers, validators, dpor.Mode())
+
+			// A custom genesis, as of a generated network, carries its own
+			// validators committee in place of the default one of the run mode
+			if dpor.Mode() == NormalMode && len(validators) != 0 && !equalAddresses(validators, configs.Validators()) {
+				snap.setHandover(0, validators)
+			}
 			break
 		}
 
-		// if numberIter is equal to #(number - 12*3*(2+2)), then create a snap, then apply a batch of headers to it
-		if numberIter == number-(dpor.TermLength()*dpor.ViewLength()*(TermDistBetweenElectionAndMining+2)) && number > dpor.config.MaxInitBlockNumber {
+		// if numberIter is equal to #(number - 12*3*(2+2)), then create a snap, then apply a batch of headers to it.
+		// Committees of the dev sealer are only carried over from genesis, it always replays from there.
+		if numberIter == number-(dpor.TermLength()*dpor.ViewLength()*(TermDistBetweenElectionAndMining+2)) && number > dpor.config.MaxInitBlockNumber && dpor.Mode() != DevMode {
 			snap = newSnapshot(dpor.config, numberIter, hash, nil, nil, dpor.Mode())
 			log.Debug("created a new snapshot at some previous term ago", "number", numberIter, "hash", hash.Hex())
+
+			// Committees handed over in older checkpoints are not in the headers
+			// applied, they are restored from the last checkpoint
+			if handovers := checkpointHandovers(dpor, chain, numberIter, hash); len(handovers) > 0 {
+				snap.Handovers = handovers
+			}
 		}
 
 		// No Snapshot for this header, gather the header and move backward
@@ -467,10 +504,24 @@ func (dh *defaultDporHelper) snapshot(dpor *Dpor, chain consensus.ChainReader, n
 
 	applyStartTime := time.Now()
 
-	// Apply headers to the snapshot and updates RPTs
-	newSnap, err := snap.apply(headers, timeToUpdateCommittee, candidateService, rptService)
-	if err != nil {
-		return nil, err
+	// Apply headers to the snapshot and updates RPTs, up to every checkpoint in
+	// turn to store the committees handed over there
+	newSnap := snap
+	for len(headers) > 0 {
+		n := 1
+		for n < len(headers) && !backend.IsCheckPoint(headers[n-1].Number.Uint64(), dpor.config.TermLen, dpor.config.ViewLen) {
+			n++
+		}
+		var err error
+		if newSnap, err = newSnap.apply(headers[:n], timeToUpdateCommittee, candidateService, rptService); err != nil {
+			return nil, err
+		}
+		if backend.IsCheckPoint(newSnap.number(), dpor.config.TermLen, dpor.config.ViewLen) {
+			if err := newSnap.storeHandovers(dpor.db); err != nil {
+				log.Warn("failed to store validators handovers", "number", newSnap.number(), "err", err)
+			}
+		}
+		headers = headers[n:]
 	}
 
 	log.Debug("now created a new snap", "number", newSnap.number(), "hash", newSnap.hash().Hex(), "apply elapsed", common.PrettyDuration(time.Now().Sub(applyStartTime)))
@@ -482,7 +533,24 @@ func (dh *defaultDporHelper) snapshot(dpor *Dpor, chain consensus.ChainReader, n
 		dpor.SetCurrentSnap(newSnap)
 	}
 
-	return newSnap, err
+	return newSnap, nil
+}
+
+// checkpointHandovers returns the validators committees handed over as of the
+// last checkpoint before the block with the given number and hash, as stored
+// when its snapshot was made.
+func checkpointHandovers(dpor *Dpor, chain consensus.ChainReader, number uint64, hash common.Hash) map[uint64][]common.Address {
+	for number > 0 {
+		header := chain.GetHeader(hash, number)
+		if header == nil {
+			return nil
+		}
+		number, hash = number-1, header.ParentHash
+		if backend.IsCheckPoint(number, dpor.config.TermLen, dpor.config.ViewLen) {
+			return loadHandovers(dpor.db, hash)
+		}
+	}
+	return nil
 }
 
 // verifySeal checks whether the dpor seal is signature of a correct proposer.
@@ -679,15 +747,8 @@ func (dh *defaultDporHelper) signHeader(dpor *Dpor, chain consensus.ChainReader,
 			return errMultiBlocksInOneHeight
 		}
 
-		// get hash with state
-		hashToSign, err := hashBytesWithState(dpor.dh.sigHash(header).Bytes(), state)
-		if err != nil {
-			log.Warn("failed to get hash bytes with state", "number", number, "hash", hash.Hex(), "state", state)
-			return err
-		}
-
-		// Sign it
-		sighash, err := dpor.SignHash(hashToSign)
+		// Sign it with state
+		sighash, err := dpor.signHeaderHash(header, state)
 		if err != nil {
 			log.Warn("signing block header failed", "error", err)
 			return err
@@ -26,6 +26,7 @@ import (
 
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -453,3 +454,72 @@ func Test_defaultDporHelper_verifySignatures(t *testing.T) {
 		t.Error("Call verifySignatures check status failed,,,")
 	}
 }
+
+// handoverChain serves a chain of headers, with a head far ahead so that no
+// election is run while applying them.
+type handoverChain struct {
+	consensus.ChainReader
+	headers []*types.Header
+}
+
+func (c *handoverChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if number < uint64(len(c.headers)) && c.headers[number].Hash() == hash {
+		return c.headers[number]
+	}
+	return nil
+}
+
+func (c *handoverChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(c.headers)) {
+		return c.headers[number]
+	}
+	return nil
+}
+
+func (c *handoverChain) KnownHead() (common.Hash, uint64) {
+	return common.Hash{}, 1 << 20
+}
+
+func TestSnapshotHandoverAfterRestart(t *testing.T) {
+	var (
+		config  = &configs.DporConfig{Period: 3, TermLen: 3, ViewLen: 3, FaultyNumber: 2}
+		current = configs.Validators()
+		next    = append([]common.Address{common.HexToAddress("0x05")}, current[1:]...)
+		db      = database.NewMemDatabase()
+		chain   = new(handoverChain)
+	)
+	genesis := &types.Header{Number: big.NewInt(0)}
+	genesis.Dpor.Proposers = getProposerAddress()
+	genesis.Dpor.Validators = current
+	chain.headers = append(chain.headers, genesis)
+	for number := uint64(1); number <= 60; number++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(number), ParentHash: chain.headers[number-1].Hash(), Coinbase: getProposerAddress()[0]}
+		if number == 9 {
+			header.Dpor.Validators = next
+		}
+		chain.headers = append(chain.headers, header)
+	}
+	head := chain.headers[60]
+
+	// Follow the chain block by block, as when verifying it
+	d := New(config, db)
+	for _, header := range chain.headers {
+		if _, err := d.dh.snapshot(d, chain, header.Number.Uint64(), header.Hash(), nil); err != nil {
+			t.Fatalf("failed to make snapshot %d: %v", header.Number, err)
+		}
+	}
+	snap, _ := d.dh.snapshot(d, chain, 60, head.Hash(), nil)
+	if validators := snap.ValidatorsOf(60); !reflect.DeepEqual(validators, next) {
+		t.Fatalf("validators mismatch: have %x, want %x", validators, next)
+	}
+
+	// After a restart, the snapshot is rebuilt from a few terms ago only
+	restarted := New(config, db)
+	snap, err := restarted.dh.snapshot(restarted, chain, 60, head.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to rebuild snapshot: %v", err)
+	}
+	if validators := snap.ValidatorsOf(60); !reflect.DeepEqual(validators, next) {
+		t.Fatalf("validators handed over lost after restart: have %x, want %x", validators, next)
+	}
+}
@@ -164,6 +164,35 @@ func (d *Dpor) IsProposerOf(addr common.Address, number uint64) (bool, error) {
 	return p == addr, err
 }
 
+// ValidatorEnodesOf returns enode urls of validators of given term and the terms a handover is scheduled for,
+// as registered in the validator registry
+func (d *Dpor) ValidatorEnodesOf(term uint64) []string {
+	snap := d.currentSnap
+	if snap == nil || d.validatorBackend == nil {
+		return nil
+	}
+
+	var (
+		enodes []string
+		seen   = make(map[common.Address]bool)
+	)
+	for t := term; t <= term+ValidatorsHandoverDelay+1; t++ {
+		for _, v := range snap.getRecentValidators(t) {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+
+			enode, err := d.validatorBackend.EnodeOf(v)
+			if err != nil || enode == "" {
+				continue
+			}
+			enodes = append(enodes, enode)
+		}
+	}
+	return enodes
+}
+
 // ValidatorsOfTerm returns validators of given term
 // TODO: this only returns validators known recently from cache,
 // does not retrieve block from local chain to get needed information.
@@ -411,16 +440,25 @@ func (d *Dpor) GetMac() (mac string, sig []byte, err error) {
 	split := "|"
 	mac = prefix + split + t
 
+	log.Debug("generated mac", "mac", mac)
+
+	// let wallets checking what they sign hash it themselves
+	d.coinbaseLock.RLock()
+	account, sealer := accounts.Account{Address: d.coinbase}, d.sealer
+	d.coinbaseLock.RUnlock()
+	if sealer != nil {
+		sig, err = sealer.SignMac(account, mac)
+		return mac, sig, err
+	}
+
 	// make a hash for it
 	var hash common.Hash
 	hasher := sha3.NewKeccak256()
 	hasher.Write([]byte(mac))
 	hasher.Sum(hash[:0])
 
-	log.Debug("generated mac", "mac", mac)
-
 	// sign it!
-	sig, err = d.signFn(accounts.Account{Address: d.Coinbase()}, hash.Bytes())
+	sig, err = d.signFn(account, hash.Bytes())
 
 	return mac, sig, err
 }
@@ -447,6 +485,10 @@ func (d *Dpor) IsDefaultProposer(address common.Address) bool {
 
 // IsCurrentOrFutureProposer checks if an address is a proposer in the period between current term and future term
 func (d *Dpor) IsCurrentOrFutureProposer(address common.Address) bool {
+	// nobody else waits for blocks of a developer chain
+	if d.Mode() == DevMode {
+		return false
+	}
 	if d.Mode() != NormalMode {
 		return true
 	}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/consensus"
 	"bitbucket.org/cpchain/chain/core"
@@ -231,3 +232,20 @@ func TestDpor_CheckStatus(t *testing.T) {
 		t.Error("Check Status failed...")
 	}
 }
+
+func TestAPI_GetValidatorsLatest(t *testing.T) {
+	validators := []common.Address{common.HexToAddress("0xe94b7b6c5a0e526a4d97f9768ad6097bde25c62a")}
+	dpor := NewDpor(&configs.DporConfig{Period: 3, TermLen: 12, ViewLen: 3, MaxInitBlockNumber: DefaultMaxInitBlockNumber}, 20, common.Hash{}, nil, validators, FakeMode)
+	dpor.chain = newBlockchain(20)
+	api := &API{chain: dpor.chain, dpor: dpor}
+
+	for _, number := range []rpc.BlockNumber{rpc.LatestBlockNumber, rpc.PendingBlockNumber, 20} {
+		have, err := api.GetValidators(number)
+		if err != nil {
+			t.Fatalf("block %d: failed to get validators: %v", number, err)
+		}
+		if !reflect.DeepEqual(have, validators) {
+			t.Errorf("block %d: validators mismatch: have %x, want %x", number, have, validators)
+		}
+	}
+}
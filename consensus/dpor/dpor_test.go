@@ -25,7 +25,10 @@ import (
 	"time"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/consensus/dpor/validators"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -209,3 +212,94 @@ func generateNAH() (number uint64, hash common.Hash) {
 	hash = common.BytesToHash(numberToBytes(number))
 	return
 }
+
+type registryReader struct {
+	consensus.ChainReader
+}
+
+func (*registryReader) Config() *configs.ChainConfig {
+	return &configs.ChainConfig{ValidatorRegistryBlock: big.NewInt(0)}
+}
+
+type registryCaller struct {
+	bind.ContractCaller
+	hash common.Hash
+}
+
+type registryValidators struct {
+	validators.ValidatorService
+	callers   []bind.ContractCaller
+	committee []common.Address // Committee in the registry, validator1 alone if nil
+}
+
+func (r *registryValidators) ValidatorsAt(caller bind.ContractCaller) ([]common.Address, error) {
+	r.callers = append(r.callers, caller)
+	if r.committee != nil {
+		return r.committee, nil
+	}
+	return []common.Address{validator1}, nil
+}
+
+func TestHandoverOfReadsParentState(t *testing.T) {
+	var (
+		config   = &configs.DporConfig{TermLen: 4, ViewLen: 3}
+		registry = new(registryValidators)
+		parent   = common.HexToHash("0x01")
+		header   = &types.Header{Number: big.NewInt(12), ParentHash: parent, Coinbase: addr1}
+		snap     = newSnapshot(config, 11, parent, nil, nil, NormalMode)
+	)
+	d := &Dpor{
+		config:           config,
+		validatorBackend: registry,
+		stateCaller: func(hash common.Hash, number uint64) (bind.ContractCaller, error) {
+			if hash != parent || number != 11 {
+				return nil, fmt.Errorf("state of %d %x read, want parent %d %x", number, hash, 11, parent)
+			}
+			return &registryCaller{hash: hash}, nil
+		},
+	}
+	d.handoverOf(&registryReader{}, snap, header)
+	if len(registry.callers) != 1 {
+		t.Fatalf("registry reads mismatch: have %d, want 1", len(registry.callers))
+	}
+	if caller, ok := registry.callers[0].(*registryCaller); !ok || caller.hash != parent {
+		t.Fatalf("registry read on the wrong state: %v", registry.callers[0])
+	}
+
+	// Registry is not read from another state if the parent one is missing
+	header.ParentHash = common.HexToHash("0x02")
+	if validators := d.handoverOf(&registryReader{}, snap, header); validators != nil {
+		t.Fatalf("validators handed over without the parent state: %v", validators)
+	}
+	if len(registry.callers) != 1 {
+		t.Fatalf("registry read without the parent state")
+	}
+}
+
+func TestValidateBlockRequiresDueHandover(t *testing.T) {
+	var (
+		config   = &configs.DporConfig{TermLen: 4, ViewLen: 3, FaultyNumber: 1}
+		current  = getValidatorAddress()
+		next     = append([]common.Address{common.HexToAddress("0x05")}, current[1:]...)
+		registry = &registryValidators{committee: next}
+		parent   = common.HexToHash("0x01")
+		snap     = newSnapshot(config, 11, parent, nil, current, FakeMode)
+	)
+	d := &Dpor{
+		config:           config,
+		dh:               &defaultDporHelper{},
+		currentSnap:      snap,
+		validatorBackend: registry,
+		stateCaller: func(hash common.Hash, number uint64) (bind.ContractCaller, error) {
+			return &registryCaller{hash: hash}, nil
+		},
+	}
+	// A checkpoint must hand the committee in the registry over, not skip it
+	for i, validators := range [][]common.Address{nil, current, append([]common.Address{common.HexToAddress("0x06")}, current[1:]...)} {
+		header := &types.Header{Number: big.NewInt(12), ParentHash: parent, Coinbase: addr1}
+		header.Dpor.Validators = validators
+		if err := d.dh.validateBlock(d, &registryReader{}, types.NewBlockWithHeader(header), false, false); err != consensus.ErrorInvalidValidatorsList {
+			t.Errorf("case %d: error mismatch: have %v, want %v", i, err, consensus.ErrorInvalidValidatorsList)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package finality verifies that a block is final under the LBFT consensus
+// of dpor, starting from a validator committee the verifier trusts.
+//
+// A block is final once a quorum of the validators of its term committed to
+// it. The committee of a term is handed over at the last block of the term
+// before, which lists the next committee and holds the commit quorum of the
+// current one. A proof thus carries the header, its committee and the chain
+// of hand-over headers from the trusted checkpoint on.
+//
+// The package depends on the block types only, so that bridges and auditors
+// can check proofs without running a node or trusting one.
+package finality
+
+import (
+	"errors"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrNoHeader is returned if a proof carries no header.
+	ErrNoHeader = errors.New("proof without header")
+
+	// ErrCheckpointMismatch is returned if a proof starts from another
+	// checkpoint than the trusted one.
+	ErrCheckpointMismatch = errors.New("proof from another checkpoint")
+
+	// ErrInvalidHandover is returned if a committee change is not the last
+	// header of a term between the checkpoint and the header, in order.
+	ErrInvalidHandover = errors.New("invalid committee hand-over")
+
+	// ErrValidatorsMismatch is returned if the committee of a proof is not
+	// the one the hand-overs lead to.
+	ErrValidatorsMismatch = errors.New("committee mismatch")
+
+	// ErrNotEnoughSigs is returned if a header lacks a quorum of signatures
+	// of its committee.
+	ErrNotEnoughSigs = errors.New("not enough signatures of the committee")
+)
+
+// Params are the chain parameters the term of a block is derived from.
+type Params struct {
+	TermLen       uint64 `json:"termLen"`
+	ViewLen       uint64 `json:"viewLen"`
+	HandoverDelay uint64 `json:"handoverDelay"` // Terms a handed over committee waits before taking effect
+}
+
+// TermOf returns the term of the given block number.
+func (p Params) TermOf(number uint64) uint64 {
+	if number == 0 {
+		return 0
+	}
+	return (number - 1) / (p.TermLen * p.ViewLen)
+}
+
+// IsLastOfTerm returns whether the given block number ends a term.
+func (p Params) IsLastOfTerm(number uint64) bool {
+	return number > 0 && number%(p.TermLen*p.ViewLen) == 0
+}
+
+// Checkpoint is a validator committee trusted from the term of a block on.
+type Checkpoint struct {
+	Number     uint64           `json:"number"`
+	Validators []common.Address `json:"validators"`
+}
+
+// NewCheckpoint returns a checkpoint trusting the committee listed in the given
+// header, the genesis one most of the time.
+func NewCheckpoint(header *types.Header) *Checkpoint {
+	return &Checkpoint{
+		Number:     header.Number.Uint64(),
+		Validators: header.Dpor.CopyValidators(),
+	}
+}
+
+// Proof is a self-contained proof that a block is final.
+type Proof struct {
+	Header     *types.Header    `json:"header"`     // Header proven final, along its signatures
+	Validators []common.Address `json:"validators"` // Committee of the term of the header
+	Checkpoint uint64           `json:"checkpoint"` // Number of the checkpoint the hand-overs start from
+	Handovers  []*types.Header  `json:"handovers"`  // Last headers of the terms handing a new committee over, in order
+}
+
+// Verify checks that the header of the proof is final, trusting the
+// committee of the given checkpoint.
+func Verify(params Params, trusted *Checkpoint, proof *Proof) error {
+	if proof.Header == nil {
+		return ErrNoHeader
+	}
+	if proof.Checkpoint != trusted.Number {
+		return ErrCheckpointMismatch
+	}
+	var (
+		number    = proof.Header.Number.Uint64()
+		term      = params.TermOf(trusted.Number)
+		committee = trusted.Validators
+		pending   []*types.Header
+	)
+	// Hand-overs take effect HandoverDelay terms after the one following them,
+	// so a hand-over is signed by the committee in effect at its own term
+	effective := func(handover *types.Header) uint64 {
+		return params.TermOf(handover.Number.Uint64()) + 1 + params.HandoverDelay
+	}
+	for _, handover := range proof.Handovers {
+		at := handover.Number.Uint64()
+		if !params.IsLastOfTerm(at) || params.TermOf(at) < term || at >= number || len(handover.Dpor.Validators) == 0 {
+			return ErrInvalidHandover
+		}
+		for len(pending) > 0 && effective(pending[0]) <= params.TermOf(at) {
+			committee, pending = pending[0].Dpor.Validators, pending[1:]
+		}
+		if err := verifyQuorum(handover, committee); err != nil {
+			return err
+		}
+		term, pending = params.TermOf(at)+1, append(pending, handover)
+	}
+	for len(pending) > 0 && effective(pending[0]) <= params.TermOf(number) {
+		committee, pending = pending[0].Dpor.Validators, pending[1:]
+	}
+	if params.TermOf(number) < term || len(pending) > 0 {
+		return ErrInvalidHandover
+	}
+	if !equalAddresses(proof.Validators, committee) {
+		return ErrValidatorsMismatch
+	}
+	return verifyQuorum(proof.Header, committee)
+}
+
+// verifyQuorum checks that the header holds the commit signatures of a quorum
+// of the committee, 2f+1 of 3f+1 validators for a proposed block and f+1 for
+// an impeachment one.
+func verifyQuorum(header *types.Header, committee []common.Address) error {
+	var (
+		hash    = header.Hash()
+		members = make(map[common.Address]bool, len(committee))
+		signers = make(map[common.Address]bool)
+	)
+	for _, validator := range committee {
+		members[validator] = true
+	}
+	for _, sig := range header.Dpor.Sigs {
+		if sig == (types.DporSignature{}) {
+			continue
+		}
+		pubkey, err := crypto.Ecrecover(hash.Bytes(), sig[:])
+		if err != nil {
+			continue
+		}
+		var signer common.Address
+		copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+		if members[signer] {
+			signers[signer] = true
+		}
+	}
+	faulty := uint64(0)
+	if len(committee) > 0 {
+		faulty = uint64(len(committee)-1) / 3
+	}
+	quorum := 2*faulty + 1
+	if header.Impeachment() {
+		quorum = faulty + 1
+	}
+	if uint64(len(signers)) < quorum {
+		return ErrNotEnoughSigs
+	}
+	return nil
+}
+
+func equalAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package finality
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newCommittee(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	addrs := make([]common.Address, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	return keys, addrs
+}
+
+func newHeader(number uint64, coinbase common.Address, next []common.Address, signers []*ecdsa.PrivateKey) *types.Header {
+	header := &types.Header{
+		Coinbase: coinbase,
+		Number:   new(big.Int).SetUint64(number),
+		Time:     new(big.Int).SetUint64(number),
+	}
+	header.Dpor.Validators = next
+	header.Dpor.Sigs = make([]types.DporSignature, len(signers))
+	for i, key := range signers {
+		sig, _ := crypto.Sign(header.Hash().Bytes(), key)
+		copy(header.Dpor.Sigs[i][:], sig)
+	}
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	var (
+		params         = Params{TermLen: 2, ViewLen: 2}
+		keys, addrs    = newCommittee(4)
+		nextKeys, next = newCommittee(4)
+		outsider, _    = crypto.GenerateKey()
+		coinbase       = common.Address{0x01}
+	)
+	genesis := newHeader(0, common.Address{}, addrs, nil)
+	trusted := NewCheckpoint(genesis)
+
+	// A block of the first term needs 2f+1 signatures of the genesis committee
+	proof := &Proof{Header: newHeader(3, coinbase, nil, keys[1:]), Validators: addrs}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	for i, signers := range [][]*ecdsa.PrivateKey{
+		keys[:2],
+		{keys[0], keys[1], outsider},
+		{keys[0], keys[0], keys[0]},
+	} {
+		proof := &Proof{Header: newHeader(3, coinbase, nil, signers), Validators: addrs}
+		if err := Verify(params, trusted, proof); err != ErrNotEnoughSigs {
+			t.Errorf("case %d: error mismatch: have %v, want %v", i, err, ErrNotEnoughSigs)
+		}
+	}
+	// Impeachment blocks only take f+1 signatures
+	proof = &Proof{Header: newHeader(3, common.Address{}, nil, keys[:2]), Validators: addrs}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("impeachment proof rejected: %v", err)
+	}
+
+	// The last block of the first term hands the committee over
+	handover := newHeader(4, coinbase, next, keys[:3])
+	proof = &Proof{
+		Header:     newHeader(9, coinbase, nil, nextKeys[:3]),
+		Validators: next,
+		Handovers:  []*types.Header{handover},
+	}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("valid proof with hand-over rejected: %v", err)
+	}
+	blob, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("failed to encode proof: %v", err)
+	}
+	decoded := new(Proof)
+	if err := json.Unmarshal(blob, decoded); err != nil {
+		t.Fatalf("failed to decode proof: %v", err)
+	}
+	if err := Verify(params, trusted, decoded); err != nil {
+		t.Fatalf("decoded proof rejected: %v", err)
+	}
+
+	// Tampered proofs are rejected
+	tests := []struct {
+		proof *Proof
+		err   error
+	}{
+		// Signatures of the old committee past the hand-over
+		{&Proof{Header: newHeader(9, coinbase, nil, keys[:3]), Validators: next, Handovers: []*types.Header{handover}}, ErrNotEnoughSigs},
+		// Hand-over left out
+		{&Proof{Header: newHeader(9, coinbase, nil, nextKeys[:3]), Validators: next}, ErrValidatorsMismatch},
+		// Hand-over signed by the new committee itself
+		{&Proof{Header: newHeader(9, coinbase, nil, nextKeys[:3]), Validators: next, Handovers: []*types.Header{newHeader(4, coinbase, next, nextKeys[:3])}}, ErrNotEnoughSigs},
+		// Hand-over in the middle of a term
+		{&Proof{Header: newHeader(9, coinbase, nil, nextKeys[:3]), Validators: next, Handovers: []*types.Header{newHeader(3, coinbase, next, keys[:3])}}, ErrInvalidHandover},
+		// Hand-over past the block
+		{&Proof{Header: newHeader(3, coinbase, nil, keys[:3]), Validators: addrs, Handovers: []*types.Header{handover}}, ErrInvalidHandover},
+		// Proof from another checkpoint
+		{&Proof{Header: newHeader(3, coinbase, nil, keys[:3]), Validators: addrs, Checkpoint: 4}, ErrCheckpointMismatch},
+		{&Proof{}, ErrNoHeader},
+	}
+	for i, tt := range tests {
+		if err := Verify(params, trusted, tt.proof); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+func TestVerifyHandoverDelay(t *testing.T) {
+	var (
+		params          = Params{TermLen: 2, ViewLen: 2, HandoverDelay: 1}
+		keys, addrs     = newCommittee(4)
+		nextKeys, next  = newCommittee(4)
+		lastKeys, last  = newCommittee(4)
+		coinbase        = common.Address{0x01}
+		trusted         = NewCheckpoint(newHeader(0, common.Address{}, addrs, nil))
+		handover        = newHeader(4, coinbase, next, keys[:3])
+		pendingHandover = newHeader(8, coinbase, last, keys[:3])
+	)
+	// The old committee keeps signing the term following the hand-over,
+	// including the next hand-over
+	proof := &Proof{Header: newHeader(7, coinbase, nil, keys[:3]), Validators: addrs}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("valid proof within the delay rejected: %v", err)
+	}
+	proof = &Proof{Header: newHeader(9, coinbase, nil, nextKeys[:3]), Validators: next, Handovers: []*types.Header{handover}}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("valid proof past the delay rejected: %v", err)
+	}
+	proof = &Proof{Header: newHeader(13, coinbase, nil, lastKeys[:3]), Validators: last, Handovers: []*types.Header{handover, pendingHandover}}
+	if err := Verify(params, trusted, proof); err != nil {
+		t.Fatalf("valid proof with overlapping hand-overs rejected: %v", err)
+	}
+
+	tests := []struct {
+		proof *Proof
+		err   error
+	}{
+		// Hand-over not in effect yet
+		{&Proof{Header: newHeader(7, coinbase, nil, nextKeys[:3]), Validators: next, Handovers: []*types.Header{handover}}, ErrInvalidHandover},
+		// Hand-over signed by the committee it has not replaced yet
+		{&Proof{Header: newHeader(13, coinbase, nil, lastKeys[:3]), Validators: last, Handovers: []*types.Header{handover, newHeader(8, coinbase, last, nextKeys[:3])}}, ErrNotEnoughSigs},
+	}
+	for i, tt := range tests {
+		if err := Verify(params, trusted, tt.proof); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
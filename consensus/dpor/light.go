@@ -0,0 +1,129 @@
+package dpor
+
+import (
+	"encoding/json"
+	"errors"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var (
+	// errProposerNotListed is returned if a header is sealed by an address
+	// missing in the proposers the header lists.
+	errProposerNotListed = errors.New("proposer not listed in header")
+)
+
+// LightVerifier checks a chain of headers with their validator signatures
+// only, tracking the validator committee of every term in a snapshot built
+// from the headers themselves.
+//
+// Electing the proposers takes the campaign and reputation contracts, that
+// is the state, so a light verifier trusts the proposers listed in a header
+// once it holds a quorum of commit signatures of the term's validators. The
+// validators being the ones that checked the proposer, this is enough to
+// consider the header final.
+type LightVerifier struct {
+	config   *configs.DporConfig
+	snap     *DporSnapshot
+	util     dporUtil
+	sigcache *lru.ARCCache
+}
+
+// NewLightVerifier creates a light verifier starting at the given genesis header.
+func NewLightVerifier(config *configs.DporConfig, genesis *types.Header, mode Mode) *LightVerifier {
+	var proposers, validators []common.Address
+	if mode != FakeMode && mode != DoNothingFakeMode {
+		proposers = genesis.Dpor.CopyProposers()
+		validators = genesis.Dpor.CopyValidators()
+	}
+	return newLightVerifier(config, newSnapshot(config, 0, genesis.Hash(), proposers, validators, mode))
+}
+
+// LoadLightVerifier restores a light verifier from its encoded snapshot.
+func LoadLightVerifier(config *configs.DporConfig, blob []byte) (*LightVerifier, error) {
+	snap := new(DporSnapshot)
+	if err := json.Unmarshal(blob, snap); err != nil {
+		return nil, err
+	}
+	snap.config = config
+	return newLightVerifier(config, snap), nil
+}
+
+func newLightVerifier(config *configs.DporConfig, snap *DporSnapshot) *LightVerifier {
+	sigcache, _ := lru.NewARC(inMemorySignatures)
+	return &LightVerifier{
+		config:   config,
+		snap:     snap,
+		util:     &defaultDporUtil{},
+		sigcache: sigcache,
+	}
+}
+
+// Encode returns the encoded snapshot of the verifier, to be restored with
+// LoadLightVerifier.
+func (v *LightVerifier) Encode() ([]byte, error) {
+	return json.Marshal(v.snap)
+}
+
+// Head returns the number and hash of the last header applied.
+func (v *LightVerifier) Head() (uint64, common.Hash) {
+	return v.snap.number(), v.snap.hash()
+}
+
+// ValidatorsOf returns the validator committee of the given block number, as
+// far as the applied headers tell.
+func (v *LightVerifier) ValidatorsOf(number uint64) []common.Address {
+	return v.snap.ValidatorsOf(number)
+}
+
+// VerifyHeader checks that header follows the last header applied and holds
+// enough signatures of the validators of its term.
+func (v *LightVerifier) VerifyHeader(header *types.Header) error {
+	number := header.Number.Uint64()
+	if number != v.snap.number()+1 || header.ParentHash != v.snap.hash() {
+		return consensus.ErrUnknownAncestor
+	}
+	if v.snap.Mode == FakeMode || v.snap.Mode == DoNothingFakeMode {
+		return nil
+	}
+	proposer, signers, err := v.util.ecrecover(header, v.sigcache)
+	if err != nil {
+		return err
+	}
+	var (
+		committee = v.snap.ValidatorsOf(number)
+		counted   = make(map[common.Address]bool)
+	)
+	for _, signer := range signers {
+		for _, validator := range committee {
+			if signer == validator {
+				counted[signer] = true
+			}
+		}
+	}
+	if header.Impeachment() {
+		if !v.config.ImpeachCertificate(uint64(len(counted))) {
+			return consensus.ErrNotEnoughSigs
+		}
+		return nil
+	}
+	if !v.config.Certificate(uint64(len(counted))) {
+		return consensus.ErrNotEnoughSigs
+	}
+	for _, listed := range header.Dpor.Proposers {
+		if listed == proposer {
+			return nil
+		}
+	}
+	return errProposerNotListed
+}
+
+// Apply moves the verifier on to a verified header, carrying the validator
+// committee over to the next term at checkpoints.
+func (v *LightVerifier) Apply(header *types.Header) error {
+	return v.snap.applyHeader(header, false, nil, nil)
+}
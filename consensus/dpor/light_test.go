@@ -0,0 +1,103 @@
+package dpor
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLightVerifier(t *testing.T) {
+	var (
+		config = &configs.DporConfig{TermLen: 2, ViewLen: 2, FaultyNumber: 1}
+		keys   = make([]*ecdsa.PrivateKey, 4)
+		addrs  = make([]common.Address, 4)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	proposer, _ := crypto.GenerateKey()
+	proposers := []common.Address{crypto.PubkeyToAddress(proposer.PublicKey)}
+
+	// The normal mode snapshot reads the configured validators, the pbft fake
+	// one keeps the committee of the genesis
+	genesis := &types.Header{Number: big.NewInt(0), Time: big.NewInt(0)}
+	genesis.Dpor.Validators = addrs
+	v := NewLightVerifier(config, genesis, PbftFakeMode)
+
+	util := &defaultDporUtil{}
+	newHeader := func(parent *types.Header, proposers []common.Address, signers []*ecdsa.PrivateKey) *types.Header {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Coinbase:   common.Address{0x01},
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Time:       new(big.Int).Add(parent.Time, common.Big1),
+		}
+		header.Dpor.Proposers = proposers
+		header.Dpor.Sigs = make([]types.DporSignature, len(signers))
+
+		hash := util.sigHash(header).Bytes()
+		seal, _ := crypto.Sign(hash, proposer)
+		copy(header.Dpor.Seal[:], seal)
+		commit, _ := hashBytesWithState(hash, consensus.Commit)
+		for i, key := range signers {
+			sig, _ := crypto.Sign(commit, key)
+			copy(header.Dpor.Sigs[i][:], sig)
+		}
+		return header
+	}
+	// Build a chain over two terms, signed by a quorum of the committee
+	parent := genesis
+	for i := 0; i < 6; i++ {
+		header := newHeader(parent, proposers, keys[i%2:i%2+3])
+		if err := v.VerifyHeader(header); err != nil {
+			t.Fatalf("header %d: verification failed: %v", header.Number, err)
+		}
+		if err := v.Apply(header); err != nil {
+			t.Fatalf("header %d: failed to apply: %v", header.Number, err)
+		}
+		parent = header
+	}
+	if number, hash := v.Head(); number != 6 || hash != parent.Hash() {
+		t.Fatalf("head mismatch: have %d/%x, want 6/%x", number, hash, parent.Hash())
+	}
+	if have := v.ValidatorsOf(7); len(have) != len(addrs) {
+		t.Fatalf("committee not carried over: have %v, want %v", have, addrs)
+	}
+	// Headers without a quorum, with foreign or repeated signers, or not
+	// following the head are rejected
+	outsider, _ := crypto.GenerateKey()
+	for i, signers := range [][]*ecdsa.PrivateKey{
+		keys[:2],
+		{keys[0], keys[1], outsider},
+		{keys[0], keys[0], keys[0]},
+	} {
+		if err := v.VerifyHeader(newHeader(parent, proposers, signers)); err != consensus.ErrNotEnoughSigs {
+			t.Errorf("signer set %d: error mismatch: have %v, want %v", i, err, consensus.ErrNotEnoughSigs)
+		}
+	}
+	if err := v.VerifyHeader(newHeader(genesis, proposers, keys)); err != consensus.ErrUnknownAncestor {
+		t.Errorf("stale header: error mismatch: have %v, want %v", err, consensus.ErrUnknownAncestor)
+	}
+	if err := v.VerifyHeader(newHeader(parent, addrs[:1], keys)); err != errProposerNotListed {
+		t.Errorf("unlisted proposer: error mismatch: have %v, want %v", err, errProposerNotListed)
+	}
+	// The encoded verifier carries on from the same head
+	blob, err := v.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode verifier: %v", err)
+	}
+	loaded, err := LoadLightVerifier(config, blob)
+	if err != nil {
+		t.Fatalf("failed to load verifier: %v", err)
+	}
+	if err := loaded.VerifyHeader(newHeader(parent, proposers, keys[1:])); err != nil {
+		t.Fatalf("loaded verifier: verification failed: %v", err)
+	}
+}
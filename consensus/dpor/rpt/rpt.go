@@ -20,6 +20,7 @@ package rpt
 // then calculates the reputations of candidates.
 
 import (
+	"math/big"
 	"time"
 
 	"bitbucket.org/cpchain/chain/accounts/abi/bind"
@@ -55,6 +56,7 @@ type RptCollector interface {
 // BasicCollector is the default rpt collector
 type RptServiceImpl struct {
 	client bind.ContractBackend
+	config *configs.ChainConfig
 
 	contractAddr common.Address
 	rptInstance  *rptContract.Rpt
@@ -64,7 +66,7 @@ type RptServiceImpl struct {
 }
 
 // NewRptService creates a concrete RPT service instance.
-func NewRptService(contractAddr common.Address, backend backend.ClientBackend) (RptService, error) {
+func NewRptService(config *configs.ChainConfig, contractAddr common.Address, backend backend.ClientBackend) (RptService, error) {
 
 	rptInstance, err := rptContract.NewRpt(contractAddr, backend)
 	if err != nil {
@@ -76,6 +78,7 @@ func NewRptService(contractAddr common.Address, backend backend.ClientBackend) (
 
 	bc := &RptServiceImpl{
 		client: backend,
+		config: config,
 
 		contractAddr: contractAddr,
 		rptInstance:  rptInstance,
@@ -190,7 +193,7 @@ func (rs *RptServiceImpl) CalcRptInfoList(addresses []common.Address, number uin
 // CalcRptInfo return the Rpt of the candidate address
 func (rs *RptServiceImpl) CalcRptInfo(address common.Address, addresses []common.Address, number uint64) Rpt {
 
-	if number < configs.NewRptWithImpeachPunishmentPivotBlockNumber {
+	if !rs.config.IsImpeachPunishment(new(big.Int).SetUint64(number)) {
 		log.Debug("now calc rpt for with rpt method 1", "addr", address.Hex(), "number", number)
 		return rs.rptCollector.RptOf(address, addresses, number)
 	}
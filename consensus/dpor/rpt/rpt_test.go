@@ -226,7 +226,7 @@ func benchCalcRptInfoList(b *testing.B, windowSize int64, numAccount int) {
 	contractAddr, backend, rptContract := newBlockchainWithDb(1000, accounts)
 	rptContract.UpdateWindow(bind.NewKeyedTransactor(testBankKey), big.NewInt(windowSize))
 	backend.Commit()
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	tstart := time.Now()
 	rpts := rptInstance.CalcRptInfoList(accounts, uint64(1000))
 	b.Log("100 candidates' rpt with window size 100 spend time", time.Now().Sub(tstart))
@@ -371,7 +371,7 @@ func benchLowRptCount(b *testing.B, total int) {
 	numAccount := 100
 	accounts := generateABatchAccounts(numAccount)
 	contractAddr, backend, _ := newBlockchainWithDb(1000, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	lowCount := rptInstance.LowRptCount(total)
 	b.Log("LowRptCount is", lowCount)
 }
@@ -532,7 +532,7 @@ func TestProxyValueOf(t *testing.T) {
 func TestFormatString(t *testing.T) {
 	accounts := generateABatchAccounts(5)
 	contractAddr, backend, _ := newBlockchainWithDb(6, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts := rptInstance.CalcRptInfoList(accounts, 6)
 	t.Log("rpt result", "rpts", rpts.FormatString())
 
@@ -541,7 +541,7 @@ func TestFormatString(t *testing.T) {
 func TestAddrs(t *testing.T) {
 	accounts := generateABatchAccounts(5)
 	contractAddr, backend, _ := newBlockchainWithDb(6, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts := rptInstance.CalcRptInfoList(accounts, 6)
 	t.Log("rpt result", "rpts", rpts.Addrs())
 }
@@ -549,7 +549,7 @@ func TestAddrs(t *testing.T) {
 func TestSwapAndLess(t *testing.T) {
 	accounts := generateABatchAccounts(6)
 	contractAddr, backend, _ := newBlockchainWithDb(6, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	rpts := rptInstance.CalcRptInfoList(accounts, 6)
 	t.Log("rpt result", "rpts'format", rpts.FormatString(), "before is less or not?", rpts.Less(0, 1))
 	rpts.Swap(0, 1)
@@ -583,11 +583,11 @@ func TestTotalSeats(t *testing.T) {
 	numAccount := 100
 	accounts := generateABatchAccounts(numAccount)
 	_, backend, _ := newBlockchainWithDb(1000, accounts)
-	rptInstance, _ := rpt.NewRptService(common.HexToAddress("dhsjhdak"), backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, common.HexToAddress("dhsjhdak"), backend)
 	abLowrptseats, _ := rptInstance.LowRptSeats()
 	t.Log("Abnormal situation ,not call contract:", abLowrptseats)
 	contractAddr, backend, _ := newBlockchainWithDb(1000, accounts)
-	rptInstance2, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance2, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	lowrptseats, _ := rptInstance2.LowRptSeats()
 	t.Log("Normal situation, call contract:", lowrptseats)
 }
@@ -596,7 +596,7 @@ func TestLowRptCount(t *testing.T) {
 	numAccount := 100
 	accounts := generateABatchAccounts(numAccount)
 	contractAddr, backend, _ := newBlockchainWithDb(1000, accounts)
-	rptInstance, _ := rpt.NewRptService(contractAddr, backend)
+	rptInstance, _ := rpt.NewRptService(configs.TestChainConfig, contractAddr, backend)
 	lowrptsum := rptInstance.LowRptCount(77)
 	t.Log("Low Rpt sum seats is:", lowrptsum)
 }
@@ -1,6 +1,7 @@
 package dpor
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -13,6 +14,7 @@ import (
 	"bitbucket.org/cpchain/chain/consensus/dpor/campaign"
 	"bitbucket.org/cpchain/chain/consensus/dpor/election"
 	"bitbucket.org/cpchain/chain/consensus/dpor/rpt"
+	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -27,6 +29,10 @@ const (
 
 	//MaxSizeOfRecentProposers is the size of the RecentProposers
 	MaxSizeOfRecentProposers = 200
+
+	// ValidatorsHandoverDelay is the number of terms a validators committee handed over in a checkpoint
+	// waits before taking effect, giving the dialer time to connect to its new members.
+	ValidatorsHandoverDelay = 1 // ValidatorsHandoverDelay = effective term - term after the checkpoint
 )
 
 const defaultProposersSeats = 4
@@ -37,16 +43,18 @@ var (
 	errSignerNotInCommittee    = errors.New("not a member in signers committee")
 	errGenesisBlockNumber      = errors.New("genesis block has no leader")
 	errInsufficientCandidates  = errors.New("insufficient candidates")
+	errInvalidHandover         = errors.New("invalid validators committee handover")
 )
 
 // DporSnapshot is the state of the authorization voting at a given point in time.
 type DporSnapshot struct {
 	Mode             Mode                        `json:"mode"`
-	Number           uint64                      `json:"number"`     // Block number where the Snapshot was created
-	Hash             common.Hash                 `json:"hash"`       // Block hash where the Snapshot was created
-	Candidates       []common.Address            `json:"candidates"` // Set of candidates read from campaign contract
-	RecentProposers  map[uint64][]common.Address `json:"proposers"`  // Set of recent proposers
-	RecentValidators map[uint64][]common.Address `json:"validators"` // Set of recent validators
+	Number           uint64                      `json:"number"`              // Block number where the Snapshot was created
+	Hash             common.Hash                 `json:"hash"`                // Block hash where the Snapshot was created
+	Candidates       []common.Address            `json:"candidates"`          // Set of candidates read from campaign contract
+	RecentProposers  map[uint64][]common.Address `json:"proposers"`           // Set of recent proposers
+	RecentValidators map[uint64][]common.Address `json:"validators"`          // Set of recent validators
+	Handovers        map[uint64][]common.Address `json:"handovers,omitempty"` // Validators committees handed over in checkpoints, by effective term
 
 	config *configs.DporConfig // Consensus engine parameters to fine tune behavior
 
@@ -152,6 +160,12 @@ func (s *DporSnapshot) getRecentValidators(term uint64) []common.Address {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
+	// validators committee handed over overrides the recent and default ones,
+	// including for future terms not recorded yet
+	if handover, ok := s.handoverOf(term); ok {
+		return handover
+	}
+
 	signers, ok := s.RecentValidators[term]
 	if !ok {
 		return nil
@@ -164,6 +178,147 @@ func (s *DporSnapshot) getRecentValidators(term uint64) []common.Address {
 	return signers
 }
 
+// handoverOf returns the latest validators committee handed over taking effect
+// at or before the given term, the caller must hold the lock.
+func (s *DporSnapshot) handoverOf(term uint64) ([]common.Address, bool) {
+	var (
+		latest    uint64
+		committee []common.Address
+	)
+	for effective, validators := range s.Handovers {
+		if effective <= term && (committee == nil || effective > latest) {
+			latest, committee = effective, validators
+		}
+	}
+	return committee, committee != nil
+}
+
+func (s *DporSnapshot) handovers() map[uint64][]common.Address {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	handovers := make(map[uint64][]common.Address)
+	for term, validators := range s.Handovers {
+		handovers[term] = make([]common.Address, len(validators))
+		copy(handovers[term], validators)
+	}
+	return handovers
+}
+
+// handoversPrefix + checkpoint hash -> validators committees handed over as of
+// the checkpoint, by effective term
+var handoversPrefix = []byte("dpor-handovers-")
+
+// storeHandovers stores the validators committees handed over as of the block
+// of the snapshot, so that snapshots rebuilt past it get them back.
+func (s *DporSnapshot) storeHandovers(db database.Database) error {
+	handovers := s.handovers()
+	if db == nil || len(handovers) == 0 {
+		return nil
+	}
+	blob, err := json.Marshal(handovers)
+	if err != nil {
+		return err
+	}
+	return db.Put(append(handoversPrefix, s.hash().Bytes()...), blob)
+}
+
+// loadHandovers retrieves the validators committees handed over as of the block
+// with the given hash, nil if none were stored.
+func loadHandovers(db database.Database, hash common.Hash) map[uint64][]common.Address {
+	if db == nil {
+		return nil
+	}
+	blob, err := db.Get(append(handoversPrefix, hash.Bytes()...))
+	if err != nil {
+		return nil
+	}
+	var handovers map[uint64][]common.Address
+	if err := json.Unmarshal(blob, &handovers); err != nil {
+		log.Warn("failed to decode stored validators handovers", "hash", hash.Hex(), "err", err)
+		return nil
+	}
+	return handovers
+}
+
+// setHandover schedules a validators committee from the given term on,
+// dropping the committees superseded before the recent validators kept.
+func (s *DporSnapshot) setHandover(effective uint64, validators []common.Address) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Handovers == nil {
+		s.Handovers = make(map[uint64][]common.Address)
+	}
+
+	ss := make([]common.Address, len(validators))
+	copy(ss, validators)
+
+	s.Handovers[effective] = ss
+
+	// a committee is kept as long as recent validators of its terms may be asked for
+	current := s.TermOf(s.Number)
+	if current < MaxSizeOfRecentValidators {
+		return
+	}
+	oldest := current - MaxSizeOfRecentValidators
+	for term := range s.Handovers {
+		for next := range s.Handovers {
+			if next > term && next <= oldest {
+				delete(s.Handovers, term)
+				break
+			}
+		}
+	}
+}
+
+// verifyHandover checks the validators committee a checkpoint header hands
+// over. The committee must have as many distinct members as configured and
+// keep a quorum of the committee it replaces.
+func (s *DporSnapshot) verifyHandover(header *types.Header) error {
+	var (
+		number     = header.Number.Uint64()
+		validators = header.Dpor.Validators
+		term       = s.TermOf(number)
+	)
+
+	if header.Impeachment() || !backend.IsCheckPoint(number, s.config.TermLen, s.config.ViewLen) {
+		return errInvalidHandover
+	}
+	if uint64(len(validators)) != s.config.ValidatorsLen() {
+		return errInvalidHandover
+	}
+
+	// The committee in effect right before the handover, which may be one
+	// handed over by a previous checkpoint still waiting for its term
+	s.lock.RLock()
+	current, ok := s.handoverOf(term + ValidatorsHandoverDelay)
+	s.lock.RUnlock()
+	if !ok {
+		current = s.getRecentValidators(term)
+	}
+
+	members := make(map[common.Address]bool, len(current))
+	for _, v := range current {
+		members[v] = true
+	}
+	seen := make(map[common.Address]bool, len(validators))
+	kept := uint64(0)
+	for _, v := range validators {
+		if v == (common.Address{}) || seen[v] {
+			return errInvalidHandover
+		}
+		seen[v] = true
+		if members[v] {
+			kept++
+		}
+	}
+	if !s.config.Certificate(kept) || equalAddresses(validators, current) {
+		return errInvalidHandover
+	}
+	return nil
+}
+
 func (s *DporSnapshot) setRecentValidators(term uint64, validators []common.Address) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -233,6 +388,9 @@ func (s *DporSnapshot) copy() *DporSnapshot {
 	}
 
 	copy(cpy.Candidates, s.candidates())
+	if handovers := s.handovers(); len(handovers) > 0 {
+		cpy.Handovers = handovers
+	}
 	for term, proposer := range s.recentProposers() {
 		cpy.setRecentProposers(term, proposer)
 	}
@@ -322,13 +480,23 @@ func (s *DporSnapshot) applyHeader(header *types.Header, ifUpdateCommittee bool,
 		}
 	}
 
+	// Validators committee handed over in the header takes effect after the
+	// handover delay, the current one is carried over to the next term meanwhile
 	term := s.TermOf(header.Number.Uint64())
-	if len(header.Dpor.Validators) != 0 && len(header.Dpor.Validators) == int(s.config.ValidatorsLen()) {
-		// TODO: there is a vulnerability about validators in header, check it!
-		// for now, i just do not update validators from header.
-		// s.setRecentValidators(term+1, header.Dpor.Validators)
-	} else if backend.IsCheckPoint(header.Number.Uint64(), s.config.TermLen, s.config.ViewLen) {
+	if len(header.Dpor.Validators) != 0 {
+		if err := s.verifyHandover(header); err != nil {
+			log.Warn("ignore invalid validators handover", "number", header.Number, "validators", header.Dpor.ValidatorsFormatText())
+		} else {
+			s.setHandover(term+1+ValidatorsHandoverDelay, header.Dpor.Validators)
+		}
+	}
+	if backend.IsCheckPoint(header.Number.Uint64(), s.config.TermLen, s.config.ViewLen) {
 		s.setRecentValidators(term+1, s.getRecentValidators(term))
+
+		// There is no election in a developer chain, proposers stay the genesis ones
+		if s.Mode == DevMode {
+			s.setRecentProposers(term+1, s.getRecentProposers(term))
+		}
 	}
 
 	return nil
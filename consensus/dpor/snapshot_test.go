@@ -100,6 +100,101 @@ func TestSnapshot_copy(t *testing.T) {
 	}
 }
 
+func TestSnapshot_handover(t *testing.T) {
+	config := &configs.DporConfig{Period: 3, TermLen: 3, ViewLen: 3, FaultyNumber: 1}
+	validators := getValidatorAddress()
+	next := append([]common.Address{common.HexToAddress("0x05")}, validators[1:]...)
+
+	newHeaders := func(handoverAt uint64, handover []common.Address) []*types.Header {
+		var headers []*types.Header
+		for number := uint64(2); number <= 10; number++ {
+			header := &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: getProposerAddress()[0]}
+			if number == handoverAt {
+				header.Dpor.Validators = handover
+			}
+			headers = append(headers, header)
+		}
+		return headers
+	}
+
+	// Invalid handovers are ignored
+	for i, headers := range [][]*types.Header{
+		newHeaders(9, next[:3]),
+		newHeaders(9, []common.Address{next[0], next[0], next[1], next[2]}),
+		newHeaders(9, append([]common.Address{common.HexToAddress("0x05"), common.HexToAddress("0x06")}, validators[2:]...)),
+		newHeaders(9, validators),
+		newHeaders(8, next),
+	} {
+		snap := newSnapshot(config, 1, common.Hash{}, getProposerAddress(), validators, FakeMode)
+		snap, err := snap.apply(headers, false, nil, nil)
+		if err != nil {
+			t.Fatalf("case %d: failed to apply headers: %v", i, err)
+		}
+		if len(snap.Handovers) != 0 || !reflect.DeepEqual(snap.ValidatorsOf(18), validators) {
+			t.Errorf("case %d: invalid handover taken, validators %v", i, snap.ValidatorsOf(18))
+		}
+	}
+
+	// A valid handover takes effect after the handover delay
+	snap := newSnapshot(config, 1, common.Hash{}, getProposerAddress(), validators, FakeMode)
+	snap, err := snap.apply(newHeaders(9, next), false, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to apply headers: %v", err)
+	}
+	pending := &types.Header{Number: big.NewInt(18), Coinbase: getProposerAddress()[0]}
+	pending.Dpor.Validators = next
+	if err := snap.verifyHandover(pending); err != errInvalidHandover {
+		t.Errorf("pending handover handed over twice")
+	}
+	for _, snap := range []*DporSnapshot{snap, snap.copy()} {
+		if !reflect.DeepEqual(snap.ValidatorsOf(18), validators) {
+			t.Errorf("handover taken before the delay, validators %v", snap.ValidatorsOf(18))
+		}
+		if !reflect.DeepEqual(snap.ValidatorsOf(19), next) || !snap.IsValidatorOf(next[0], 100) {
+			t.Errorf("handover not taken after the delay, validators %v", snap.ValidatorsOf(19))
+		}
+	}
+
+	blob, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+	decoded := new(DporSnapshot)
+	if err := json.Unmarshal(blob, decoded); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	decoded.config = config
+	if !reflect.DeepEqual(decoded.ValidatorsOf(19), next) {
+		t.Errorf("handover lost in encoding, validators %v", decoded.ValidatorsOf(19))
+	}
+}
+
+func TestSnapshot_devCommittees(t *testing.T) {
+	config := &configs.DporConfig{Period: 0, TermLen: 3, ViewLen: 3}
+	developer := getProposerAddress()[0]
+	proposers := []common.Address{developer, developer, developer}
+
+	var headers []*types.Header
+	for number := uint64(1); number <= 20; number++ {
+		headers = append(headers, &types.Header{Number: new(big.Int).SetUint64(number), Coinbase: developer})
+	}
+
+	// Committees of the genesis are carried over term by term
+	snap := newSnapshot(config, 0, common.Hash{}, proposers, []common.Address{developer}, DevMode)
+	snap, err := snap.apply(headers, true, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to apply headers: %v", err)
+	}
+	for _, number := range []uint64{1, 10, 19, 21} {
+		if !reflect.DeepEqual(snap.ProposersOf(number), proposers) {
+			t.Errorf("proposers of block %d mismatch: have %v, want %v", number, snap.ProposersOf(number), proposers)
+		}
+		if !snap.IsValidatorOf(developer, number) {
+			t.Errorf("developer is not the validator of block %d", number)
+		}
+	}
+}
+
 func TestSnapshot_setRecentProposers(t *testing.T) {
 	snap := newSnapshot(&configs.DporConfig{Period: 3, TermLen: 3, ViewLen: 3}, 1, common.Hash{}, getProposerAddress(), getValidatorAddress(), FakeMode)
 	proposers := getCandidates()
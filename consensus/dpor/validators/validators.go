@@ -0,0 +1,90 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package validators
+
+import (
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	validatorsContract "bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidatorService provides methods to obtain the validator committee from validator registry contract
+type ValidatorService interface {
+	// Validators returns the validator committee currently in the registry
+	Validators() ([]common.Address, error)
+
+	// ValidatorsAt returns the validator committee in the registry on the state
+	// the caller executes calls on
+	ValidatorsAt(caller bind.ContractCaller) ([]common.Address, error)
+
+	// EnodeOf returns the enode url a validator registered with
+	EnodeOf(validator common.Address) (string, error)
+}
+
+// ValidatorServiceImpl is the default validator committee collector
+type ValidatorServiceImpl struct {
+	client   bind.ContractBackend
+	contract common.Address
+}
+
+// NewValidatorService creates a concrete validator service instance.
+func NewValidatorService(validatorsContract common.Address, backend bind.ContractBackend) (ValidatorService, error) {
+
+	vs := &ValidatorServiceImpl{
+		contract: validatorsContract,
+		client:   backend,
+	}
+	return vs, nil
+}
+
+// Validators implements ValidatorService
+func (vs *ValidatorServiceImpl) Validators() ([]common.Address, error) {
+	return vs.ValidatorsAt(vs.client)
+}
+
+func (vs *ValidatorServiceImpl) ValidatorsAt(caller bind.ContractCaller) ([]common.Address, error) {
+
+	// new validator registry instance
+	contractInstance, err := validatorsContract.NewValidatorRegistryCaller(vs.contract, caller)
+	if err != nil {
+		log.Debug("error when create validator registry instance", "err", err)
+		return nil, err
+	}
+
+	validators, err := contractInstance.GetValidators(nil)
+	if err != nil {
+		log.Debug("error when read validators from validator registry", "err", err)
+		return nil, err
+	}
+
+	log.Debug("now read validators from validator registry", "len", len(validators), "contract addr", vs.contract.Hex())
+	return validators, nil
+}
+
+// EnodeOf implements ValidatorService
+func (vs *ValidatorServiceImpl) EnodeOf(validator common.Address) (string, error) {
+
+	// new validator registry instance
+	contractInstance, err := validatorsContract.NewValidatorRegistry(vs.contract, vs.client)
+	if err != nil {
+		log.Debug("error when create validator registry instance", "err", err)
+		return "", err
+	}
+
+	return contractInstance.EnodeOf(nil, validator)
+}
@@ -50,6 +50,11 @@ var (
 	// ErrNotInProposerCommittee is returned  if the account is not in proposer committee.
 	ErrNotInProposerCommittee = errors.New("not in proposer committee")
 
+	// ErrWaitTransactions is returned if an empty block is attempted to be sealed
+	// on an instant chain (0 second period). It's important to refuse these as the
+	// block reward is zero, so an empty block just bloats the chain... fast.
+	ErrWaitTransactions = errors.New("waiting for transactions")
+
 	// ErrUnknownLbftState is returned if committee handler's state is unknown
 	ErrUnknownLbftState = errors.New("unknown lbft state")
 
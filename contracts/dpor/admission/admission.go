@@ -200,6 +200,19 @@ func (_Admission *AdmissionCallerSession) CpuDifficulty() (*big.Int, error) {
 	return _Admission.Contract.CpuDifficulty(&_Admission.CallOpts)
 }
 
+// BatchCpuDifficulty adds a call of the contract method 0x8b654613 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function cpuDifficulty() constant returns(uint256)
+func (_Admission *AdmissionCaller) BatchCpuDifficulty(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "cpuDifficulty")
+	return ret0, err
+}
+
 // CpuWorkTimeout is a free data retrieval call binding the contract method 0x615cc243.
 //
 // Solidity: function cpuWorkTimeout() constant returns(uint256)
@@ -226,6 +239,19 @@ func (_Admission *AdmissionCallerSession) CpuWorkTimeout() (*big.Int, error) {
 	return _Admission.Contract.CpuWorkTimeout(&_Admission.CallOpts)
 }
 
+// BatchCpuWorkTimeout adds a call of the contract method 0x615cc243 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function cpuWorkTimeout() constant returns(uint256)
+func (_Admission *AdmissionCaller) BatchCpuWorkTimeout(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "cpuWorkTimeout")
+	return ret0, err
+}
+
 // GetAdmissionParameters is a free data retrieval call binding the contract method 0xc651cfc9.
 //
 // Solidity: function getAdmissionParameters() constant returns(uint256, uint256, uint256, uint256)
@@ -260,6 +286,27 @@ func (_Admission *AdmissionCallerSession) GetAdmissionParameters() (*big.Int, *b
 	return _Admission.Contract.GetAdmissionParameters(&_Admission.CallOpts)
 }
 
+// BatchGetAdmissionParameters adds a call of the contract method 0xc651cfc9 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getAdmissionParameters() constant returns(uint256, uint256, uint256, uint256)
+func (_Admission *AdmissionCaller) BatchGetAdmissionParameters(batch *bind.BatchCall) (**big.Int, **big.Int, **big.Int, **big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+		ret1 = new(*big.Int)
+		ret2 = new(*big.Int)
+		ret3 = new(*big.Int)
+	)
+	out := &[]interface{}{
+		ret0,
+		ret1,
+		ret2,
+		ret3,
+	}
+	err := _Admission.contract.BatchCall(batch, out, "getAdmissionParameters")
+	return ret0, ret1, ret2, ret3, err
+}
+
 // MemoryDifficulty is a free data retrieval call binding the contract method 0x17e6b966.
 //
 // Solidity: function memoryDifficulty() constant returns(uint256)
@@ -286,6 +333,19 @@ func (_Admission *AdmissionCallerSession) MemoryDifficulty() (*big.Int, error) {
 	return _Admission.Contract.MemoryDifficulty(&_Admission.CallOpts)
 }
 
+// BatchMemoryDifficulty adds a call of the contract method 0x17e6b966 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function memoryDifficulty() constant returns(uint256)
+func (_Admission *AdmissionCaller) BatchMemoryDifficulty(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "memoryDifficulty")
+	return ret0, err
+}
+
 // MemoryWorkTimeout is a free data retrieval call binding the contract method 0x6d44a935.
 //
 // Solidity: function memoryWorkTimeout() constant returns(uint256)
@@ -312,6 +372,19 @@ func (_Admission *AdmissionCallerSession) MemoryWorkTimeout() (*big.Int, error)
 	return _Admission.Contract.MemoryWorkTimeout(&_Admission.CallOpts)
 }
 
+// BatchMemoryWorkTimeout adds a call of the contract method 0x6d44a935 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function memoryWorkTimeout() constant returns(uint256)
+func (_Admission *AdmissionCaller) BatchMemoryWorkTimeout(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "memoryWorkTimeout")
+	return ret0, err
+}
+
 // Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
 //
 // Solidity: function owner() constant returns(address)
@@ -338,6 +411,19 @@ func (_Admission *AdmissionCallerSession) Owner() (common.Address, error) {
 	return _Admission.Contract.Owner(&_Admission.CallOpts)
 }
 
+// BatchOwner adds a call of the contract method 0x8da5cb5b to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function owner() constant returns(address)
+func (_Admission *AdmissionCaller) BatchOwner(batch *bind.BatchCall) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "owner")
+	return ret0, err
+}
+
 // Verify is a free data retrieval call binding the contract method 0x3395492e.
 //
 // Solidity: function verify(_cpuNonce uint64, _cpuBlockNumber uint256, _memoryNonce uint64, _memoryBlockNumber uint256, _sender address) constant returns(bool)
@@ -364,6 +450,19 @@ func (_Admission *AdmissionCallerSession) Verify(_cpuNonce uint64, _cpuBlockNumb
 	return _Admission.Contract.Verify(&_Admission.CallOpts, _cpuNonce, _cpuBlockNumber, _memoryNonce, _memoryBlockNumber, _sender)
 }
 
+// BatchVerify adds a call of the contract method 0x3395492e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function verify(_cpuNonce uint64, _cpuBlockNumber uint256, _memoryNonce uint64, _memoryBlockNumber uint256, _sender address) constant returns(bool)
+func (_Admission *AdmissionCaller) BatchVerify(batch *bind.BatchCall, _cpuNonce uint64, _cpuBlockNumber *big.Int, _memoryNonce uint64, _memoryBlockNumber *big.Int, _sender common.Address) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "verify", _cpuNonce, _cpuBlockNumber, _memoryNonce, _memoryBlockNumber, _sender)
+	return ret0, err
+}
+
 // VerifyCPU is a free data retrieval call binding the contract method 0x6ac03dcc.
 //
 // Solidity: function verifyCPU(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
@@ -390,6 +489,19 @@ func (_Admission *AdmissionCallerSession) VerifyCPU(_sender common.Address, _non
 	return _Admission.Contract.VerifyCPU(&_Admission.CallOpts, _sender, _nonce, _blockNumber, _difficulty)
 }
 
+// BatchVerifyCPU adds a call of the contract method 0x6ac03dcc to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function verifyCPU(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
+func (_Admission *AdmissionCaller) BatchVerifyCPU(batch *bind.BatchCall, _sender common.Address, _nonce uint64, _blockNumber *big.Int, _difficulty *big.Int) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "verifyCPU", _sender, _nonce, _blockNumber, _difficulty)
+	return ret0, err
+}
+
 // VerifyMemory is a free data retrieval call binding the contract method 0x4bda8957.
 //
 // Solidity: function verifyMemory(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
@@ -416,6 +528,19 @@ func (_Admission *AdmissionCallerSession) VerifyMemory(_sender common.Address, _
 	return _Admission.Contract.VerifyMemory(&_Admission.CallOpts, _sender, _nonce, _blockNumber, _difficulty)
 }
 
+// BatchVerifyMemory adds a call of the contract method 0x4bda8957 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function verifyMemory(_sender address, _nonce uint64, _blockNumber uint256, _difficulty uint256) constant returns(b bool)
+func (_Admission *AdmissionCaller) BatchVerifyMemory(batch *bind.BatchCall, _sender common.Address, _nonce uint64, _blockNumber *big.Int, _difficulty *big.Int) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Admission.contract.BatchCall(batch, out, "verifyMemory", _sender, _nonce, _blockNumber, _difficulty)
+	return ret0, err
+}
+
 // UpdateCPUDifficulty is a paid mutator transaction binding the contract method 0xbe981db8.
 //
 // Solidity: function updateCPUDifficulty(_difficulty uint256) returns()
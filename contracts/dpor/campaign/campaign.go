@@ -202,6 +202,19 @@ func (_AdmissionInterface *AdmissionInterfaceCallerSession) Verify(_cpuNonce uin
 	return _AdmissionInterface.Contract.Verify(&_AdmissionInterface.CallOpts, _cpuNonce, _cpuBlockNumber, _memoryNonce, _memoryBlockNumber, _sender)
 }
 
+// BatchVerify adds a call of the contract method 0x3395492e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function verify(_cpuNonce uint64, _cpuBlockNumber uint256, _memoryNonce uint64, _memoryBlockNumber uint256, _sender address) constant returns(bool)
+func (_AdmissionInterface *AdmissionInterfaceCaller) BatchVerify(batch *bind.BatchCall, _cpuNonce uint64, _cpuBlockNumber *big.Int, _memoryNonce uint64, _memoryBlockNumber *big.Int, _sender common.Address) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _AdmissionInterface.contract.BatchCall(batch, out, "verify", _cpuNonce, _cpuBlockNumber, _memoryNonce, _memoryBlockNumber, _sender)
+	return ret0, err
+}
+
 // CampaignABI is the input ABI used to generate the binding from.
 const CampaignABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"termLen\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_termIdx\",\"type\":\"uint256\"}],\"name\":\"candidatesOf\",\"outputs\":[{\"name\":\"\",\"type\":\"address[]\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_termsToCampaign\",\"type\":\"uint256\"},{\"name\":\"_cpuNonce\",\"type\":\"uint64\"},{\"name\":\"_cpuBlockNumber\",\"type\":\"uint256\"},{\"name\":\"_memoryNonce\",\"type\":\"uint64\"},{\"name\":\"_memoryBlockNumber\",\"type\":\"uint256\"},{\"name\":\"version\",\"type\":\"uint256\"}],\"name\":\"claimCampaign\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"termIdx\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"minNoc\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"numPerRound\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"viewLen\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_supportedVersion\",\"type\":\"uint256\"}],\"name\":\"updateSupportedVersion\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_maxNoc\",\"type\":\"uint256\"}],\"name\":\"updateMaxNoc\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_minNoc\",\"type\":\"uint256\"}],\"name\":\"updateMinNoc\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"acceptableBlocks\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"setAdmissionAddr\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_termLen\",\"type\":\"uint256\"}],\"name\":\"updateTermLen\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"supportedVersion\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_acceptableBlocks\",\"type\":\"uint256\"}],\"name\":\"updateAcceptableBlocks\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_candidate\",\"type\":\"address\"}],\"name\":\"candidateInfoOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"},{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"maxNoc\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"setRnodeInterface\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_admissionAddr\",\"type\":\"address\"},{\"name\":\"_rnodeAddr\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"fallback\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"name\":\"candidate\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"startTermIdx\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"stopTermIdx\",\"type\":\"uint256\"}],\"name\":\"ClaimCampaign\",\"type\":\"event\"}]"
 
@@ -389,6 +402,19 @@ func (_Campaign *CampaignCallerSession) AcceptableBlocks() (*big.Int, error) {
 	return _Campaign.Contract.AcceptableBlocks(&_Campaign.CallOpts)
 }
 
+// BatchAcceptableBlocks adds a call of the contract method 0xa9d1de48 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function acceptableBlocks() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchAcceptableBlocks(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "acceptableBlocks")
+	return ret0, err
+}
+
 // CandidateInfoOf is a free data retrieval call binding the contract method 0xdb438269.
 //
 // Solidity: function candidateInfoOf(_candidate address) constant returns(uint256, uint256, uint256)
@@ -421,6 +447,25 @@ func (_Campaign *CampaignCallerSession) CandidateInfoOf(_candidate common.Addres
 	return _Campaign.Contract.CandidateInfoOf(&_Campaign.CallOpts, _candidate)
 }
 
+// BatchCandidateInfoOf adds a call of the contract method 0xdb438269 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function candidateInfoOf(_candidate address) constant returns(uint256, uint256, uint256)
+func (_Campaign *CampaignCaller) BatchCandidateInfoOf(batch *bind.BatchCall, _candidate common.Address) (**big.Int, **big.Int, **big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+		ret1 = new(*big.Int)
+		ret2 = new(*big.Int)
+	)
+	out := &[]interface{}{
+		ret0,
+		ret1,
+		ret2,
+	}
+	err := _Campaign.contract.BatchCall(batch, out, "candidateInfoOf", _candidate)
+	return ret0, ret1, ret2, err
+}
+
 // CandidatesOf is a free data retrieval call binding the contract method 0x1984ab00.
 //
 // Solidity: function candidatesOf(_termIdx uint256) constant returns(address[])
@@ -447,6 +492,19 @@ func (_Campaign *CampaignCallerSession) CandidatesOf(_termIdx *big.Int) ([]commo
 	return _Campaign.Contract.CandidatesOf(&_Campaign.CallOpts, _termIdx)
 }
 
+// BatchCandidatesOf adds a call of the contract method 0x1984ab00 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function candidatesOf(_termIdx uint256) constant returns(address[])
+func (_Campaign *CampaignCaller) BatchCandidatesOf(batch *bind.BatchCall, _termIdx *big.Int) (*[]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "candidatesOf", _termIdx)
+	return ret0, err
+}
+
 // MaxNoc is a free data retrieval call binding the contract method 0xe2b28158.
 //
 // Solidity: function maxNoc() constant returns(uint256)
@@ -473,6 +531,19 @@ func (_Campaign *CampaignCallerSession) MaxNoc() (*big.Int, error) {
 	return _Campaign.Contract.MaxNoc(&_Campaign.CallOpts)
 }
 
+// BatchMaxNoc adds a call of the contract method 0xe2b28158 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function maxNoc() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchMaxNoc(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "maxNoc")
+	return ret0, err
+}
+
 // MinNoc is a free data retrieval call binding the contract method 0x3a713e37.
 //
 // Solidity: function minNoc() constant returns(uint256)
@@ -499,6 +570,19 @@ func (_Campaign *CampaignCallerSession) MinNoc() (*big.Int, error) {
 	return _Campaign.Contract.MinNoc(&_Campaign.CallOpts)
 }
 
+// BatchMinNoc adds a call of the contract method 0x3a713e37 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function minNoc() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchMinNoc(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "minNoc")
+	return ret0, err
+}
+
 // NumPerRound is a free data retrieval call binding the contract method 0x4b6b164b.
 //
 // Solidity: function numPerRound() constant returns(uint256)
@@ -525,6 +609,19 @@ func (_Campaign *CampaignCallerSession) NumPerRound() (*big.Int, error) {
 	return _Campaign.Contract.NumPerRound(&_Campaign.CallOpts)
 }
 
+// BatchNumPerRound adds a call of the contract method 0x4b6b164b to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function numPerRound() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchNumPerRound(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "numPerRound")
+	return ret0, err
+}
+
 // SupportedVersion is a free data retrieval call binding the contract method 0xd5601e9f.
 //
 // Solidity: function supportedVersion() constant returns(uint256)
@@ -551,6 +648,19 @@ func (_Campaign *CampaignCallerSession) SupportedVersion() (*big.Int, error) {
 	return _Campaign.Contract.SupportedVersion(&_Campaign.CallOpts)
 }
 
+// BatchSupportedVersion adds a call of the contract method 0xd5601e9f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function supportedVersion() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchSupportedVersion(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "supportedVersion")
+	return ret0, err
+}
+
 // TermIdx is a free data retrieval call binding the contract method 0x35805726.
 //
 // Solidity: function termIdx() constant returns(uint256)
@@ -577,6 +687,19 @@ func (_Campaign *CampaignCallerSession) TermIdx() (*big.Int, error) {
 	return _Campaign.Contract.TermIdx(&_Campaign.CallOpts)
 }
 
+// BatchTermIdx adds a call of the contract method 0x35805726 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function termIdx() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchTermIdx(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "termIdx")
+	return ret0, err
+}
+
 // TermLen is a free data retrieval call binding the contract method 0x14b5980e.
 //
 // Solidity: function termLen() constant returns(uint256)
@@ -603,6 +726,19 @@ func (_Campaign *CampaignCallerSession) TermLen() (*big.Int, error) {
 	return _Campaign.Contract.TermLen(&_Campaign.CallOpts)
 }
 
+// BatchTermLen adds a call of the contract method 0x14b5980e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function termLen() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchTermLen(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "termLen")
+	return ret0, err
+}
+
 // ViewLen is a free data retrieval call binding the contract method 0x68f237a1.
 //
 // Solidity: function viewLen() constant returns(uint256)
@@ -629,6 +765,19 @@ func (_Campaign *CampaignCallerSession) ViewLen() (*big.Int, error) {
 	return _Campaign.Contract.ViewLen(&_Campaign.CallOpts)
 }
 
+// BatchViewLen adds a call of the contract method 0x68f237a1 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function viewLen() constant returns(uint256)
+func (_Campaign *CampaignCaller) BatchViewLen(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Campaign.contract.BatchCall(batch, out, "viewLen")
+	return ret0, err
+}
+
 // ClaimCampaign is a paid mutator transaction binding the contract method 0x350cc724.
 //
 // Solidity: function claimCampaign(_termsToCampaign uint256, _cpuNonce uint64, _cpuBlockNumber uint256, _memoryNonce uint64, _memoryBlockNumber uint256, version uint256) returns()
@@ -921,6 +1070,66 @@ func (_Campaign *CampaignFilterer) WatchClaimCampaign(opts *bind.WatchOpts, sink
 	}), nil
 }
 
+// CampaignClaimCampaignFollower is returned from FollowClaimCampaign and is used to follow the unpacked data for ClaimCampaign events raised by the Campaign contract across chain reorganisations.
+type CampaignClaimCampaignFollower struct {
+	Event *CampaignClaimCampaign // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *CampaignClaimCampaignFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(CampaignClaimCampaign)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *CampaignClaimCampaignFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *CampaignClaimCampaignFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *CampaignClaimCampaignFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowClaimCampaign is a free log following operation binding the contract event 0x8d468194bdd18296bee5d126aa15cc492d26bdf22a0585c4a47ec4490d3a0fcf,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ClaimCampaign(candidate address, startTermIdx uint256, stopTermIdx uint256)
+func (_Campaign *CampaignFilterer) FollowClaimCampaign(opts *bind.FollowOpts) (*CampaignClaimCampaignFollower, error) {
+
+	follower, err := _Campaign.contract.FollowLogs(opts, "ClaimCampaign")
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignClaimCampaignFollower{contract: _Campaign.contract, event: "ClaimCampaign", follower: follower}, nil
+}
+
 // RnodeInterfaceABI is the input ABI used to generate the binding from.
 const RnodeInterfaceABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"isRnode\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"}]"
 
@@ -1108,6 +1317,19 @@ func (_RnodeInterface *RnodeInterfaceCallerSession) IsRnode(_addr common.Address
 	return _RnodeInterface.Contract.IsRnode(&_RnodeInterface.CallOpts, _addr)
 }
 
+// BatchIsRnode adds a call of the contract method 0xa8f07697 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function isRnode(_addr address) constant returns(bool)
+func (_RnodeInterface *RnodeInterfaceCaller) BatchIsRnode(batch *bind.BatchCall, _addr common.Address) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _RnodeInterface.contract.BatchCall(batch, out, "isRnode", _addr)
+	return ret0, err
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
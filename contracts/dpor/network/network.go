@@ -202,6 +202,19 @@ func (_Network *NetworkCallerSession) Count() (*big.Int, error) {
 	return _Network.Contract.Count(&_Network.CallOpts)
 }
 
+// BatchCount adds a call of the contract method 0x06661abd to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function count() constant returns(uint256)
+func (_Network *NetworkCaller) BatchCount(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Network.contract.BatchCall(batch, out, "count")
+	return ret0, err
+}
+
 // Gap is a free data retrieval call binding the contract method 0x6c32c0a6.
 //
 // Solidity: function gap() constant returns(uint256)
@@ -228,6 +241,19 @@ func (_Network *NetworkCallerSession) Gap() (*big.Int, error) {
 	return _Network.Contract.Gap(&_Network.CallOpts)
 }
 
+// BatchGap adds a call of the contract method 0x6c32c0a6 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function gap() constant returns(uint256)
+func (_Network *NetworkCaller) BatchGap(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Network.contract.BatchCall(batch, out, "gap")
+	return ret0, err
+}
+
 // Host is a free data retrieval call binding the contract method 0xf437bc59.
 //
 // Solidity: function host() constant returns(string)
@@ -254,6 +280,19 @@ func (_Network *NetworkCallerSession) Host() (string, error) {
 	return _Network.Contract.Host(&_Network.CallOpts)
 }
 
+// BatchHost adds a call of the contract method 0xf437bc59 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function host() constant returns(string)
+func (_Network *NetworkCaller) BatchHost(batch *bind.BatchCall) (*string, error) {
+	var (
+		ret0 = new(string)
+	)
+	out := ret0
+	err := _Network.contract.BatchCall(batch, out, "host")
+	return ret0, err
+}
+
 // Open is a free data retrieval call binding the contract method 0xfcfff16f.
 //
 // Solidity: function open() constant returns(bool)
@@ -280,6 +319,19 @@ func (_Network *NetworkCallerSession) Open() (bool, error) {
 	return _Network.Contract.Open(&_Network.CallOpts)
 }
 
+// BatchOpen adds a call of the contract method 0xfcfff16f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function open() constant returns(bool)
+func (_Network *NetworkCaller) BatchOpen(batch *bind.BatchCall) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Network.contract.BatchCall(batch, out, "open")
+	return ret0, err
+}
+
 // Timeout is a free data retrieval call binding the contract method 0x70dea79a.
 //
 // Solidity: function timeout() constant returns(uint256)
@@ -306,6 +358,19 @@ func (_Network *NetworkCallerSession) Timeout() (*big.Int, error) {
 	return _Network.Contract.Timeout(&_Network.CallOpts)
 }
 
+// BatchTimeout adds a call of the contract method 0x70dea79a to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function timeout() constant returns(uint256)
+func (_Network *NetworkCaller) BatchTimeout(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Network.contract.BatchCall(batch, out, "timeout")
+	return ret0, err
+}
+
 // UpdateCount is a paid mutator transaction binding the contract method 0x45516ee0.
 //
 // Solidity: function updateCount(_count uint256) returns()
@@ -534,6 +599,66 @@ func (_Network *NetworkFilterer) WatchUpdateConfig(opts *bind.WatchOpts, sink ch
 	}), nil
 }
 
+// NetworkUpdateConfigFollower is returned from FollowUpdateConfig and is used to follow the unpacked data for UpdateConfig events raised by the Network contract across chain reorganisations.
+type NetworkUpdateConfigFollower struct {
+	Event *NetworkUpdateConfig // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *NetworkUpdateConfigFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(NetworkUpdateConfig)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *NetworkUpdateConfigFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *NetworkUpdateConfigFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *NetworkUpdateConfigFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateConfig is a free log following operation binding the contract event 0x6818c9181f3a8cb0f4d8178667c423a4c4ed24fc2410822be08e76ef50b2de1e,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateConfig(config string, value uint256)
+func (_Network *NetworkFilterer) FollowUpdateConfig(opts *bind.FollowOpts) (*NetworkUpdateConfigFollower, error) {
+
+	follower, err := _Network.contract.FollowLogs(opts, "UpdateConfig")
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkUpdateConfigFollower{contract: _Network.contract, event: "UpdateConfig", follower: follower}, nil
+}
+
 // NetworkUpdateHostIterator is returned from FilterUpdateHost and is used to iterate over the raw logs and unpacked data for UpdateHost events raised by the Network contract.
 type NetworkUpdateHostIterator struct {
 	Event *NetworkUpdateHost // Event containing the contract specifics and raw log
@@ -656,6 +781,66 @@ func (_Network *NetworkFilterer) WatchUpdateHost(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// NetworkUpdateHostFollower is returned from FollowUpdateHost and is used to follow the unpacked data for UpdateHost events raised by the Network contract across chain reorganisations.
+type NetworkUpdateHostFollower struct {
+	Event *NetworkUpdateHost // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *NetworkUpdateHostFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(NetworkUpdateHost)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *NetworkUpdateHostFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *NetworkUpdateHostFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *NetworkUpdateHostFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateHost is a free log following operation binding the contract event 0x958adb313e9ddd949012b779ce40fe9b2e593e682b7b256c58f100f1c3235c33,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateHost(value string)
+func (_Network *NetworkFilterer) FollowUpdateHost(opts *bind.FollowOpts) (*NetworkUpdateHostFollower, error) {
+
+	follower, err := _Network.contract.FollowLogs(opts, "UpdateHost")
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkUpdateHostFollower{contract: _Network.contract, event: "UpdateHost", follower: follower}, nil
+}
+
 // NetworkUpdateOpenIterator is returned from FilterUpdateOpen and is used to iterate over the raw logs and unpacked data for UpdateOpen events raised by the Network contract.
 type NetworkUpdateOpenIterator struct {
 	Event *NetworkUpdateOpen // Event containing the contract specifics and raw log
@@ -777,3 +962,63 @@ func (_Network *NetworkFilterer) WatchUpdateOpen(opts *bind.WatchOpts, sink chan
 		}
 	}), nil
 }
+
+// NetworkUpdateOpenFollower is returned from FollowUpdateOpen and is used to follow the unpacked data for UpdateOpen events raised by the Network contract across chain reorganisations.
+type NetworkUpdateOpenFollower struct {
+	Event *NetworkUpdateOpen // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *NetworkUpdateOpenFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(NetworkUpdateOpen)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *NetworkUpdateOpenFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *NetworkUpdateOpenFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *NetworkUpdateOpenFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateOpen is a free log following operation binding the contract event 0x3acb93048c9b0c52dcccea319343be25e3be345a3a087b9f3304ef4b7be66449,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateOpen(value bool)
+func (_Network *NetworkFilterer) FollowUpdateOpen(opts *bind.FollowOpts) (*NetworkUpdateOpenFollower, error) {
+
+	follower, err := _Network.contract.FollowLogs(opts, "UpdateOpen")
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkUpdateOpenFollower{contract: _Network.contract, event: "UpdateOpen", follower: follower}, nil
+}
@@ -88,12 +88,11 @@ func (cc *ApiClient) BlockByNumber(ctx context.Context, number *big.Int) (*types
 // HeaderByNumber returns a block header from the current canonical chain. If number is
 // nil, the latest known header is returned.
 func (cc *ApiClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	return cc.ChainBackend.HeaderByNumber(ctx, rpc.BlockNumber(number.Uint64()))
+	return cc.ChainBackend.HeaderByNumber(ctx, toBlockNumber(number))
 }
 
 func (cc *ApiClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
-	blockNr := rpc.LatestBlockNumber
-	state, _, err := cc.ChainBackend.StateAndHeaderByNumber(ctx, blockNr, false)
+	state, _, err := cc.ChainBackend.StateAndHeaderByNumber(ctx, toBlockNumber(blockNumber), false)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -102,12 +101,21 @@ func (cc *ApiClient) CodeAt(ctx context.Context, account common.Address, blockNu
 }
 
 func (cc *ApiClient) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), rpc.LatestBlockNumber)
+	result, err := cc.ContractBackend.Call(ctx, toCallArg(call), toBlockNumber(blockNumber))
 	if err != nil {
 		log.Fatal("CallContract using PublicBlockChainAPI is error ", "error is ", err)
 	}
 	return result, err
 }
+
+// toBlockNumber returns the rpc block number of number, the latest one if nil.
+func toBlockNumber(number *big.Int) rpc.BlockNumber {
+	if number == nil {
+		return rpc.LatestBlockNumber
+	}
+	return rpc.BlockNumber(number.Int64())
+}
+
 func toCallArg(msg cpchain.CallMsg) cpcapi.CallArgs {
 	arg := cpcapi.CallArgs{
 		From: msg.From,
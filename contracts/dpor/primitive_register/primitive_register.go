@@ -6,6 +6,7 @@ import (
 
 	cpchain "bitbucket.org/cpchain/chain"
 	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/contracts/dpor/primitives"
 	"bitbucket.org/cpchain/chain/core/vm"
 	"bitbucket.org/cpchain/chain/types"
@@ -27,18 +28,20 @@ type ContractAPI interface {
 }
 
 func RegisterPrimitiveContracts() {
-	for addr, c := range MakePrimitiveContracts() {
-		err := vm.RegisterPrimitiveContract(addr, c)
+	for name, c := range MakePrimitiveContracts() {
+		err := vm.RegisterPrimitive(name, c)
 		if err != nil {
-			log.Fatal("register primitive contract error", "error", err, "addr", addr)
+			log.Fatal("register primitive contract error", "error", err, "name", name)
 		}
 	}
 }
 
-func MakePrimitiveContracts() map[common.Address]vm.PrimitiveContract {
-	contracts := make(map[common.Address]vm.PrimitiveContract)
+// MakePrimitiveContracts returns the primitive contracts by name, the chain
+// config enabling them at their addresses.
+func MakePrimitiveContracts() map[string]vm.PrimitiveContract {
+	contracts := make(map[string]vm.PrimitiveContract)
 
-	contracts[common.BytesToAddress([]byte{106})] = &primitives.CpuPowValidate{}
-	contracts[common.BytesToAddress([]byte{107})] = &primitives.MemPowValidate{}
+	contracts[configs.PrimitiveCpuPowValidate] = &primitives.CpuPowValidate{}
+	contracts[configs.PrimitiveMemPowValidate] = &primitives.MemPowValidate{}
 	return contracts
 }
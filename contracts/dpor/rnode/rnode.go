@@ -212,6 +212,23 @@ func (_Rnode *RnodeCallerSession) Participants(arg0 common.Address) (struct {
 	return _Rnode.Contract.Participants(&_Rnode.CallOpts, arg0)
 }
 
+// BatchParticipants adds a call of the contract method 0x595aa13d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function Participants( address) constant returns(lockedDeposit uint256, lockedTime uint256)
+func (_Rnode *RnodeCaller) BatchParticipants(batch *bind.BatchCall, arg0 common.Address) (*struct {
+	LockedDeposit *big.Int
+	LockedTime    *big.Int
+}, error) {
+	ret := new(struct {
+		LockedDeposit *big.Int
+		LockedTime    *big.Int
+	})
+	out := ret
+	err := _Rnode.contract.BatchCall(batch, out, "Participants", arg0)
+	return ret, err
+}
+
 // Enabled is a free data retrieval call binding the contract method 0x238dafe0.
 //
 // Solidity: function enabled() constant returns(bool)
@@ -238,6 +255,19 @@ func (_Rnode *RnodeCallerSession) Enabled() (bool, error) {
 	return _Rnode.Contract.Enabled(&_Rnode.CallOpts)
 }
 
+// BatchEnabled adds a call of the contract method 0x238dafe0 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function enabled() constant returns(bool)
+func (_Rnode *RnodeCaller) BatchEnabled(batch *bind.BatchCall) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "enabled")
+	return ret0, err
+}
+
 // GetRnodeNum is a free data retrieval call binding the contract method 0x0b443f42.
 //
 // Solidity: function getRnodeNum() constant returns(uint256)
@@ -264,6 +294,19 @@ func (_Rnode *RnodeCallerSession) GetRnodeNum() (*big.Int, error) {
 	return _Rnode.Contract.GetRnodeNum(&_Rnode.CallOpts)
 }
 
+// BatchGetRnodeNum adds a call of the contract method 0x0b443f42 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getRnodeNum() constant returns(uint256)
+func (_Rnode *RnodeCaller) BatchGetRnodeNum(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "getRnodeNum")
+	return ret0, err
+}
+
 // GetRnodes is a free data retrieval call binding the contract method 0xe508bb85.
 //
 // Solidity: function getRnodes() constant returns(address[])
@@ -290,6 +333,19 @@ func (_Rnode *RnodeCallerSession) GetRnodes() ([]common.Address, error) {
 	return _Rnode.Contract.GetRnodes(&_Rnode.CallOpts)
 }
 
+// BatchGetRnodes adds a call of the contract method 0xe508bb85 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getRnodes() constant returns(address[])
+func (_Rnode *RnodeCaller) BatchGetRnodes(batch *bind.BatchCall) (*[]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "getRnodes")
+	return ret0, err
+}
+
 // IsContract is a free data retrieval call binding the contract method 0x16279055.
 //
 // Solidity: function isContract(addr address) constant returns(bool)
@@ -316,6 +372,19 @@ func (_Rnode *RnodeCallerSession) IsContract(addr common.Address) (bool, error)
 	return _Rnode.Contract.IsContract(&_Rnode.CallOpts, addr)
 }
 
+// BatchIsContract adds a call of the contract method 0x16279055 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function isContract(addr address) constant returns(bool)
+func (_Rnode *RnodeCaller) BatchIsContract(batch *bind.BatchCall, addr common.Address) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "isContract", addr)
+	return ret0, err
+}
+
 // IsRnode is a free data retrieval call binding the contract method 0xa8f07697.
 //
 // Solidity: function isRnode(addr address) constant returns(bool)
@@ -342,6 +411,19 @@ func (_Rnode *RnodeCallerSession) IsRnode(addr common.Address) (bool, error) {
 	return _Rnode.Contract.IsRnode(&_Rnode.CallOpts, addr)
 }
 
+// BatchIsRnode adds a call of the contract method 0xa8f07697 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function isRnode(addr address) constant returns(bool)
+func (_Rnode *RnodeCaller) BatchIsRnode(batch *bind.BatchCall, addr common.Address) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "isRnode", addr)
+	return ret0, err
+}
+
 // Period is a free data retrieval call binding the contract method 0xef78d4fd.
 //
 // Solidity: function period() constant returns(uint256)
@@ -368,6 +450,19 @@ func (_Rnode *RnodeCallerSession) Period() (*big.Int, error) {
 	return _Rnode.Contract.Period(&_Rnode.CallOpts)
 }
 
+// BatchPeriod adds a call of the contract method 0xef78d4fd to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function period() constant returns(uint256)
+func (_Rnode *RnodeCaller) BatchPeriod(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "period")
+	return ret0, err
+}
+
 // RnodeThreshold is a free data retrieval call binding the contract method 0xb7b3e9da.
 //
 // Solidity: function rnodeThreshold() constant returns(uint256)
@@ -394,6 +489,19 @@ func (_Rnode *RnodeCallerSession) RnodeThreshold() (*big.Int, error) {
 	return _Rnode.Contract.RnodeThreshold(&_Rnode.CallOpts)
 }
 
+// BatchRnodeThreshold adds a call of the contract method 0xb7b3e9da to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function rnodeThreshold() constant returns(uint256)
+func (_Rnode *RnodeCaller) BatchRnodeThreshold(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "rnodeThreshold")
+	return ret0, err
+}
+
 // SupportedVersion is a free data retrieval call binding the contract method 0xd5601e9f.
 //
 // Solidity: function supportedVersion() constant returns(uint256)
@@ -420,6 +528,19 @@ func (_Rnode *RnodeCallerSession) SupportedVersion() (*big.Int, error) {
 	return _Rnode.Contract.SupportedVersion(&_Rnode.CallOpts)
 }
 
+// BatchSupportedVersion adds a call of the contract method 0xd5601e9f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function supportedVersion() constant returns(uint256)
+func (_Rnode *RnodeCaller) BatchSupportedVersion(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rnode.contract.BatchCall(batch, out, "supportedVersion")
+	return ret0, err
+}
+
 // DisableContract is a paid mutator transaction binding the contract method 0x894ba833.
 //
 // Solidity: function disableContract() returns()
@@ -733,6 +854,66 @@ func (_Rnode *RnodeFilterer) WatchNewRnode(opts *bind.WatchOpts, sink chan<- *Rn
 	}), nil
 }
 
+// RnodeNewRnodeFollower is returned from FollowNewRnode and is used to follow the unpacked data for NewRnode events raised by the Rnode contract across chain reorganisations.
+type RnodeNewRnodeFollower struct {
+	Event *RnodeNewRnode // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RnodeNewRnodeFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RnodeNewRnode)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RnodeNewRnodeFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RnodeNewRnodeFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RnodeNewRnodeFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowNewRnode is a free log following operation binding the contract event 0x586bfaa7a657ad9313326c9269639546950d589bd479b3d6928be469d6dc2903,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e NewRnode(who address, lockedDeposit uint256, lockedTime uint256)
+func (_Rnode *RnodeFilterer) FollowNewRnode(opts *bind.FollowOpts) (*RnodeNewRnodeFollower, error) {
+
+	follower, err := _Rnode.contract.FollowLogs(opts, "NewRnode")
+	if err != nil {
+		return nil, err
+	}
+	return &RnodeNewRnodeFollower{contract: _Rnode.contract, event: "NewRnode", follower: follower}, nil
+}
+
 // RnodeRnodeQuitIterator is returned from FilterRnodeQuit and is used to iterate over the raw logs and unpacked data for RnodeQuit events raised by the Rnode contract.
 type RnodeRnodeQuitIterator struct {
 	Event *RnodeRnodeQuit // Event containing the contract specifics and raw log
@@ -855,6 +1036,66 @@ func (_Rnode *RnodeFilterer) WatchRnodeQuit(opts *bind.WatchOpts, sink chan<- *R
 	}), nil
 }
 
+// RnodeRnodeQuitFollower is returned from FollowRnodeQuit and is used to follow the unpacked data for RnodeQuit events raised by the Rnode contract across chain reorganisations.
+type RnodeRnodeQuitFollower struct {
+	Event *RnodeRnodeQuit // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RnodeRnodeQuitFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RnodeRnodeQuit)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RnodeRnodeQuitFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RnodeRnodeQuitFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RnodeRnodeQuitFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowRnodeQuit is a free log following operation binding the contract event 0x602a2a9c94f70293aa2be9077f0b2dc89d388bc293fdbcd968274f43494c380d,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e RnodeQuit(who address)
+func (_Rnode *RnodeFilterer) FollowRnodeQuit(opts *bind.FollowOpts) (*RnodeRnodeQuitFollower, error) {
+
+	follower, err := _Rnode.contract.FollowLogs(opts, "RnodeQuit")
+	if err != nil {
+		return nil, err
+	}
+	return &RnodeRnodeQuitFollower{contract: _Rnode.contract, event: "RnodeQuit", follower: follower}, nil
+}
+
 // RnodeOwnerRefundIterator is returned from FilterOwnerRefund and is used to iterate over the raw logs and unpacked data for OwnerRefund events raised by the Rnode contract.
 type RnodeOwnerRefundIterator struct {
 	Event *RnodeOwnerRefund // Event containing the contract specifics and raw log
@@ -978,6 +1219,66 @@ func (_Rnode *RnodeFilterer) WatchOwnerRefund(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// RnodeOwnerRefundFollower is returned from FollowOwnerRefund and is used to follow the unpacked data for OwnerRefund events raised by the Rnode contract across chain reorganisations.
+type RnodeOwnerRefundFollower struct {
+	Event *RnodeOwnerRefund // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RnodeOwnerRefundFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RnodeOwnerRefund)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RnodeOwnerRefundFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RnodeOwnerRefundFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RnodeOwnerRefundFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowOwnerRefund is a free log following operation binding the contract event 0x3914ba80eb00486e7a58b91fb4795283df0c5b507eea9cf7c77cce26cc70d25c,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ownerRefund(who address, amount uint256)
+func (_Rnode *RnodeFilterer) FollowOwnerRefund(opts *bind.FollowOpts) (*RnodeOwnerRefundFollower, error) {
+
+	follower, err := _Rnode.contract.FollowLogs(opts, "ownerRefund")
+	if err != nil {
+		return nil, err
+	}
+	return &RnodeOwnerRefundFollower{contract: _Rnode.contract, event: "ownerRefund", follower: follower}, nil
+}
+
 // RnodeOwnerRefundAllIterator is returned from FilterOwnerRefundAll and is used to iterate over the raw logs and unpacked data for OwnerRefundAll events raised by the Rnode contract.
 type RnodeOwnerRefundAllIterator struct {
 	Event *RnodeOwnerRefundAll // Event containing the contract specifics and raw log
@@ -1100,6 +1401,66 @@ func (_Rnode *RnodeFilterer) WatchOwnerRefundAll(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// RnodeOwnerRefundAllFollower is returned from FollowOwnerRefundAll and is used to follow the unpacked data for OwnerRefundAll events raised by the Rnode contract across chain reorganisations.
+type RnodeOwnerRefundAllFollower struct {
+	Event *RnodeOwnerRefundAll // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RnodeOwnerRefundAllFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RnodeOwnerRefundAll)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RnodeOwnerRefundAllFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RnodeOwnerRefundAllFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RnodeOwnerRefundAllFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowOwnerRefundAll is a free log following operation binding the contract event 0xb65ebb6b17695b3a5612c7a0f6f60e649c02ba24b36b546b8d037e98215fdb8d,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ownerRefundAll(numOfInvestor uint256)
+func (_Rnode *RnodeFilterer) FollowOwnerRefundAll(opts *bind.FollowOpts) (*RnodeOwnerRefundAllFollower, error) {
+
+	follower, err := _Rnode.contract.FollowLogs(opts, "ownerRefundAll")
+	if err != nil {
+		return nil, err
+	}
+	return &RnodeOwnerRefundAllFollower{contract: _Rnode.contract, event: "ownerRefundAll", follower: follower}, nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
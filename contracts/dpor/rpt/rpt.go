@@ -202,6 +202,19 @@ func (_Rpt *RptCallerSession) Alpha() (*big.Int, error) {
 	return _Rpt.Contract.Alpha(&_Rpt.CallOpts)
 }
 
+// BatchAlpha adds a call of the contract method 0xdb1d0fd5 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function alpha() constant returns(uint256)
+func (_Rpt *RptCaller) BatchAlpha(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "alpha")
+	return ret0, err
+}
+
 // Beta is a free data retrieval call binding the contract method 0x9faa3c91.
 //
 // Solidity: function beta() constant returns(uint256)
@@ -228,6 +241,19 @@ func (_Rpt *RptCallerSession) Beta() (*big.Int, error) {
 	return _Rpt.Contract.Beta(&_Rpt.CallOpts)
 }
 
+// BatchBeta adds a call of the contract method 0x9faa3c91 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function beta() constant returns(uint256)
+func (_Rpt *RptCaller) BatchBeta(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "beta")
+	return ret0, err
+}
+
 // Gamma is a free data retrieval call binding the contract method 0xb1373929.
 //
 // Solidity: function gamma() constant returns(uint256)
@@ -254,6 +280,19 @@ func (_Rpt *RptCallerSession) Gamma() (*big.Int, error) {
 	return _Rpt.Contract.Gamma(&_Rpt.CallOpts)
 }
 
+// BatchGamma adds a call of the contract method 0xb1373929 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function gamma() constant returns(uint256)
+func (_Rpt *RptCaller) BatchGamma(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "gamma")
+	return ret0, err
+}
+
 // LowRptPercentage is a free data retrieval call binding the contract method 0xf75ca7ff.
 //
 // Solidity: function lowRptPercentage() constant returns(uint256)
@@ -280,6 +319,19 @@ func (_Rpt *RptCallerSession) LowRptPercentage() (*big.Int, error) {
 	return _Rpt.Contract.LowRptPercentage(&_Rpt.CallOpts)
 }
 
+// BatchLowRptPercentage adds a call of the contract method 0xf75ca7ff to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function lowRptPercentage() constant returns(uint256)
+func (_Rpt *RptCaller) BatchLowRptPercentage(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "lowRptPercentage")
+	return ret0, err
+}
+
 // LowRptSeats is a free data retrieval call binding the contract method 0xfb2eb7f6.
 //
 // Solidity: function lowRptSeats() constant returns(uint256)
@@ -306,6 +358,19 @@ func (_Rpt *RptCallerSession) LowRptSeats() (*big.Int, error) {
 	return _Rpt.Contract.LowRptSeats(&_Rpt.CallOpts)
 }
 
+// BatchLowRptSeats adds a call of the contract method 0xfb2eb7f6 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function lowRptSeats() constant returns(uint256)
+func (_Rpt *RptCaller) BatchLowRptSeats(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "lowRptSeats")
+	return ret0, err
+}
+
 // Omega is a free data retrieval call binding the contract method 0x2262a1b3.
 //
 // Solidity: function omega() constant returns(uint256)
@@ -332,6 +397,19 @@ func (_Rpt *RptCallerSession) Omega() (*big.Int, error) {
 	return _Rpt.Contract.Omega(&_Rpt.CallOpts)
 }
 
+// BatchOmega adds a call of the contract method 0x2262a1b3 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function omega() constant returns(uint256)
+func (_Rpt *RptCaller) BatchOmega(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "omega")
+	return ret0, err
+}
+
 // Psi is a free data retrieval call binding the contract method 0x86f87fdd.
 //
 // Solidity: function psi() constant returns(uint256)
@@ -358,6 +436,19 @@ func (_Rpt *RptCallerSession) Psi() (*big.Int, error) {
 	return _Rpt.Contract.Psi(&_Rpt.CallOpts)
 }
 
+// BatchPsi adds a call of the contract method 0x86f87fdd to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function psi() constant returns(uint256)
+func (_Rpt *RptCaller) BatchPsi(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "psi")
+	return ret0, err
+}
+
 // TotalSeats is a free data retrieval call binding the contract method 0x1ffc615e.
 //
 // Solidity: function totalSeats() constant returns(uint256)
@@ -384,6 +475,19 @@ func (_Rpt *RptCallerSession) TotalSeats() (*big.Int, error) {
 	return _Rpt.Contract.TotalSeats(&_Rpt.CallOpts)
 }
 
+// BatchTotalSeats adds a call of the contract method 0x1ffc615e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function totalSeats() constant returns(uint256)
+func (_Rpt *RptCaller) BatchTotalSeats(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "totalSeats")
+	return ret0, err
+}
+
 // Window is a free data retrieval call binding the contract method 0x461645bf.
 //
 // Solidity: function window() constant returns(uint256)
@@ -410,6 +514,19 @@ func (_Rpt *RptCallerSession) Window() (*big.Int, error) {
 	return _Rpt.Contract.Window(&_Rpt.CallOpts)
 }
 
+// BatchWindow adds a call of the contract method 0x461645bf to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function window() constant returns(uint256)
+func (_Rpt *RptCaller) BatchWindow(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "window")
+	return ret0, err
+}
+
 // UpdateAlpha is a paid mutator transaction binding the contract method 0x06d2d3dc.
 //
 // Solidity: function updateAlpha(_alpha uint256) returns()
@@ -763,6 +880,66 @@ func (_Rpt *RptFilterer) WatchUpdateElectionConfigs(opts *bind.WatchOpts, sink c
 	}), nil
 }
 
+// RptUpdateElectionConfigsFollower is returned from FollowUpdateElectionConfigs and is used to follow the unpacked data for UpdateElectionConfigs events raised by the Rpt contract across chain reorganisations.
+type RptUpdateElectionConfigsFollower struct {
+	Event *RptUpdateElectionConfigs // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptUpdateElectionConfigsFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptUpdateElectionConfigs)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptUpdateElectionConfigsFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptUpdateElectionConfigsFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptUpdateElectionConfigsFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateElectionConfigs is a free log following operation binding the contract event 0x09e649367469a85db638685b74f92f1ef17cdebb4610b4c42b7da19c2f1b189c,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateElectionConfigs(blockNumber uint256)
+func (_Rpt *RptFilterer) FollowUpdateElectionConfigs(opts *bind.FollowOpts) (*RptUpdateElectionConfigsFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "UpdateElectionConfigs")
+	if err != nil {
+		return nil, err
+	}
+	return &RptUpdateElectionConfigsFollower{contract: _Rpt.contract, event: "UpdateElectionConfigs", follower: follower}, nil
+}
+
 // RptUpdateOneConfigIterator is returned from FilterUpdateOneConfig and is used to iterate over the raw logs and unpacked data for UpdateOneConfig events raised by the Rpt contract.
 type RptUpdateOneConfigIterator struct {
 	Event *RptUpdateOneConfig // Event containing the contract specifics and raw log
@@ -887,6 +1064,66 @@ func (_Rpt *RptFilterer) WatchUpdateOneConfig(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// RptUpdateOneConfigFollower is returned from FollowUpdateOneConfig and is used to follow the unpacked data for UpdateOneConfig events raised by the Rpt contract across chain reorganisations.
+type RptUpdateOneConfigFollower struct {
+	Event *RptUpdateOneConfig // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptUpdateOneConfigFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptUpdateOneConfig)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptUpdateOneConfigFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptUpdateOneConfigFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptUpdateOneConfigFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateOneConfig is a free log following operation binding the contract event 0x7c2d85cf45868065466ed7df2e23f26349626794d112e41a734a4e34727fcb21,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateOneConfig(blockNumber uint256, configName string, configValue uint256)
+func (_Rpt *RptFilterer) FollowUpdateOneConfig(opts *bind.FollowOpts) (*RptUpdateOneConfigFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "UpdateOneConfig")
+	if err != nil {
+		return nil, err
+	}
+	return &RptUpdateOneConfigFollower{contract: _Rpt.contract, event: "UpdateOneConfig", follower: follower}, nil
+}
+
 // RptUpdateWeightConfigsIterator is returned from FilterUpdateWeightConfigs and is used to iterate over the raw logs and unpacked data for UpdateWeightConfigs events raised by the Rpt contract.
 type RptUpdateWeightConfigsIterator struct {
 	Event *RptUpdateWeightConfigs // Event containing the contract specifics and raw log
@@ -1008,3 +1245,63 @@ func (_Rpt *RptFilterer) WatchUpdateWeightConfigs(opts *bind.WatchOpts, sink cha
 		}
 	}), nil
 }
+
+// RptUpdateWeightConfigsFollower is returned from FollowUpdateWeightConfigs and is used to follow the unpacked data for UpdateWeightConfigs events raised by the Rpt contract across chain reorganisations.
+type RptUpdateWeightConfigsFollower struct {
+	Event *RptUpdateWeightConfigs // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptUpdateWeightConfigsFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptUpdateWeightConfigs)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptUpdateWeightConfigsFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptUpdateWeightConfigsFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptUpdateWeightConfigsFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateWeightConfigs is a free log following operation binding the contract event 0x94cb95e42d1f9f5b3e73da8fddc18ba25b0c89408eb91f64e417c59c6833a82b,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateWeightConfigs(blockNumber uint256)
+func (_Rpt *RptFilterer) FollowUpdateWeightConfigs(opts *bind.FollowOpts) (*RptUpdateWeightConfigsFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "UpdateWeightConfigs")
+	if err != nil {
+		return nil, err
+	}
+	return &RptUpdateWeightConfigsFollower{contract: _Rpt.contract, event: "UpdateWeightConfigs", follower: follower}, nil
+}
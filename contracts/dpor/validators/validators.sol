@@ -0,0 +1,52 @@
+pragma solidity ^0.4.24;
+
+
+contract ValidatorRegistry {
+    address public owner;
+
+    address[] validators;
+    mapping(address => string) enodes;
+    mapping(address => bool) public isValidator;
+
+    modifier onlyOwner() {require(msg.sender == owner);_;}
+
+    event AddValidator(address indexed validator, string enode);
+    event RemoveValidator(address indexed validator);
+
+    constructor() public {
+        owner = msg.sender;
+    }
+
+    // addValidator adds a validator to the committee or updates its enode url.
+    function addValidator(address _addr, string _enode) public onlyOwner {
+        if (!isValidator[_addr]) {
+            isValidator[_addr] = true;
+            validators.push(_addr);
+        }
+        enodes[_addr] = _enode;
+        emit AddValidator(_addr, _enode);
+    }
+
+    // removeValidator removes a validator from the committee, moving the last
+    // validator to its place.
+    function removeValidator(address _addr) public onlyOwner {
+        require(isValidator[_addr]);
+        isValidator[_addr] = false;
+        for (uint256 i = 0; i < validators.length; i++) {
+            if (validators[i] == _addr) {
+                validators[i] = validators[validators.length - 1];
+                validators.length--;
+                break;
+            }
+        }
+        emit RemoveValidator(_addr);
+    }
+
+    function getValidators() public view returns (address[]) {
+        return validators;
+    }
+
+    function enodeOf(address _addr) public view returns (string) {
+        return enodes[_addr];
+    }
+}
@@ -0,0 +1,160 @@
+package validators_test
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"bitbucket.org/cpchain/chain/core"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ownerAddr   = crypto.PubkeyToAddress(ownerKey.PublicKey)
+
+	otherKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	otherAddr   = crypto.PubkeyToAddress(otherKey.PublicKey)
+
+	longEnode = "enode://" + strings.Repeat("ab", 64) + "@127.0.0.1:30310"
+)
+
+func deploy(t *testing.T) (*backends.SimulatedBackend, *validators.ValidatorRegistry) {
+	backend := backends.NewDporSimulatedBackend(core.GenesisAlloc{
+		ownerAddr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(configs.Cpc))},
+		otherAddr: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(configs.Cpc))},
+	})
+	_, _, instance, err := validators.DeployValidatorRegistry(bind.NewKeyedTransactor(ownerKey), backend)
+	if err != nil {
+		t.Fatalf("failed to deploy registry: %v", err)
+	}
+	backend.Commit()
+	return backend, instance
+}
+
+func checkValidators(t *testing.T, instance *validators.ValidatorRegistry, want []common.Address) {
+	have, err := instance.GetValidators(nil)
+	if err != nil {
+		t.Fatalf("failed to get validators: %v", err)
+	}
+	if len(have) != len(want) || (len(want) > 0 && !reflect.DeepEqual(have, want)) {
+		t.Fatalf("validators mismatch: have %x, want %x", have, want)
+	}
+	for _, addr := range want {
+		if ok, _ := instance.IsValidator(nil, addr); !ok {
+			t.Fatalf("%x not a validator", addr)
+		}
+	}
+}
+
+func TestValidatorRegistry(t *testing.T) {
+	backend, instance := deploy(t)
+	if owner, _ := instance.Owner(nil); owner != ownerAddr {
+		t.Fatalf("owner mismatch: have %x, want %x", owner, ownerAddr)
+	}
+	checkValidators(t, instance, nil)
+
+	addrs := []common.Address{{0x01}, {0x02}, {0x03}}
+	enodes := []string{"enode://short@127.0.0.1:30310", longEnode, ""}
+	opts := bind.NewKeyedTransactor(ownerKey)
+	for i, addr := range addrs {
+		if _, err := instance.AddValidator(opts, addr, enodes[i]); err != nil {
+			t.Fatalf("failed to add validator: %v", err)
+		}
+		backend.Commit()
+	}
+	checkValidators(t, instance, addrs)
+	for i, addr := range addrs {
+		if enode, _ := instance.EnodeOf(nil, addr); enode != enodes[i] {
+			t.Fatalf("enode mismatch: have %q, want %q", enode, enodes[i])
+		}
+	}
+
+	// Adding a validator again updates its enode only
+	if _, err := instance.AddValidator(opts, addrs[2], longEnode); err != nil {
+		t.Fatalf("failed to update validator: %v", err)
+	}
+	if _, err := instance.AddValidator(opts, addrs[1], "enode://updated@127.0.0.1:30310"); err != nil {
+		t.Fatalf("failed to update validator: %v", err)
+	}
+	backend.Commit()
+	checkValidators(t, instance, addrs)
+	if enode, _ := instance.EnodeOf(nil, addrs[2]); enode != longEnode {
+		t.Fatalf("enode mismatch: have %q, want %q", enode, longEnode)
+	}
+	if enode, _ := instance.EnodeOf(nil, addrs[1]); enode != "enode://updated@127.0.0.1:30310" {
+		t.Fatalf("short enode over a long one mismatch: have %q", enode)
+	}
+
+	// Removing a validator moves the last one to its place
+	if _, err := instance.RemoveValidator(opts, addrs[0]); err != nil {
+		t.Fatalf("failed to remove validator: %v", err)
+	}
+	backend.Commit()
+	checkValidators(t, instance, []common.Address{addrs[2], addrs[1]})
+	if ok, _ := instance.IsValidator(nil, addrs[0]); ok {
+		t.Fatalf("removed validator still listed")
+	}
+	if _, err := instance.RemoveValidator(opts, addrs[1]); err != nil {
+		t.Fatalf("failed to remove validator: %v", err)
+	}
+	if _, err := instance.RemoveValidator(opts, addrs[2]); err != nil {
+		t.Fatalf("failed to remove validator: %v", err)
+	}
+	backend.Commit()
+	checkValidators(t, instance, nil)
+
+	// Events are emitted along the changes
+	added, err := instance.FilterAddValidator(&bind.FilterOpts{Start: 0}, []common.Address{addrs[1]})
+	if err != nil {
+		t.Fatalf("failed to filter events: %v", err)
+	}
+	var enodesAdded []string
+	for added.Next() {
+		enodesAdded = append(enodesAdded, added.Event.Enode)
+	}
+	if want := []string{longEnode, "enode://updated@127.0.0.1:30310"}; !reflect.DeepEqual(enodesAdded, want) {
+		t.Fatalf("add events mismatch: have %q, want %q", enodesAdded, want)
+	}
+	removed, err := instance.FilterRemoveValidator(&bind.FilterOpts{Start: 0}, nil)
+	if err != nil {
+		t.Fatalf("failed to filter events: %v", err)
+	}
+	count := 0
+	for removed.Next() {
+		count++
+	}
+	if count != 3 {
+		t.Fatalf("remove events mismatch: have %d, want 3", count)
+	}
+}
+
+func TestValidatorRegistryOnlyOwner(t *testing.T) {
+	backend, instance := deploy(t)
+	other := bind.NewKeyedTransactor(otherKey)
+	other.GasLimit = 1000000
+	instance.AddValidator(other, common.Address{0x01}, "enode://other@127.0.0.1:30310")
+	backend.Commit()
+	checkValidators(t, instance, nil)
+
+	if _, err := instance.AddValidator(bind.NewKeyedTransactor(ownerKey), common.Address{0x01}, ""); err != nil {
+		t.Fatalf("failed to add validator: %v", err)
+	}
+	backend.Commit()
+	instance.RemoveValidator(other, common.Address{0x01})
+	backend.Commit()
+	checkValidators(t, instance, []common.Address{{0x01}})
+
+	// Unknown validators can't be removed
+	opts := bind.NewKeyedTransactor(ownerKey)
+	opts.GasLimit = 1000000
+	instance.RemoveValidator(opts, common.Address{0x02})
+	backend.Commit()
+	checkValidators(t, instance, []common.Address{{0x01}})
+}
@@ -202,6 +202,19 @@ func (_Pdash *PdashCallerSession) BlockOrders(arg0 *big.Int, arg1 *big.Int) (*bi
 	return _Pdash.Contract.BlockOrders(&_Pdash.CallOpts, arg0, arg1)
 }
 
+// BatchBlockOrders adds a call of the contract method 0x7478d485 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function blockOrders( uint256,  uint256) constant returns(uint256)
+func (_Pdash *PdashCaller) BatchBlockOrders(batch *bind.BatchCall, arg0 *big.Int, arg1 *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "blockOrders", arg0, arg1)
+	return ret0, err
+}
+
 // BlockOrdersLength is a free data retrieval call binding the contract method 0xdf6c559f.
 //
 // Solidity: function blockOrdersLength( uint256) constant returns(uint256)
@@ -228,6 +241,19 @@ func (_Pdash *PdashCallerSession) BlockOrdersLength(arg0 *big.Int) (*big.Int, er
 	return _Pdash.Contract.BlockOrdersLength(&_Pdash.CallOpts, arg0)
 }
 
+// BatchBlockOrdersLength adds a call of the contract method 0xdf6c559f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function blockOrdersLength( uint256) constant returns(uint256)
+func (_Pdash *PdashCaller) BatchBlockOrdersLength(batch *bind.BatchCall, arg0 *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "blockOrdersLength", arg0)
+	return ret0, err
+}
+
 // DisputeRecords is a free data retrieval call binding the contract method 0x7f0ca367.
 //
 // Solidity: function disputeRecords( uint256) constant returns(orderId uint256, badBuyer bool, badSeller bool, badProxy bool, buyerAgree bool, sellerAgree bool, endTime uint256, disputeState uint8)
@@ -288,6 +314,35 @@ func (_Pdash *PdashCallerSession) DisputeRecords(arg0 *big.Int) (struct {
 	return _Pdash.Contract.DisputeRecords(&_Pdash.CallOpts, arg0)
 }
 
+// BatchDisputeRecords adds a call of the contract method 0x7f0ca367 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function disputeRecords( uint256) constant returns(orderId uint256, badBuyer bool, badSeller bool, badProxy bool, buyerAgree bool, sellerAgree bool, endTime uint256, disputeState uint8)
+func (_Pdash *PdashCaller) BatchDisputeRecords(batch *bind.BatchCall, arg0 *big.Int) (*struct {
+	OrderId      *big.Int
+	BadBuyer     bool
+	BadSeller    bool
+	BadProxy     bool
+	BuyerAgree   bool
+	SellerAgree  bool
+	EndTime      *big.Int
+	DisputeState uint8
+}, error) {
+	ret := new(struct {
+		OrderId      *big.Int
+		BadBuyer     bool
+		BadSeller    bool
+		BadProxy     bool
+		BuyerAgree   bool
+		SellerAgree  bool
+		EndTime      *big.Int
+		DisputeState uint8
+	})
+	out := ret
+	err := _Pdash.contract.BatchCall(batch, out, "disputeRecords", arg0)
+	return ret, err
+}
+
 // NumDisputes is a free data retrieval call binding the contract method 0x90089ba0.
 //
 // Solidity: function numDisputes() constant returns(uint256)
@@ -314,6 +369,19 @@ func (_Pdash *PdashCallerSession) NumDisputes() (*big.Int, error) {
 	return _Pdash.Contract.NumDisputes(&_Pdash.CallOpts)
 }
 
+// BatchNumDisputes adds a call of the contract method 0x90089ba0 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function numDisputes() constant returns(uint256)
+func (_Pdash *PdashCaller) BatchNumDisputes(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "numDisputes")
+	return ret0, err
+}
+
 // NumOrders is a free data retrieval call binding the contract method 0x45d53788.
 //
 // Solidity: function numOrders() constant returns(uint256)
@@ -340,6 +408,19 @@ func (_Pdash *PdashCallerSession) NumOrders() (*big.Int, error) {
 	return _Pdash.Contract.NumOrders(&_Pdash.CallOpts)
 }
 
+// BatchNumOrders adds a call of the contract method 0x45d53788 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function numOrders() constant returns(uint256)
+func (_Pdash *PdashCaller) BatchNumOrders(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "numOrders")
+	return ret0, err
+}
+
 // OrderRecords is a free data retrieval call binding the contract method 0xe0f29cec.
 //
 // Solidity: function orderRecords( uint256) constant returns(descHash bytes32, buyerRSAPubkey bytes, buyerAddress address, sellerAddress address, proxyAddress address, secondaryProxyAddress address, offeredPrice uint256, proxyFee uint256, deliverHash bytes32, endTime uint256, state uint8, disputeId uint256)
@@ -416,6 +497,43 @@ func (_Pdash *PdashCallerSession) OrderRecords(arg0 *big.Int) (struct {
 	return _Pdash.Contract.OrderRecords(&_Pdash.CallOpts, arg0)
 }
 
+// BatchOrderRecords adds a call of the contract method 0xe0f29cec to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function orderRecords( uint256) constant returns(descHash bytes32, buyerRSAPubkey bytes, buyerAddress address, sellerAddress address, proxyAddress address, secondaryProxyAddress address, offeredPrice uint256, proxyFee uint256, deliverHash bytes32, endTime uint256, state uint8, disputeId uint256)
+func (_Pdash *PdashCaller) BatchOrderRecords(batch *bind.BatchCall, arg0 *big.Int) (*struct {
+	DescHash              [32]byte
+	BuyerRSAPubkey        []byte
+	BuyerAddress          common.Address
+	SellerAddress         common.Address
+	ProxyAddress          common.Address
+	SecondaryProxyAddress common.Address
+	OfferedPrice          *big.Int
+	ProxyFee              *big.Int
+	DeliverHash           [32]byte
+	EndTime               *big.Int
+	State                 uint8
+	DisputeId             *big.Int
+}, error) {
+	ret := new(struct {
+		DescHash              [32]byte
+		BuyerRSAPubkey        []byte
+		BuyerAddress          common.Address
+		SellerAddress         common.Address
+		ProxyAddress          common.Address
+		SecondaryProxyAddress common.Address
+		OfferedPrice          *big.Int
+		ProxyFee              *big.Int
+		DeliverHash           [32]byte
+		EndTime               *big.Int
+		State                 uint8
+		DisputeId             *big.Int
+	})
+	out := ret
+	err := _Pdash.contract.BatchCall(batch, out, "orderRecords", arg0)
+	return ret, err
+}
+
 // Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
 //
 // Solidity: function owner() constant returns(address)
@@ -442,6 +560,19 @@ func (_Pdash *PdashCallerSession) Owner() (common.Address, error) {
 	return _Pdash.Contract.Owner(&_Pdash.CallOpts)
 }
 
+// BatchOwner adds a call of the contract method 0x8da5cb5b to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function owner() constant returns(address)
+func (_Pdash *PdashCaller) BatchOwner(batch *bind.BatchCall) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "owner")
+	return ret0, err
+}
+
 // ProxyCredits is a free data retrieval call binding the contract method 0xa8dc7e63.
 //
 // Solidity: function proxyCredits( address) constant returns(count uint256, amount uint256, rate uint256)
@@ -482,6 +613,25 @@ func (_Pdash *PdashCallerSession) ProxyCredits(arg0 common.Address) (struct {
 	return _Pdash.Contract.ProxyCredits(&_Pdash.CallOpts, arg0)
 }
 
+// BatchProxyCredits adds a call of the contract method 0xa8dc7e63 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function proxyCredits( address) constant returns(count uint256, amount uint256, rate uint256)
+func (_Pdash *PdashCaller) BatchProxyCredits(batch *bind.BatchCall, arg0 common.Address) (*struct {
+	Count  *big.Int
+	Amount *big.Int
+	Rate   *big.Int
+}, error) {
+	ret := new(struct {
+		Count  *big.Int
+		Amount *big.Int
+		Rate   *big.Int
+	})
+	out := ret
+	err := _Pdash.contract.BatchCall(batch, out, "proxyCredits", arg0)
+	return ret, err
+}
+
 // ProxyDeposits is a free data retrieval call binding the contract method 0x04e1b52e.
 //
 // Solidity: function proxyDeposits( address) constant returns(uint256)
@@ -508,6 +658,19 @@ func (_Pdash *PdashCallerSession) ProxyDeposits(arg0 common.Address) (*big.Int,
 	return _Pdash.Contract.ProxyDeposits(&_Pdash.CallOpts, arg0)
 }
 
+// BatchProxyDeposits adds a call of the contract method 0x04e1b52e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function proxyDeposits( address) constant returns(uint256)
+func (_Pdash *PdashCaller) BatchProxyDeposits(batch *bind.BatchCall, arg0 common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Pdash.contract.BatchCall(batch, out, "proxyDeposits", arg0)
+	return ret0, err
+}
+
 // BuyerAgreeOrNot is a paid mutator transaction binding the contract method 0xfed7ef34.
 //
 // Solidity: function buyerAgreeOrNot(id uint256, if_agree bool) returns()
@@ -988,6 +1151,66 @@ func (_Pdash *PdashFilterer) WatchBuyerConfirmed(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashBuyerConfirmedFollower is returned from FollowBuyerConfirmed and is used to follow the unpacked data for BuyerConfirmed events raised by the Pdash contract across chain reorganisations.
+type PdashBuyerConfirmedFollower struct {
+	Event *PdashBuyerConfirmed // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashBuyerConfirmedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashBuyerConfirmed)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashBuyerConfirmedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashBuyerConfirmedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashBuyerConfirmedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowBuyerConfirmed is a free log following operation binding the contract event 0xd87dd92b1de3627ad322286c5c45566583bef03d0334ca6f0c9a9db7f7c0f16b,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e BuyerConfirmed(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowBuyerConfirmed(opts *bind.FollowOpts) (*PdashBuyerConfirmedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "BuyerConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashBuyerConfirmedFollower{contract: _Pdash.contract, event: "BuyerConfirmed", follower: follower}, nil
+}
+
 // PdashBuyerDisputedIterator is returned from FilterBuyerDisputed and is used to iterate over the raw logs and unpacked data for BuyerDisputed events raised by the Pdash contract.
 type PdashBuyerDisputedIterator struct {
 	Event *PdashBuyerDisputed // Event containing the contract specifics and raw log
@@ -1111,6 +1334,66 @@ func (_Pdash *PdashFilterer) WatchBuyerDisputed(opts *bind.WatchOpts, sink chan<
 	}), nil
 }
 
+// PdashBuyerDisputedFollower is returned from FollowBuyerDisputed and is used to follow the unpacked data for BuyerDisputed events raised by the Pdash contract across chain reorganisations.
+type PdashBuyerDisputedFollower struct {
+	Event *PdashBuyerDisputed // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashBuyerDisputedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashBuyerDisputed)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashBuyerDisputedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashBuyerDisputedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashBuyerDisputedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowBuyerDisputed is a free log following operation binding the contract event 0xa6c88e175a49cd3945a40a26a490ae07ede7d4eb825c4f26a7ff37a464d05d35,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e BuyerDisputed(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowBuyerDisputed(opts *bind.FollowOpts) (*PdashBuyerDisputedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "BuyerDisputed")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashBuyerDisputedFollower{contract: _Pdash.contract, event: "BuyerDisputed", follower: follower}, nil
+}
+
 // PdashOrderFinishedIterator is returned from FilterOrderFinished and is used to iterate over the raw logs and unpacked data for OrderFinished events raised by the Pdash contract.
 type PdashOrderFinishedIterator struct {
 	Event *PdashOrderFinished // Event containing the contract specifics and raw log
@@ -1234,6 +1517,66 @@ func (_Pdash *PdashFilterer) WatchOrderFinished(opts *bind.WatchOpts, sink chan<
 	}), nil
 }
 
+// PdashOrderFinishedFollower is returned from FollowOrderFinished and is used to follow the unpacked data for OrderFinished events raised by the Pdash contract across chain reorganisations.
+type PdashOrderFinishedFollower struct {
+	Event *PdashOrderFinished // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashOrderFinishedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashOrderFinished)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashOrderFinishedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashOrderFinishedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashOrderFinishedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowOrderFinished is a free log following operation binding the contract event 0x581a6384d4701bb245eaf6ebd9afd551a8c4f9d4e4ec70b1dfbab15569272bba,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e OrderFinished(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowOrderFinished(opts *bind.FollowOpts) (*PdashOrderFinishedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "OrderFinished")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashOrderFinishedFollower{contract: _Pdash.contract, event: "OrderFinished", follower: follower}, nil
+}
+
 // PdashOrderInitiatedIterator is returned from FilterOrderInitiated and is used to iterate over the raw logs and unpacked data for OrderInitiated events raised by the Pdash contract.
 type PdashOrderInitiatedIterator struct {
 	Event *PdashOrderInitiated // Event containing the contract specifics and raw log
@@ -1359,6 +1702,66 @@ func (_Pdash *PdashFilterer) WatchOrderInitiated(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashOrderInitiatedFollower is returned from FollowOrderInitiated and is used to follow the unpacked data for OrderInitiated events raised by the Pdash contract across chain reorganisations.
+type PdashOrderInitiatedFollower struct {
+	Event *PdashOrderInitiated // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashOrderInitiatedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashOrderInitiated)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashOrderInitiatedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashOrderInitiatedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashOrderInitiatedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowOrderInitiated is a free log following operation binding the contract event 0xca46e2845b8de28445b9ac838c4fe91c25ecde13a4d5661c14acf05fcc89d7d9,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e OrderInitiated(from address, orderId uint256, value uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowOrderInitiated(opts *bind.FollowOpts) (*PdashOrderInitiatedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "OrderInitiated")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashOrderInitiatedFollower{contract: _Pdash.contract, event: "OrderInitiated", follower: follower}, nil
+}
+
 // PdashOrderWithdrawnIterator is returned from FilterOrderWithdrawn and is used to iterate over the raw logs and unpacked data for OrderWithdrawn events raised by the Pdash contract.
 type PdashOrderWithdrawnIterator struct {
 	Event *PdashOrderWithdrawn // Event containing the contract specifics and raw log
@@ -1482,6 +1885,66 @@ func (_Pdash *PdashFilterer) WatchOrderWithdrawn(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashOrderWithdrawnFollower is returned from FollowOrderWithdrawn and is used to follow the unpacked data for OrderWithdrawn events raised by the Pdash contract across chain reorganisations.
+type PdashOrderWithdrawnFollower struct {
+	Event *PdashOrderWithdrawn // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashOrderWithdrawnFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashOrderWithdrawn)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashOrderWithdrawnFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashOrderWithdrawnFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashOrderWithdrawnFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowOrderWithdrawn is a free log following operation binding the contract event 0x01e7164b56bfdcd76ac7df9a68a09f177aed22f0b3ef728d56f452745418ebb0,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e OrderWithdrawn(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowOrderWithdrawn(opts *bind.FollowOpts) (*PdashOrderWithdrawnFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "OrderWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashOrderWithdrawnFollower{contract: _Pdash.contract, event: "OrderWithdrawn", follower: follower}, nil
+}
+
 // PdashProxyDeliveredIterator is returned from FilterProxyDelivered and is used to iterate over the raw logs and unpacked data for ProxyDelivered events raised by the Pdash contract.
 type PdashProxyDeliveredIterator struct {
 	Event *PdashProxyDelivered // Event containing the contract specifics and raw log
@@ -1605,6 +2068,66 @@ func (_Pdash *PdashFilterer) WatchProxyDelivered(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashProxyDeliveredFollower is returned from FollowProxyDelivered and is used to follow the unpacked data for ProxyDelivered events raised by the Pdash contract across chain reorganisations.
+type PdashProxyDeliveredFollower struct {
+	Event *PdashProxyDelivered // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashProxyDeliveredFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashProxyDelivered)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashProxyDeliveredFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashProxyDeliveredFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashProxyDeliveredFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowProxyDelivered is a free log following operation binding the contract event 0xee6a57b211b9b5284bd6650f9cca90a9ecbc0236e1610fe97c468faa0fc68287,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ProxyDelivered(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowProxyDelivered(opts *bind.FollowOpts) (*PdashProxyDeliveredFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "ProxyDelivered")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashProxyDeliveredFollower{contract: _Pdash.contract, event: "ProxyDelivered", follower: follower}, nil
+}
+
 // PdashProxyDepositedIterator is returned from FilterProxyDeposited and is used to iterate over the raw logs and unpacked data for ProxyDeposited events raised by the Pdash contract.
 type PdashProxyDepositedIterator struct {
 	Event *PdashProxyDeposited // Event containing the contract specifics and raw log
@@ -1729,6 +2252,66 @@ func (_Pdash *PdashFilterer) WatchProxyDeposited(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashProxyDepositedFollower is returned from FollowProxyDeposited and is used to follow the unpacked data for ProxyDeposited events raised by the Pdash contract across chain reorganisations.
+type PdashProxyDepositedFollower struct {
+	Event *PdashProxyDeposited // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashProxyDepositedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashProxyDeposited)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashProxyDepositedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashProxyDepositedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashProxyDepositedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowProxyDeposited is a free log following operation binding the contract event 0xe485b77aa65aed2e79b44431303e4512f39e8d8d6bb557ba6273ff499f6c4cec,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ProxyDeposited(from address, value uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowProxyDeposited(opts *bind.FollowOpts) (*PdashProxyDepositedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "ProxyDeposited")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashProxyDepositedFollower{contract: _Pdash.contract, event: "ProxyDeposited", follower: follower}, nil
+}
+
 // PdashProxyFetchedIterator is returned from FilterProxyFetched and is used to iterate over the raw logs and unpacked data for ProxyFetched events raised by the Pdash contract.
 type PdashProxyFetchedIterator struct {
 	Event *PdashProxyFetched // Event containing the contract specifics and raw log
@@ -1852,6 +2435,66 @@ func (_Pdash *PdashFilterer) WatchProxyFetched(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// PdashProxyFetchedFollower is returned from FollowProxyFetched and is used to follow the unpacked data for ProxyFetched events raised by the Pdash contract across chain reorganisations.
+type PdashProxyFetchedFollower struct {
+	Event *PdashProxyFetched // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashProxyFetchedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashProxyFetched)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashProxyFetchedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashProxyFetchedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashProxyFetchedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowProxyFetched is a free log following operation binding the contract event 0xf432f8d0b15f3b091c000b649a96bedc45f97d6f5361ff4cfd391f16a581fe8f,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ProxyFetched(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowProxyFetched(opts *bind.FollowOpts) (*PdashProxyFetchedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "ProxyFetched")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashProxyFetchedFollower{contract: _Pdash.contract, event: "ProxyFetched", follower: follower}, nil
+}
+
 // PdashProxyWithdrawnIterator is returned from FilterProxyWithdrawn and is used to iterate over the raw logs and unpacked data for ProxyWithdrawn events raised by the Pdash contract.
 type PdashProxyWithdrawnIterator struct {
 	Event *PdashProxyWithdrawn // Event containing the contract specifics and raw log
@@ -1976,6 +2619,66 @@ func (_Pdash *PdashFilterer) WatchProxyWithdrawn(opts *bind.WatchOpts, sink chan
 	}), nil
 }
 
+// PdashProxyWithdrawnFollower is returned from FollowProxyWithdrawn and is used to follow the unpacked data for ProxyWithdrawn events raised by the Pdash contract across chain reorganisations.
+type PdashProxyWithdrawnFollower struct {
+	Event *PdashProxyWithdrawn // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashProxyWithdrawnFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashProxyWithdrawn)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashProxyWithdrawnFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashProxyWithdrawnFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashProxyWithdrawnFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowProxyWithdrawn is a free log following operation binding the contract event 0xc6311a7ead0ac41d26e6a97e6c05f885c84fa52336b5fc201c83451f05b08b7d,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ProxyWithdrawn(from address, value uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowProxyWithdrawn(opts *bind.FollowOpts) (*PdashProxyWithdrawnFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "ProxyWithdrawn")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashProxyWithdrawnFollower{contract: _Pdash.contract, event: "ProxyWithdrawn", follower: follower}, nil
+}
+
 // PdashSellerClaimTimeoutIterator is returned from FilterSellerClaimTimeout and is used to iterate over the raw logs and unpacked data for SellerClaimTimeout events raised by the Pdash contract.
 type PdashSellerClaimTimeoutIterator struct {
 	Event *PdashSellerClaimTimeout // Event containing the contract specifics and raw log
@@ -2099,6 +2802,66 @@ func (_Pdash *PdashFilterer) WatchSellerClaimTimeout(opts *bind.WatchOpts, sink
 	}), nil
 }
 
+// PdashSellerClaimTimeoutFollower is returned from FollowSellerClaimTimeout and is used to follow the unpacked data for SellerClaimTimeout events raised by the Pdash contract across chain reorganisations.
+type PdashSellerClaimTimeoutFollower struct {
+	Event *PdashSellerClaimTimeout // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashSellerClaimTimeoutFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashSellerClaimTimeout)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashSellerClaimTimeoutFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashSellerClaimTimeoutFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashSellerClaimTimeoutFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSellerClaimTimeout is a free log following operation binding the contract event 0x34ce961a05a1558f29e54cc2618b644ec6d298fda6c1eda6395c910d04c63f21,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SellerClaimTimeout(orderId uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowSellerClaimTimeout(opts *bind.FollowOpts) (*PdashSellerClaimTimeoutFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "SellerClaimTimeout")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashSellerClaimTimeoutFollower{contract: _Pdash.contract, event: "SellerClaimTimeout", follower: follower}, nil
+}
+
 // PdashSellerConfirmedIterator is returned from FilterSellerConfirmed and is used to iterate over the raw logs and unpacked data for SellerConfirmed events raised by the Pdash contract.
 type PdashSellerConfirmedIterator struct {
 	Event *PdashSellerConfirmed // Event containing the contract specifics and raw log
@@ -2223,6 +2986,66 @@ func (_Pdash *PdashFilterer) WatchSellerConfirmed(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// PdashSellerConfirmedFollower is returned from FollowSellerConfirmed and is used to follow the unpacked data for SellerConfirmed events raised by the Pdash contract across chain reorganisations.
+type PdashSellerConfirmedFollower struct {
+	Event *PdashSellerConfirmed // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *PdashSellerConfirmedFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(PdashSellerConfirmed)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *PdashSellerConfirmedFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *PdashSellerConfirmedFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *PdashSellerConfirmedFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSellerConfirmed is a free log following operation binding the contract event 0x76408f7d8666ddeb495564b182efc9e10ee49e1caa750fc79568b86ae940e42b,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SellerConfirmed(orderId uint256, value uint256, time uint256)
+func (_Pdash *PdashFilterer) FollowSellerConfirmed(opts *bind.FollowOpts) (*PdashSellerConfirmedFollower, error) {
+
+	follower, err := _Pdash.contract.FollowLogs(opts, "SellerConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	return &PdashSellerConfirmedFollower{contract: _Pdash.contract, event: "SellerConfirmed", follower: follower}, nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
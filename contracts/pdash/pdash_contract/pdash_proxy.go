@@ -200,6 +200,19 @@ func (_PdashProxy *PdashProxyCallerSession) GetProxyFileNumber(proxy common.Addr
 	return _PdashProxy.Contract.GetProxyFileNumber(&_PdashProxy.CallOpts, proxy)
 }
 
+// BatchGetProxyFileNumber adds a call of the contract method 0x122de578 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyFileNumber(proxy address) constant returns(uint256)
+func (_PdashProxy *PdashProxyCaller) BatchGetProxyFileNumber(batch *bind.BatchCall, proxy common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PdashProxy.contract.BatchCall(batch, out, "getProxyFileNumber", proxy)
+	return ret0, err
+}
+
 // GetProxyFileNumberInBlock is a free data retrieval call binding the contract method 0x55a20afc.
 //
 // Solidity: function getProxyFileNumberInBlock(user address, block_num uint256) constant returns(uint256)
@@ -226,6 +239,19 @@ func (_PdashProxy *PdashProxyCallerSession) GetProxyFileNumberInBlock(user commo
 	return _PdashProxy.Contract.GetProxyFileNumberInBlock(&_PdashProxy.CallOpts, user, block_num)
 }
 
+// BatchGetProxyFileNumberInBlock adds a call of the contract method 0x55a20afc to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyFileNumberInBlock(user address, block_num uint256) constant returns(uint256)
+func (_PdashProxy *PdashProxyCaller) BatchGetProxyFileNumberInBlock(batch *bind.BatchCall, user common.Address, block_num *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PdashProxy.contract.BatchCall(batch, out, "getProxyFileNumberInBlock", user, block_num)
+	return ret0, err
+}
+
 // Pdash is a free data retrieval call binding the contract method 0xc946286d.
 //
 // Solidity: function pdash() constant returns(address)
@@ -252,6 +278,19 @@ func (_PdashProxy *PdashProxyCallerSession) Pdash() (common.Address, error) {
 	return _PdashProxy.Contract.Pdash(&_PdashProxy.CallOpts)
 }
 
+// BatchPdash adds a call of the contract method 0xc946286d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function pdash() constant returns(address)
+func (_PdashProxy *PdashProxyCaller) BatchPdash(batch *bind.BatchCall) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _PdashProxy.contract.BatchCall(batch, out, "pdash")
+	return ret0, err
+}
+
 // ProxyHistory is a free data retrieval call binding the contract method 0xc642648d.
 //
 // Solidity: function proxyHistory( address,  uint256) constant returns(fileName string, fileHash bytes32, fileSize uint256, timeStamp uint256)
@@ -296,6 +335,27 @@ func (_PdashProxy *PdashProxyCallerSession) ProxyHistory(arg0 common.Address, ar
 	return _PdashProxy.Contract.ProxyHistory(&_PdashProxy.CallOpts, arg0, arg1)
 }
 
+// BatchProxyHistory adds a call of the contract method 0xc642648d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function proxyHistory( address,  uint256) constant returns(fileName string, fileHash bytes32, fileSize uint256, timeStamp uint256)
+func (_PdashProxy *PdashProxyCaller) BatchProxyHistory(batch *bind.BatchCall, arg0 common.Address, arg1 *big.Int) (*struct {
+	FileName  string
+	FileHash  [32]byte
+	FileSize  *big.Int
+	TimeStamp *big.Int
+}, error) {
+	ret := new(struct {
+		FileName  string
+		FileHash  [32]byte
+		FileSize  *big.Int
+		TimeStamp *big.Int
+	})
+	out := ret
+	err := _PdashProxy.contract.BatchCall(batch, out, "proxyHistory", arg0, arg1)
+	return ret, err
+}
+
 // ProxyRegister is a paid mutator transaction binding the contract method 0xac50f815.
 //
 // Solidity: function proxyRegister(fileName string, fileHash bytes32, fileSize uint256) returns()
@@ -545,3 +605,16 @@ func (_ProxyContractInterface *ProxyContractInterfaceSession) GetProxyContract(a
 func (_ProxyContractInterface *ProxyContractInterfaceCallerSession) GetProxyContract(addr common.Address) (common.Address, error) {
 	return _ProxyContractInterface.Contract.GetProxyContract(&_ProxyContractInterface.CallOpts, addr)
 }
+
+// BatchGetProxyContract adds a call of the contract method 0xfc4fdd3d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyContract(addr address) constant returns(address)
+func (_ProxyContractInterface *ProxyContractInterfaceCaller) BatchGetProxyContract(batch *bind.BatchCall, addr common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractInterface.contract.BatchCall(batch, out, "getProxyContract", addr)
+	return ret0, err
+}
@@ -200,6 +200,19 @@ func (_ProxyInterface *ProxyInterfaceCallerSession) GetProxyContract(addr common
 	return _ProxyInterface.Contract.GetProxyContract(&_ProxyInterface.CallOpts, addr)
 }
 
+// BatchGetProxyContract adds a call of the contract method 0xfc4fdd3d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyContract(addr address) constant returns(address)
+func (_ProxyInterface *ProxyInterfaceCaller) BatchGetProxyContract(batch *bind.BatchCall, addr common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyInterface.contract.BatchCall(batch, out, "getProxyContract", addr)
+	return ret0, err
+}
+
 // RegisterABI is the input ABI used to generate the binding from.
 const RegisterABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"\",\"type\":\"address\"},{\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"uploadHistory\",\"outputs\":[{\"name\":\"fileName\",\"type\":\"string\"},{\"name\":\"fileHash\",\"type\":\"bytes32\"},{\"name\":\"fileSize\",\"type\":\"uint256\"},{\"name\":\"timeStamp\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"addr\",\"type\":\"address\"}],\"name\":\"updatePdashAddress\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"name\":\"setAdmissionAddr\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"fileName\",\"type\":\"string\"},{\"name\":\"fileHash\",\"type\":\"bytes32\"},{\"name\":\"fileSize\",\"type\":\"uint256\"}],\"name\":\"claimRegister\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"user\",\"type\":\"address\"}],\"name\":\"getUploadCount\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"user\",\"type\":\"address\"},{\"name\":\"block_num\",\"type\":\"uint256\"}],\"name\":\"getUploadCountAfterBlock\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"name\":\"_addr\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"}]"
 
@@ -387,6 +400,19 @@ func (_Register *RegisterCallerSession) GetUploadCount(user common.Address) (*bi
 	return _Register.Contract.GetUploadCount(&_Register.CallOpts, user)
 }
 
+// BatchGetUploadCount adds a call of the contract method 0xf1227d36 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getUploadCount(user address) constant returns(uint256)
+func (_Register *RegisterCaller) BatchGetUploadCount(batch *bind.BatchCall, user common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Register.contract.BatchCall(batch, out, "getUploadCount", user)
+	return ret0, err
+}
+
 // GetUploadCountAfterBlock is a free data retrieval call binding the contract method 0xfcf197b2.
 //
 // Solidity: function getUploadCountAfterBlock(user address, block_num uint256) constant returns(uint256)
@@ -413,6 +439,19 @@ func (_Register *RegisterCallerSession) GetUploadCountAfterBlock(user common.Add
 	return _Register.Contract.GetUploadCountAfterBlock(&_Register.CallOpts, user, block_num)
 }
 
+// BatchGetUploadCountAfterBlock adds a call of the contract method 0xfcf197b2 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getUploadCountAfterBlock(user address, block_num uint256) constant returns(uint256)
+func (_Register *RegisterCaller) BatchGetUploadCountAfterBlock(batch *bind.BatchCall, user common.Address, block_num *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Register.contract.BatchCall(batch, out, "getUploadCountAfterBlock", user, block_num)
+	return ret0, err
+}
+
 // UploadHistory is a free data retrieval call binding the contract method 0x26cea737.
 //
 // Solidity: function uploadHistory( address,  uint256) constant returns(fileName string, fileHash bytes32, fileSize uint256, timeStamp uint256)
@@ -457,6 +496,27 @@ func (_Register *RegisterCallerSession) UploadHistory(arg0 common.Address, arg1
 	return _Register.Contract.UploadHistory(&_Register.CallOpts, arg0, arg1)
 }
 
+// BatchUploadHistory adds a call of the contract method 0x26cea737 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function uploadHistory( address,  uint256) constant returns(fileName string, fileHash bytes32, fileSize uint256, timeStamp uint256)
+func (_Register *RegisterCaller) BatchUploadHistory(batch *bind.BatchCall, arg0 common.Address, arg1 *big.Int) (*struct {
+	FileName  string
+	FileHash  [32]byte
+	FileSize  *big.Int
+	TimeStamp *big.Int
+}, error) {
+	ret := new(struct {
+		FileName  string
+		FileHash  [32]byte
+		FileSize  *big.Int
+		TimeStamp *big.Int
+	})
+	out := ret
+	err := _Register.contract.BatchCall(batch, out, "uploadHistory", arg0, arg1)
+	return ret, err
+}
+
 // ClaimRegister is a paid mutator transaction binding the contract method 0xd44fd261.
 //
 // Solidity: function claimRegister(fileName string, fileHash bytes32, fileSize uint256) returns()
@@ -7,6 +7,7 @@ import (
 	cpchain "bitbucket.org/cpchain/chain"
 	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/contracts/dpor/primitive_backend"
 	"bitbucket.org/cpchain/chain/contracts/primitives_example/primitives"
 	"bitbucket.org/cpchain/chain/core/vm"
@@ -30,10 +31,10 @@ type ContractAPI interface {
 
 func RegisterPrimitiveContracts() {
 	chainClient := GetChainClient()
-	for addr, c := range MakePrimitiveContracts(chainClient, chainClient) {
-		err := vm.RegisterPrimitiveContract(addr, c)
+	for name, c := range MakePrimitiveContracts(chainClient, chainClient) {
+		err := vm.RegisterPrimitive(name, c)
 		if err != nil {
-			log.Fatal("register primitive contract error", "error", err, "addr", addr)
+			log.Fatal("register primitive contract error", "error", err, "name", name)
 		}
 	}
 }
@@ -42,19 +43,21 @@ func GetChainClient() *primitive_backend.ApiClient {
 	return &primitive_backend.ApiClient{ChainBackend: primitive_backend.GetApiBackendHolderInstance().ChainBackend, ContractBackend: primitive_backend.GetApiBackendHolderInstance().ContractBackend}
 }
 
-func MakePrimitiveContracts(contractClient bind.ContractBackend, chainClient *primitive_backend.ApiClient) map[common.Address]vm.PrimitiveContract {
-	contracts := make(map[common.Address]vm.PrimitiveContract)
+// MakePrimitiveContracts returns the primitive contracts by name, the chain
+// config enabling them at their addresses, from 100 on by convention to leave
+// room for the upstream precompiled contracts.
+func MakePrimitiveContracts(contractClient bind.ContractBackend, chainClient *primitive_backend.ApiClient) map[string]vm.PrimitiveContract {
+	contracts := make(map[string]vm.PrimitiveContract)
 
-	// we start from 100 to reserve enough space for upstream primitive contracts.
 	RptEvaluator, err := primitives.NewRptEvaluator(contractClient, chainClient)
 	if err != nil {
 		log.Fatal("s.RptEvaluator is file")
 	}
-	contracts[common.BytesToAddress([]byte{100})] = &primitives.GetRank{Backend: RptEvaluator}
-	contracts[common.BytesToAddress([]byte{101})] = &primitives.GetMaintenance{Backend: RptEvaluator}
-	contracts[common.BytesToAddress([]byte{102})] = &primitives.GetProxyCount{Backend: RptEvaluator}
-	contracts[common.BytesToAddress([]byte{103})] = &primitives.GetUploadReward{Backend: RptEvaluator}
-	contracts[common.BytesToAddress([]byte{104})] = &primitives.GetTxVolume{Backend: RptEvaluator}
-	contracts[common.BytesToAddress([]byte{105})] = &primitives.IsProxy{Backend: RptEvaluator}
+	contracts[configs.PrimitiveRank] = &primitives.GetRank{Backend: RptEvaluator}
+	contracts[configs.PrimitiveMaintenance] = &primitives.GetMaintenance{Backend: RptEvaluator}
+	contracts[configs.PrimitiveProxyCount] = &primitives.GetProxyCount{Backend: RptEvaluator}
+	contracts[configs.PrimitiveUploadReward] = &primitives.GetUploadReward{Backend: RptEvaluator}
+	contracts[configs.PrimitiveTxVolume] = &primitives.GetTxVolume{Backend: RptEvaluator}
+	contracts[configs.PrimitiveIsProxy] = &primitives.IsProxy{Backend: RptEvaluator}
 	return contracts
 }
@@ -361,6 +361,19 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) GetMaintenan
 	return _PrimitiveContractsTest.Contract.GetMaintenance(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
 
+// BatchGetMaintenance adds a call of the contract method 0x37cdbe7f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getMaintenance(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchGetMaintenance(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "getMaintenance", addr, blockNum)
+	return ret0, err
+}
+
 // GetProxyCount is a free data retrieval call binding the contract method 0x98c9c279.
 //
 // Solidity: function getProxyCount(addr address, blockNum uint256) constant returns(b uint256)
@@ -387,6 +400,19 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) GetProxyCoun
 	return _PrimitiveContractsTest.Contract.GetProxyCount(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
 
+// BatchGetProxyCount adds a call of the contract method 0x98c9c279 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyCount(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchGetProxyCount(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "getProxyCount", addr, blockNum)
+	return ret0, err
+}
+
 // GetRank is a free data retrieval call binding the contract method 0xf7935969.
 //
 // Solidity: function getRank(addr address, blockNum uint256) constant returns(b uint256)
@@ -413,6 +439,19 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) GetRank(addr
 	return _PrimitiveContractsTest.Contract.GetRank(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
 
+// BatchGetRank adds a call of the contract method 0xf7935969 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getRank(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchGetRank(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "getRank", addr, blockNum)
+	return ret0, err
+}
+
 // GetTxVolume is a free data retrieval call binding the contract method 0x435f8a27.
 //
 // Solidity: function getTxVolume(addr address, blockNum uint256) constant returns(b uint256)
@@ -439,6 +478,19 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) GetTxVolume(
 	return _PrimitiveContractsTest.Contract.GetTxVolume(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
 
+// BatchGetTxVolume adds a call of the contract method 0x435f8a27 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getTxVolume(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchGetTxVolume(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "getTxVolume", addr, blockNum)
+	return ret0, err
+}
+
 // GetUploadInfo is a free data retrieval call binding the contract method 0xb68d0d43.
 //
 // Solidity: function getUploadInfo(addr address, blockNum uint256) constant returns(b uint256)
@@ -465,6 +517,19 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) GetUploadInf
 	return _PrimitiveContractsTest.Contract.GetUploadInfo(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
 
+// BatchGetUploadInfo adds a call of the contract method 0xb68d0d43 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getUploadInfo(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchGetUploadInfo(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "getUploadInfo", addr, blockNum)
+	return ret0, err
+}
+
 // IsProxy is a free data retrieval call binding the contract method 0xfae4a1e1.
 //
 // Solidity: function isProxy(addr address, blockNum uint256) constant returns(b uint256)
@@ -490,3 +555,16 @@ func (_PrimitiveContractsTest *PrimitiveContractsTestSession) IsProxy(addr commo
 func (_PrimitiveContractsTest *PrimitiveContractsTestCallerSession) IsProxy(addr common.Address, blockNum *big.Int) (*big.Int, error) {
 	return _PrimitiveContractsTest.Contract.IsProxy(&_PrimitiveContractsTest.CallOpts, addr, blockNum)
 }
+
+// BatchIsProxy adds a call of the contract method 0xfae4a1e1 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function isProxy(addr address, blockNum uint256) constant returns(b uint256)
+func (_PrimitiveContractsTest *PrimitiveContractsTestCaller) BatchIsProxy(batch *bind.BatchCall, addr common.Address, blockNum *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _PrimitiveContractsTest.contract.BatchCall(batch, out, "isProxy", addr, blockNum)
+	return ret0, err
+}
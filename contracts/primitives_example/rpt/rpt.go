@@ -363,6 +363,19 @@ func (_Rpt *RptCallerSession) Alpha() (*big.Int, error) {
 	return _Rpt.Contract.Alpha(&_Rpt.CallOpts)
 }
 
+// BatchAlpha adds a call of the contract method 0xdb1d0fd5 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function alpha() constant returns(uint256)
+func (_Rpt *RptCaller) BatchAlpha(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "alpha")
+	return ret0, err
+}
+
 // Beta is a free data retrieval call binding the contract method 0x9faa3c91.
 //
 // Solidity: function beta() constant returns(uint256)
@@ -389,6 +402,19 @@ func (_Rpt *RptCallerSession) Beta() (*big.Int, error) {
 	return _Rpt.Contract.Beta(&_Rpt.CallOpts)
 }
 
+// BatchBeta adds a call of the contract method 0x9faa3c91 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function beta() constant returns(uint256)
+func (_Rpt *RptCaller) BatchBeta(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "beta")
+	return ret0, err
+}
+
 // F is a free data retrieval call binding the contract method 0x26121ff0.
 //
 // Solidity: function f() constant returns(uint256)
@@ -415,6 +441,19 @@ func (_Rpt *RptCallerSession) F() (*big.Int, error) {
 	return _Rpt.Contract.F(&_Rpt.CallOpts)
 }
 
+// BatchF adds a call of the contract method 0x26121ff0 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function f() constant returns(uint256)
+func (_Rpt *RptCaller) BatchF(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "f")
+	return ret0, err
+}
+
 // Gamma is a free data retrieval call binding the contract method 0xb1373929.
 //
 // Solidity: function gamma() constant returns(uint256)
@@ -441,6 +480,19 @@ func (_Rpt *RptCallerSession) Gamma() (*big.Int, error) {
 	return _Rpt.Contract.Gamma(&_Rpt.CallOpts)
 }
 
+// BatchGamma adds a call of the contract method 0xb1373929 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function gamma() constant returns(uint256)
+func (_Rpt *RptCaller) BatchGamma(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "gamma")
+	return ret0, err
+}
+
 // GetBlockchainMaintenance is a free data retrieval call binding the contract method 0xe81092a5.
 //
 // Solidity: function getBlockchainMaintenance(_addr address, _blockNumber uint256) constant returns(uint256)
@@ -467,6 +519,19 @@ func (_Rpt *RptCallerSession) GetBlockchainMaintenance(_addr common.Address, _bl
 	return _Rpt.Contract.GetBlockchainMaintenance(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetBlockchainMaintenance adds a call of the contract method 0xe81092a5 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getBlockchainMaintenance(_addr address, _blockNumber uint256) constant returns(uint256)
+func (_Rpt *RptCaller) BatchGetBlockchainMaintenance(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getBlockchainMaintenance", _addr, _blockNumber)
+	return ret0, err
+}
+
 // GetCoinage is a free data retrieval call binding the contract method 0xdb40a12e.
 //
 // Solidity: function getCoinage(_addr address, _blockNumber uint256) constant returns(uint256)
@@ -493,6 +558,19 @@ func (_Rpt *RptCallerSession) GetCoinage(_addr common.Address, _blockNumber *big
 	return _Rpt.Contract.GetCoinage(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetCoinage adds a call of the contract method 0xdb40a12e to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getCoinage(_addr address, _blockNumber uint256) constant returns(uint256)
+func (_Rpt *RptCaller) BatchGetCoinage(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getCoinage", _addr, _blockNumber)
+	return ret0, err
+}
+
 // GetDataContribution is a free data retrieval call binding the contract method 0x7c9f4b66.
 //
 // Solidity: function getDataContribution(_addr address, _blockNumber uint256) constant returns(uint256)
@@ -519,6 +597,19 @@ func (_Rpt *RptCallerSession) GetDataContribution(_addr common.Address, _blockNu
 	return _Rpt.Contract.GetDataContribution(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetDataContribution adds a call of the contract method 0x7c9f4b66 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getDataContribution(_addr address, _blockNumber uint256) constant returns(uint256)
+func (_Rpt *RptCaller) BatchGetDataContribution(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getDataContribution", _addr, _blockNumber)
+	return ret0, err
+}
+
 // GetProxyRep is a free data retrieval call binding the contract method 0x44cc5c52.
 //
 // Solidity: function getProxyRep(_addr address, _blockNumber uint256) constant returns(uint256)
@@ -545,6 +636,19 @@ func (_Rpt *RptCallerSession) GetProxyRep(_addr common.Address, _blockNumber *bi
 	return _Rpt.Contract.GetProxyRep(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetProxyRep adds a call of the contract method 0x44cc5c52 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyRep(_addr address, _blockNumber uint256) constant returns(uint256)
+func (_Rpt *RptCaller) BatchGetProxyRep(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getProxyRep", _addr, _blockNumber)
+	return ret0, err
+}
+
 // GetRpt is a free data retrieval call binding the contract method 0x6f126a8f.
 //
 // Solidity: function getRpt(_addr address, _blockNumber uint256) constant returns(rpt uint256)
@@ -571,6 +675,19 @@ func (_Rpt *RptCallerSession) GetRpt(_addr common.Address, _blockNumber *big.Int
 	return _Rpt.Contract.GetRpt(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetRpt adds a call of the contract method 0x6f126a8f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getRpt(_addr address, _blockNumber uint256) constant returns(rpt uint256)
+func (_Rpt *RptCaller) BatchGetRpt(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getRpt", _addr, _blockNumber)
+	return ret0, err
+}
+
 // GetTx is a free data retrieval call binding the contract method 0x741a35c4.
 //
 // Solidity: function getTx(_addr address, _blockNumber uint256) constant returns(uint256)
@@ -597,6 +714,19 @@ func (_Rpt *RptCallerSession) GetTx(_addr common.Address, _blockNumber *big.Int)
 	return _Rpt.Contract.GetTx(&_Rpt.CallOpts, _addr, _blockNumber)
 }
 
+// BatchGetTx adds a call of the contract method 0x741a35c4 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getTx(_addr address, _blockNumber uint256) constant returns(uint256)
+func (_Rpt *RptCaller) BatchGetTx(batch *bind.BatchCall, _addr common.Address, _blockNumber *big.Int) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "getTx", _addr, _blockNumber)
+	return ret0, err
+}
+
 // Omega is a free data retrieval call binding the contract method 0x2262a1b3.
 //
 // Solidity: function omega() constant returns(uint256)
@@ -623,6 +753,19 @@ func (_Rpt *RptCallerSession) Omega() (*big.Int, error) {
 	return _Rpt.Contract.Omega(&_Rpt.CallOpts)
 }
 
+// BatchOmega adds a call of the contract method 0x2262a1b3 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function omega() constant returns(uint256)
+func (_Rpt *RptCaller) BatchOmega(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "omega")
+	return ret0, err
+}
+
 // Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
 //
 // Solidity: function owner() constant returns(address)
@@ -649,6 +792,19 @@ func (_Rpt *RptCallerSession) Owner() (common.Address, error) {
 	return _Rpt.Contract.Owner(&_Rpt.CallOpts)
 }
 
+// BatchOwner adds a call of the contract method 0x8da5cb5b to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function owner() constant returns(address)
+func (_Rpt *RptCaller) BatchOwner(batch *bind.BatchCall) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "owner")
+	return ret0, err
+}
+
 // Psi is a free data retrieval call binding the contract method 0x86f87fdd.
 //
 // Solidity: function psi() constant returns(uint256)
@@ -675,6 +831,19 @@ func (_Rpt *RptCallerSession) Psi() (*big.Int, error) {
 	return _Rpt.Contract.Psi(&_Rpt.CallOpts)
 }
 
+// BatchPsi adds a call of the contract method 0x86f87fdd to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function psi() constant returns(uint256)
+func (_Rpt *RptCaller) BatchPsi(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "psi")
+	return ret0, err
+}
+
 // Window is a free data retrieval call binding the contract method 0x461645bf.
 //
 // Solidity: function window() constant returns(uint256)
@@ -701,6 +870,19 @@ func (_Rpt *RptCallerSession) Window() (*big.Int, error) {
 	return _Rpt.Contract.Window(&_Rpt.CallOpts)
 }
 
+// BatchWindow adds a call of the contract method 0x461645bf to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function window() constant returns(uint256)
+func (_Rpt *RptCaller) BatchWindow(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Rpt.contract.BatchCall(batch, out, "window")
+	return ret0, err
+}
+
 // UpdateAlpha is a paid mutator transaction binding the contract method 0x06d2d3dc.
 //
 // Solidity: function updateAlpha(_alpha uint256) returns()
@@ -972,6 +1154,66 @@ func (_Rpt *RptFilterer) WatchSetExp(opts *bind.WatchOpts, sink chan<- *RptSetEx
 	}), nil
 }
 
+// RptSetExpFollower is returned from FollowSetExp and is used to follow the unpacked data for SetExp events raised by the Rpt contract across chain reorganisations.
+type RptSetExpFollower struct {
+	Event *RptSetExp // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptSetExpFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptSetExp)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptSetExpFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptSetExpFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptSetExpFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSetExp is a free log following operation binding the contract event 0xb14fcf0092d0dcc5be78d778e4f514b5d12a1671f6260cf9d84ac5847b08e894,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SetExp(blockNumber uint256, exprNmae string, expr string)
+func (_Rpt *RptFilterer) FollowSetExp(opts *bind.FollowOpts) (*RptSetExpFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "SetExp")
+	if err != nil {
+		return nil, err
+	}
+	return &RptSetExpFollower{contract: _Rpt.contract, event: "SetExp", follower: follower}, nil
+}
+
 // RptUpdateConfigsIterator is returned from FilterUpdateConfigs and is used to iterate over the raw logs and unpacked data for UpdateConfigs events raised by the Rpt contract.
 type RptUpdateConfigsIterator struct {
 	Event *RptUpdateConfigs // Event containing the contract specifics and raw log
@@ -1094,6 +1336,66 @@ func (_Rpt *RptFilterer) WatchUpdateConfigs(opts *bind.WatchOpts, sink chan<- *R
 	}), nil
 }
 
+// RptUpdateConfigsFollower is returned from FollowUpdateConfigs and is used to follow the unpacked data for UpdateConfigs events raised by the Rpt contract across chain reorganisations.
+type RptUpdateConfigsFollower struct {
+	Event *RptUpdateConfigs // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptUpdateConfigsFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptUpdateConfigs)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptUpdateConfigsFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptUpdateConfigsFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptUpdateConfigsFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateConfigs is a free log following operation binding the contract event 0x78a3671679b68721aaad9eb74535be0be119bd34c0efa671eb6ab3210d1fe257,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateConfigs(blockNumber uint256)
+func (_Rpt *RptFilterer) FollowUpdateConfigs(opts *bind.FollowOpts) (*RptUpdateConfigsFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "UpdateConfigs")
+	if err != nil {
+		return nil, err
+	}
+	return &RptUpdateConfigsFollower{contract: _Rpt.contract, event: "UpdateConfigs", follower: follower}, nil
+}
+
 // RptUpdateOneConfigIterator is returned from FilterUpdateOneConfig and is used to iterate over the raw logs and unpacked data for UpdateOneConfig events raised by the Rpt contract.
 type RptUpdateOneConfigIterator struct {
 	Event *RptUpdateOneConfig // Event containing the contract specifics and raw log
@@ -1218,6 +1520,66 @@ func (_Rpt *RptFilterer) WatchUpdateOneConfig(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// RptUpdateOneConfigFollower is returned from FollowUpdateOneConfig and is used to follow the unpacked data for UpdateOneConfig events raised by the Rpt contract across chain reorganisations.
+type RptUpdateOneConfigFollower struct {
+	Event *RptUpdateOneConfig // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RptUpdateOneConfigFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RptUpdateOneConfig)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RptUpdateOneConfigFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RptUpdateOneConfigFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RptUpdateOneConfigFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowUpdateOneConfig is a free log following operation binding the contract event 0x7c2d85cf45868065466ed7df2e23f26349626794d112e41a734a4e34727fcb21,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e UpdateOneConfig(blockNumber uint256, configName string, configValue uint256)
+func (_Rpt *RptFilterer) FollowUpdateOneConfig(opts *bind.FollowOpts) (*RptUpdateOneConfigFollower, error) {
+
+	follower, err := _Rpt.contract.FollowLogs(opts, "UpdateOneConfig")
+	if err != nil {
+		return nil, err
+	}
+	return &RptUpdateOneConfigFollower{contract: _Rpt.contract, event: "UpdateOneConfig", follower: follower}, nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
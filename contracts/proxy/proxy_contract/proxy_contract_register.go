@@ -202,6 +202,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) ContractAddres
 	return _ProxyContractRegister.Contract.ContractAddresses(&_ProxyContractRegister.CallOpts, arg0)
 }
 
+// BatchContractAddresses adds a call of the contract method 0x4661ac95 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function contractAddresses( address) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchContractAddresses(batch *bind.BatchCall, arg0 common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "contractAddresses", arg0)
+	return ret0, err
+}
+
 // GetContractVersion is a free data retrieval call binding the contract method 0xc4b9d89d.
 //
 // Solidity: function getContractVersion(_proxyAddress address) constant returns(uint256)
@@ -228,6 +241,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) GetContractVer
 	return _ProxyContractRegister.Contract.GetContractVersion(&_ProxyContractRegister.CallOpts, _proxyAddress)
 }
 
+// BatchGetContractVersion adds a call of the contract method 0xc4b9d89d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getContractVersion(_proxyAddress address) constant returns(uint256)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchGetContractVersion(batch *bind.BatchCall, _proxyAddress common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "getContractVersion", _proxyAddress)
+	return ret0, err
+}
+
 // GetOldContract is a free data retrieval call binding the contract method 0x4c9150c8.
 //
 // Solidity: function getOldContract(_addr address, _version uint256) constant returns(address)
@@ -254,6 +280,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) GetOldContract
 	return _ProxyContractRegister.Contract.GetOldContract(&_ProxyContractRegister.CallOpts, _addr, _version)
 }
 
+// BatchGetOldContract adds a call of the contract method 0x4c9150c8 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getOldContract(_addr address, _version uint256) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchGetOldContract(batch *bind.BatchCall, _addr common.Address, _version *big.Int) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "getOldContract", _addr, _version)
+	return ret0, err
+}
+
 // GetProxyContract is a free data retrieval call binding the contract method 0xfc4fdd3d.
 //
 // Solidity: function getProxyContract(_addr address) constant returns(address)
@@ -280,6 +319,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) GetProxyContra
 	return _ProxyContractRegister.Contract.GetProxyContract(&_ProxyContractRegister.CallOpts, _addr)
 }
 
+// BatchGetProxyContract adds a call of the contract method 0xfc4fdd3d to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getProxyContract(_addr address) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchGetProxyContract(batch *bind.BatchCall, _addr common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "getProxyContract", _addr)
+	return ret0, err
+}
+
 // GetRealContract is a free data retrieval call binding the contract method 0x8099b681.
 //
 // Solidity: function getRealContract(_addr address) constant returns(address)
@@ -306,6 +358,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) GetRealContrac
 	return _ProxyContractRegister.Contract.GetRealContract(&_ProxyContractRegister.CallOpts, _addr)
 }
 
+// BatchGetRealContract adds a call of the contract method 0x8099b681 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getRealContract(_addr address) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchGetRealContract(batch *bind.BatchCall, _addr common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "getRealContract", _addr)
+	return ret0, err
+}
+
 // HistroyContract is a free data retrieval call binding the contract method 0x0d100b03.
 //
 // Solidity: function histroyContract( address,  uint256) constant returns(address)
@@ -332,6 +397,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) HistroyContrac
 	return _ProxyContractRegister.Contract.HistroyContract(&_ProxyContractRegister.CallOpts, arg0, arg1)
 }
 
+// BatchHistroyContract adds a call of the contract method 0x0d100b03 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function histroyContract( address,  uint256) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchHistroyContract(batch *bind.BatchCall, arg0 common.Address, arg1 *big.Int) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "histroyContract", arg0, arg1)
+	return ret0, err
+}
+
 // ProxyContractAddress is a free data retrieval call binding the contract method 0x7dadbe99.
 //
 // Solidity: function proxyContractAddress( address) constant returns(address)
@@ -358,6 +436,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) ProxyContractA
 	return _ProxyContractRegister.Contract.ProxyContractAddress(&_ProxyContractRegister.CallOpts, arg0)
 }
 
+// BatchProxyContractAddress adds a call of the contract method 0x7dadbe99 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function proxyContractAddress( address) constant returns(address)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchProxyContractAddress(batch *bind.BatchCall, arg0 common.Address) (*common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "proxyContractAddress", arg0)
+	return ret0, err
+}
+
 // ProxyContractAddressVersion is a free data retrieval call binding the contract method 0x810d816a.
 //
 // Solidity: function proxyContractAddressVersion( address) constant returns(uint256)
@@ -384,6 +475,19 @@ func (_ProxyContractRegister *ProxyContractRegisterCallerSession) ProxyContractA
 	return _ProxyContractRegister.Contract.ProxyContractAddressVersion(&_ProxyContractRegister.CallOpts, arg0)
 }
 
+// BatchProxyContractAddressVersion adds a call of the contract method 0x810d816a to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function proxyContractAddressVersion( address) constant returns(uint256)
+func (_ProxyContractRegister *ProxyContractRegisterCaller) BatchProxyContractAddressVersion(batch *bind.BatchCall, arg0 common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _ProxyContractRegister.contract.BatchCall(batch, out, "proxyContractAddressVersion", arg0)
+	return ret0, err
+}
+
 // RegisterProxyContract is a paid mutator transaction binding the contract method 0x0e0bee3e.
 //
 // Solidity: function registerProxyContract(_proxyAddress address, _realAddress address) returns()
@@ -528,3 +632,63 @@ func (_ProxyContractRegister *ProxyContractRegisterFilterer) WatchProxyContractA
 		}
 	}), nil
 }
+
+// ProxyContractRegisterProxyContractAddressVersionFollower is returned from FollowProxyContractAddressVersion and is used to follow the unpacked data for ProxyContractAddressVersion events raised by the ProxyContractRegister contract across chain reorganisations.
+type ProxyContractRegisterProxyContractAddressVersionFollower struct {
+	Event *ProxyContractRegisterProxyContractAddressVersion // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *ProxyContractRegisterProxyContractAddressVersionFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(ProxyContractRegisterProxyContractAddressVersion)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *ProxyContractRegisterProxyContractAddressVersionFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *ProxyContractRegisterProxyContractAddressVersionFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *ProxyContractRegisterProxyContractAddressVersionFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowProxyContractAddressVersion is a free log following operation binding the contract event 0x9d57b1c7a777e15a03e127c41649b8c7d8d0d63df11ad3750bb52634544f9f31,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ProxyContractAddressVersion(_proxy address, _real address, _version uint256)
+func (_ProxyContractRegister *ProxyContractRegisterFilterer) FollowProxyContractAddressVersion(opts *bind.FollowOpts) (*ProxyContractRegisterProxyContractAddressVersionFollower, error) {
+
+	follower, err := _ProxyContractRegister.contract.FollowLogs(opts, "ProxyContractAddressVersion")
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyContractRegisterProxyContractAddressVersionFollower{contract: _ProxyContractRegister.contract, event: "ProxyContractAddressVersion", follower: follower}, nil
+}
@@ -7,8 +7,10 @@ import (
 
 	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/contracts/proxy"
 	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/vm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -34,7 +36,7 @@ func deploy(prvKey *ecdsa.PrivateKey, amount *big.Int, backend *backends.Simulat
 
 func TestProxyContractRegister_GetRealContract(t *testing.T) {
 	contractBackend := backends.NewDporSimulatedBackend(core.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000)}})
-	_, _, instance, err := deploy(key, big.NewInt(0), contractBackend)
+	register, _, instance, err := deploy(key, big.NewInt(0), contractBackend)
 	checkError(t, "deploy contract: expected no error, got %v", err)
 
 	_, err = instance.RegisterPublicKey(proxyaddr, realaddr)
@@ -58,6 +60,21 @@ func TestProxyContractRegister_GetRealContract(t *testing.T) {
 	if addrByversion != realaddr {
 		t.Fatal("get wrong address", "get addr:", addrByversion, "real address", realaddr)
 	}
+
+	// the EVM redirects calls of the proxy by reading the register's storage
+	config := *configs.ChainConfigInfo()
+	dpor := *config.Dpor
+	dpor.ProxyContractRegister = register
+	config.Dpor, config.ProxyContractBlock = &dpor, big.NewInt(0)
+	statedb, err := contractBackend.Blockchain().State()
+	checkError(t, "State : expected no error, got %v ", err)
+	evm := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1)}, statedb, &config, vm.Config{})
+	if logic := evm.ResolveProxy(proxyaddr); logic != realaddr {
+		t.Fatal("resolved wrong address", "resolved addr:", logic, "real address", realaddr)
+	}
+	if logic := evm.ResolveProxy(realaddr); logic != realaddr {
+		t.Fatal("resolved unregistered address", "resolved addr:", logic)
+	}
 }
 
 func checkError(t *testing.T, msg string, err error) {
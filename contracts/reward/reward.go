@@ -202,6 +202,19 @@ func (_Reward *RewardCallerSession) BonusPool() (*big.Int, error) {
 	return _Reward.Contract.BonusPool(&_Reward.CallOpts)
 }
 
+// BatchBonusPool adds a call of the contract method 0x2693ee80 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function bonusPool() constant returns(uint256)
+func (_Reward *RewardCaller) BatchBonusPool(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "bonusPool")
+	return ret0, err
+}
+
 // EnodeThreshold is a free data retrieval call binding the contract method 0xea86298c.
 //
 // Solidity: function enodeThreshold() constant returns(uint256)
@@ -228,6 +241,19 @@ func (_Reward *RewardCallerSession) EnodeThreshold() (*big.Int, error) {
 	return _Reward.Contract.EnodeThreshold(&_Reward.CallOpts)
 }
 
+// BatchEnodeThreshold adds a call of the contract method 0xea86298c to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function enodeThreshold() constant returns(uint256)
+func (_Reward *RewardCaller) BatchEnodeThreshold(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "enodeThreshold")
+	return ret0, err
+}
+
 // GetEnodes is a free data retrieval call binding the contract method 0xde4ff4bf.
 //
 // Solidity: function getEnodes() constant returns(address[])
@@ -254,6 +280,19 @@ func (_Reward *RewardCallerSession) GetEnodes() ([]common.Address, error) {
 	return _Reward.Contract.GetEnodes(&_Reward.CallOpts)
 }
 
+// BatchGetEnodes adds a call of the contract method 0xde4ff4bf to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function getEnodes() constant returns(address[])
+func (_Reward *RewardCaller) BatchGetEnodes(batch *bind.BatchCall) (*[]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "getEnodes")
+	return ret0, err
+}
+
 // InLock is a free data retrieval call binding the contract method 0x197331b8.
 //
 // Solidity: function inLock() constant returns(bool)
@@ -280,6 +319,19 @@ func (_Reward *RewardCallerSession) InLock() (bool, error) {
 	return _Reward.Contract.InLock(&_Reward.CallOpts)
 }
 
+// BatchInLock adds a call of the contract method 0x197331b8 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function inLock() constant returns(bool)
+func (_Reward *RewardCaller) BatchInLock(batch *bind.BatchCall) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "inLock")
+	return ret0, err
+}
+
 // InRaise is a free data retrieval call binding the contract method 0x273bac0b.
 //
 // Solidity: function inRaise() constant returns(bool)
@@ -306,6 +358,19 @@ func (_Reward *RewardCallerSession) InRaise() (bool, error) {
 	return _Reward.Contract.InRaise(&_Reward.CallOpts)
 }
 
+// BatchInRaise adds a call of the contract method 0x273bac0b to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function inRaise() constant returns(bool)
+func (_Reward *RewardCaller) BatchInRaise(batch *bind.BatchCall) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "inRaise")
+	return ret0, err
+}
+
 // InSettlement is a free data retrieval call binding the contract method 0x24eceaf6.
 //
 // Solidity: function inSettlement() constant returns(bool)
@@ -332,6 +397,19 @@ func (_Reward *RewardCallerSession) InSettlement() (bool, error) {
 	return _Reward.Contract.InSettlement(&_Reward.CallOpts)
 }
 
+// BatchInSettlement adds a call of the contract method 0x24eceaf6 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function inSettlement() constant returns(bool)
+func (_Reward *RewardCaller) BatchInSettlement(batch *bind.BatchCall) (*bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "inSettlement")
+	return ret0, err
+}
+
 // Investments is a free data retrieval call binding the contract method 0x96b98862.
 //
 // Solidity: function investments( address) constant returns(uint256)
@@ -358,6 +436,19 @@ func (_Reward *RewardCallerSession) Investments(arg0 common.Address) (*big.Int,
 	return _Reward.Contract.Investments(&_Reward.CallOpts, arg0)
 }
 
+// BatchInvestments adds a call of the contract method 0x96b98862 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function investments( address) constant returns(uint256)
+func (_Reward *RewardCaller) BatchInvestments(batch *bind.BatchCall, arg0 common.Address) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "investments", arg0)
+	return ret0, err
+}
+
 // LockPeriod is a free data retrieval call binding the contract method 0x3fd8b02f.
 //
 // Solidity: function lockPeriod() constant returns(uint256)
@@ -384,6 +475,19 @@ func (_Reward *RewardCallerSession) LockPeriod() (*big.Int, error) {
 	return _Reward.Contract.LockPeriod(&_Reward.CallOpts)
 }
 
+// BatchLockPeriod adds a call of the contract method 0x3fd8b02f to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function lockPeriod() constant returns(uint256)
+func (_Reward *RewardCaller) BatchLockPeriod(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "lockPeriod")
+	return ret0, err
+}
+
 // NextLockTime is a free data retrieval call binding the contract method 0x3086f3a4.
 //
 // Solidity: function nextLockTime() constant returns(uint256)
@@ -410,6 +514,19 @@ func (_Reward *RewardCallerSession) NextLockTime() (*big.Int, error) {
 	return _Reward.Contract.NextLockTime(&_Reward.CallOpts)
 }
 
+// BatchNextLockTime adds a call of the contract method 0x3086f3a4 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function nextLockTime() constant returns(uint256)
+func (_Reward *RewardCaller) BatchNextLockTime(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "nextLockTime")
+	return ret0, err
+}
+
 // NextRaiseTime is a free data retrieval call binding the contract method 0x6e216366.
 //
 // Solidity: function nextRaiseTime() constant returns(uint256)
@@ -436,6 +553,19 @@ func (_Reward *RewardCallerSession) NextRaiseTime() (*big.Int, error) {
 	return _Reward.Contract.NextRaiseTime(&_Reward.CallOpts)
 }
 
+// BatchNextRaiseTime adds a call of the contract method 0x6e216366 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function nextRaiseTime() constant returns(uint256)
+func (_Reward *RewardCaller) BatchNextRaiseTime(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "nextRaiseTime")
+	return ret0, err
+}
+
 // NextSettlementTime is a free data retrieval call binding the contract method 0x2b024aaf.
 //
 // Solidity: function nextSettlementTime() constant returns(uint256)
@@ -462,6 +592,19 @@ func (_Reward *RewardCallerSession) NextSettlementTime() (*big.Int, error) {
 	return _Reward.Contract.NextSettlementTime(&_Reward.CallOpts)
 }
 
+// BatchNextSettlementTime adds a call of the contract method 0x2b024aaf to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function nextSettlementTime() constant returns(uint256)
+func (_Reward *RewardCaller) BatchNextSettlementTime(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "nextSettlementTime")
+	return ret0, err
+}
+
 // RaisePeriod is a free data retrieval call binding the contract method 0x4618d9d2.
 //
 // Solidity: function raisePeriod() constant returns(uint256)
@@ -488,6 +631,19 @@ func (_Reward *RewardCallerSession) RaisePeriod() (*big.Int, error) {
 	return _Reward.Contract.RaisePeriod(&_Reward.CallOpts)
 }
 
+// BatchRaisePeriod adds a call of the contract method 0x4618d9d2 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function raisePeriod() constant returns(uint256)
+func (_Reward *RewardCaller) BatchRaisePeriod(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "raisePeriod")
+	return ret0, err
+}
+
 // Round is a free data retrieval call binding the contract method 0x146ca531.
 //
 // Solidity: function round() constant returns(uint256)
@@ -514,6 +670,19 @@ func (_Reward *RewardCallerSession) Round() (*big.Int, error) {
 	return _Reward.Contract.Round(&_Reward.CallOpts)
 }
 
+// BatchRound adds a call of the contract method 0x146ca531 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function round() constant returns(uint256)
+func (_Reward *RewardCaller) BatchRound(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "round")
+	return ret0, err
+}
+
 // SettlementPeriod is a free data retrieval call binding the contract method 0x0f1071be.
 //
 // Solidity: function settlementPeriod() constant returns(uint256)
@@ -540,6 +709,19 @@ func (_Reward *RewardCallerSession) SettlementPeriod() (*big.Int, error) {
 	return _Reward.Contract.SettlementPeriod(&_Reward.CallOpts)
 }
 
+// BatchSettlementPeriod adds a call of the contract method 0x0f1071be to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function settlementPeriod() constant returns(uint256)
+func (_Reward *RewardCaller) BatchSettlementPeriod(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "settlementPeriod")
+	return ret0, err
+}
+
 // TotalInterest is a free data retrieval call binding the contract method 0xbc7a36d6.
 //
 // Solidity: function totalInterest() constant returns(uint256)
@@ -566,6 +748,19 @@ func (_Reward *RewardCallerSession) TotalInterest() (*big.Int, error) {
 	return _Reward.Contract.TotalInterest(&_Reward.CallOpts)
 }
 
+// BatchTotalInterest adds a call of the contract method 0xbc7a36d6 to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function totalInterest() constant returns(uint256)
+func (_Reward *RewardCaller) BatchTotalInterest(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "totalInterest")
+	return ret0, err
+}
+
 // TotalInvestment is a free data retrieval call binding the contract method 0x10ea13df.
 //
 // Solidity: function totalInvestment() constant returns(uint256)
@@ -592,6 +787,19 @@ func (_Reward *RewardCallerSession) TotalInvestment() (*big.Int, error) {
 	return _Reward.Contract.TotalInvestment(&_Reward.CallOpts)
 }
 
+// BatchTotalInvestment adds a call of the contract method 0x10ea13df to batch, the
+// returned results being set once the batch is executed.
+//
+// Solidity: function totalInvestment() constant returns(uint256)
+func (_Reward *RewardCaller) BatchTotalInvestment(batch *bind.BatchCall) (**big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Reward.contract.BatchCall(batch, out, "totalInvestment")
+	return ret0, err
+}
+
 // ClaimInterest is a paid mutator transaction binding the contract method 0x35981fd8.
 //
 // Solidity: function claimInterest() returns()
@@ -1052,6 +1260,66 @@ func (_Reward *RewardFilterer) WatchAddInvestment(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// RewardAddInvestmentFollower is returned from FollowAddInvestment and is used to follow the unpacked data for AddInvestment events raised by the Reward contract across chain reorganisations.
+type RewardAddInvestmentFollower struct {
+	Event *RewardAddInvestment // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardAddInvestmentFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardAddInvestment)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardAddInvestmentFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardAddInvestmentFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardAddInvestmentFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowAddInvestment is a free log following operation binding the contract event 0x843f02bcc52ab5b45e8e33b61e593cb6e8f8b5d725107e495cfb41169c020b1a,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e AddInvestment(who address, amount uint256, total uint256)
+func (_Reward *RewardFilterer) FollowAddInvestment(opts *bind.FollowOpts) (*RewardAddInvestmentFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "AddInvestment")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardAddInvestmentFollower{contract: _Reward.contract, event: "AddInvestment", follower: follower}, nil
+}
+
 // RewardApplyForSettlementIterator is returned from FilterApplyForSettlement and is used to iterate over the raw logs and unpacked data for ApplyForSettlement events raised by the Reward contract.
 type RewardApplyForSettlementIterator struct {
 	Event *RewardApplyForSettlement // Event containing the contract specifics and raw log
@@ -1175,6 +1443,66 @@ func (_Reward *RewardFilterer) WatchApplyForSettlement(opts *bind.WatchOpts, sin
 	}), nil
 }
 
+// RewardApplyForSettlementFollower is returned from FollowApplyForSettlement and is used to follow the unpacked data for ApplyForSettlement events raised by the Reward contract across chain reorganisations.
+type RewardApplyForSettlementFollower struct {
+	Event *RewardApplyForSettlement // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardApplyForSettlementFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardApplyForSettlement)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardApplyForSettlementFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardApplyForSettlementFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardApplyForSettlementFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowApplyForSettlement is a free log following operation binding the contract event 0x1e6476e13208eaabfb4df240debd08374faf3c82dbf157ad79e99f812429bf21,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e ApplyForSettlement(who address, income uint256)
+func (_Reward *RewardFilterer) FollowApplyForSettlement(opts *bind.FollowOpts) (*RewardApplyForSettlementFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "ApplyForSettlement")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardApplyForSettlementFollower{contract: _Reward.contract, event: "ApplyForSettlement", follower: follower}, nil
+}
+
 // RewardEnodeQuitIterator is returned from FilterEnodeQuit and is used to iterate over the raw logs and unpacked data for EnodeQuit events raised by the Reward contract.
 type RewardEnodeQuitIterator struct {
 	Event *RewardEnodeQuit // Event containing the contract specifics and raw log
@@ -1298,6 +1626,66 @@ func (_Reward *RewardFilterer) WatchEnodeQuit(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// RewardEnodeQuitFollower is returned from FollowEnodeQuit and is used to follow the unpacked data for EnodeQuit events raised by the Reward contract across chain reorganisations.
+type RewardEnodeQuitFollower struct {
+	Event *RewardEnodeQuit // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardEnodeQuitFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardEnodeQuit)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardEnodeQuitFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardEnodeQuitFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardEnodeQuitFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowEnodeQuit is a free log following operation binding the contract event 0x79586139b58324379448df2efc55fe4634876e66799646ecabe665279eac8e3e,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e EnodeQuit(who address, balance uint256)
+func (_Reward *RewardFilterer) FollowEnodeQuit(opts *bind.FollowOpts) (*RewardEnodeQuitFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "EnodeQuit")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardEnodeQuitFollower{contract: _Reward.contract, event: "EnodeQuit", follower: follower}, nil
+}
+
 // RewardFundBonusPoolIterator is returned from FilterFundBonusPool and is used to iterate over the raw logs and unpacked data for FundBonusPool events raised by the Reward contract.
 type RewardFundBonusPoolIterator struct {
 	Event *RewardFundBonusPool // Event containing the contract specifics and raw log
@@ -1422,6 +1810,66 @@ func (_Reward *RewardFilterer) WatchFundBonusPool(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// RewardFundBonusPoolFollower is returned from FollowFundBonusPool and is used to follow the unpacked data for FundBonusPool events raised by the Reward contract across chain reorganisations.
+type RewardFundBonusPoolFollower struct {
+	Event *RewardFundBonusPool // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardFundBonusPoolFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardFundBonusPool)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardFundBonusPoolFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardFundBonusPoolFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardFundBonusPoolFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowFundBonusPool is a free log following operation binding the contract event 0xa1610f5f05db2d5caefef4f1bb2d913438a2fdb236ebc0ceedfacb398af1955b,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e FundBonusPool(who address, amount uint256, total uint256)
+func (_Reward *RewardFilterer) FollowFundBonusPool(opts *bind.FollowOpts) (*RewardFundBonusPoolFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "FundBonusPool")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardFundBonusPoolFollower{contract: _Reward.contract, event: "FundBonusPool", follower: follower}, nil
+}
+
 // RewardNewEnodeIterator is returned from FilterNewEnode and is used to iterate over the raw logs and unpacked data for NewEnode events raised by the Reward contract.
 type RewardNewEnodeIterator struct {
 	Event *RewardNewEnode // Event containing the contract specifics and raw log
@@ -1545,6 +1993,66 @@ func (_Reward *RewardFilterer) WatchNewEnode(opts *bind.WatchOpts, sink chan<- *
 	}), nil
 }
 
+// RewardNewEnodeFollower is returned from FollowNewEnode and is used to follow the unpacked data for NewEnode events raised by the Reward contract across chain reorganisations.
+type RewardNewEnodeFollower struct {
+	Event *RewardNewEnode // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardNewEnodeFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardNewEnode)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardNewEnodeFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardNewEnodeFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardNewEnodeFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowNewEnode is a free log following operation binding the contract event 0x210672d0bc3ee003d955e13c2d9d0a8f32e584d6afd71d9c442d62c93fa388fd,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e NewEnode(who address, investment uint256)
+func (_Reward *RewardFilterer) FollowNewEnode(opts *bind.FollowOpts) (*RewardNewEnodeFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "NewEnode")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardNewEnodeFollower{contract: _Reward.contract, event: "NewEnode", follower: follower}, nil
+}
+
 // RewardNewLockIterator is returned from FilterNewLock and is used to iterate over the raw logs and unpacked data for NewLock events raised by the Reward contract.
 type RewardNewLockIterator struct {
 	Event *RewardNewLock // Event containing the contract specifics and raw log
@@ -1667,6 +2175,66 @@ func (_Reward *RewardFilterer) WatchNewLock(opts *bind.WatchOpts, sink chan<- *R
 	}), nil
 }
 
+// RewardNewLockFollower is returned from FollowNewLock and is used to follow the unpacked data for NewLock events raised by the Reward contract across chain reorganisations.
+type RewardNewLockFollower struct {
+	Event *RewardNewLock // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardNewLockFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardNewLock)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardNewLockFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardNewLockFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardNewLockFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowNewLock is a free log following operation binding the contract event 0xb31d85c8dd6420b94a0301198a31f222bf0121111239360678a05581918363d2,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e NewLock(when uint256)
+func (_Reward *RewardFilterer) FollowNewLock(opts *bind.FollowOpts) (*RewardNewLockFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "NewLock")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardNewLockFollower{contract: _Reward.contract, event: "NewLock", follower: follower}, nil
+}
+
 // RewardNewRaiseIterator is returned from FilterNewRaise and is used to iterate over the raw logs and unpacked data for NewRaise events raised by the Reward contract.
 type RewardNewRaiseIterator struct {
 	Event *RewardNewRaise // Event containing the contract specifics and raw log
@@ -1789,6 +2357,66 @@ func (_Reward *RewardFilterer) WatchNewRaise(opts *bind.WatchOpts, sink chan<- *
 	}), nil
 }
 
+// RewardNewRaiseFollower is returned from FollowNewRaise and is used to follow the unpacked data for NewRaise events raised by the Reward contract across chain reorganisations.
+type RewardNewRaiseFollower struct {
+	Event *RewardNewRaise // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardNewRaiseFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardNewRaise)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardNewRaiseFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardNewRaiseFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardNewRaiseFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowNewRaise is a free log following operation binding the contract event 0x2c6acffc376bae0dce9f74a673af6142300bd796866c1acec95c70f80d233282,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e NewRaise(when uint256)
+func (_Reward *RewardFilterer) FollowNewRaise(opts *bind.FollowOpts) (*RewardNewRaiseFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "NewRaise")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardNewRaiseFollower{contract: _Reward.contract, event: "NewRaise", follower: follower}, nil
+}
+
 // RewardNewSettlementIterator is returned from FilterNewSettlement and is used to iterate over the raw logs and unpacked data for NewSettlement events raised by the Reward contract.
 type RewardNewSettlementIterator struct {
 	Event *RewardNewSettlement // Event containing the contract specifics and raw log
@@ -1911,6 +2539,66 @@ func (_Reward *RewardFilterer) WatchNewSettlement(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// RewardNewSettlementFollower is returned from FollowNewSettlement and is used to follow the unpacked data for NewSettlement events raised by the Reward contract across chain reorganisations.
+type RewardNewSettlementFollower struct {
+	Event *RewardNewSettlement // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardNewSettlementFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardNewSettlement)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardNewSettlementFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardNewSettlementFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardNewSettlementFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowNewSettlement is a free log following operation binding the contract event 0x5281dfe0dbeb8cfb053968120a0ecb9da2b12bc2dcc60f9734171a83ec610333,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e NewSettlement(when uint256)
+func (_Reward *RewardFilterer) FollowNewSettlement(opts *bind.FollowOpts) (*RewardNewSettlementFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "NewSettlement")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardNewSettlementFollower{contract: _Reward.contract, event: "NewSettlement", follower: follower}, nil
+}
+
 // RewardSetConfigIterator is returned from FilterSetConfig and is used to iterate over the raw logs and unpacked data for SetConfig events raised by the Reward contract.
 type RewardSetConfigIterator struct {
 	Event *RewardSetConfig // Event containing the contract specifics and raw log
@@ -2034,6 +2722,66 @@ func (_Reward *RewardFilterer) WatchSetConfig(opts *bind.WatchOpts, sink chan<-
 	}), nil
 }
 
+// RewardSetConfigFollower is returned from FollowSetConfig and is used to follow the unpacked data for SetConfig events raised by the Reward contract across chain reorganisations.
+type RewardSetConfigFollower struct {
+	Event *RewardSetConfig // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardSetConfigFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardSetConfig)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardSetConfigFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardSetConfigFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardSetConfigFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSetConfig is a free log following operation binding the contract event 0x361737d1051ccafab7e68fcbafe4466aaec6ee5ac4b3eb51447ac84a15cce16d,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SetConfig(what string, value uint256)
+func (_Reward *RewardFilterer) FollowSetConfig(opts *bind.FollowOpts) (*RewardSetConfigFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "SetConfig")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardSetConfigFollower{contract: _Reward.contract, event: "SetConfig", follower: follower}, nil
+}
+
 // RewardSetTimeIterator is returned from FilterSetTime and is used to iterate over the raw logs and unpacked data for SetTime events raised by the Reward contract.
 type RewardSetTimeIterator struct {
 	Event *RewardSetTime // Event containing the contract specifics and raw log
@@ -2157,6 +2905,66 @@ func (_Reward *RewardFilterer) WatchSetTime(opts *bind.WatchOpts, sink chan<- *R
 	}), nil
 }
 
+// RewardSetTimeFollower is returned from FollowSetTime and is used to follow the unpacked data for SetTime events raised by the Reward contract across chain reorganisations.
+type RewardSetTimeFollower struct {
+	Event *RewardSetTime // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardSetTimeFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardSetTime)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardSetTimeFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardSetTimeFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardSetTimeFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSetTime is a free log following operation binding the contract event 0x46e55608d5463575e90f64d3ae173372234546746315e0f3574a3bdf35567f9f,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SetTime(what string, when uint256)
+func (_Reward *RewardFilterer) FollowSetTime(opts *bind.FollowOpts) (*RewardSetTimeFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "SetTime")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardSetTimeFollower{contract: _Reward.contract, event: "SetTime", follower: follower}, nil
+}
+
 // RewardSubInvestmentIterator is returned from FilterSubInvestment and is used to iterate over the raw logs and unpacked data for SubInvestment events raised by the Reward contract.
 type RewardSubInvestmentIterator struct {
 	Event *RewardSubInvestment // Event containing the contract specifics and raw log
@@ -2281,6 +3089,66 @@ func (_Reward *RewardFilterer) WatchSubInvestment(opts *bind.WatchOpts, sink cha
 	}), nil
 }
 
+// RewardSubInvestmentFollower is returned from FollowSubInvestment and is used to follow the unpacked data for SubInvestment events raised by the Reward contract across chain reorganisations.
+type RewardSubInvestmentFollower struct {
+	Event *RewardSubInvestment // Event containing the contract specifics and raw log, removed if reorganised away
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	follower *bind.LogFollower // Follower delivering the raw logs
+	fail     error             // Occurred error to stop following
+}
+
+// Next waits for the subsequent event, returning false if following failed or
+// was closed. In case of a retrieval or parsing error, Error() can be queried
+// for the exact failure.
+func (it *RewardSubInvestmentFollower) Next() bool {
+	if it.fail != nil || !it.follower.Next() {
+		return false
+	}
+	log := it.follower.Log()
+	it.Event = new(RewardSubInvestment)
+	if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+		it.fail = err
+		return false
+	}
+	it.Event.Raw = log
+	return true
+}
+
+// Checkpoint returns the checkpoint to resume following after once the events
+// delivered so far are handled.
+func (it *RewardSubInvestmentFollower) Checkpoint() bind.Checkpoint {
+	return it.follower.Checkpoint()
+}
+
+// Error returns any retrieval or parsing error occurred during following.
+func (it *RewardSubInvestmentFollower) Error() error {
+	if it.fail != nil {
+		return it.fail
+	}
+	return it.follower.Error()
+}
+
+// Close terminates the following process, releasing a pending Next.
+func (it *RewardSubInvestmentFollower) Close() error {
+	return it.follower.Close()
+}
+
+// FollowSubInvestment is a free log following operation binding the contract event 0xdcd7220e3580673dbcb5899da3a05c073c2df48aa178b65b33fec6b43e13a09d,
+// delivering again the events of the blocks reorganised away as removed.
+//
+// Solidity: e SubInvestment(who address, amount uint256, total uint256)
+func (_Reward *RewardFilterer) FollowSubInvestment(opts *bind.FollowOpts) (*RewardSubInvestmentFollower, error) {
+
+	follower, err := _Reward.contract.FollowLogs(opts, "SubInvestment")
+	if err != nil {
+		return nil, err
+	}
+	return &RewardSubInvestmentFollower{contract: _Reward.contract, event: "SubInvestment", follower: follower}, nil
+}
+
 // SafeMathABI is the input ABI used to generate the binding from.
 const SafeMathABI = "[]"
 
@@ -0,0 +1,209 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kinds of chain data issues found by VerifyChainData.
+const (
+	IssueMissingCanonical = "missing-canonical-hash" // No canonical hash for the number
+	IssueMissingHeader    = "missing-header"         // Canonical header not found
+	IssueHeaderHash       = "header-hash"            // Stored header hashing to another hash
+	IssueHeaderNumber     = "header-number"          // Wrong or missing hash to number mapping, repairable
+	IssueParentLink       = "parent-link"            // Header not linked to the previous canonical one
+	IssueMissingBody      = "missing-body"           // Block body not found
+	IssueTxRoot           = "tx-root"                // Body not matching the transaction root
+	IssueMissingReceipts  = "missing-receipts"       // Receipts of a block with transactions not found
+	IssueReceiptsRoot     = "receipts-root"          // Receipts not matching the receipt root
+	IssueTxLookup         = "tx-lookup"              // Wrong or missing transaction lookup entry, repairable
+	IssueSeal             = "seal"                   // Seal not signed by the proposer of the snapshot
+	IssueSignatures       = "signatures"             // Validator signatures not matching the snapshot
+	IssueMissingState     = "missing-state"          // State trie of the block not resolvable
+	IssueDanglingNumber   = "dangling-header-number" // Hash to number mapping without header, repairable
+)
+
+// ChainIssue is a problem found in the chain data.
+type ChainIssue struct {
+	Number   uint64      `json:"number"`
+	Hash     common.Hash `json:"hash"`
+	Kind     string      `json:"kind"`
+	Detail   string      `json:"detail,omitempty"`
+	Repaired bool        `json:"repaired"`
+}
+
+// sealVerifier is implemented by the engines able to check the seal of a
+// header apart from its signatures.
+type sealVerifier interface {
+	VerifySeal(chain consensus.ChainReader, header *types.Header, refHeader *types.Header) error
+}
+
+// VerifyConfig selects the checks of VerifyChainData.
+type VerifyConfig struct {
+	From, To      uint64 // Range of canonical blocks to check, To beyond the head meaning the head
+	StateInterval uint64 // Check the state of every block with a number multiple of it, 0 for the last block only
+	Seals         bool   // Whether to check the seals and validator signatures
+	Repair        bool   // Whether to rewrite the broken indices
+}
+
+// VerifyChainData walks the canonical chain of bc between cfg.From and cfg.To,
+// reporting the problems found in the stored data. The header linkage, block
+// content roots, transaction lookup entries, seals and sampled states are
+// checked, followed by the hash to number mappings of the whole database.
+// Broken indices are rewritten if cfg.Repair is set. It returns the number of
+// blocks checked.
+//
+// Note that nodes pruning the state only keep the state of recent blocks.
+func VerifyChainData(bc *BlockChain, cfg *VerifyConfig, report func(*ChainIssue)) (uint64, error) {
+	var (
+		db      = bc.db
+		head    = bc.CurrentBlock().NumberU64()
+		to      = cfg.To
+		checked uint64
+	)
+	if to > head {
+		to = head
+	}
+	if cfg.From > to {
+		return 0, fmt.Errorf("invalid block range %d-%d", cfg.From, to)
+	}
+	issue := func(number uint64, hash common.Hash, kind string, repaired bool, format string, args ...interface{}) {
+		report(&ChainIssue{Number: number, Hash: hash, Kind: kind, Detail: fmt.Sprintf(format, args...), Repaired: repaired})
+	}
+	var parent common.Hash
+	if cfg.From > 0 {
+		parent = rawdb.ReadCanonicalHash(db, cfg.From-1)
+	}
+	for number := cfg.From; number <= to; number++ {
+		checked++
+
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			issue(number, hash, IssueMissingCanonical, false, "")
+			parent = common.Hash{}
+			continue
+		}
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			issue(number, hash, IssueMissingHeader, false, "")
+			parent = hash
+			continue
+		}
+		if have := header.Hash(); have != hash {
+			issue(number, hash, IssueHeaderHash, false, "stored header hashes to %x", have)
+		}
+		if mapped := rawdb.ReadHeaderNumber(db, hash); mapped == nil || *mapped != number {
+			if cfg.Repair {
+				rawdb.WriteHeaderNumber(db, hash, number)
+			}
+			issue(number, hash, IssueHeaderNumber, cfg.Repair, "hash mapped to %v", describeNumber(mapped))
+		}
+		if number > 0 && parent != (common.Hash{}) && header.ParentHash != parent {
+			issue(number, hash, IssueParentLink, false, "parent %x, previous canonical block %x", header.ParentHash, parent)
+		}
+		parent = hash
+
+		verifyBlockContent(bc, header, hash, cfg.Repair, issue)
+
+		if cfg.Seals && number > 0 {
+			if sealer, ok := bc.engine.(sealVerifier); ok {
+				if err := sealer.VerifySeal(bc, header, nil); err != nil {
+					issue(number, hash, IssueSeal, false, "%v", err)
+				}
+			}
+			if err := bc.engine.VerifySigs(bc, header, nil); err != nil {
+				issue(number, hash, IssueSignatures, false, "%v", err)
+			}
+		}
+		if (cfg.StateInterval > 0 && number%cfg.StateInterval == 0) || number == to {
+			if _, err := bc.StateAt(header.StateRoot); err != nil {
+				issue(number, hash, IssueMissingState, false, "state root %x: %v", header.StateRoot, err)
+			}
+		}
+	}
+	// Look for mappings left behind by deleted headers
+	if iteratee, ok := rawdb.KeyValueStore(db).(database.Iteratee); ok {
+		var dangling []common.Hash
+		err := rawdb.IterateHeaderNumbers(iteratee, func(hash common.Hash, number uint64) bool {
+			if !rawdb.HasHeader(db, hash, number) {
+				issue(number, hash, IssueDanglingNumber, cfg.Repair, "")
+				dangling = append(dangling, hash)
+			}
+			return true
+		})
+		if err != nil {
+			return checked, err
+		}
+		if cfg.Repair {
+			for _, hash := range dangling {
+				rawdb.DeleteHeaderNumber(db, hash)
+			}
+		}
+	}
+	return checked, nil
+}
+
+// verifyBlockContent checks the body, receipts and transaction lookup entries
+// of a canonical block against its header.
+func verifyBlockContent(bc *BlockChain, header *types.Header, hash common.Hash, repair bool, issue func(uint64, common.Hash, string, bool, string, ...interface{})) {
+	var (
+		db     = bc.db
+		number = header.Number.Uint64()
+	)
+	body := rawdb.ReadBody(db, hash, number)
+	if body == nil {
+		issue(number, hash, IssueMissingBody, false, "")
+		return
+	}
+	if root := types.DeriveSha(types.Transactions(body.Transactions)); root != header.TxsRoot {
+		issue(number, hash, IssueTxRoot, false, "body root %x, header root %x", root, header.TxsRoot)
+	}
+	receipts := rawdb.ReadReceipts(db, hash, number)
+	switch {
+	case receipts == nil && len(body.Transactions) > 0:
+		issue(number, hash, IssueMissingReceipts, false, "")
+	case receipts != nil:
+		if root := types.DeriveSha(receipts); root != header.ReceiptsRoot {
+			issue(number, hash, IssueReceiptsRoot, false, "receipts root %x, header root %x", root, header.ReceiptsRoot)
+		}
+	}
+	broken := 0
+	for i, tx := range body.Transactions {
+		blockHash, blockNumber, index := rawdb.ReadTxLookupEntry(db, tx.Hash())
+		if blockHash != hash || blockNumber != number || index != uint64(i) {
+			issue(number, hash, IssueTxLookup, repair, "transaction %x indexed at %x/%d/%d", tx.Hash(), blockHash, blockNumber, index)
+			broken++
+		}
+	}
+	if broken > 0 && repair {
+		rawdb.WriteTxLookupEntries(db, types.NewBlockWithHeader(header).WithBody(body.Transactions))
+	}
+}
+
+func describeNumber(number *uint64) string {
+	if number == nil {
+		return "nothing"
+	}
+	return fmt.Sprintf("%d", *number)
+}
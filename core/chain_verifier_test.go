@@ -0,0 +1,78 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVerifyChainData(t *testing.T) {
+	db := database.NewMemDatabase()
+	blockchain, err := newCanonical(fakeDpor(db), 8, db)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	verify := func(repair bool) map[string][]uint64 {
+		found := make(map[string][]uint64)
+		cfg := &VerifyConfig{To: math.MaxUint64, StateInterval: 4, Seals: true, Repair: repair}
+		checked, err := VerifyChainData(blockchain, cfg, func(issue *ChainIssue) {
+			found[issue.Kind] = append(found[issue.Kind], issue.Number)
+		})
+		if err != nil {
+			t.Fatalf("verification failed: %v", err)
+		}
+		if checked != 9 {
+			t.Fatalf("checked block count mismatch: have %d, want 9", checked)
+		}
+		return found
+	}
+	if found := verify(false); len(found) != 0 {
+		t.Fatalf("issues found in healthy chain: %v", found)
+	}
+	// Break a few things and check they are all reported
+	var (
+		hash3   = rawdb.ReadCanonicalHash(db, 3)
+		hash5   = rawdb.ReadCanonicalHash(db, 5)
+		orphan  = common.Hash{0x01}
+		corrupt = map[string]uint64{IssueMissingBody: 3, IssueHeaderNumber: 5, IssueDanglingNumber: 7}
+	)
+	rawdb.DeleteBody(db, hash3, 3)
+	rawdb.DeleteHeaderNumber(db, hash5)
+	rawdb.WriteHeaderNumber(db, orphan, 7)
+
+	found := verify(true)
+	if len(found) != len(corrupt) {
+		t.Fatalf("issue kinds mismatch: have %v, want %v", found, corrupt)
+	}
+	for kind, number := range corrupt {
+		if numbers := found[kind]; len(numbers) != 1 || numbers[0] != number {
+			t.Errorf("%s issues mismatch: have %v, want [%d]", kind, numbers, number)
+		}
+	}
+	// The repairable issues must be gone
+	found = verify(false)
+	if len(found) != 1 || len(found[IssueMissingBody]) != 1 {
+		t.Fatalf("issues left after repair mismatch: %v", found)
+	}
+}
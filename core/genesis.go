@@ -157,35 +157,51 @@ func SetupGenesisBlock(db database.Database, genesis *Genesis) (*configs.ChainCo
 		// Get the existing chain configuration.
 		storedCfg := rawdb.ReadChainConfig(db, stored)
 		newCfg := genesis.configOrDefault(stored)
-		var finalCfg *configs.ChainConfig
 		if genesis != nil {
 			// Check whether the genesis block is already written.
 			hash := genesis.ToBlock(nil).Hash()
 			if hash != stored {
 				return genesis.Config, hash, &GenesisMismatchError{stored, hash}
 			}
-			finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
+			return updateChainConfig(storedCfg, newCfg, db, stored)
 		} else {
 			// Special case: don't change the existing config of a non-mainnet chain if no new
-			// config is supplied. These chains would get AllProtocolChanges (and a compat error)
-			// if we just continued here.
-			if stored != MainnetGenesisHash {
+			// config is supplied, unless it is the chain of the run mode. Other chains would
+			// get the forks of the run mode (and a compat error) if we just continued here.
+			if stored != MainnetGenesisHash && !sameChain(storedCfg, newCfg) {
 				return storedCfg, stored, nil
-			} else {
-				finalCfg = updateChainConfig(storedCfg, newCfg, db, stored)
 			}
+			return updateChainConfig(storedCfg, newCfg, db, stored)
 		}
-		return finalCfg, stored, nil
 	}
 }
 
-func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) *configs.ChainConfig {
+// updateChainConfig stores the new chain configuration unless it reschedules a
+// fork the local chain is already past, in which case the new configuration is
+// returned along the point the chain has to be rewound to.
+func updateChainConfig(storedcfg *configs.ChainConfig, newcfg *configs.ChainConfig, db database.Database, stored common.Hash) (*configs.ChainConfig, common.Hash, error) {
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
+		rawdb.WriteChainConfig(db, stored, newcfg)
+		return newcfg, stored, nil
+	}
+	// Check config compatibility and write the config. Compatibility errors
+	// are returned to the caller unless we're already at block zero.
+	height := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadHeaderHash(db))
+	if height == nil {
+		return newcfg, stored, fmt.Errorf("missing block number for head header hash")
+	}
+	compatErr := storedcfg.CheckCompatible(newcfg, *height)
+	if compatErr != nil && *height != 0 && compatErr.RewindTo != 0 {
+		return newcfg, stored, compatErr
 	}
-	// NOTICE: in future we may need to check the compatibility of old and new configurations.
 	rawdb.WriteChainConfig(db, stored, newcfg)
-	return newcfg
+	return newcfg, stored, nil
+}
+
+// sameChain returns whether both chain configurations are of the same chain.
+func sameChain(storedcfg, newcfg *configs.ChainConfig) bool {
+	return storedcfg != nil && storedcfg.ChainID != nil && newcfg.ChainID != nil && storedcfg.ChainID.Cmp(newcfg.ChainID) == 0
 }
 
 // OpenGenesisBlock opens genesis block and returns its chain configuration and hash.
@@ -445,3 +461,38 @@ func newTestMainnetGenesisBlock() *Genesis {
 		},
 	}
 }
+
+// DeveloperGenesisBlock returns the genesis block of a developer chain, the
+// developer is the only proposer and validator. The developer and the faucets
+// are funded.
+func DeveloperGenesisBlock(period uint64, developer common.Address, faucets ...common.Address) *Genesis {
+	config := configs.DeveloperChainConfig(developer, period)
+
+	alloc := GenesisAlloc{
+		developer: {Balance: new(big.Int).Mul(big.NewInt(800000000), big.NewInt(configs.Cpc))},
+	}
+	for _, faucet := range faucets {
+		if _, ok := alloc[faucet]; !ok {
+			alloc[faucet] = GenesisAccount{Balance: new(big.Int).Mul(big.NewInt(1000000), big.NewInt(configs.Cpc))}
+		}
+	}
+
+	proposers := make([]common.Address, config.Dpor.TermLen)
+	for i := range proposers {
+		proposers[i] = developer
+	}
+	return &Genesis{
+		Config:     config,
+		Timestamp:  1492009146000,
+		ExtraData:  hexutil.MustDecode("0x0000000000000000000000000000000000000000000000000000000000000000"),
+		GasLimit:   configs.DefaultGasLimitPerBlock,
+		Difficulty: big.NewInt(1),
+		Alloc:      alloc,
+		Dpor: types.DporSnap{
+			Proposers:  proposers,
+			Seal:       types.DporSignature{},
+			Sigs:       make([]types.DporSignature, configs.DeveloperValidatorsNumber),
+			Validators: []common.Address{developer},
+		},
+	}
+}
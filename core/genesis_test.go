@@ -25,8 +25,10 @@ import (
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/core/rawdb"
 	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestDefaultGenesisBlock(t *testing.T) {
@@ -45,14 +47,14 @@ func TestSetupGenesis(t *testing.T) {
 	var (
 		customghash = common.HexToHash("0x7665f953c35e95322ebc826f0293500e3bf00689f1f9565be0b7cd097897988d")
 		customg     = Genesis{
-			Config: &configs.ChainConfig{},
+			Config: &configs.ChainConfig{ImpeachPunishmentBlock: big.NewInt(3)},
 			Alloc: GenesisAlloc{
 				{1}: {Balance: big.NewInt(1), Storage: map[common.Hash]common.Hash{{1}: {1}}},
 			},
 		}
 		oldcustomg = customg
 	)
-	oldcustomg.Config = &configs.ChainConfig{}
+	oldcustomg.Config = &configs.ChainConfig{ImpeachPunishmentBlock: big.NewInt(2)}
 	tests := []struct {
 		name       string
 		fn         func(database.Database) (*configs.ChainConfig, common.Hash, error)
@@ -103,6 +105,27 @@ func TestSetupGenesis(t *testing.T) {
 			wantHash:   customghash,
 			wantConfig: customg.Config,
 		},
+		{
+			name: "incompatible config in DB",
+			fn: func(db database.Database) (*configs.ChainConfig, common.Hash, error) {
+				// Commit the 'old' genesis block with the impeach punishment fork at #2
+				// and advance the head to #4, past the fork of customg.
+				genesis := oldcustomg.MustCommit(db)
+				head := &types.Header{ParentHash: genesis.Hash(), Number: big.NewInt(4)}
+				rawdb.WriteHeader(db, head)
+				rawdb.WriteCanonicalHash(db, head.Hash(), 4)
+				rawdb.WriteHeadHeaderHash(db, head.Hash())
+				return SetupGenesisBlock(db, &customg)
+			},
+			wantHash:   customghash,
+			wantConfig: customg.Config,
+			wantErr: &configs.ConfigCompatError{
+				What:         "impeach punishment fork block",
+				StoredConfig: big.NewInt(2),
+				NewConfig:    big.NewInt(3),
+				RewindTo:     1,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -159,3 +182,32 @@ func TestGenesisAlloc(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestDeveloperGenesisBlock(t *testing.T) {
+	var (
+		developer = common.HexToAddress("0x01")
+		faucet    = common.HexToAddress("0x02")
+		genesis   = DeveloperGenesisBlock(0, developer, developer, faucet)
+	)
+	if len(genesis.Alloc) != 2 || genesis.Alloc[faucet].Balance.Sign() <= 0 {
+		t.Errorf("accounts not funded: %v", genesis.Alloc)
+	}
+	if len(genesis.Dpor.Validators) != int(genesis.Config.Dpor.ValidatorsLen()) || genesis.Dpor.Validators[0] != developer {
+		t.Errorf("validators mismatch: %v", genesis.Dpor.Validators)
+	}
+	for _, proposer := range genesis.Dpor.Proposers {
+		if proposer != developer {
+			t.Errorf("proposers mismatch: %v", genesis.Dpor.Proposers)
+		}
+	}
+	// System contracts are at the addresses the developer deploys them to
+	if addr := genesis.Config.Dpor.Contracts[configs.ContractRnode]; addr != crypto.CreateAddress(developer, 0) {
+		t.Errorf("rnode contract address mismatch: %v", addr.Hex())
+	}
+	if addr := genesis.Config.Dpor.ProxyContractRegister; addr != crypto.CreateAddress(developer, uint64(len(configs.DeveloperContracts))) {
+		t.Errorf("proxy contract register address mismatch: %v", addr.Hex())
+	}
+	if _, _, err := SetupGenesisBlock(database.NewMemDatabase(), genesis); err != nil {
+		t.Errorf("failed to set up developer genesis: %v", err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -30,6 +31,9 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHashTable, number)
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -60,6 +64,38 @@ func ReadHeaderNumber(db DatabaseReader, hash common.Hash) *uint64 {
 	return &number
 }
 
+// WriteHeaderNumber stores the hash to number mapping of a header.
+func WriteHeaderNumber(db DatabaseWriter, hash common.Hash, number uint64) {
+	if err := db.Put(headerNumberKey(hash), encodeBlockNumber(number)); err != nil {
+		log.Fatal("Failed to store hash to number mapping", "err", err)
+	}
+}
+
+// DeleteHeaderNumber removes the hash to number mapping of a header.
+func DeleteHeaderNumber(db DatabaseDeleter, hash common.Hash) {
+	if err := db.Delete(headerNumberKey(hash)); err != nil {
+		log.Fatal("Failed to delete hash to number mapping", "err", err)
+	}
+}
+
+// IterateHeaderNumbers calls fn with every hash to number mapping of db, until
+// fn returns false.
+func IterateHeaderNumbers(db database.Iteratee, fn func(hash common.Hash, number uint64) bool) error {
+	it := db.NewPrefixIterator(headerNumberPrefix)
+	defer it.Release()
+
+	for it.Next() {
+		key, value := it.Key(), it.Value()
+		if len(key) != len(headerNumberPrefix)+common.HashLength || len(value) != 8 {
+			continue
+		}
+		if !fn(common.BytesToHash(key[len(headerNumberPrefix):]), binary.BigEndian.Uint64(value)) {
+			break
+		}
+	}
+	return it.Error()
+}
+
 // ReadHeadHeaderHash retrieves the hash of the current canonical head header.
 func ReadHeadHeaderHash(db DatabaseReader) common.Hash {
 	data, _ := db.Get(headHeaderKey)
@@ -129,13 +165,16 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncientBlock(db, freezerHeaderTable, hash, number)) > 0
 	}
 	return true
 }
@@ -191,6 +230,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions and uncles) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -204,7 +246,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncientBlock(db, freezerBodiesTable, hash, number)) > 0
 	}
 	return true
 }
@@ -243,6 +285,9 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
 	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncientBlock(db, freezerReceiptTable, hash, number)
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -290,6 +290,7 @@ func TestBlockReceiptStorage(t *testing.T) {
 		TxHash:          common.BytesToHash([]byte{0x22, 0x22}),
 		ContractAddress: common.BytesToAddress([]byte{0x02, 0x22, 0x22}),
 		GasUsed:         222222,
+		LogicAddress:    common.BytesToAddress([]byte{0x02, 0x33}),
 	}
 	receipts := []*types.Receipt{receipt1, receipt2}
 
@@ -310,6 +311,9 @@ func TestBlockReceiptStorage(t *testing.T) {
 			if !bytes.Equal(rlpHave, rlpWant) {
 				t.Fatalf("receipt #%d: receipt mismatch: have %v, want %v", i, rs[i], receipts[i])
 			}
+			if rs[i].LogicAddress != receipts[i].LogicAddress {
+				t.Fatalf("receipt #%d: logic address mismatch: have %x, want %x", i, rs[i].LogicAddress, receipts[i].LogicAddress)
+			}
 		}
 	}
 	// Delete the receipt slice and check purge
@@ -105,3 +105,129 @@ func WriteBloomBits(db DatabaseWriter, bit uint, section uint64, head common.Has
 		log.Fatal("Failed to store bloom bits", "err", err)
 	}
 }
+
+// ReadAddrTxEntries retrieves the transactions involving an address within a
+// section of the address index, in chain order.
+func ReadAddrTxEntries(db DatabaseReader, addr common.Address, section uint64) []AddrTxEntry {
+	data, _ := db.Get(addrTxKey(addr, section))
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []AddrTxEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("Invalid address transaction entries RLP", "address", addr, "section", section, "err", err)
+		return nil
+	}
+	return entries
+}
+
+// ReadAddrTxSectionAddresses retrieves the addresses having entries in a
+// section of the address index.
+func ReadAddrTxSectionAddresses(db DatabaseReader, section uint64) []common.Address {
+	data, _ := db.Get(addrTxSectionKey(section))
+	if len(data) == 0 {
+		return nil
+	}
+	var addrs []common.Address
+	if err := rlp.DecodeBytes(data, &addrs); err != nil {
+		log.Error("Invalid address index section RLP", "section", section, "err", err)
+		return nil
+	}
+	return addrs
+}
+
+// WriteAddrTxSection stores the entries of all addresses involved in a section
+// of the address index. Entries left from a previous version of the section
+// must be deleted first with DeleteAddrTxSection.
+func WriteAddrTxSection(db DatabaseWriter, section uint64, entries map[common.Address][]AddrTxEntry) {
+	addrs := make([]common.Address, 0, len(entries))
+	for addr, list := range entries {
+		data, err := rlp.EncodeToBytes(list)
+		if err != nil {
+			log.Fatal("Failed to encode address transaction entries", "err", err)
+		}
+		if err := db.Put(addrTxKey(addr, section), data); err != nil {
+			log.Fatal("Failed to store address transaction entries", "err", err)
+		}
+		addrs = append(addrs, addr)
+	}
+	data, err := rlp.EncodeToBytes(addrs)
+	if err != nil {
+		log.Fatal("Failed to encode address index section", "err", err)
+	}
+	if err := db.Put(addrTxSectionKey(section), data); err != nil {
+		log.Fatal("Failed to store address index section", "err", err)
+	}
+}
+
+// DeleteAddrTxSection removes the entries of the given addresses in a section
+// of the address index, together with the section's address list.
+func DeleteAddrTxSection(db DatabaseDeleter, section uint64, addrs []common.Address) {
+	for _, addr := range addrs {
+		db.Delete(addrTxKey(addr, section))
+	}
+	db.Delete(addrTxSectionKey(section))
+}
+
+// ReadLogIndexEntries retrieves the positions of the logs emitted by an address
+// with a first topic within a section of the log index, in chain order.
+func ReadLogIndexEntries(db DatabaseReader, key LogIndexKey, section uint64) []LogIndexEntry {
+	data, _ := db.Get(logIndexKey(key, section))
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []LogIndexEntry
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("Invalid log index entries RLP", "address", key.Address, "topic", key.Topic, "section", section, "err", err)
+		return nil
+	}
+	return entries
+}
+
+// ReadLogIndexSectionKeys retrieves the keys having entries in a section of the
+// log index.
+func ReadLogIndexSectionKeys(db DatabaseReader, section uint64) []LogIndexKey {
+	data, _ := db.Get(logIndexSectionKey(section))
+	if len(data) == 0 {
+		return nil
+	}
+	var keys []LogIndexKey
+	if err := rlp.DecodeBytes(data, &keys); err != nil {
+		log.Error("Invalid log index section RLP", "section", section, "err", err)
+		return nil
+	}
+	return keys
+}
+
+// WriteLogIndexSection stores the entries of all keys of a section of the log
+// index. Entries left from a previous version of the section must be deleted
+// first with DeleteLogIndexSection.
+func WriteLogIndexSection(db DatabaseWriter, section uint64, entries map[LogIndexKey][]LogIndexEntry) {
+	keys := make([]LogIndexKey, 0, len(entries))
+	for key, list := range entries {
+		data, err := rlp.EncodeToBytes(list)
+		if err != nil {
+			log.Fatal("Failed to encode log index entries", "err", err)
+		}
+		if err := db.Put(logIndexKey(key, section), data); err != nil {
+			log.Fatal("Failed to store log index entries", "err", err)
+		}
+		keys = append(keys, key)
+	}
+	data, err := rlp.EncodeToBytes(keys)
+	if err != nil {
+		log.Fatal("Failed to encode log index section", "err", err)
+	}
+	if err := db.Put(logIndexSectionKey(section), data); err != nil {
+		log.Fatal("Failed to store log index section", "err", err)
+	}
+}
+
+// DeleteLogIndexSection removes the entries of the given keys in a section of
+// the log index, together with the section's key list.
+func DeleteLogIndexSection(db DatabaseDeleter, section uint64, keys []LogIndexKey) {
+	for _, key := range keys {
+		db.Delete(logIndexKey(key, section))
+	}
+	db.Delete(logIndexSectionKey(section))
+}
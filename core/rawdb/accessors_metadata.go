@@ -88,3 +88,41 @@ func WritePreimages(db DatabaseWriter, number uint64, preimages map[common.Hash]
 	preimageCounter.Inc(int64(len(preimages)))
 	preimageHitCounter.Inc(int64(len(preimages)))
 }
+
+// ContractABI is the ABI of a contract registered by the user.
+type ContractABI struct {
+	Name string // Name of the contract, for display only
+	ABI  string // JSON ABI of the contract
+}
+
+// ReadContractABI retrieves the ABI registered for a contract, nil if none.
+func ReadContractABI(db DatabaseReader, addr common.Address) *ContractABI {
+	data, _ := db.Get(contractABIKey(addr))
+	if len(data) == 0 {
+		return nil
+	}
+	var entry ContractABI
+	if err := rlp.DecodeBytes(data, &entry); err != nil {
+		log.Error("Invalid contract ABI RLP", "address", addr, "err", err)
+		return nil
+	}
+	return &entry
+}
+
+// WriteContractABI registers the ABI of a contract.
+func WriteContractABI(db DatabaseWriter, addr common.Address, entry *ContractABI) {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Fatal("Failed to RLP encode contract ABI", "err", err)
+	}
+	if err := db.Put(contractABIKey(addr), data); err != nil {
+		log.Fatal("Failed to store contract ABI", "err", err)
+	}
+}
+
+// DeleteContractABI removes the ABI registered for a contract.
+func DeleteContractABI(db DatabaseDeleter, addr common.Address) {
+	if err := db.Delete(contractABIKey(addr)); err != nil {
+		log.Fatal("Failed to delete contract ABI", "err", err)
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The tables of the ancient store, one item per block number.
+const (
+	freezerHashTable     = "hashes"   // Canonical block hashes
+	freezerHeaderTable   = "headers"  // Header RLP encodings
+	freezerBodiesTable   = "bodies"   // Body RLP encodings
+	freezerReceiptTable  = "receipts" // Receipt storage RLP encodings
+	freezerBatchMaxItems = 2048       // Maximum number of blocks moved per sync
+)
+
+var freezerTables = []string{freezerHashTable, freezerHeaderTable, freezerBodiesTable, freezerReceiptTable}
+
+// errUnknownTable is returned if the user attempts to read from a table that is
+// not tracked by the freezer.
+var errUnknownTable = errors.New("unknown table")
+
+// AncientReader is implemented by databases backed by an ancient store.
+type AncientReader interface {
+	// Ancient retrieves an item of a kind of ancient data by block number.
+	Ancient(kind string, number uint64) ([]byte, error)
+
+	// Ancients returns the number of blocks in the ancient store.
+	Ancients() uint64
+}
+
+// Freezer is an append-only store of the canonical chain data older than a
+// given depth, one flat file table per kind of data. Blocks are stored from
+// the genesis on without gaps.
+type Freezer struct {
+	frozen uint64 // Number of blocks already frozen, accessed atomically
+
+	tables map[string]*freezerTable
+}
+
+// NewFreezer opens the ancient store in dir, creating it if needed. Tables left
+// uneven by an interrupted freeze are cut back to their common length.
+func NewFreezer(dir string) (*Freezer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	freezer := &Freezer{tables: make(map[string]*freezerTable)}
+	for _, name := range freezerTables {
+		table, err := newFreezerTable(dir, name)
+		if err != nil {
+			freezer.Close()
+			return nil, err
+		}
+		freezer.tables[name] = table
+	}
+	frozen := freezer.tables[freezerHashTable].Items()
+	for _, table := range freezer.tables {
+		if items := table.Items(); items < frozen {
+			frozen = items
+		}
+	}
+	if err := freezer.truncate(frozen); err != nil {
+		freezer.Close()
+		return nil, err
+	}
+	freezer.frozen = frozen
+	return freezer, nil
+}
+
+// Ancient implements AncientReader.
+func (f *Freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table := f.tables[kind]
+	if table == nil {
+		return nil, errUnknownTable
+	}
+	return table.Retrieve(number)
+}
+
+// Ancients implements AncientReader.
+func (f *Freezer) Ancients() uint64 {
+	return atomic.LoadUint64(&f.frozen)
+}
+
+// AppendAncient stores the data of the next block number.
+func (f *Freezer) AppendAncient(number uint64, hash common.Hash, header, body, receipts []byte) error {
+	if frozen := f.Ancients(); number != frozen {
+		return fmt.Errorf("%v (have %d, want %d)", errOutOrderInsertion, number, frozen)
+	}
+	blobs := map[string][]byte{
+		freezerHashTable:    hash.Bytes(),
+		freezerHeaderTable:  header,
+		freezerBodiesTable:  body,
+		freezerReceiptTable: receipts,
+	}
+	for _, name := range freezerTables {
+		if err := f.tables[name].Append(number, blobs[name]); err != nil {
+			// Drop the partially stored block
+			f.truncate(number)
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, number+1)
+	return nil
+}
+
+// TruncateAncients discards the blocks from number items on.
+func (f *Freezer) TruncateAncients(items uint64) error {
+	if f.Ancients() <= items {
+		return nil
+	}
+	if err := f.truncate(items); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&f.frozen, items)
+	return nil
+}
+
+func (f *Freezer) truncate(items uint64) error {
+	for _, table := range f.tables {
+		if err := table.truncate(items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync flushes all the tables to disk.
+func (f *Freezer) Sync() error {
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the tables.
+func (f *Freezer) Close() error {
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// freezerdb is a database serving the recent data from a key-value store and
+// the ancient data from a freezer.
+type freezerdb struct {
+	database.Database
+	*Freezer
+}
+
+// AncientPath returns the directory of the ancient store kept along the
+// key-value store at path.
+func AncientPath(path string) string {
+	return filepath.Join(path, "ancient")
+}
+
+// NewDatabaseWithFreezer returns a database reading the canonical chain data
+// missing from db from the ancient store in dir.
+func NewDatabaseWithFreezer(db database.Database, dir string) (database.Database, error) {
+	freezer, err := NewFreezer(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &freezerdb{Database: db, Freezer: freezer}, nil
+}
+
+// Close closes both the key-value store and the freezer.
+func (db *freezerdb) Close() {
+	if err := db.Freezer.Close(); err != nil {
+		log.Error("Failed to close ancient store", "err", err)
+	}
+	db.Database.Close()
+}
+
+// KeyValueStore returns the key-value store of db, db itself if it has no
+// ancient store.
+func KeyValueStore(db database.Database) database.Database {
+	if fdb, ok := db.(*freezerdb); ok {
+		return fdb.Database
+	}
+	return db
+}
+
+// AncientStore returns the ancient store of db, nil if it has none.
+func AncientStore(db database.Database) *Freezer {
+	if fdb, ok := db.(*freezerdb); ok {
+		return fdb.Freezer
+	}
+	return nil
+}
+
+// readAncient retrieves an item of ancient data if db has an ancient store.
+func readAncient(db DatabaseReader, kind string, number uint64) []byte {
+	reader, ok := db.(AncientReader)
+	if !ok {
+		return nil
+	}
+	data, _ := reader.Ancient(kind, number)
+	return data
+}
+
+// readAncientBlock retrieves an item of ancient data, provided the ancient
+// block with the number is the one with the hash.
+func readAncientBlock(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	if common.BytesToHash(readAncient(db, freezerHashTable, number)) != hash {
+		return nil
+	}
+	return readAncient(db, kind, number)
+}
+
+// FreezeBlocks moves the canonical blocks below limit from the key-value store
+// of db to its ancient store, returning the number of blocks moved. Data is
+// only deleted from the key-value store once it is synced to the freezer.
+// Header numbers and total difficulties stay in the key-value store.
+func FreezeBlocks(db database.Database, limit uint64) (int, error) {
+	var (
+		freezer = AncientStore(db)
+		kvdb    = KeyValueStore(db)
+		moved   int
+	)
+	if freezer == nil {
+		return 0, errors.New("database has no ancient store")
+	}
+	for freezer.Ancients() < limit {
+		var (
+			first  = freezer.Ancients()
+			last   = limit
+			hashes []common.Hash
+		)
+		if last-first > freezerBatchMaxItems {
+			last = first + freezerBatchMaxItems
+		}
+		for number := first; number < last; number++ {
+			hash := ReadCanonicalHash(kvdb, number)
+			if hash == (common.Hash{}) {
+				return moved, fmt.Errorf("canonical hash missing, can't freeze block %d", number)
+			}
+			header := ReadHeaderRLP(kvdb, hash, number)
+			if len(header) == 0 {
+				return moved, fmt.Errorf("block header missing, can't freeze block %d", number)
+			}
+			body := ReadBodyRLP(kvdb, hash, number)
+			if len(body) == 0 {
+				return moved, fmt.Errorf("block body missing, can't freeze block %d", number)
+			}
+			receipts, _ := kvdb.Get(blockReceiptsKey(number, hash))
+			if len(receipts) == 0 {
+				// Blocks without transactions may have no stored receipts
+				receipts, _ = rlp.EncodeToBytes([]*types.ReceiptForStorage{})
+			}
+			if err := freezer.AppendAncient(number, hash, header, body, receipts); err != nil {
+				return moved, err
+			}
+			hashes = append(hashes, hash)
+		}
+		if err := freezer.Sync(); err != nil {
+			return moved, err
+		}
+		batch := kvdb.NewBatch()
+		for i, hash := range hashes {
+			number := first + uint64(i)
+			for _, key := range [][]byte{headerKey(number, hash), blockBodyKey(number, hash), blockReceiptsKey(number, hash), headerHashKey(number)} {
+				if err := batch.Delete(key); err != nil {
+					return moved, err
+				}
+			}
+		}
+		if err := batch.Write(); err != nil {
+			return moved, err
+		}
+		moved += len(hashes)
+	}
+	return moved, nil
+}
+
+// VerifyAncients checks the integrity of the ancient store of db. Every stored
+// header must hash to the stored hash and link to its parent, and its
+// transaction and receipt roots must match the stored body and receipts. The
+// recent blocks of the key-value store must continue the ancient chain.
+func VerifyAncients(db database.Database) error {
+	freezer := AncientStore(db)
+	if freezer == nil {
+		return errors.New("database has no ancient store")
+	}
+	var parent common.Hash
+	for number := uint64(0); number < freezer.Ancients(); number++ {
+		hash := ReadCanonicalHash(db, number)
+		header := ReadHeader(db, hash, number)
+		if header == nil {
+			return fmt.Errorf("block %d: header missing or not matching hash %x", number, hash)
+		}
+		if header.Hash() != hash {
+			return fmt.Errorf("block %d: header hash mismatch: have %x, want %x", number, header.Hash(), hash)
+		}
+		if header.Number.Uint64() != number {
+			return fmt.Errorf("block %d: header number mismatch: have %d", number, header.Number)
+		}
+		if number > 0 && header.ParentHash != parent {
+			return fmt.Errorf("block %d: parent hash mismatch: have %x, want %x", number, header.ParentHash, parent)
+		}
+		body := ReadBody(db, hash, number)
+		if body == nil {
+			return fmt.Errorf("block %d: body missing", number)
+		}
+		if root := types.DeriveSha(types.Transactions(body.Transactions)); root != header.TxsRoot {
+			return fmt.Errorf("block %d: transaction root mismatch: have %x, want %x", number, root, header.TxsRoot)
+		}
+		receipts := ReadReceipts(db, hash, number)
+		if root := types.DeriveSha(receipts); root != header.ReceiptsRoot {
+			return fmt.Errorf("block %d: receipt root mismatch: have %x, want %x", number, root, header.ReceiptsRoot)
+		}
+		parent = hash
+	}
+	// The first block left in the key-value store must follow the ancient ones
+	if frozen := freezer.Ancients(); frozen > 0 {
+		if hash := ReadCanonicalHash(db, frozen); hash != (common.Hash{}) {
+			if header := ReadHeader(db, hash, frozen); header == nil || header.ParentHash != parent {
+				return fmt.Errorf("block %d: not linked to the last ancient block %x", frozen, parent)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// indexEntrySize is the size of an entry of a freezer table index, the big
+// endian end offset of the item in the data file.
+const indexEntrySize = 8
+
+var (
+	// errOutOfBounds is returned if the item requested is not contained within
+	// the freezer table.
+	errOutOfBounds = errors.New("out of bounds")
+
+	// errOutOrderInsertion is returned if the user attempts to append an item
+	// out of order.
+	errOutOrderInsertion = errors.New("the append operation is out-order")
+
+	// errClosed is returned if an operation attempts to use a closed freezer.
+	errClosed = errors.New("closed")
+)
+
+// freezerTable is an append-only flat file store of a single kind of items,
+// addressed by their position. The items are concatenated in the data file,
+// the index file holding the end offset of each of them.
+type freezerTable struct {
+	name  string
+	index *os.File // File holding the end offsets of the items
+	data  *os.File // File holding the concatenated items
+	items uint64   // Number of items stored in the table
+	size  uint64   // Size of the data file
+
+	lock sync.RWMutex // Protects the files and counters
+}
+
+// newFreezerTable opens the table name in dir, creating it if needed. Items
+// partially written by an interrupted append are discarded.
+func newFreezerTable(dir, name string) (*freezerTable, error) {
+	index, err := os.OpenFile(filepath.Join(dir, name+".ridx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".rdat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		index.Close()
+		return nil, err
+	}
+	table := &freezerTable{name: name, index: index, data: data}
+	if err := table.repair(); err != nil {
+		table.Close()
+		return nil, err
+	}
+	return table, nil
+}
+
+// repair cuts the index and data files back to the last complete item.
+func (t *freezerTable) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	items := uint64(stat.Size()) / indexEntrySize
+	if stat, err = t.data.Stat(); err != nil {
+		return err
+	}
+	size := uint64(stat.Size())
+
+	// Drop the items whose data did not make it to disk
+	for ; items > 0; items-- {
+		end, err := t.offset(items)
+		if err != nil {
+			return err
+		}
+		if end <= size {
+			size = end
+			break
+		}
+	}
+	if items == 0 {
+		size = 0
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.items, t.size = items, size
+	return nil
+}
+
+// offset returns the end offset of the item at position items-1, zero for the
+// start of the table.
+func (t *freezerTable) offset(items uint64) (uint64, error) {
+	if items == 0 {
+		return 0, nil
+	}
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64((items-1)*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// Items returns the number of items in the table.
+func (t *freezerTable) Items() uint64 {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.items
+}
+
+// Append stores blob as the item at position item, which must be the next one.
+func (t *freezerTable) Append(item uint64, blob []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if item != t.items {
+		return fmt.Errorf("%s: %v (have %d, want %d)", t.name, errOutOrderInsertion, item, t.items)
+	}
+	// Write the data first, an index entry is only valid with its data
+	if _, err := t.data.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+	var buf [indexEntrySize]byte
+	binary.BigEndian.PutUint64(buf[:], t.size+uint64(len(blob)))
+	if _, err := t.index.WriteAt(buf[:], int64(t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.items, t.size = t.items+1, t.size+uint64(len(blob))
+	return nil
+}
+
+// Retrieve returns the item at position item.
+func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.index == nil {
+		return nil, errClosed
+	}
+	if item >= t.items {
+		return nil, errOutOfBounds
+	}
+	start, err := t.offset(item)
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.offset(item + 1)
+	if err != nil {
+		return nil, err
+	}
+	if start > end || end > t.size {
+		return nil, fmt.Errorf("%s: corrupted index entry %d", t.name, item)
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// truncate discards all items from position items on.
+func (t *freezerTable) truncate(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if items >= t.items {
+		return nil
+	}
+	size, err := t.offset(items)
+	if err != nil {
+		return err
+	}
+	if err := t.index.Truncate(int64(items * indexEntrySize)); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.items, t.size = items, size
+	return nil
+}
+
+// Sync flushes the table files to disk, data first.
+func (t *freezerTable) Sync() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil {
+		return errClosed
+	}
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the table files.
+func (t *freezerTable) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var errs []error
+	for _, f := range []*os.File{t.data, t.index} {
+		if f == nil {
+			continue
+		}
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	t.index, t.data = nil, nil
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// writeTestChain stores a canonical chain of n blocks, each with a transaction
+// and its receipt.
+func writeTestChain(db database.Database, n int) []*types.Block {
+	var (
+		blocks []*types.Block
+		parent common.Hash
+	)
+	for i := 0; i < n; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(int64(i)), 21000, big.NewInt(1), nil)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: tx.Hash(), GasUsed: 21000}
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Time: big.NewInt(int64(i))}
+		block := types.NewBlock(header, []*types.Transaction{tx}, []*types.Receipt{receipt})
+
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{receipt})
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		blocks, parent = append(blocks, block), block.Hash()
+	}
+	return blocks
+}
+
+// Tests that frozen blocks are served from the ancient store, survive a restart
+// and pass the integrity check.
+func TestFreezeBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := database.NewMemDatabase()
+	blocks := writeTestChain(kvdb, 10)
+
+	db, err := NewDatabaseWithFreezer(kvdb, dir)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	if moved, err := FreezeBlocks(db, 6); err != nil || moved != 6 {
+		t.Fatalf("freeze mismatch: moved %d, err %v", moved, err)
+	}
+	check := func(db database.Database) {
+		for _, block := range blocks {
+			hash, number := block.Hash(), block.NumberU64()
+			if have := ReadCanonicalHash(db, number); have != hash {
+				t.Errorf("block %d: canonical hash mismatch: have %x, want %x", number, have, hash)
+			}
+			if !HasHeader(db, hash, number) || !HasBody(db, hash, number) {
+				t.Errorf("block %d: header or body missing", number)
+			}
+			if have := ReadBlock(db, hash, number); have == nil || have.Hash() != hash || len(have.Transactions()) != 1 {
+				t.Errorf("block %d: block mismatch: %v", number, have)
+			}
+			if receipts := ReadReceipts(db, hash, number); len(receipts) != 1 || receipts[0].TxHash != block.Transactions()[0].Hash() {
+				t.Errorf("block %d: receipts mismatch: %v", number, receipts)
+			}
+			// Ancient data of another block with the same number must not leak
+			if ReadHeader(db, common.Hash{0xff}, number) != nil {
+				t.Errorf("block %d: header returned for unknown hash", number)
+			}
+		}
+	}
+	check(db)
+	if ReadCanonicalHash(kvdb, 5) != (common.Hash{}) || ReadHeader(kvdb, blocks[5].Hash(), 5) != nil {
+		t.Fatalf("frozen block left in key-value store")
+	}
+	if err := VerifyAncients(db); err != nil {
+		t.Fatalf("integrity check failed: %v", err)
+	}
+	// Reopen the freezer and append a partial item, which must be dropped
+	AncientStore(db).Close()
+	data, err := os.OpenFile(filepath.Join(dir, freezerBodiesTable+".rdat"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.Write([]byte{0xc0, 0x01})
+	data.Close()
+
+	if db, err = NewDatabaseWithFreezer(kvdb, dir); err != nil {
+		t.Fatalf("failed to reopen freezer: %v", err)
+	}
+	defer db.Close()
+	if frozen := AncientStore(db).Ancients(); frozen != 6 {
+		t.Fatalf("ancient count mismatch after reopen: have %d, want 6", frozen)
+	}
+	check(db)
+	if moved, err := FreezeBlocks(db, 10); err != nil || moved != 4 {
+		t.Fatalf("freeze mismatch after reopen: moved %d, err %v", moved, err)
+	}
+	check(db)
+	if err := VerifyAncients(db); err != nil {
+		t.Fatalf("integrity check failed after reopen: %v", err)
+	}
+}
+
+// Tests that the integrity check detects a broken ancient chain.
+func TestVerifyAncientsCorruption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := database.NewMemDatabase()
+	blocks := writeTestChain(kvdb, 4)
+
+	db, err := NewDatabaseWithFreezer(kvdb, dir)
+	if err != nil {
+		t.Fatalf("failed to open freezer: %v", err)
+	}
+	defer db.Close()
+
+	// Freeze the first two blocks, then append a body of a different block
+	if _, err := FreezeBlocks(db, 2); err != nil {
+		t.Fatalf("failed to freeze: %v", err)
+	}
+	hash := blocks[2].Hash()
+	header, receipts := ReadHeaderRLP(kvdb, hash, 2), readRaw(kvdb, blockReceiptsKey(2, hash))
+	if err := AncientStore(db).AppendAncient(2, hash, header, ReadBodyRLP(kvdb, blocks[3].Hash(), 3), receipts); err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+	DeleteBody(kvdb, hash, 2)
+	if err := VerifyAncients(db); err == nil {
+		t.Fatalf("corrupted body not detected")
+	}
+}
+
+func readRaw(db database.Database, key []byte) []byte {
+	data, _ := db.Get(key)
+	return data
+}
@@ -53,11 +53,20 @@ var (
 	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
-	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
-	configPrefix   = []byte("ethereum-config-") // config prefix for the db
+	addrTxPrefix        = []byte("a") // addrTxPrefix + address + section (uint64 big endian) -> address transaction entries
+	addrTxSectionPrefix = []byte("A") // addrTxSectionPrefix + section (uint64 big endian) -> addresses indexed in the section
+
+	logIndexPrefix        = []byte("g") // logIndexPrefix + address + topic0 + section (uint64 big endian) -> log positions
+	logIndexSectionPrefix = []byte("G") // logIndexSectionPrefix + section (uint64 big endian) -> log keys indexed in the section
+
+	preimagePrefix    = []byte("secure-key-")      // preimagePrefix + hash -> preimage
+	configPrefix      = []byte("ethereum-config-") // config prefix for the db
+	contractABIPrefix = []byte("contract-abi-")    // contractABIPrefix + address -> registered contract ABI
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
+	AddrTxIndexPrefix    = []byte("iA") // AddrTxIndexPrefix is the data table of the address index to track its progress
+	LogIndexPrefix       = []byte("iG") // LogIndexPrefix is the data table of the log index to track its progress
 
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
@@ -71,6 +80,33 @@ type TxLookupEntry struct {
 	Index      uint64
 }
 
+// Address transaction directions, combined as bit flags in AddrTxEntry.
+const (
+	AddrTxSent     uint8 = 1 << iota // The address sent the transaction
+	AddrTxReceived                   // The address is the recipient of the transaction
+	AddrTxCreated                    // The address is the contract created by the transaction
+)
+
+// AddrTxEntry locates a transaction involving an address.
+type AddrTxEntry struct {
+	BlockNumber uint64
+	TxIndex     uint64
+	TxHash      common.Hash
+	Direction   uint8
+}
+
+// LogIndexKey is the (address, first topic) pair logs are indexed by.
+type LogIndexKey struct {
+	Address common.Address
+	Topic   common.Hash
+}
+
+// LogIndexEntry locates a log, by its index within its block.
+type LogIndexEntry struct {
+	BlockNumber uint64
+	LogIndex    uint64
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -113,6 +149,26 @@ func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// addrTxKey = addrTxPrefix + address + section (uint64 big endian)
+func addrTxKey(addr common.Address, section uint64) []byte {
+	return append(append(addrTxPrefix, addr.Bytes()...), encodeBlockNumber(section)...)
+}
+
+// addrTxSectionKey = addrTxSectionPrefix + section (uint64 big endian)
+func addrTxSectionKey(section uint64) []byte {
+	return append(addrTxSectionPrefix, encodeBlockNumber(section)...)
+}
+
+// logIndexKey = logIndexPrefix + address + topic0 + section (uint64 big endian)
+func logIndexKey(key LogIndexKey, section uint64) []byte {
+	return append(append(append(logIndexPrefix, key.Address.Bytes()...), key.Topic.Bytes()...), encodeBlockNumber(section)...)
+}
+
+// logIndexSectionKey = logIndexSectionPrefix + section (uint64 big endian)
+func logIndexSectionKey(section uint64) []byte {
+	return append(logIndexSectionPrefix, encodeBlockNumber(section)...)
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -132,3 +188,8 @@ func preimageKey(hash common.Hash) []byte {
 func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
+
+// contractABIKey = contractABIPrefix + address
+func contractABIKey(addr common.Address) []byte {
+	return append(contractABIPrefix, addr.Bytes()...)
+}
@@ -18,6 +18,7 @@
 package state
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"sort"
@@ -260,6 +261,34 @@ func (self *StateDB) StorageTrie(addr common.Address) Trie {
 	return cpy.updateTrie(self.db)
 }
 
+// GetProof returns the merkle proof of the given account in the state trie,
+// from the root down.
+func (self *StateDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := self.trie.Prove(crypto.Keccak256(addr[:]), 0, &proof)
+	return proof, err
+}
+
+// GetStorageProof returns the merkle proof of the given storage slot in the
+// storage trie of the account, from the root down.
+func (self *StateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	tr := self.StorageTrie(addr)
+	if tr == nil {
+		return nil, errors.New("storage trie for requested address does not exist")
+	}
+	var proof proofList
+	err := tr.Prove(crypto.Keccak256(key[:]), 0, &proof)
+	return proof, err
+}
+
+// proofList collects the nodes of a merkle proof.
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
 func (self *StateDB) HasSuicided(addr common.Address) bool {
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
@@ -451,6 +480,31 @@ func (db *StateDB) ForEachStorage(addr common.Address, cb func(key, value common
 	}
 }
 
+// Touched returns the accounts modified since the state was created or last
+// committed, together with the storage slots accessed in each of them.
+func (self *StateDB) Touched() map[common.Address][]common.Hash {
+	touched := make(map[common.Address][]common.Hash)
+	collect := func(addr common.Address) {
+		if _, ok := touched[addr]; ok {
+			return
+		}
+		var keys []common.Hash
+		if object, exist := self.stateObjects[addr]; exist {
+			for key := range object.cachedStorage {
+				keys = append(keys, key)
+			}
+		}
+		touched[addr] = keys
+	}
+	for addr := range self.stateObjectsDirty {
+		collect(addr)
+	}
+	for addr := range self.journal.dirties {
+		collect(addr)
+	}
+	return touched
+}
+
 // Copy creates a deep, independent copy of the state.
 // Snapshots of the copied state cannot be applied to the copy.
 func (self *StateDB) Copy() *StateDB {
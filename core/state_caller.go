@@ -0,0 +1,58 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateCaller executes the calls of contract bindings on the state of a block,
+// whatever block the bindings ask for.
+type StateCaller struct {
+	chain  ChainContext
+	config *configs.ChainConfig
+	header *types.Header
+	state  *state.StateDB
+}
+
+// NewStateCaller returns a contract caller executing calls on the given state,
+// in the context of the given header.
+func NewStateCaller(chain ChainContext, config *configs.ChainConfig, header *types.Header, statedb *state.StateDB) *StateCaller {
+	return &StateCaller{chain: chain, config: config, header: header, state: statedb}
+}
+
+// CodeAt implements bind.ContractCaller.
+func (c *StateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.state.GetCode(contract), nil
+}
+
+// CallContract implements bind.ContractCaller.
+func (c *StateCaller) CallContract(ctx context.Context, call cpchain.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	msg := types.NewMessage(call.From, call.To, 0, new(big.Int), math.MaxUint64/2, new(big.Int), call.Data, false)
+	evm := vm.NewEVM(NewEVMContext(msg, c.header, c.chain, nil), c.state.Copy(), c.config, vm.Config{})
+	ret, _, _, err := ApplyMessage(evm, msg, new(GasPool).AddGas(math.MaxUint64))
+	return ret, err
+}
@@ -77,6 +77,8 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, sta
 
 		author = (*common.Address)(nil)
 	)
+	// Proxy lookups are cached across the transactions of the block
+	cfg.ProxyCache = vm.NewProxyCache()
 
 	beneficiary, err := p.bc.Engine().Author(header)
 	if err == nil {
@@ -150,6 +152,8 @@ func ApplyTransaction(config *configs.ChainConfig, bc ChainContext, author *comm
 	if msg.To() == nil {
 		pubReceipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
 	}
+	// if the recipient is a proxy contract, store the logic contract it was redirected to.
+	pubReceipt.LogicAddress = vmenv.LogicAddress()
 	// Set the pubReceipt logs and create a bloom for filtering
 	pubReceipt.Logs = pubStateDb.GetLogs(tx.Hash())
 	pubReceipt.Bloom = types.CreateBloom(types.Receipts{pubReceipt})
@@ -222,6 +226,7 @@ func tryApplyPrivateTx(config *configs.ChainConfig, bc ChainContext, author *com
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
 	}
+	receipt.LogicAddress = vmenv.LogicAddress()
 	// Set the receipt logs and create a bloom for filtering
 	receipt.Logs = privateStateDb.GetLogs(tx.Hash())
 	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
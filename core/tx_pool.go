@@ -79,6 +79,10 @@ var (
 
 	// ErrExceedQueueMapSize is returned if exceed txpool.queue map size
 	ErrExceedQueueMapSize = errors.New("exceeds queue map size")
+
+	// ErrAlreadyKnown is returned if the transaction is already contained
+	// within the pool.
+	ErrAlreadyKnown = errors.New("known transaction")
 )
 
 var (
@@ -129,6 +133,7 @@ type TxPoolConfig struct {
 	NoLocals  bool          // Whether local transaction handling should be disabled
 	Journal   string        // Journal of local transactions to survive node restarts
 	Rejournal time.Duration // Time interval to regenerate the local transaction journal
+	Schedule  string        // File of local transactions waiting for their scheduling conditions
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -149,6 +154,7 @@ type TxPoolConfig struct {
 var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
+	Schedule:  "scheduled.rlp",
 
 	PriceLimit: 1,
 	PriceBump:  10,
@@ -728,7 +734,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
 		log.Debug("Discarding already known transaction", "hash", hash.Hex())
-		return false, fmt.Errorf("known transaction: %x", hash)
+		return false, ErrAlreadyKnown
 	}
 	// If the transaction fails basic validation, discard it
 	if err := pool.validateTx(tx, local); err != nil {
@@ -0,0 +1,328 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// maxScheduledTxs is the maximum number of transactions the scheduler keeps
+// waiting for their conditions at the same time.
+const maxScheduledTxs = 4096
+
+var (
+	// ErrScheduledTxKnown is returned if a transaction is scheduled twice.
+	ErrScheduledTxKnown = errors.New("transaction already scheduled")
+
+	// ErrScheduleFull is returned if the scheduler has no room for another
+	// transaction.
+	ErrScheduleFull = errors.New("transaction schedule is full")
+)
+
+// TxCondition describes when a scheduled transaction becomes eligible to be
+// added to the transaction pool. All the non-zero fields must hold.
+type TxCondition struct {
+	MinBlock      uint64      // Minimum head block number
+	MinTimestamp  uint64      // Minimum head block timestamp, in seconds since the epoch
+	AfterTx       common.Hash // Transaction that must be included in the canonical chain first
+	Confirmations uint64      // Number of blocks (including its own) AfterTx must be buried under
+}
+
+// ScheduledTx is a signed transaction waiting for its condition.
+type ScheduledTx struct {
+	Tx        *types.Transaction
+	Condition TxCondition
+	Added     uint64 // Head block number when the transaction was scheduled
+}
+
+// scheduleChain is the subset of the blockchain the scheduler needs to evaluate
+// the conditions of its transactions.
+type scheduleChain interface {
+	CurrentBlock() *types.Block
+	SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription
+}
+
+// TxScheduler holds locally submitted, signed transactions until their
+// conditions are met and then injects them into the transaction pool. The
+// waiting transactions are persisted on disk so they survive node restarts.
+type TxScheduler struct {
+	path  string
+	chain scheduleChain
+	db    rawdb.DatabaseReader
+	add   func(tx *types.Transaction) error
+
+	mu  sync.Mutex
+	txs map[common.Hash]*ScheduledTx
+
+	chainHeadCh  chan ChainHeadEvent
+	chainHeadSub event.Subscription
+	wg           sync.WaitGroup
+}
+
+// NewTxScheduler creates a transaction scheduler persisted at path, loading any
+// previously scheduled transactions. Ready transactions are handed to add,
+// usually TxPool.AddLocal.
+func NewTxScheduler(path string, chain scheduleChain, db rawdb.DatabaseReader, add func(tx *types.Transaction) error) *TxScheduler {
+	s := &TxScheduler{
+		path:        path,
+		chain:       chain,
+		db:          db,
+		add:         add,
+		txs:         make(map[common.Hash]*ScheduledTx),
+		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
+	}
+	if err := s.load(); err != nil {
+		log.Warn("Failed to load scheduled transactions", "err", err)
+	}
+	s.chainHeadSub = chain.SubscribeChainHeadEvent(s.chainHeadCh)
+
+	s.wg.Add(1)
+	go s.loop()
+
+	return s
+}
+
+// loop re-evaluates the scheduled transactions on every new chain head.
+func (s *TxScheduler) loop() {
+	defer s.wg.Done()
+
+	s.process(s.chain.CurrentBlock().Header())
+	for {
+		select {
+		case ev := <-s.chainHeadCh:
+			if ev.Block != nil {
+				s.process(ev.Block.Header())
+			}
+		case <-s.chainHeadSub.Err():
+			return
+		}
+	}
+}
+
+// Stop terminates the scheduler. The waiting transactions stay on disk.
+func (s *TxScheduler) Stop() {
+	s.chainHeadSub.Unsubscribe()
+	s.wg.Wait()
+
+	log.Info("Transaction scheduler stopped")
+}
+
+// Schedule stores tx until cond holds. The transaction is persisted before
+// Schedule returns.
+func (s *TxScheduler) Schedule(tx *types.Transaction, cond TxCondition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := tx.Hash()
+	if _, ok := s.txs[hash]; ok {
+		return ErrScheduledTxKnown
+	}
+	if len(s.txs) >= maxScheduledTxs {
+		return ErrScheduleFull
+	}
+	s.txs[hash] = &ScheduledTx{
+		Tx:        tx,
+		Condition: cond,
+		Added:     s.chain.CurrentBlock().NumberU64(),
+	}
+	if err := s.store(); err != nil {
+		delete(s.txs, hash)
+		return err
+	}
+	log.Debug("Scheduled transaction", "hash", hash, "minBlock", cond.MinBlock, "minTimestamp", cond.MinTimestamp, "afterTx", cond.AfterTx)
+	return nil
+}
+
+// Cancel removes a waiting transaction, reporting whether it was found.
+func (s *TxScheduler) Cancel(hash common.Hash) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.txs[hash]; !ok {
+		return false
+	}
+	delete(s.txs, hash)
+	if err := s.store(); err != nil {
+		log.Warn("Failed to store scheduled transactions", "err", err)
+	}
+	return true
+}
+
+// Scheduled returns the waiting transactions, ordered by the block they were
+// scheduled at.
+func (s *TxScheduler) Scheduled() []*ScheduledTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sorted()
+}
+
+// sorted returns the waiting transactions in a stable order. The caller must
+// hold the lock.
+func (s *TxScheduler) sorted() []*ScheduledTx {
+	list := make([]*ScheduledTx, 0, len(s.txs))
+	for _, stx := range s.txs {
+		list = append(list, stx)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Added != list[j].Added {
+			return list[i].Added < list[j].Added
+		}
+		if list[i].Tx.Nonce() != list[j].Tx.Nonce() {
+			return list[i].Tx.Nonce() < list[j].Tx.Nonce()
+		}
+		return list[i].Tx.Hash().Big().Cmp(list[j].Tx.Hash().Big()) < 0
+	})
+	return list
+}
+
+// process injects every transaction whose condition holds at head.
+func (s *TxScheduler) process(head *types.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for _, stx := range s.sorted() {
+		if !s.ready(stx.Condition, head) {
+			continue
+		}
+		hash := stx.Tx.Hash()
+		switch err := s.add(stx.Tx); {
+		case err == ErrAlreadyKnown:
+			log.Debug("Scheduled transaction already pooled", "hash", hash)
+		case err != nil:
+			if !permanentPoolError(err) {
+				// Keep the transaction around and retry on the next head
+				log.Debug("Deferred scheduled transaction", "hash", hash, "err", err)
+				continue
+			}
+			log.Warn("Dropped scheduled transaction", "hash", hash, "err", err)
+		default:
+			log.Debug("Injected scheduled transaction", "hash", hash, "number", head.Number)
+		}
+		delete(s.txs, hash)
+		removed++
+	}
+	if removed > 0 {
+		if err := s.store(); err != nil {
+			log.Warn("Failed to store scheduled transactions", "err", err)
+		}
+	}
+}
+
+// permanentPoolError reports whether the pool rejected a transaction for a
+// reason that cannot go away by waiting. Anything else (pool full, underpriced,
+// insufficient funds, ...) may resolve itself on a later head.
+func permanentPoolError(err error) bool {
+	switch err {
+	case ErrInvalidSender, ErrNonceTooLow, ErrIntrinsicGas, ErrGasLimit, ErrNegativeValue,
+		ErrOversizedData, types.ErrNotSupportedTxType:
+		return true
+	}
+	return false
+}
+
+// ready checks whether cond holds on top of head.
+func (s *TxScheduler) ready(cond TxCondition, head *types.Header) bool {
+	number := head.Number.Uint64()
+	if number < cond.MinBlock {
+		return false
+	}
+	// header time has millisecond accuracy
+	if head.Time.Uint64()/1000 < cond.MinTimestamp {
+		return false
+	}
+	if cond.AfterTx != (common.Hash{}) {
+		blockHash, blockNumber, _ := rawdb.ReadTxLookupEntry(s.db, cond.AfterTx)
+		if blockHash == (common.Hash{}) || blockNumber > number {
+			return false
+		}
+		if rawdb.ReadCanonicalHash(s.db, blockNumber) != blockHash {
+			return false
+		}
+		confirmations := cond.Confirmations
+		if confirmations == 0 {
+			confirmations = 1
+		}
+		if number-blockNumber+1 < confirmations {
+			return false
+		}
+	}
+	return true
+}
+
+// load reads the scheduled transactions from disk.
+func (s *TxScheduler) load() error {
+	if s.path == "" {
+		return nil
+	}
+	input, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(input, 0)
+	for {
+		stx := new(ScheduledTx)
+		if err := stream.Decode(stx); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		s.txs[stx.Tx.Hash()] = stx
+	}
+	log.Info("Loaded scheduled transactions", "transactions", len(s.txs))
+	return nil
+}
+
+// store regenerates the schedule file from the waiting transactions. The
+// caller must hold the lock.
+func (s *TxScheduler) store() error {
+	if s.path == "" {
+		return nil
+	}
+	replacement, err := os.OpenFile(s.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, stx := range s.sorted() {
+		if err = rlp.Encode(replacement, stx); err != nil {
+			replacement.Close()
+			return err
+		}
+	}
+	if err = replacement.Close(); err != nil {
+		return err
+	}
+	return os.Rename(s.path+".new", s.path)
+}
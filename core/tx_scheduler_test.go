@@ -0,0 +1,250 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+type scheduleTestChain struct {
+	mu   sync.Mutex
+	head *types.Block
+	feed event.Feed
+}
+
+func (c *scheduleTestChain) CurrentBlock() *types.Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head
+}
+
+func (c *scheduleTestChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func (c *scheduleTestChain) setHead(number uint64, timestamp uint64) {
+	block := types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(number), Time: new(big.Int).SetUint64(timestamp * 1000)}, nil, nil)
+	c.mu.Lock()
+	c.head = block
+	c.mu.Unlock()
+	c.feed.Send(ChainHeadEvent{Block: block})
+}
+
+type scheduleTestSink struct {
+	mu  sync.Mutex
+	txs []*types.Transaction
+}
+
+func (s *scheduleTestSink) add(tx *types.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs = append(s.txs, tx)
+	return nil
+}
+
+func (s *scheduleTestSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.txs)
+}
+
+func scheduledTransaction(nonce uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+	return tx
+}
+
+// waitInjected waits until the sink received n transactions.
+func waitInjected(t *testing.T, sink *scheduleTestSink, n int) {
+	for i := 0; i < 100; i++ {
+		if sink.count() == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("injected transactions mismatch: have %d, want %d", sink.count(), n)
+}
+
+func TestTxSchedulerConditions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txscheduler")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		db     = database.NewMemDatabase()
+		chain  = new(scheduleTestChain)
+		sink   = new(scheduleTestSink)
+		key, _ = crypto.GenerateKey()
+	)
+	chain.head = types.NewBlock(&types.Header{Number: big.NewInt(1), Time: big.NewInt(0)}, nil, nil)
+	scheduler := NewTxScheduler(filepath.Join(dir, "scheduled.rlp"), chain, db, sink.add)
+
+	byBlock := scheduledTransaction(0, key)
+	byTime := scheduledTransaction(1, key)
+	byTx := scheduledTransaction(2, key)
+	cancelled := scheduledTransaction(3, key)
+
+	if err := scheduler.Schedule(byBlock, TxCondition{MinBlock: 3}); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if err := scheduler.Schedule(byBlock, TxCondition{MinBlock: 3}); err != ErrScheduledTxKnown {
+		t.Fatalf("duplicate schedule error mismatch: have %v, want %v", err, ErrScheduledTxKnown)
+	}
+	if err := scheduler.Schedule(byTime, TxCondition{MinTimestamp: 100}); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if err := scheduler.Schedule(byTx, TxCondition{AfterTx: byBlock.Hash(), Confirmations: 2}); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if err := scheduler.Schedule(cancelled, TxCondition{MinBlock: 1000}); err != nil {
+		t.Fatalf("failed to schedule transaction: %v", err)
+	}
+	if !scheduler.Cancel(cancelled.Hash()) {
+		t.Fatalf("failed to cancel scheduled transaction")
+	}
+	if scheduler.Cancel(cancelled.Hash()) {
+		t.Fatalf("cancelled a transaction twice")
+	}
+	if have := len(scheduler.Scheduled()); have != 3 {
+		t.Fatalf("scheduled transactions mismatch: have %d, want %d", have, 3)
+	}
+	scheduler.Stop()
+
+	// Restart the scheduler to ensure the schedule is persisted
+	scheduler = NewTxScheduler(filepath.Join(dir, "scheduled.rlp"), chain, db, sink.add)
+	defer scheduler.Stop()
+
+	if have := len(scheduler.Scheduled()); have != 3 {
+		t.Fatalf("loaded transactions mismatch: have %d, want %d", have, 3)
+	}
+	chain.setHead(2, 10)
+	time.Sleep(50 * time.Millisecond)
+	if have := sink.count(); have != 0 {
+		t.Fatalf("premature injection: have %d transactions", have)
+	}
+	chain.setHead(3, 20)
+	waitInjected(t, sink, 1)
+
+	// Include the first transaction at block 3, it needs to be confirmed twice
+	block := types.NewBlock(&types.Header{Number: big.NewInt(3)}, []*types.Transaction{byBlock}, nil)
+	rawdb.WriteCanonicalHash(db, block.Hash(), 3)
+	rawdb.WriteTxLookupEntries(db, block)
+
+	chain.setHead(3, 30)
+	time.Sleep(50 * time.Millisecond)
+	if have := sink.count(); have != 1 {
+		t.Fatalf("premature injection: have %d transactions", have)
+	}
+	chain.setHead(4, 40)
+	waitInjected(t, sink, 2)
+
+	chain.setHead(5, 100)
+	waitInjected(t, sink, 3)
+
+	if have := len(scheduler.Scheduled()); have != 0 {
+		t.Fatalf("scheduled transactions mismatch: have %d, want %d", have, 0)
+	}
+	if sink.txs[0].Hash() != byBlock.Hash() || sink.txs[1].Hash() != byTx.Hash() || sink.txs[2].Hash() != byTime.Hash() {
+		t.Fatalf("injection order mismatch")
+	}
+	// Ensure the injected transactions are not reloaded
+	reloaded := NewTxScheduler(filepath.Join(dir, "scheduled.rlp"), chain, db, func(*types.Transaction) error { return nil })
+	defer reloaded.Stop()
+	if have := len(reloaded.Scheduled()); have != 0 {
+		t.Fatalf("reloaded transactions mismatch: have %d, want %d", have, 0)
+	}
+}
+
+func TestTxSchedulerRetry(t *testing.T) {
+	var (
+		db     = database.NewMemDatabase()
+		chain  = new(scheduleTestChain)
+		key, _ = crypto.GenerateKey()
+
+		mu       sync.Mutex
+		attempts = make(map[common.Hash]int)
+		errs     = make(map[common.Hash]error)
+	)
+	add := func(tx *types.Transaction) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[tx.Hash()]++
+		return errs[tx.Hash()]
+	}
+	chain.head = types.NewBlock(&types.Header{Number: big.NewInt(1), Time: big.NewInt(0)}, nil, nil)
+	scheduler := NewTxScheduler("", chain, db, add)
+	defer scheduler.Stop()
+
+	temporary := scheduledTransaction(0, key)
+	permanent := scheduledTransaction(1, key)
+	oversized := scheduledTransaction(2, key)
+	known := scheduledTransaction(3, key)
+
+	mu.Lock()
+	errs[temporary.Hash()] = ErrUnderpriced
+	errs[permanent.Hash()] = ErrNonceTooLow
+	errs[oversized.Hash()] = ErrGasLimit
+	errs[known.Hash()] = ErrAlreadyKnown
+	mu.Unlock()
+
+	for _, tx := range []*types.Transaction{temporary, permanent, oversized, known} {
+		if err := scheduler.Schedule(tx, TxCondition{MinBlock: 2}); err != nil {
+			t.Fatalf("failed to schedule transaction: %v", err)
+		}
+	}
+	chain.setHead(2, 10)
+	time.Sleep(50 * time.Millisecond)
+
+	scheduled := scheduler.Scheduled()
+	if len(scheduled) != 1 || scheduled[0].Tx.Hash() != temporary.Hash() {
+		t.Fatalf("scheduled transactions mismatch: have %d, want only the temporarily rejected one", len(scheduled))
+	}
+	// Accept the transaction on the next head
+	mu.Lock()
+	delete(errs, temporary.Hash())
+	mu.Unlock()
+
+	chain.setHead(3, 20)
+	time.Sleep(50 * time.Millisecond)
+
+	if have := len(scheduler.Scheduled()); have != 0 {
+		t.Fatalf("scheduled transactions mismatch: have %d, want %d", have, 0)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts[temporary.Hash()] < 2 || attempts[permanent.Hash()] != 1 {
+		t.Fatalf("injection attempts mismatch: temporary %d, permanent %d", attempts[temporary.Hash()], attempts[permanent.Hash()])
+	}
+	if attempts[oversized.Hash()] != 1 || attempts[known.Hash()] != 1 {
+		t.Fatalf("injection attempts mismatch: oversized %d, known %d", attempts[oversized.Hash()], attempts[known.Hash()])
+	}
+}
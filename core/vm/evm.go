@@ -43,8 +43,7 @@ type (
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		primitives := PrimitiveContracts
-		if p := primitives[*contract.CodeAddr]; p != nil {
+		if p := evm.primitives[*contract.CodeAddr]; p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -95,6 +94,13 @@ type EVM struct {
 	chainConfig *configs.ChainConfig
 	// chain rules contains the chain rules for the current epoch
 	chainRules configs.Rules
+	// primitives contains the primitive contracts callable in the current block
+	primitives map[common.Address]PrimitiveContract
+	// proxyCache caches the logic contracts of proxy contracts in the current block
+	proxyCache *ProxyCache
+	// logicAddress is the logic contract the recipient of the transaction was
+	// redirected to, if it is a proxy contract
+	logicAddress common.Address
 	// virtual machine configuration options used to initialise the
 	// evm.
 	vmConfig Config
@@ -119,12 +125,23 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *configs.ChainConfig, vmCo
 		vmConfig:    vmConfig,
 		chainConfig: chainConfig,
 		chainRules:  chainConfig.Rules(ctx.BlockNumber),
+		primitives:  ActivePrimitives(chainConfig, ctx.BlockNumber),
+		proxyCache:  vmConfig.ProxyCache,
+	}
+	if evm.proxyCache == nil {
+		evm.proxyCache = NewProxyCache()
 	}
 
 	evm.interpreter = NewInterpreter(evm, vmConfig)
 	return evm
 }
 
+// IsPrimitive returns whether a primitive contract is callable at the given
+// address in the current block.
+func (evm *EVM) IsPrimitive(addr common.Address) bool {
+	return evm.primitives[addr] != nil
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -136,10 +153,8 @@ func (evm *EVM) Cancel() {
 // the necessary steps to create accounts and reverses the state in case of an
 // execution error or failed value transfer.
 func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas uint64, value *big.Int) (ret []byte, leftOverGas uint64, err error) {
-	// query addr from statedb,check if some proxy flag exist.
-	// if is proxy contract ,get real contract address,replace addr with real contract address
-	realAddress := GetRealContractAddress(evm, caller, addr, gas)
-	return evm.call(caller, realAddress, input, gas, value)
+	// Calls of proxy contracts are redirected to their logic contracts
+	return evm.call(caller, evm.resolveCall(addr), input, gas, value)
 }
 
 // Call executes the contract associated with the addr with the given input as
@@ -160,12 +175,17 @@ func (evm *EVM) call(caller ContractRef, addr common.Address, input []byte, gas
 		return nil, gas, ErrInsufficientBalance
 	}
 
+	// Calls of the register may change the logic contracts of proxies
+	if register, ok := evm.proxyRegister(); ok && addr == register {
+		evm.proxyCache.invalidate()
+	}
+
 	var (
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		if PrimitiveContracts[addr] == nil && value.Sign() == 0 {
+		if evm.primitives[addr] == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do antything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -207,8 +227,7 @@ func (evm *EVM) call(caller ContractRef, addr common.Address, input []byte, gas
 }
 
 func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte, gas uint64, value *big.Int) (ret []byte, leftOverGas uint64, err error) {
-	realAddress := GetRealContractAddress(evm, caller, addr, gas)
-	return evm.callCode(caller, realAddress, input, gas, value)
+	return evm.callCode(caller, evm.resolveCall(addr), input, gas, value)
 }
 
 // CallCode executes the contract associated with the addr with the given input
@@ -253,8 +272,7 @@ func (evm *EVM) callCode(caller ContractRef, addr common.Address, input []byte,
 }
 
 func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []byte, gas uint64) (ret []byte, leftOverGas uint64, err error) {
-	realAddress := GetRealContractAddress(evm, caller, addr, gas)
-	return evm.delegateCall(caller, realAddress, input, gas)
+	return evm.delegateCall(caller, evm.resolveCall(addr), input, gas)
 }
 
 // DelegateCall executes the contract associated with the addr with the given input
@@ -3,69 +3,95 @@
 package vm
 
 import (
-	"fmt"
-	"time"
-
-	"bitbucket.org/cpchain/chain/commons/cache"
-	"bitbucket.org/cpchain/chain/commons/log"
-	"bitbucket.org/cpchain/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-var (
-	forceLookupTTL      = 5 * time.Second
-	emptyAddress        = common.Address{}
-	contractLookupCache = cache.NewLRUExpireCache(200)
-)
+// registerLogicSlot is the storage slot of the contractAddresses mapping of
+// proxyContractRegister.sol, which maps proxy contracts to their logic contracts.
+var registerLogicSlot = common.BigToHash(common.Big1)
 
-// GetRealContractAddress get real logic contract address by proxy contract address
-func GetRealContractAddress(evm *EVM, caller ContractRef, proxyContractAddress common.Address, gas uint64) common.Address {
-	if !configs.EnableProxyContract {
-		return proxyContractAddress
-	}
+// ProxyCache caches the logic contracts resolved for proxy contracts while
+// processing a block. Once the register is called in the block, the cache stops
+// serving lookups, so every lookup reflects the current state.
+//
+// A ProxyCache must only be shared by the transactions of a single block.
+type ProxyCache struct {
+	logic   map[common.Address]common.Address
+	invalid bool
+}
 
-	realAddress := proxyContractAddress
+// NewProxyCache returns an empty cache for the proxy lookups of a block.
+func NewProxyCache() *ProxyCache {
+	return &ProxyCache{logic: make(map[common.Address]common.Address)}
+}
 
-	if dc := evm.chainConfig.Dpor; dc != nil {
-		if proxyRegister := dc.ProxyContractRegister; proxyRegister != emptyAddress {
-			// lookup in cache
-			realContractFromCache, gotIt := contractLookupCache.Get(proxyContractAddress)
-			if gotIt {
-				log.Debug("get real address from cache for", "proxyContractAddress", proxyContractAddress.Hex())
-				return realContractFromCache.(common.Address)
-			}
+// invalidate stops the cache from serving lookups for the rest of the block.
+func (c *ProxyCache) invalidate() {
+	c.invalid = true
+	c.logic = nil
+}
+
+// proxyRegister returns the proxy contract register if proxy redirection is
+// active in the current block.
+func (evm *EVM) proxyRegister() (common.Address, bool) {
+	if !evm.chainRules.IsProxyContract || evm.chainConfig.Dpor == nil {
+		return common.Address{}, false
+	}
+	register := evm.chainConfig.Dpor.ProxyContractRegister
+	return register, register != (common.Address{})
+}
 
-			// setup param from #getContractInput(methodSignature,proxyAddress)
-			paramBytes := getContractInput(proxyContractAddress)
-			if ret, _, err := evm.StaticCall(caller, proxyRegister, paramBytes, gas); err == nil {
-				// get real contract address parse from ret
-				address := common.BytesToAddress(ret)
-				if address != emptyAddress {
-					log.Debug("GetRealContractAddress ", "hex(address)", common.Bytes2Hex(ret), "address", address.Hex())
-				}
+// ResolveProxy returns the logic contract registered for addr, or addr itself
+// if it is not a proxy contract or proxy redirection is not active. The
+// register's storage is read at the current state.
+func (evm *EVM) ResolveProxy(addr common.Address) common.Address {
+	register, ok := evm.proxyRegister()
+	if !ok || addr == register {
+		return addr
+	}
+	cache := evm.proxyCache
+	if !cache.invalid {
+		if logic, ok := cache.logic[addr]; ok {
+			return logic
+		}
+	}
+	logic := addr
+	if registered := common.BytesToAddress(evm.StateDB.GetState(register, proxyLogicKey(addr)).Bytes()); registered != (common.Address{}) {
+		logic = registered
+	}
+	if !cache.invalid {
+		cache.logic[addr] = logic
+	}
+	return logic
+}
 
-				if address != emptyAddress {
-					log.Debug("parseAddress ok", "realAddress", realAddress.Hex())
-					realAddress = address
-					contractLookupCache.Add(proxyContractAddress, realAddress, forceLookupTTL)
-				}
-			} else {
-				log.Warn("GetRealContractAddress", "err", err)
-			}
+// resolveCall resolves the contract called at addr, reporting redirections to
+// the tracer and the first one of the transaction as its logic contract.
+func (evm *EVM) resolveCall(addr common.Address) common.Address {
+	logic := evm.ResolveProxy(addr)
+	if logic == addr {
+		return addr
+	}
+	if evm.depth == 0 {
+		evm.logicAddress = logic
+	}
+	if evm.vmConfig.Debug {
+		if tracer, ok := evm.vmConfig.Tracer.(ProxyTracer); ok {
+			tracer.CaptureProxy(addr, logic, evm.depth+1)
 		}
 	}
+	return logic
+}
 
-	log.Debug("GetRealContractAddress", "realAddress", realAddress.Hex())
-	return realAddress
+// LogicAddress returns the logic contract the recipient of the transaction was
+// redirected to, or the zero address if it was not a proxy contract.
+func (evm *EVM) LogicAddress() common.Address {
+	return evm.logicAddress
 }
 
-// invoke contract method in proxyContractRegister.sol#getRealContract
-func getContractInput(proxyContract common.Address) []byte {
-	// the name is in line with the one in the contract
-	methodSignBytes := []byte("getRealContract(address)")
-	bytes := crypto.Keccak256(methodSignBytes)
-	methodSignature := fmt.Sprintf("%v", common.Bytes2Hex(bytes)[0:8])
-	addrBytes := fmt.Sprintf("%064v\n", common.Bytes2Hex(proxyContract.Bytes()))
-	return common.Hex2Bytes(methodSignature + addrBytes)
+// proxyLogicKey returns the storage key of the logic contract of proxy in the
+// contractAddresses mapping of the register.
+func proxyLogicKey(proxy common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(proxy.Bytes(), common.HashLength), registerLogicSlot.Bytes())
 }
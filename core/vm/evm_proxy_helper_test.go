@@ -3,17 +3,73 @@
 package vm
 
 import (
+	"math/big"
 	"testing"
 
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func TestGetContractInput(t *testing.T) {
-	addr := common.HexToAddress("0x7900dd1d71fc5c57ba56e4b768de3c2264253335")
-	expected := "8099b6810000000000000000000000007900dd1d71fc5c57ba56e4b768de3c2264253335"
-	b1 := getContractInput(addr)
-	bh := common.Bytes2Hex(b1)
-	if bh != expected {
-		t.Errorf("result error expected:%v,got:%v", expected, bh)
+func TestResolveProxy(t *testing.T) {
+	var (
+		register = common.BytesToAddress([]byte{200})
+		proxy    = common.BytesToAddress([]byte{201})
+		logic    = common.BytesToAddress([]byte{202})
+		upgraded = common.BytesToAddress([]byte{203})
+		caller   = AccountRef(common.BytesToAddress([]byte{204}))
+		config   = &configs.ChainConfig{
+			ChainID:            big.NewInt(1),
+			ProxyContractBlock: big.NewInt(5),
+			Dpor:               &configs.DporConfig{ProxyContractRegister: register},
+		}
+		ctx = Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		}
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+	statedb.SetCode(register, []byte{byte(STOP)})
+	statedb.SetState(register, proxyLogicKey(proxy), logic.Hash())
+
+	// Calls are not redirected before the fork
+	ctx.BlockNumber = big.NewInt(4)
+	if have := NewEVM(ctx, statedb, config, Config{}).ResolveProxy(proxy); have != proxy {
+		t.Fatalf("proxy redirected before the fork: %x", have)
+	}
+
+	// Lookups are cached across the EVMs of a block
+	ctx.BlockNumber = big.NewInt(5)
+	cache := NewProxyCache()
+	evm := NewEVM(ctx, statedb, config, Config{ProxyCache: cache})
+	if have := evm.ResolveProxy(proxy); have != logic {
+		t.Fatalf("resolved logic mismatch: have %x, want %x", have, logic)
+	}
+	if have := evm.ResolveProxy(logic); have != logic {
+		t.Fatalf("unregistered contract redirected: %x", have)
+	}
+	statedb.SetState(register, proxyLogicKey(proxy), upgraded.Hash())
+	evm = NewEVM(ctx, statedb, config, Config{ProxyCache: cache})
+	if have := evm.ResolveProxy(proxy); have != logic {
+		t.Fatalf("cached logic mismatch: have %x, want %x", have, logic)
+	}
+
+	// Calling the register stops the cache, and calls of the proxy report the
+	// logic contract to the receipt and the tracer
+	if _, _, err := evm.Call(caller, register, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("failed to call register: %v", err)
+	}
+	tracer := NewStructLogger(nil)
+	evm = NewEVM(ctx, statedb, config, Config{ProxyCache: cache, Debug: true, Tracer: tracer})
+	if _, _, err := evm.Call(caller, proxy, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("failed to call proxy: %v", err)
+	}
+	if have := evm.LogicAddress(); have != upgraded {
+		t.Fatalf("logic address mismatch: have %x, want %x", have, upgraded)
+	}
+	redirects := tracer.ProxyRedirects()
+	if len(redirects) != 1 || redirects[0] != (ProxyRedirect{Proxy: proxy, Logic: upgraded, Depth: 1}) {
+		t.Fatalf("traced redirects mismatch: %v", redirects)
 	}
 }
@@ -35,6 +35,9 @@ type Config struct {
 	NoRecursion bool
 	// Enable recording of SHA3/keccak preimages
 	EnablePreimageRecording bool
+	// ProxyCache caches proxy lookups across the transactions of a block,
+	// each EVM uses its own cache if nil
+	ProxyCache *ProxyCache
 	// JumpTable contains the EVM instruction table. This
 	// may be left uninitialised and will be set to the default
 	// table.
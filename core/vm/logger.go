@@ -103,6 +103,20 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
 }
 
+// ProxyTracer is implemented by tracers interested in the calls of proxy
+// contracts redirected to their logic contracts. The depth is that of the
+// redirected call, as reported to CaptureState.
+type ProxyTracer interface {
+	CaptureProxy(proxy common.Address, logic common.Address, depth int)
+}
+
+// ProxyRedirect is a call of a proxy contract redirected to its logic contract.
+type ProxyRedirect struct {
+	Proxy common.Address `json:"proxy"`
+	Logic common.Address `json:"logic"`
+	Depth int            `json:"depth"`
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
@@ -112,6 +126,7 @@ type StructLogger struct {
 	cfg LogConfig
 
 	logs          []StructLog
+	proxies       []ProxyRedirect
 	changedValues map[common.Address]Storage
 	output        []byte
 	err           error
@@ -189,6 +204,12 @@ func (l *StructLogger) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost ui
 	return nil
 }
 
+// CaptureProxy implements the ProxyTracer interface to record a redirected
+// call of a proxy contract.
+func (l *StructLogger) CaptureProxy(proxy common.Address, logic common.Address, depth int) {
+	l.proxies = append(l.proxies, ProxyRedirect{proxy, logic, depth})
+}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
 	l.output = output
@@ -205,6 +226,9 @@ func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration
 // StructLogs returns the captured log entries.
 func (l *StructLogger) StructLogs() []StructLog { return l.logs }
 
+// ProxyRedirects returns the redirected calls of proxy contracts captured.
+func (l *StructLogger) ProxyRedirects() []ProxyRedirect { return l.proxies }
+
 // Error returns the VM error captured by the trace.
 func (l *StructLogger) Error() error { return l.err }
 
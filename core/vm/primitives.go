@@ -0,0 +1,125 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	primitivesLock sync.RWMutex
+	primitiveImpls = make(map[string]PrimitiveContract) // Implementations chain configs can enable, by name
+)
+
+// RegisterPrimitive makes a primitive contract implementation available under
+// the given name, for chain configs to enable it at an address from a block on.
+func RegisterPrimitive(name string, contract PrimitiveContract) error {
+	primitivesLock.Lock()
+	defer primitivesLock.Unlock()
+
+	if primitiveImpls[name] != nil {
+		return ErrPrimitiveContractExists
+	}
+	primitiveImpls[name] = contract
+	return nil
+}
+
+// CheckPrimitives returns an error if the chain config enables a primitive
+// contract whose implementation is not registered, as the node would then
+// execute calls to it differently than the rest of the network. It warns about
+// the default primitive contracts a config declaring its own ones drops.
+func CheckPrimitives(config *configs.ChainConfig) error {
+	primitivesLock.RLock()
+	defer primitivesLock.RUnlock()
+
+	primitives := config.Primitives
+	if primitives == nil {
+		primitives = configs.DefaultPrimitives
+	}
+	for _, primitive := range primitives {
+		if primitiveImpls[primitive.Name] == nil {
+			return fmt.Errorf("primitive contract %q at %x is not registered", primitive.Name, primitive.Address)
+		}
+	}
+	for _, primitive := range droppedPrimitives(config) {
+		log.Warn("Chain config drops a default primitive contract", "name", primitive.Name, "address", primitive.Address)
+	}
+	return nil
+}
+
+// droppedPrimitives returns the default primitive contracts the chain config
+// does not enable at their default address.
+func droppedPrimitives(config *configs.ChainConfig) []configs.PrimitiveConfig {
+	if config.Primitives == nil {
+		return nil
+	}
+	var dropped []configs.PrimitiveConfig
+	for _, def := range configs.DefaultPrimitives {
+		enabled := false
+		for _, primitive := range config.Primitives {
+			if primitive.Name == def.Name && primitive.Address == def.Address {
+				enabled = true
+				break
+			}
+		}
+		if !enabled {
+			dropped = append(dropped, def)
+		}
+	}
+	return dropped
+}
+
+// ActivePrimitives returns the primitive contracts callable in the given block:
+// the built-in ones, those registered at fixed addresses and those the chain
+// config enables by then. Enabled contracts without a registered
+// implementation are left out.
+func ActivePrimitives(config *configs.ChainConfig, number *big.Int) map[common.Address]PrimitiveContract {
+	primitivesLock.RLock()
+	defer primitivesLock.RUnlock()
+
+	active := make(map[common.Address]PrimitiveContract, len(PrimitiveContracts))
+	for addr, contract := range PrimitiveContracts {
+		active[addr] = contract
+	}
+	for _, primitive := range config.ActivePrimitives(number) {
+		contract := primitiveImpls[primitive.Name]
+		if contract == nil {
+			continue
+		}
+		if primitive.Gas != 0 {
+			contract = &pricedPrimitive{PrimitiveContract: contract, gas: primitive.Gas}
+		}
+		active[primitive.Address] = contract
+	}
+	return active
+}
+
+// pricedPrimitive charges the flat gas cost configured for a primitive contract.
+type pricedPrimitive struct {
+	PrimitiveContract
+	gas uint64
+}
+
+func (p *pricedPrimitive) RequiredGas(input []byte) uint64 {
+	return p.gas
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestActivePrimitives(t *testing.T) {
+	if err := RegisterPrimitive("testIdentity", &dataCopy{}); err != nil {
+		t.Fatalf("failed to register primitive: %v", err)
+	}
+	if err := RegisterPrimitive("testIdentity", &dataCopy{}); err != ErrPrimitiveContractExists {
+		t.Fatalf("duplicate registration error mismatch: have %v, want %v", err, ErrPrimitiveContractExists)
+	}
+	var (
+		early  = common.BytesToAddress([]byte{110})
+		forked = common.BytesToAddress([]byte{111})
+		config = &configs.ChainConfig{
+			ChainID: big.NewInt(1),
+			Primitives: []configs.PrimitiveConfig{
+				{Name: "testIdentity", Address: early},
+				{Name: "testIdentity", Address: forked, Block: big.NewInt(5), Gas: 1000},
+			},
+		}
+	)
+	if err := CheckPrimitives(config); err != nil {
+		t.Fatalf("registered primitives rejected: %v", err)
+	}
+	// Declared primitives replace the default ones
+	if dropped := droppedPrimitives(config); len(dropped) != len(configs.DefaultPrimitives) {
+		t.Fatalf("dropped default primitives mismatch: have %d, want %d", len(dropped), len(configs.DefaultPrimitives))
+	}
+	if dropped := droppedPrimitives(&configs.ChainConfig{Primitives: append(config.Primitives, configs.DefaultPrimitives...)}); len(dropped) != 0 {
+		t.Fatalf("kept default primitives reported dropped: %v", dropped)
+	}
+	if dropped := droppedPrimitives(&configs.ChainConfig{}); len(dropped) != 0 {
+		t.Fatalf("default primitives reported dropped: %v", dropped)
+	}
+	// The forked primitive is enabled from its activation block on
+	evm := NewEVM(Context{BlockNumber: big.NewInt(4)}, nil, config, Config{})
+	if !evm.IsPrimitive(early) || evm.IsPrimitive(forked) {
+		t.Fatalf("primitives mismatch before the fork")
+	}
+	if !evm.IsPrimitive(common.BytesToAddress([]byte{1})) {
+		t.Fatalf("built-in primitive missing")
+	}
+	active := ActivePrimitives(config, big.NewInt(5))
+	if active[forked] == nil {
+		t.Fatalf("forked primitive missing at its activation block")
+	}
+	if gas := active[forked].RequiredGas(make([]byte, 64)); gas != 1000 {
+		t.Fatalf("configured gas mismatch: have %d, want 1000", gas)
+	}
+	if have, want := active[early].RequiredGas(make([]byte, 64)), (&dataCopy{}).RequiredGas(make([]byte, 64)); have != want {
+		t.Fatalf("own gas mismatch: have %d, want %d", have, want)
+	}
+	// Primitives without implementation are refused
+	config.Primitives = append(config.Primitives, configs.PrimitiveConfig{Name: "testMissing", Address: common.BytesToAddress([]byte{112})})
+	if err := CheckPrimitives(config); err == nil {
+		t.Fatalf("unregistered primitive accepted")
+	}
+	if active := ActivePrimitives(config, big.NewInt(5)); active[common.BytesToAddress([]byte{112})] != nil {
+		t.Fatalf("unregistered primitive enabled")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 The cpchain authors
+
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The persistent key-value store backends.
+const (
+	LevelDBBackend = "leveldb" // LevelDB, the default backend
+	LogDBBackend   = "logdb"   // Log-structured merge tree
+)
+
+// Backends lists the persistent backends.
+var Backends = []string{LevelDBBackend, LogDBBackend}
+
+// DetectBackend returns the backend of the database in dir, an empty string if
+// there is none.
+func DetectBackend(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "CURRENT")); err == nil {
+		return LevelDBBackend
+	}
+	if _, err := os.Stat(filepath.Join(dir, logDataFile)); err == nil {
+		return LogDBBackend
+	}
+	return ""
+}
+
+// Open opens the database in dir with the backend, creating it if needed. An
+// empty backend selects the one of the existing database, or the default one.
+// Opening a database created by another backend fails.
+func Open(backend string, dir string, cache int, handles int) (Database, error) {
+	existing := DetectBackend(dir)
+	if backend == "" {
+		backend = existing
+	}
+	if backend == "" {
+		backend = LevelDBBackend
+	}
+	if existing != "" && existing != backend {
+		return nil, fmt.Errorf("database %s uses the %s backend, not %s", dir, existing, backend)
+	}
+	switch backend {
+	case LevelDBBackend:
+		return NewLDBDatabase(dir, cache, handles)
+	case LogDBBackend:
+		return NewLogDatabase(dir)
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+}
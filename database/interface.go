@@ -27,6 +27,35 @@ type Database interface {
 	NewBatch() Batch
 }
 
+// Iterator iterates over the key-value pairs of a database in ascending key
+// order. It must be released after use.
+type Iterator interface {
+	// Next moves to the next pair, returning whether there is one.
+	Next() bool
+	// Error returns any accumulated error.
+	Error() error
+	// Key returns the key of the current pair. The caller must not modify it.
+	Key() []byte
+	// Value returns the value of the current pair. The caller must not modify it.
+	Value() []byte
+	// Release releases the resources of the iterator.
+	Release()
+}
+
+// Iteratee wraps the iteration over the content of a database.
+type Iteratee interface {
+	// NewPrefixIterator returns an iterator over the pairs whose key starts
+	// with prefix.
+	NewPrefixIterator(prefix []byte) Iterator
+}
+
+// Compacter wraps the compaction of a database.
+type Compacter interface {
+	// Compact flattens the storage of the keys in the range [start, limit), nil
+	// meaning unbounded. Backends may compact more than the range.
+	Compact(start []byte, limit []byte) error
+}
+
 // Batch is a write-only database that commits changes to its host database
 // when Write is called. Batch cannot be used concurrently.
 type Batch interface {
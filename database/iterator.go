@@ -0,0 +1,78 @@
+// Copyright 2018 The cpchain authors
+
+package database
+
+import (
+	"sort"
+	"strings"
+)
+
+// snapshotIterator iterates over the keys present when it was created, in
+// ascending order. Values are loaded on the fly, keys deleted in the meantime
+// being skipped.
+type snapshotIterator struct {
+	keys  []string
+	load  func(key string) ([]byte, bool, error)
+	next  int
+	key   []byte
+	value []byte
+	err   error
+}
+
+// newSnapshotIterator sorts the keys and returns an iterator over them using
+// load to retrieve their values.
+func newSnapshotIterator(keys []string, load func(key string) ([]byte, bool, error)) *snapshotIterator {
+	sort.Strings(keys)
+	return &snapshotIterator{keys: keys, load: load}
+}
+
+// prefixedKeys filters the keys starting with prefix, reusing the slice.
+func prefixedKeys(keys []string, prefix []byte) []string {
+	matched := keys[:0]
+	for _, key := range keys {
+		if strings.HasPrefix(key, string(prefix)) {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+// Next implements Iterator.
+func (it *snapshotIterator) Next() bool {
+	for it.err == nil && it.next < len(it.keys) {
+		key := it.keys[it.next]
+		it.next++
+
+		value, ok, err := it.load(key)
+		if err != nil {
+			it.err = err
+			break
+		}
+		if ok {
+			it.key, it.value = []byte(key), value
+			return true
+		}
+	}
+	it.key, it.value = nil, nil
+	return false
+}
+
+// Error implements Iterator.
+func (it *snapshotIterator) Error() error {
+	return it.err
+}
+
+// Key implements Iterator.
+func (it *snapshotIterator) Key() []byte {
+	return it.key
+}
+
+// Value implements Iterator.
+func (it *snapshotIterator) Value() []byte {
+	return it.value
+}
+
+// Release implements Iterator.
+func (it *snapshotIterator) Release() {
+	it.keys, it.key, it.value = nil, nil, nil
+}
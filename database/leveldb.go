@@ -89,6 +89,16 @@ func (db *LDBDatabase) NewIteratorWithPrefix(prefix []byte) iterator.Iterator {
 	return db.db.NewIterator(util.BytesPrefix(prefix), nil)
 }
 
+// NewPrefixIterator implements Iteratee.
+func (db *LDBDatabase) NewPrefixIterator(prefix []byte) Iterator {
+	return db.NewIteratorWithPrefix(prefix)
+}
+
+// Compact flattens the underlying storage of the key range.
+func (db *LDBDatabase) Compact(start []byte, limit []byte) error {
+	return db.db.CompactRange(util.Range{Start: start, Limit: limit})
+}
+
 func (db *LDBDatabase) Close() {
 	err := db.db.Close()
 	if err == nil {
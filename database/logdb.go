@@ -0,0 +1,1078 @@
+// Copyright 2018 The cpchain authors
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+)
+
+const (
+	// logDataFile is the name of the write-ahead log of the memtable within
+	// the database directory.
+	logDataFile = "logdb.data"
+
+	// logFrozenFile is the name of the write-ahead log of the memtable being
+	// flushed.
+	logFrozenFile = "logdb.frozen"
+
+	// logManifestFile is the name of the file listing the tables of every level.
+	logManifestFile = "MANIFEST.logdb"
+
+	// logFrameHeaderSize is the size of a frame header, a crc32 checksum and
+	// the length of the frame payload.
+	logFrameHeaderSize = 8
+
+	// logMemTableSize is the amount of data the memtable collects before it
+	// is flushed into a level 0 table.
+	logMemTableSize = 4 * 1024 * 1024
+
+	// logTableSize is the size compactions split their output tables at.
+	logTableSize = 2 * 1024 * 1024
+
+	// logLevels is the number of levels of the tree.
+	logLevels = 7
+
+	// logL0CompactTrigger is the number of level 0 tables starting a compaction
+	// into level 1, logL0StopTrigger the number stalling the writes until then.
+	logL0CompactTrigger = 4
+	logL0StopTrigger    = 12
+
+	// logL1Size is the size of level 1 starting a compaction, every further
+	// level being logLevelFactor times larger.
+	logL1Size      = 10 * 1024 * 1024
+	logLevelFactor = 10
+)
+
+// Operations recorded in the log frames and tables.
+const (
+	logOpPut byte = iota
+	logOpDelete
+)
+
+var (
+	errLogDBClosed     = errors.New("database closed")
+	errLogFrameCorrupt = errors.New("corrupted log frame")
+)
+
+// logManifest is the persisted list of the tables of every level.
+type logManifest struct {
+	Next   uint64     `json:"next"`   // Number of the next table
+	Levels [][]uint64 `json:"levels"` // Table numbers, newest first in level 0 and by key in the others
+}
+
+// memTable collects the recent writes in memory until they are flushed.
+type memTable struct {
+	entries map[string]logWrite
+	size    int
+}
+
+func newMemTable() *memTable {
+	return &memTable{entries: make(map[string]logWrite)}
+}
+
+// apply records the operations, copying their data.
+func (m *memTable) apply(writes []logWrite) {
+	for _, w := range writes {
+		entry := logWrite{key: append([]byte{}, w.key...), delete: w.delete}
+		if !w.delete {
+			entry.value = append([]byte{}, w.value...)
+		}
+		m.entries[string(w.key)] = entry
+		m.size += len(w.key) + len(w.value) + 1
+	}
+}
+
+// sorted returns the entries with keys in [start, limit) in key order, nil
+// meaning unbounded.
+func (m *memTable) sorted(start, limit []byte) []logWrite {
+	entries := make([]logWrite, 0, len(m.entries))
+	for _, entry := range m.entries {
+		if start != nil && bytes.Compare(entry.key, start) < 0 {
+			continue
+		}
+		if limit != nil && bytes.Compare(entry.key, limit) >= 0 {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].key, entries[j].key) < 0 })
+	return entries
+}
+
+// LogDatabase is a persistent key-value store organised as a log-structured
+// merge tree. Writes are appended to a write-ahead log and collected in a
+// memtable, which is flushed into a sorted table of level 0 once full. A
+// background compaction merges the tables of a level into the next, larger
+// one, dropping overwritten and deleted values on the way. Every level but
+// the first holds non-overlapping tables, so a lookup reads at most one table
+// per level, skipping most with the bloom filter of the table.
+//
+// Memory is bounded by the memtables and the block index and filter of every
+// table, and a compaction only locks the database to install its result.
+type LogDatabase struct {
+	path    string
+	wal     *os.File // Write-ahead log of the memtable
+	walSize int64
+	mem     *memTable        // Memtable taking the writes
+	imm     *memTable        // Memtable being flushed, nil if none
+	levels  [][]*sortedTable // Tables of every level, see logManifest for the order
+	next    uint64           // Number of the next table
+	pointer [][]byte         // Last key compacted in every level, to rotate through the keys
+	bgErr   error            // Failure of the background work, failing the writes
+	closed  bool
+
+	lock    sync.RWMutex
+	cond    *sync.Cond // Signalled with the write lock when background work is done
+	compact sync.Mutex // Serialises the flushes and compactions
+	wake    chan struct{}
+	quit    chan struct{}
+	wg      sync.WaitGroup
+
+	log *log.Logger
+}
+
+// NewLogDatabase opens the log database in the directory, creating it if
+// needed.
+func NewLogDatabase(dir string) (*LogDatabase, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	db := &LogDatabase{
+		path:    dir,
+		levels:  make([][]*sortedTable, logLevels),
+		pointer: make([][]byte, logLevels),
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+		log:     log.Root(),
+	}
+	db.cond = sync.NewCond(&db.lock)
+	if err := db.open(); err != nil {
+		db.closeTables()
+		return nil, err
+	}
+	db.wg.Add(1)
+	go db.loop()
+	db.schedule()
+
+	return db, nil
+}
+
+// open loads the tables of the manifest and replays the write-ahead logs into
+// the memtables, removing the files of interrupted compactions.
+func (db *LogDatabase) open() error {
+	manifest := new(logManifest)
+	blob, err := ioutil.ReadFile(filepath.Join(db.path, logManifestFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(blob, manifest); err != nil {
+			return fmt.Errorf("invalid manifest: %v", err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+	db.next = manifest.Next
+	live := make(map[uint64]bool)
+	for level, nums := range manifest.Levels {
+		if level >= logLevels {
+			return fmt.Errorf("manifest level %d out of range", level)
+		}
+		for _, num := range nums {
+			t, err := openTable(db.path, num)
+			if err != nil {
+				return err
+			}
+			db.levels[level] = append(db.levels[level], t)
+			live[num] = true
+		}
+	}
+	files, err := ioutil.ReadDir(db.path)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".tbl") {
+			continue
+		}
+		if num, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), ".tbl"), 10, 64); err == nil && !live[num] {
+			os.Remove(filepath.Join(db.path, file.Name()))
+		}
+	}
+	// Replay the memtable being flushed when the database was closed, if any
+	if _, err := os.Stat(filepath.Join(db.path, logFrozenFile)); err == nil {
+		frozen, err := os.OpenFile(filepath.Join(db.path, logFrozenFile), os.O_RDWR, 0644)
+		if err != nil {
+			return err
+		}
+		db.imm = newMemTable()
+		_, err = db.replay(frozen, db.imm)
+		frozen.Close()
+		if err != nil {
+			return err
+		}
+	}
+	wal, err := os.OpenFile(filepath.Join(db.path, logDataFile), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	db.mem = newMemTable()
+	if db.walSize, err = db.replay(wal, db.mem); err != nil {
+		wal.Close()
+		return err
+	}
+	db.wal = wal
+	return nil
+}
+
+// replay applies the frames of a write-ahead log to the memtable, dropping
+// the frames after the first incomplete or corrupted one. It returns the
+// length of the valid log.
+func (db *LogDatabase) replay(file *os.File, mem *memTable) (int64, error) {
+	var (
+		reader = bufio.NewReaderSize(io.NewSectionReader(file, 0, 1<<62), 1024*1024)
+		size   int64
+	)
+	for {
+		payload, err := readLogFrame(reader)
+		if err == io.EOF {
+			return size, nil
+		}
+		var writes []logWrite
+		if err == nil {
+			writes, err = decodeLogFrame(payload)
+		}
+		if err != nil {
+			db.log.Warn("Dropping damaged tail of database log", "path", file.Name(), "offset", size, "err", err)
+			return size, file.Truncate(size)
+		}
+		mem.apply(writes)
+		size += logFrameHeaderSize + int64(len(payload))
+	}
+}
+
+// readLogFrame reads the payload of the next frame, io.EOF at the end of the log.
+func readLogFrame(r io.Reader) ([]byte, error) {
+	var header [logFrameHeaderSize]byte
+	if n, err := io.ReadFull(r, header[:]); err != nil {
+		if n == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, errLogFrameCorrupt
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[4:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, errLogFrameCorrupt
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[:4]) {
+		return nil, errLogFrameCorrupt
+	}
+	return payload, nil
+}
+
+// decodeLogFrame decodes the operations of a frame payload.
+func decodeLogFrame(payload []byte) ([]logWrite, error) {
+	var writes []logWrite
+	for len(payload) > 0 {
+		write, rest, err := decodeTableEntry(payload)
+		if err != nil {
+			return nil, errLogFrameCorrupt
+		}
+		writes, payload = append(writes, write), rest
+	}
+	return writes, nil
+}
+
+// logWrite is a single operation of a frame, or an entry of a table.
+type logWrite struct {
+	key, value []byte
+	delete     bool
+}
+
+// encodeLogFrame encodes operations as a frame.
+func encodeLogFrame(writes []logWrite) []byte {
+	frame := make([]byte, logFrameHeaderSize, logFrameHeaderSize+len(writes)*64)
+	for _, w := range writes {
+		if w.delete {
+			frame = append(frame, logOpDelete)
+			frame = appendTableBytes(frame, w.key)
+		} else {
+			frame = append(frame, logOpPut)
+			frame = appendTableBytes(frame, w.key)
+			frame = appendTableBytes(frame, w.value)
+		}
+	}
+	payload := frame[logFrameHeaderSize:]
+	binary.BigEndian.PutUint32(frame[:4], crc32.ChecksumIEEE(payload))
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(payload)))
+	return frame
+}
+
+// write appends the operations to the write-ahead log as a single frame and
+// applies them to the memtable.
+func (db *LogDatabase) write(writes []logWrite) error {
+	frame := encodeLogFrame(writes)
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.makeRoom(); err != nil {
+		return err
+	}
+	if _, err := db.wal.WriteAt(frame, db.walSize); err != nil {
+		// Drop whatever part of the frame made it to the file
+		db.wal.Truncate(db.walSize)
+		return err
+	}
+	db.walSize += int64(len(frame))
+	db.mem.apply(writes)
+	return nil
+}
+
+// makeRoom freezes a full memtable for the background flush, stalling while
+// the previous one is still being flushed or level 0 holds too many tables.
+// The caller must hold the write lock.
+func (db *LogDatabase) makeRoom() error {
+	for {
+		if db.closed {
+			return errLogDBClosed
+		}
+		if db.bgErr != nil {
+			return db.bgErr
+		}
+		switch {
+		case db.mem.size < logMemTableSize:
+			return nil
+		case db.imm != nil || len(db.levels[0]) >= logL0StopTrigger:
+			db.schedule()
+			db.cond.Wait()
+		default:
+			return db.rotate()
+		}
+	}
+}
+
+// rotate freezes the memtable and starts a new write-ahead log. The caller
+// must hold the write lock and make sure no memtable is being flushed.
+func (db *LogDatabase) rotate() error {
+	if err := db.wal.Sync(); err != nil {
+		return err
+	}
+	db.wal.Close()
+	if err := os.Rename(filepath.Join(db.path, logDataFile), filepath.Join(db.path, logFrozenFile)); err != nil {
+		return err
+	}
+	wal, err := os.OpenFile(filepath.Join(db.path, logDataFile), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	db.wal, db.walSize = wal, 0
+	db.imm, db.mem = db.mem, newMemTable()
+	db.schedule()
+	return nil
+}
+
+// schedule wakes the background flushes and compactions up.
+func (db *LogDatabase) schedule() {
+	select {
+	case db.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop runs the flushes and compactions in the background.
+func (db *LogDatabase) loop() {
+	defer db.wg.Done()
+
+	for {
+		select {
+		case <-db.wake:
+			db.compact.Lock()
+			var err error
+			for {
+				// Flush between the compactions, writes may be waiting for it
+				if err = db.flush(); err != nil {
+					break
+				}
+				var more bool
+				if more, err = db.compactOnce(); err != nil || !more {
+					break
+				}
+				select {
+				case <-db.quit:
+					db.compact.Unlock()
+					return
+				default:
+				}
+			}
+			db.compact.Unlock()
+
+			if err != nil {
+				db.log.Error("Database compaction failed", "path", db.path, "err", err)
+				db.lock.Lock()
+				db.bgErr = err
+				db.cond.Broadcast()
+				db.lock.Unlock()
+			}
+		case <-db.quit:
+			return
+		}
+	}
+}
+
+// flush writes the frozen memtable into level 0 tables, if there is one. The
+// caller must hold the compaction lock.
+func (db *LogDatabase) flush() error {
+	db.lock.RLock()
+	imm := db.imm
+	db.lock.RUnlock()
+
+	if imm == nil {
+		return nil
+	}
+	tables, err := db.writeTables(&sliceIterator{entries: imm.sorted(nil, nil)}, false)
+	if err != nil {
+		return err
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	// Level 0 lists the newest tables first
+	for _, t := range tables {
+		db.levels[0] = append([]*sortedTable{t}, db.levels[0]...)
+	}
+	if err := db.storeManifest(); err != nil {
+		return err
+	}
+	db.imm = nil
+	os.Remove(filepath.Join(db.path, logFrozenFile))
+	db.cond.Broadcast()
+	return nil
+}
+
+// writeTables writes the entries of the iterator into new tables of about
+// logTableSize each, dropping the deletions if dropDeleted is set.
+func (db *LogDatabase) writeTables(it entryIterator, dropDeleted bool) ([]*sortedTable, error) {
+	var (
+		tables []*sortedTable
+		writer *tableWriter
+		num    uint64
+	)
+	fail := func(err error) ([]*sortedTable, error) {
+		if writer != nil {
+			writer.abort()
+		}
+		for _, t := range tables {
+			t.close()
+			os.Remove(filepath.Join(db.path, tableName(t.num)))
+		}
+		return nil, err
+	}
+	finish := func() error {
+		if err := writer.finish(); err != nil {
+			return err
+		}
+		writer = nil
+		t, err := openTable(db.path, num)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, t)
+		return nil
+	}
+	for it.next() {
+		entry := it.entry()
+		if entry.delete && dropDeleted {
+			continue
+		}
+		if writer == nil {
+			db.lock.Lock()
+			num = db.next
+			db.next++
+			db.lock.Unlock()
+
+			var err error
+			if writer, err = newTableWriter(db.path, num); err != nil {
+				return fail(err)
+			}
+		}
+		if err := writer.add(entry); err != nil {
+			return fail(err)
+		}
+		if writer.size() >= logTableSize {
+			if err := finish(); err != nil {
+				return fail(err)
+			}
+		}
+	}
+	if err := it.err(); err != nil {
+		return fail(err)
+	}
+	if writer != nil {
+		if err := finish(); err != nil {
+			return fail(err)
+		}
+	}
+	return tables, nil
+}
+
+// levelSize returns the size of the tables of a level.
+func levelSize(tables []*sortedTable) int64 {
+	var size int64
+	for _, t := range tables {
+		size += t.size
+	}
+	return size
+}
+
+// levelLimit returns the size of a level starting a compaction.
+func levelLimit(level int) int64 {
+	limit := int64(logL1Size)
+	for i := 1; i < level; i++ {
+		limit *= logLevelFactor
+	}
+	return limit
+}
+
+// compactOnce runs the most urgent compaction, if any is due, reporting
+// whether one ran. The caller must hold the compaction lock.
+func (db *LogDatabase) compactOnce() (bool, error) {
+	db.lock.RLock()
+	var (
+		best  = -1
+		score = 1.0
+	)
+	if s := float64(len(db.levels[0])) / logL0CompactTrigger; s >= score {
+		best, score = 0, s
+	}
+	for level := 1; level < logLevels-1; level++ {
+		if s := float64(levelSize(db.levels[level])) / float64(levelLimit(level)); s >= score {
+			best, score = level, s
+		}
+	}
+	var inputs []*sortedTable
+	switch {
+	case best == 0:
+		inputs = append(inputs, db.levels[0]...)
+	case best > 0:
+		// Rotate through the keys of the level, one table at a time
+		tables := db.levels[best]
+		inputs = []*sortedTable{tables[0]}
+		for _, t := range tables {
+			if db.pointer[best] == nil || bytes.Compare(t.first, db.pointer[best]) > 0 {
+				inputs = []*sortedTable{t}
+				break
+			}
+		}
+	}
+	db.lock.RUnlock()
+
+	if best < 0 {
+		return false, nil
+	}
+	if best > 0 {
+		db.pointer[best] = inputs[0].last
+	}
+	return true, db.compactTables(best, inputs)
+}
+
+// overlapping returns the tables of a level holding keys in [start, limit].
+// The caller must hold the lock.
+func (db *LogDatabase) overlapping(level int, start, limit []byte) []*sortedTable {
+	var tables []*sortedTable
+	for _, t := range db.levels[level] {
+		if t.overlaps(start, limit) {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// keyRange returns the smallest and the largest key of the tables.
+func keyRange(tables []*sortedTable) (first, last []byte) {
+	for _, t := range tables {
+		if first == nil || bytes.Compare(t.first, first) < 0 {
+			first = t.first
+		}
+		if last == nil || bytes.Compare(t.last, last) > 0 {
+			last = t.last
+		}
+	}
+	return first, last
+}
+
+// compactTables merges the input tables of a level with the overlapping ones
+// of the next level into new tables of the next level. Only the compaction
+// changes the tables, so they are read without the lock. The caller must hold
+// the compaction lock.
+func (db *LogDatabase) compactTables(level int, inputs []*sortedTable) error {
+	first, last := keyRange(inputs)
+
+	db.lock.RLock()
+	overlaps := db.overlapping(level+1, first, last)
+
+	// The overlapping tables are rewritten too, so their deletions may hide
+	// older values outside the keys of the inputs
+	first, last = keyRange(append(append([]*sortedTable{}, inputs...), overlaps...))
+	bottom := true
+	for deeper := level + 2; deeper < logLevels; deeper++ {
+		if len(db.overlapping(deeper, first, last)) > 0 {
+			bottom = false
+		}
+	}
+	db.lock.RUnlock()
+
+	// A table not overlapping the next level simply moves down
+	if level > 0 && len(overlaps) == 0 {
+		return db.install(level, inputs, nil, inputs)
+	}
+	var iters []entryIterator
+	if level == 0 {
+		for _, t := range inputs {
+			iters = append(iters, newTableIterator(t, nil))
+		}
+	} else {
+		iters = append(iters, &levelIterator{tables: inputs})
+	}
+	iters = append(iters, &levelIterator{tables: overlaps})
+
+	// Deletions are only needed while older values may hide below
+	outputs, err := db.writeTables(newMergeIterator(iters), bottom)
+	if err != nil {
+		return err
+	}
+	db.log.Debug("Compacted database tables", "path", db.path, "level", level, "inputs", len(inputs)+len(overlaps), "outputs", len(outputs))
+	return db.install(level, inputs, overlaps, outputs)
+}
+
+// install replaces the input tables of a level and the overlapping tables of
+// the next level with the outputs, releasing the replaced tables.
+func (db *LogDatabase) install(level int, inputs, overlaps, outputs []*sortedTable) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	removed := make(map[*sortedTable]bool)
+	for _, t := range inputs {
+		removed[t] = true
+	}
+	for _, t := range overlaps {
+		removed[t] = true
+	}
+	keep := func(tables []*sortedTable) []*sortedTable {
+		var kept []*sortedTable
+		for _, t := range tables {
+			if !removed[t] {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	}
+	db.levels[level] = keep(db.levels[level])
+	next := append(keep(db.levels[level+1]), outputs...)
+	sort.Slice(next, func(i, j int) bool { return bytes.Compare(next[i].first, next[j].first) < 0 })
+	db.levels[level+1] = next
+
+	if err := db.storeManifest(); err != nil {
+		return err
+	}
+	moved := make(map[*sortedTable]bool)
+	for _, t := range outputs {
+		moved[t] = true
+	}
+	for t := range removed {
+		if !moved[t] {
+			t.obsolete = true
+			db.unref(t)
+		}
+	}
+	db.cond.Broadcast()
+	return nil
+}
+
+// unref releases a reference to a table, deleting it once unused and
+// compacted away. The caller must hold the write lock.
+func (db *LogDatabase) unref(t *sortedTable) {
+	if t.refs--; t.refs == 0 && t.obsolete {
+		t.close()
+		os.Remove(filepath.Join(db.path, tableName(t.num)))
+	}
+}
+
+// storeManifest persists the tables of every level. The caller must hold the
+// write lock.
+func (db *LogDatabase) storeManifest() error {
+	manifest := logManifest{Next: db.next, Levels: make([][]uint64, logLevels)}
+	for level, tables := range db.levels {
+		manifest.Levels[level] = []uint64{}
+		for _, t := range tables {
+			manifest.Levels[level] = append(manifest.Levels[level], t.num)
+		}
+	}
+	blob, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(db.path, logManifestFile)
+	tmp, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(blob); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Path returns the path to the database directory.
+func (db *LogDatabase) Path() string {
+	return db.path
+}
+
+// Put stores the value of the key.
+func (db *LogDatabase) Put(key []byte, value []byte) error {
+	return db.write([]logWrite{{key: key, value: value}})
+}
+
+// Delete removes the key.
+func (db *LogDatabase) Delete(key []byte) error {
+	return db.write([]logWrite{{key: key, delete: true}})
+}
+
+// Has returns whether the key is present.
+func (db *LogDatabase) Has(key []byte) (bool, error) {
+	_, ok, err := db.get(key)
+	return ok, err
+}
+
+// Get returns the value of the key.
+func (db *LogDatabase) Get(key []byte) ([]byte, error) {
+	value, ok, err := db.get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// get looks the key up from the newest data to the oldest. The read lock is
+// held throughout, so that no table is deleted meanwhile.
+func (db *LogDatabase) get(key []byte) ([]byte, bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, false, errLogDBClosed
+	}
+	for _, mem := range []*memTable{db.mem, db.imm} {
+		if mem == nil {
+			continue
+		}
+		if entry, ok := mem.entries[string(key)]; ok {
+			return append([]byte{}, entry.value...), !entry.delete, nil
+		}
+	}
+	for level, tables := range db.levels {
+		if level > 0 {
+			// Only the table covering the key may hold it
+			i := sort.Search(len(tables), func(i int) bool {
+				return bytes.Compare(tables[i].last, key) >= 0
+			})
+			if i == len(tables) {
+				continue
+			}
+			tables = tables[i : i+1]
+		}
+		for _, t := range tables {
+			value, deleted, found, err := t.get(key)
+			if err != nil {
+				return nil, false, err
+			}
+			if found {
+				return value, !deleted, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// NewPrefixIterator returns an iterator over the keys with the prefix, as
+// they were at the time of the call.
+func (db *LogDatabase) NewPrefixIterator(prefix []byte) Iterator {
+	start, limit := prefix, prefixLimit(prefix)
+	if len(prefix) == 0 {
+		start = nil
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return &logIterator{fail: errLogDBClosed}
+	}
+	var (
+		iters  []entryIterator
+		tables []*sortedTable
+	)
+	for _, mem := range []*memTable{db.mem, db.imm} {
+		if mem != nil {
+			iters = append(iters, &sliceIterator{entries: mem.sorted(start, limit)})
+		}
+	}
+	// The upper bound of overlapping is inclusive, the limit itself is cut
+	// off by the iterator
+	for level := range db.levels {
+		overlaps := db.overlapping(level, start, limit)
+		for _, t := range overlaps {
+			t.refs++
+			tables = append(tables, t)
+		}
+		if level == 0 {
+			for _, t := range overlaps {
+				iters = append(iters, newTableIterator(t, start))
+			}
+		} else if len(overlaps) > 0 {
+			iters = append(iters, &levelIterator{tables: overlaps, start: start})
+		}
+	}
+	return &logIterator{db: db, iter: newMergeIterator(iters), limit: limit, tables: tables}
+}
+
+// prefixLimit returns the smallest key larger than all the keys with the
+// prefix, nil if there is none.
+func prefixLimit(prefix []byte) []byte {
+	limit := append([]byte{}, prefix...)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] < 0xff {
+			limit[i]++
+			return limit[:i+1]
+		}
+	}
+	return nil
+}
+
+// logIterator iterates over the live values of a database snapshot, holding
+// its tables until released.
+type logIterator struct {
+	db     *LogDatabase
+	iter   *mergeIterator
+	limit  []byte
+	tables []*sortedTable
+	key    []byte
+	value  []byte
+	fail   error
+}
+
+// Next implements Iterator.
+func (it *logIterator) Next() bool {
+	for it.iter != nil && it.iter.next() {
+		entry := it.iter.entry()
+		if it.limit != nil && bytes.Compare(entry.key, it.limit) >= 0 {
+			break
+		}
+		if !entry.delete {
+			it.key, it.value = entry.key, entry.value
+			return true
+		}
+	}
+	if it.iter != nil && it.fail == nil {
+		it.fail = it.iter.err()
+	}
+	it.key, it.value = nil, nil
+	return false
+}
+
+// Error implements Iterator.
+func (it *logIterator) Error() error {
+	return it.fail
+}
+
+// Key implements Iterator.
+func (it *logIterator) Key() []byte {
+	return it.key
+}
+
+// Value implements Iterator.
+func (it *logIterator) Value() []byte {
+	return it.value
+}
+
+// Release implements Iterator.
+func (it *logIterator) Release() {
+	if it.db != nil {
+		it.db.lock.Lock()
+		for _, t := range it.tables {
+			it.db.unref(t)
+		}
+		it.db.lock.Unlock()
+	}
+	it.db, it.iter, it.tables, it.key, it.value = nil, nil, nil, nil, nil
+}
+
+// Compact flushes the memtable and merges the tables holding keys in
+// [start, limit) down the levels until they all sit in a single level.
+// Writes only wait for the installation of every merge step.
+func (db *LogDatabase) Compact(start []byte, limit []byte) error {
+	db.lock.Lock()
+	for db.imm != nil && db.bgErr == nil && !db.closed {
+		db.schedule()
+		db.cond.Wait()
+	}
+	var err error
+	switch {
+	case db.closed:
+		err = errLogDBClosed
+	case db.bgErr != nil:
+		err = db.bgErr
+	case len(db.mem.entries) > 0:
+		err = db.rotate()
+	}
+	db.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	db.compact.Lock()
+	defer db.compact.Unlock()
+
+	if err := db.flush(); err != nil {
+		return err
+	}
+	for level := 0; level < logLevels-1; level++ {
+		db.lock.RLock()
+		inputs := db.overlapping(level, start, limit)
+		if level == 0 && len(inputs) > 0 {
+			// Older level 0 tables may hide below the overlapping ones
+			inputs = append([]*sortedTable{}, db.levels[0]...)
+		}
+		deeper := false
+		for below := level + 1; below < logLevels; below++ {
+			deeper = deeper || len(db.levels[below]) > 0
+		}
+		db.lock.RUnlock()
+
+		if len(inputs) == 0 {
+			continue
+		}
+		if !deeper && level > 0 {
+			break
+		}
+		if err := db.compactTables(level, inputs); err != nil {
+			return err
+		}
+	}
+	db.log.Info("Compacted database", "path", db.path)
+	return nil
+}
+
+// Stats returns the number and size of the tables of every level.
+func (db *LogDatabase) Stats() string {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	stats := " Level | Tables | Size(MB)\n-------+--------+---------\n"
+	for level, tables := range db.levels {
+		if len(tables) > 0 {
+			stats += fmt.Sprintf(" %5d | %6d | %8.3f\n", level, len(tables), float64(levelSize(tables))/1024/1024)
+		}
+	}
+	return stats
+}
+
+// Close stops the background work, syncs the write-ahead log and closes the
+// files. The memtables are replayed from their logs when reopening.
+func (db *LogDatabase) Close() {
+	db.lock.Lock()
+	if db.closed {
+		db.lock.Unlock()
+		return
+	}
+	db.closed = true
+	db.cond.Broadcast()
+	db.lock.Unlock()
+
+	close(db.quit)
+	db.wg.Wait()
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	err := db.wal.Sync()
+	if closeErr := db.wal.Close(); err == nil {
+		err = closeErr
+	}
+	db.closeTables()
+	if err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
+
+// closeTables closes the files of all tables.
+func (db *LogDatabase) closeTables() {
+	for _, tables := range db.levels {
+		for _, t := range tables {
+			t.close()
+		}
+	}
+}
+
+// NewBatch returns a batch written to the log as a single frame.
+func (db *LogDatabase) NewBatch() Batch {
+	return &logBatch{db: db}
+}
+
+type logBatch struct {
+	db     *LogDatabase
+	writes []logWrite
+	size   int
+}
+
+func (b *logBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, logWrite{key: append([]byte{}, key...), value: append([]byte{}, value...)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *logBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, logWrite{key: append([]byte{}, key...), delete: true})
+	b.size++
+	return nil
+}
+
+func (b *logBatch) Write() error {
+	if len(b.writes) == 0 {
+		return nil
+	}
+	return b.db.write(b.writes)
+}
+
+func (b *logBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *logBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
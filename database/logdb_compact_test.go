@@ -0,0 +1,77 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package database
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// Tests that the deletions of the next level tables rewritten by a compaction
+// are kept while older values of their keys sit deeper, even outside the keys
+// of the compacted tables.
+func TestLogDBCompactOverlapDeletion(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "cpcdb_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewLogDatabase(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.compact.Lock()
+	table := func(writes ...logWrite) *sortedTable {
+		tables, err := db.writeTables(&sliceIterator{entries: writes}, false)
+		if err != nil || len(tables) != 1 {
+			t.Fatalf("failed to write table: %v", err)
+		}
+		return tables[0]
+	}
+	// "k" is deleted in level 1, whose table spans beyond the level 0 one,
+	// over an older value in level 2
+	var (
+		level0 = table(logWrite{key: []byte("a"), value: []byte("new")})
+		level1 = table(
+			logWrite{key: []byte("a"), value: []byte("old")},
+			logWrite{key: []byte("k"), delete: true},
+			logWrite{key: []byte("z"), value: []byte("old")},
+		)
+		level2 = table(logWrite{key: []byte("k"), value: []byte("old")})
+	)
+	db.lock.Lock()
+	db.levels[0] = []*sortedTable{level0}
+	db.levels[1] = []*sortedTable{level1}
+	db.levels[2] = []*sortedTable{level2}
+	db.lock.Unlock()
+
+	err = db.compactTables(0, []*sortedTable{level0})
+	db.compact.Unlock()
+	if err != nil {
+		t.Fatalf("compaction failed: %v", err)
+	}
+	if value, err := db.Get([]byte("k")); err != ErrKeyNotFound {
+		t.Fatalf("deleted key revived: %q (%v)", value, err)
+	}
+	if value, err := db.Get([]byte("a")); err != nil || string(value) != "new" {
+		t.Fatalf("value mismatch: %q (%v)", value, err)
+	}
+}
@@ -0,0 +1,594 @@
+// Copyright 2018 The cpchain authors
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// tableBlockSize is the payload size the entries of a table are grouped
+	// in. The index of a table holds one key per block.
+	tableBlockSize = 4 * 1024
+
+	// tableBloomBits is the number of bloom filter bits per key of a table.
+	tableBloomBits = 10
+
+	// tableFooterSize is the size of the footer closing a table: the offset
+	// and size of the index and of the filter, and the magic number.
+	tableFooterSize = 40
+
+	// tableMagic marks the end of a complete table.
+	tableMagic = 0x6370636c73737462 // "cpclsstb"
+)
+
+var errTableCorrupt = errors.New("corrupted table")
+
+// tableName returns the file name of the table with the number.
+func tableName(num uint64) string {
+	return fmt.Sprintf("%06d.tbl", num)
+}
+
+// tableBlock locates a block of a table by its first key.
+type tableBlock struct {
+	first  []byte
+	offset int64
+	size   uint32 // Payload size, without the trailing checksum
+}
+
+// sortedTable is an immutable sorted file of entries, tombstones included.
+// Tables are written once by a flush or a compaction and deleted once no
+// version of the database nor iterator references them any more.
+//
+// A table is a sequence of checksummed blocks of entries, followed by the
+// index of the blocks, a bloom filter of the keys and a fixed size footer.
+// Only the index and the filter are held in memory.
+type sortedTable struct {
+	num         uint64
+	file        *os.File
+	size        int64
+	first, last []byte
+	blocks      []tableBlock
+	filter      []byte
+
+	refs     int  // Versions and iterators using the table, guarded by the database lock
+	obsolete bool // Whether the table was compacted away
+}
+
+// openTable opens the table with the number in dir, loading its index.
+func openTable(dir string, num uint64) (*sortedTable, error) {
+	file, err := os.Open(filepath.Join(dir, tableName(num)))
+	if err != nil {
+		return nil, err
+	}
+	t := &sortedTable{num: num, file: file, refs: 1}
+	if err := t.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("table %d: %v", num, err)
+	}
+	return t, nil
+}
+
+// load reads the footer, the index and the filter of the table.
+func (t *sortedTable) load() error {
+	stat, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+	t.size = stat.Size()
+	if t.size < tableFooterSize {
+		return errTableCorrupt
+	}
+	var footer [tableFooterSize]byte
+	if _, err := t.file.ReadAt(footer[:], t.size-tableFooterSize); err != nil {
+		return err
+	}
+	if binary.BigEndian.Uint64(footer[32:]) != tableMagic {
+		return errTableCorrupt
+	}
+	var (
+		indexOffset  = int64(binary.BigEndian.Uint64(footer[0:]))
+		indexSize    = int64(binary.BigEndian.Uint64(footer[8:]))
+		filterOffset = int64(binary.BigEndian.Uint64(footer[16:]))
+		filterSize   = int64(binary.BigEndian.Uint64(footer[24:]))
+	)
+	if indexOffset+indexSize > t.size || filterOffset+filterSize > t.size {
+		return errTableCorrupt
+	}
+	index, err := t.readChecked(indexOffset, uint32(indexSize))
+	if err != nil {
+		return err
+	}
+	if t.filter, err = t.readChecked(filterOffset, uint32(filterSize)); err != nil {
+		return err
+	}
+	// Decode the block list, closed by the last key of the table
+	count, n := binary.Uvarint(index)
+	if n <= 0 {
+		return errTableCorrupt
+	}
+	index = index[n:]
+	for i := uint64(0); i <= count; i++ {
+		key, rest, ok := decodeTableBytes(index)
+		if !ok {
+			return errTableCorrupt
+		}
+		index = rest
+		if i == count {
+			t.last = key
+			break
+		}
+		offset, n := binary.Uvarint(index)
+		if n <= 0 {
+			return errTableCorrupt
+		}
+		size, m := binary.Uvarint(index[n:])
+		if m <= 0 {
+			return errTableCorrupt
+		}
+		index = index[n+m:]
+		t.blocks = append(t.blocks, tableBlock{first: key, offset: int64(offset), size: uint32(size)})
+	}
+	if len(t.blocks) == 0 {
+		return errTableCorrupt
+	}
+	t.first = t.blocks[0].first
+	return nil
+}
+
+// readChecked reads a payload of the table followed by its crc32 checksum.
+func (t *sortedTable) readChecked(offset int64, size uint32) ([]byte, error) {
+	buf := make([]byte, size+4)
+	if _, err := t.file.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+	payload := buf[:size]
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(buf[size:]) {
+		return nil, errTableCorrupt
+	}
+	return payload, nil
+}
+
+// overlaps returns whether the table holds keys in [start, limit], nil
+// meaning unbounded.
+func (t *sortedTable) overlaps(start, limit []byte) bool {
+	if start != nil && bytes.Compare(t.last, start) < 0 {
+		return false
+	}
+	if limit != nil && bytes.Compare(t.first, limit) > 0 {
+		return false
+	}
+	return true
+}
+
+// get looks the key up, reporting whether the table has an entry for it and
+// whether that entry is a deletion.
+func (t *sortedTable) get(key []byte) (value []byte, deleted bool, found bool, err error) {
+	if !t.overlaps(key, key) || !bloomContains(t.filter, key) {
+		return nil, false, false, nil
+	}
+	// Find the last block starting at or before the key
+	i := sort.Search(len(t.blocks), func(i int) bool {
+		return bytes.Compare(t.blocks[i].first, key) > 0
+	}) - 1
+	if i < 0 {
+		return nil, false, false, nil
+	}
+	block, err := t.readChecked(t.blocks[i].offset, t.blocks[i].size)
+	if err != nil {
+		return nil, false, false, err
+	}
+	for len(block) > 0 {
+		var entry logWrite
+		if entry, block, err = decodeTableEntry(block); err != nil {
+			return nil, false, false, err
+		}
+		switch bytes.Compare(entry.key, key) {
+		case 0:
+			return entry.value, entry.delete, true, nil
+		case 1:
+			return nil, false, false, nil
+		}
+	}
+	return nil, false, false, nil
+}
+
+// close closes the file of the table.
+func (t *sortedTable) close() {
+	t.file.Close()
+}
+
+// decodeTableBytes decodes a length prefixed byte string.
+func decodeTableBytes(buf []byte) ([]byte, []byte, bool) {
+	size, n := binary.Uvarint(buf)
+	if n <= 0 || uint64(len(buf)-n) < size {
+		return nil, nil, false
+	}
+	return buf[n : n+int(size)], buf[n+int(size):], true
+}
+
+// appendTableBytes appends a length prefixed byte string.
+func appendTableBytes(buf []byte, data []byte) []byte {
+	var size [binary.MaxVarintLen64]byte
+	buf = append(buf, size[:binary.PutUvarint(size[:], uint64(len(data)))]...)
+	return append(buf, data...)
+}
+
+// decodeTableEntry decodes the next entry of a block.
+func decodeTableEntry(block []byte) (logWrite, []byte, error) {
+	var (
+		entry logWrite
+		ok    bool
+	)
+	op := block[0]
+	if entry.key, block, ok = decodeTableBytes(block[1:]); !ok {
+		return entry, nil, errTableCorrupt
+	}
+	switch op {
+	case logOpPut:
+		if entry.value, block, ok = decodeTableBytes(block); !ok {
+			return entry, nil, errTableCorrupt
+		}
+	case logOpDelete:
+		entry.delete = true
+	default:
+		return entry, nil, errTableCorrupt
+	}
+	return entry, block, nil
+}
+
+// tableWriter writes the entries of a table, in ascending key order.
+type tableWriter struct {
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+	offset int64
+
+	block  []byte
+	first  []byte // First key of the current block
+	last   []byte // Last key written
+	blocks []tableBlock
+	hashes []uint32
+}
+
+// newTableWriter creates the file of the table with the number in dir.
+func newTableWriter(dir string, num uint64) (*tableWriter, error) {
+	path := filepath.Join(dir, tableName(num))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &tableWriter{path: path, file: file, writer: bufio.NewWriterSize(file, 256*1024)}, nil
+}
+
+// add appends an entry to the table.
+func (w *tableWriter) add(entry logWrite) error {
+	if w.first == nil {
+		w.first = append([]byte{}, entry.key...)
+	}
+	if entry.delete {
+		w.block = append(w.block, logOpDelete)
+		w.block = appendTableBytes(w.block, entry.key)
+	} else {
+		w.block = append(w.block, logOpPut)
+		w.block = appendTableBytes(w.block, entry.key)
+		w.block = appendTableBytes(w.block, entry.value)
+	}
+	w.last = append(w.last[:0], entry.key...)
+	w.hashes = append(w.hashes, bloomHash(entry.key))
+
+	if len(w.block) >= tableBlockSize {
+		return w.flushBlock()
+	}
+	return nil
+}
+
+// size returns the number of bytes written so far.
+func (w *tableWriter) size() int64 {
+	return w.offset + int64(len(w.block))
+}
+
+// writeChecked writes a payload followed by its crc32 checksum.
+func (w *tableWriter) writeChecked(payload []byte) error {
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(payload))
+	if _, err := w.writer.Write(payload); err != nil {
+		return err
+	}
+	if _, err := w.writer.Write(sum[:]); err != nil {
+		return err
+	}
+	w.offset += int64(len(payload)) + 4
+	return nil
+}
+
+// flushBlock writes the current block and records it in the index.
+func (w *tableWriter) flushBlock() error {
+	if len(w.block) == 0 {
+		return nil
+	}
+	w.blocks = append(w.blocks, tableBlock{first: w.first, offset: w.offset, size: uint32(len(w.block))})
+	if err := w.writeChecked(w.block); err != nil {
+		return err
+	}
+	w.block, w.first = w.block[:0], nil
+	return nil
+}
+
+// finish writes the index, the filter and the footer and syncs the table.
+func (w *tableWriter) finish() error {
+	if err := w.flushBlock(); err != nil {
+		return err
+	}
+	var num [binary.MaxVarintLen64]byte
+
+	index := append([]byte{}, num[:binary.PutUvarint(num[:], uint64(len(w.blocks)))]...)
+	for _, block := range w.blocks {
+		index = appendTableBytes(index, block.first)
+		index = append(index, num[:binary.PutUvarint(num[:], uint64(block.offset))]...)
+		index = append(index, num[:binary.PutUvarint(num[:], uint64(block.size))]...)
+	}
+	index = appendTableBytes(index, w.last)
+
+	indexOffset := w.offset
+	if err := w.writeChecked(index); err != nil {
+		return err
+	}
+	filter := newBloomFilter(w.hashes)
+	filterOffset := w.offset
+	if err := w.writeChecked(filter); err != nil {
+		return err
+	}
+	var footer [tableFooterSize]byte
+	binary.BigEndian.PutUint64(footer[0:], uint64(indexOffset))
+	binary.BigEndian.PutUint64(footer[8:], uint64(len(index)))
+	binary.BigEndian.PutUint64(footer[16:], uint64(filterOffset))
+	binary.BigEndian.PutUint64(footer[24:], uint64(len(filter)))
+	binary.BigEndian.PutUint64(footer[32:], tableMagic)
+	if _, err := w.writer.Write(footer[:]); err != nil {
+		return err
+	}
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
+
+// abort closes and removes the partially written table.
+func (w *tableWriter) abort() {
+	w.file.Close()
+	os.Remove(w.path)
+}
+
+// bloomHash hashes a key for the bloom filter.
+func bloomHash(key []byte) uint32 {
+	hasher := fnv.New32a()
+	hasher.Write(key)
+	return hasher.Sum32()
+}
+
+// bloomProbes is the number of bits set per key, about ln(2) times the bits
+// per key.
+const bloomProbes = tableBloomBits * 69 / 100
+
+// newBloomFilter creates a bloom filter of the key hashes, probing with double
+// hashing as leveldb does.
+func newBloomFilter(hashes []uint32) []byte {
+	bits := len(hashes) * tableBloomBits
+	if bits < 64 {
+		bits = 64
+	}
+	filter := make([]byte, (bits+7)/8)
+	bits = len(filter) * 8
+
+	for _, h := range hashes {
+		delta := h>>17 | h<<15
+		for i := 0; i < bloomProbes; i++ {
+			pos := h % uint32(bits)
+			filter[pos/8] |= 1 << (pos % 8)
+			h += delta
+		}
+	}
+	return filter
+}
+
+// bloomContains returns whether the key may be in the filter.
+func bloomContains(filter []byte, key []byte) bool {
+	bits := uint32(len(filter) * 8)
+	if bits == 0 {
+		return true
+	}
+	h := bloomHash(key)
+	delta := h>>17 | h<<15
+	for i := 0; i < bloomProbes; i++ {
+		pos := h % bits
+		if filter[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+		h += delta
+	}
+	return true
+}
+
+// entryIterator iterates over entries in ascending key order, tombstones
+// included.
+type entryIterator interface {
+	next() bool
+	entry() logWrite
+	err() error
+}
+
+// tableIterator iterates over the entries of a table from a start key on,
+// reading one block at a time.
+type tableIterator struct {
+	table *sortedTable
+	start []byte
+	index int    // Index of the next block to read
+	block []byte // Remaining entries of the current block
+	cur   logWrite
+	fail  error
+}
+
+// newTableIterator returns an iterator over the entries of the table from
+// start on.
+func newTableIterator(t *sortedTable, start []byte) *tableIterator {
+	index := 0
+	if start != nil {
+		index = sort.Search(len(t.blocks), func(i int) bool {
+			return bytes.Compare(t.blocks[i].first, start) > 0
+		}) - 1
+		if index < 0 {
+			index = 0
+		}
+	}
+	return &tableIterator{table: t, start: start, index: index}
+}
+
+func (it *tableIterator) next() bool {
+	for it.fail == nil {
+		for len(it.block) > 0 {
+			if it.cur, it.block, it.fail = decodeTableEntry(it.block); it.fail != nil {
+				return false
+			}
+			if it.start == nil || bytes.Compare(it.cur.key, it.start) >= 0 {
+				return true
+			}
+		}
+		if it.index >= len(it.table.blocks) {
+			return false
+		}
+		block := it.table.blocks[it.index]
+		it.index++
+		it.block, it.fail = it.table.readChecked(block.offset, block.size)
+	}
+	return false
+}
+
+func (it *tableIterator) entry() logWrite { return it.cur }
+func (it *tableIterator) err() error      { return it.fail }
+
+// levelIterator chains the iterators of the sorted, non-overlapping tables of
+// a level.
+type levelIterator struct {
+	tables []*sortedTable
+	start  []byte
+	cur    *tableIterator
+}
+
+func (it *levelIterator) next() bool {
+	for {
+		if it.cur != nil {
+			if it.cur.next() {
+				return true
+			}
+			if it.cur.err() != nil {
+				return false
+			}
+		}
+		if len(it.tables) == 0 {
+			return false
+		}
+		it.cur, it.tables = newTableIterator(it.tables[0], it.start), it.tables[1:]
+	}
+}
+
+func (it *levelIterator) entry() logWrite { return it.cur.entry() }
+
+func (it *levelIterator) err() error {
+	if it.cur == nil {
+		return nil
+	}
+	return it.cur.err()
+}
+
+// sliceIterator iterates over sorted entries held in memory.
+type sliceIterator struct {
+	entries []logWrite
+	pos     int
+}
+
+func (it *sliceIterator) next() bool {
+	if it.pos >= len(it.entries) {
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *sliceIterator) entry() logWrite { return it.entries[it.pos-1] }
+func (it *sliceIterator) err() error      { return nil }
+
+// mergeIterator merges iterators ordered from the newest to the oldest data,
+// yielding the newest entry of every key.
+type mergeIterator struct {
+	iters []entryIterator
+	live  []bool // Whether the iterator has a current entry
+	init  bool
+	cur   logWrite
+	fail  error
+}
+
+func newMergeIterator(iters []entryIterator) *mergeIterator {
+	return &mergeIterator{iters: iters, live: make([]bool, len(iters))}
+}
+
+func (it *mergeIterator) next() bool {
+	if it.fail != nil {
+		return false
+	}
+	if !it.init {
+		for i, iter := range it.iters {
+			it.live[i] = it.advance(iter)
+		}
+		it.init = true
+	}
+	if it.fail != nil {
+		return false
+	}
+	// Pick the smallest key, the newest iterator winning ties
+	min := -1
+	for i, iter := range it.iters {
+		if it.live[i] && (min < 0 || bytes.Compare(iter.entry().key, it.iters[min].entry().key) < 0) {
+			min = i
+		}
+	}
+	if min < 0 {
+		return false
+	}
+	it.cur = it.iters[min].entry()
+	it.cur.key = append([]byte{}, it.cur.key...)
+
+	// Step over the older entries of the same key
+	for i, iter := range it.iters {
+		if it.live[i] && bytes.Equal(iter.entry().key, it.cur.key) {
+			it.live[i] = it.advance(iter)
+		}
+	}
+	return it.fail == nil
+}
+
+// advance steps an iterator, recording its failure.
+func (it *mergeIterator) advance(iter entryIterator) bool {
+	if iter.next() {
+		return true
+	}
+	if err := iter.err(); err != nil && it.fail == nil {
+		it.fail = err
+	}
+	return false
+}
+
+func (it *mergeIterator) entry() logWrite { return it.cur }
+func (it *mergeIterator) err() error      { return it.fail }
@@ -0,0 +1,226 @@
+package database_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/database"
+)
+
+func newTestLogDB() (*database.LogDatabase, string, func()) {
+	dirname, err := ioutil.TempDir(os.TempDir(), "cpcdb_test")
+	if err != nil {
+		panic("failed to create test file: " + err.Error())
+	}
+	db, err := database.NewLogDatabase(dirname)
+	if err != nil {
+		panic("failed to create test database: " + err.Error())
+	}
+	return db, dirname, func() {
+		db.Close()
+		_ = os.RemoveAll(dirname)
+	}
+}
+
+func TestLogDBFuncs(t *testing.T) {
+	db, _, remove := newTestLogDB()
+	defer remove()
+	testDBFuncs(db, t)
+}
+
+func TestLogDBBatch(t *testing.T) {
+	db, _, remove := newTestLogDB()
+	defer remove()
+	testBatchFuncs(db.NewBatch(), t)
+}
+
+func TestLogDBParallelFuncs(t *testing.T) {
+	db, _, remove := newTestLogDB()
+	defer remove()
+	testParallelDBFuncs(db, t)
+}
+
+func TestLogDBTableFuncs(t *testing.T) {
+	db, _, remove := newTestLogDB()
+	defer remove()
+
+	tbl := database.NewTable(db, "foobar")
+	defer tbl.Close()
+
+	testDBFuncs(tbl, t)
+}
+
+func TestLogDBReopen(t *testing.T) {
+	db, dir, remove := newTestLogDB()
+	defer remove()
+
+	db.Put([]byte("a"), []byte("1"))
+	db.Put([]byte("b"), []byte("2"))
+	db.Delete([]byte("a"))
+	batch := db.NewBatch()
+	batch.Put([]byte("c"), []byte("3"))
+	batch.Put([]byte("b"), []byte("4"))
+	batch.Write()
+	db.Close()
+
+	// Append a torn frame, which must be dropped on reopen
+	f, err := os.OpenFile(filepath.Join(dir, "logdb.data"), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0x01, 0x02, 0x03, 0x04, 0x00, 0x00, 0x00, 0x10, 0x00})
+	f.Close()
+
+	if db, err = database.NewLogDatabase(dir); err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	check := func() {
+		if has, _ := db.Has([]byte("a")); has {
+			t.Fatal("deleted key present")
+		}
+		for key, want := range map[string]string{"b": "4", "c": "3"} {
+			if value, err := db.Get([]byte(key)); err != nil || string(value) != want {
+				t.Fatalf("key %s: have %q (%v), want %q", key, value, err, want)
+			}
+		}
+	}
+	check()
+	if err := db.Put([]byte("d"), []byte("5")); err != nil {
+		t.Fatalf("put after reopen failed: %v", err)
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("compaction failed: %v", err)
+	}
+	check()
+	if value, err := db.Get([]byte("d")); err != nil || string(value) != "5" {
+		t.Fatalf("value lost by compaction: %q (%v)", value, err)
+	}
+	// The compacted tables must be found again after reopening
+	db.Close()
+	if db, err = database.NewLogDatabase(dir); err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	check()
+}
+
+func TestLogDBCompaction(t *testing.T) {
+	db, dir, remove := newTestLogDB()
+	defer remove()
+
+	// Write enough to flush several memtables and compact level 0, overwriting
+	// and deleting on the way
+	value := bytes.Repeat([]byte{0xaa}, 1024)
+	for round := 0; round < 3; round++ {
+		batch := db.NewBatch()
+		for i := 0; i < 8192; i++ {
+			key := []byte(fmt.Sprintf("key-%05d", i))
+			if round == 2 && i%2 == 0 {
+				batch.Delete(key)
+			} else {
+				batch.Put(key, append(value, byte(round)))
+			}
+			if batch.ValueSize() >= database.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					t.Fatalf("batch write failed: %v", err)
+				}
+				batch.Reset()
+			}
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatalf("batch write failed: %v", err)
+		}
+	}
+	check := func() {
+		for i := 0; i < 8192; i++ {
+			key := []byte(fmt.Sprintf("key-%05d", i))
+			have, err := db.Get(key)
+			if i%2 == 0 {
+				if err != database.ErrKeyNotFound {
+					t.Fatalf("key %s: deleted key present (%v)", key, err)
+				}
+				continue
+			}
+			if err != nil || !bytes.Equal(have, append(value, 2)) {
+				t.Fatalf("key %s: value mismatch (%v)", key, err)
+			}
+		}
+		iter := db.NewPrefixIterator([]byte("key-"))
+		defer iter.Release()
+
+		count := 0
+		for iter.Next() {
+			if want := fmt.Sprintf("key-%05d", 2*count+1); string(iter.Key()) != want {
+				t.Fatalf("iterated key mismatch: have %s, want %s", iter.Key(), want)
+			}
+			count++
+		}
+		if err := iter.Error(); err != nil || count != 4096 {
+			t.Fatalf("iterated keys mismatch: have %d (%v), want %d", count, err, 4096)
+		}
+	}
+	check()
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("compaction failed: %v", err)
+	}
+	check()
+	if stats := db.Stats(); !strings.Contains(stats, "|") {
+		t.Fatalf("missing stats: %q", stats)
+	}
+	db.Close()
+
+	// Reopen from the tables and ensure obsolete ones were removed
+	reopened, err := database.NewLogDatabase(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	db = reopened
+	check()
+}
+
+func TestLogDBIterator(t *testing.T) {
+	db, _, remove := newTestLogDB()
+	defer remove()
+
+	for _, key := range []string{"foobar2", "foo", "foobar1", "bar"} {
+		db.Put([]byte(key), []byte("v"+key))
+	}
+	iter := db.NewPrefixIterator([]byte("foobar"))
+	db.Delete([]byte("foobar2"))
+
+	var keys []string
+	for iter.Next() {
+		if !bytes.Equal(iter.Value(), append([]byte("v"), iter.Key()...)) {
+			t.Fatalf("value mismatch for %s: %s", iter.Key(), iter.Value())
+		}
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	// The iterator sees the database as it was when created
+	if len(keys) != 2 || keys[0] != "foobar1" || keys[1] != "foobar2" {
+		t.Fatalf("iterated keys mismatch: %v", keys)
+	}
+	if has, _ := db.Has([]byte("foobar2")); has {
+		t.Fatal("deleted key present")
+	}
+}
+
+func TestOpenBackendMismatch(t *testing.T) {
+	db, dir, remove := newTestLogDB()
+	defer remove()
+	db.Put([]byte("a"), []byte("1"))
+
+	if backend := database.DetectBackend(dir); backend != database.LogDBBackend {
+		t.Fatalf("backend mismatch: have %s, want %s", backend, database.LogDBBackend)
+	}
+	if _, err := database.Open(database.LevelDBBackend, dir, 0, 0); err == nil {
+		t.Fatal("opened log database as leveldb")
+	}
+}
@@ -60,6 +60,25 @@ func (db *MemDatabase) Keys() [][]byte {
 	return keys
 }
 
+// NewPrefixIterator returns an iterator over the keys with the prefix
+// present at the time of the call.
+func (db *MemDatabase) NewPrefixIterator(prefix []byte) Iterator {
+	db.rw.RLock()
+	defer db.rw.RUnlock()
+
+	keys := make([]string, 0, len(db.db))
+	for key := range db.db {
+		keys = append(keys, key)
+	}
+	return newSnapshotIterator(prefixedKeys(keys, prefix), func(key string) ([]byte, bool, error) {
+		db.rw.RLock()
+		defer db.rw.RUnlock()
+
+		value, ok := db.db[key]
+		return value, ok, nil
+	})
+}
+
 func (db *MemDatabase) Delete(key []byte) error {
 	db.rw.Lock()
 	defer db.rw.Unlock()
@@ -15,6 +15,7 @@ import (
 	"bitbucket.org/cpchain/chain/accounts"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/api/proof"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
@@ -35,12 +36,18 @@ import (
 
 const (
 	defaultGasPrice = 50 * configs.Shannon
+
+	// maxBlockRangeSpan is the maximum number of blocks served by a single
+	// range request.
+	maxBlockRangeSpan = 128
 )
 
 var (
 	InvalidPrivateTxErr    = errors.New("Private transaction should have participants defined and payload data.")
 	NotSupportPrivateTxErr = errors.New("Not support private transaction")
 	NoSupportTxTypeErr     = errors.New("Transaction type not supported")
+	InvalidBlockRangeErr   = errors.New("invalid block range")
+	PendingBlockRangeErr   = errors.New("pending block is not allowed in a block range")
 )
 
 // PublicCpchainAPI provides an API to access Cpchain related information.
@@ -170,6 +177,94 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// ScheduleArgs represents the conditions of a scheduled transaction. All the
+// given conditions must hold before the transaction is sent to the pool.
+type ScheduleArgs struct {
+	MinBlock      *hexutil.Uint64 `json:"minBlock"`
+	MinTimestamp  *hexutil.Uint64 `json:"minTimestamp"`
+	AfterTxHash   *common.Hash    `json:"afterTxHash"`
+	Confirmations *hexutil.Uint64 `json:"confirmations"`
+}
+
+// toCondition converts the arguments into a core.TxCondition.
+func (args *ScheduleArgs) toCondition() core.TxCondition {
+	var cond core.TxCondition
+	if args.MinBlock != nil {
+		cond.MinBlock = uint64(*args.MinBlock)
+	}
+	if args.MinTimestamp != nil {
+		cond.MinTimestamp = uint64(*args.MinTimestamp)
+	}
+	if args.AfterTxHash != nil {
+		cond.AfterTx = *args.AfterTxHash
+	}
+	if args.Confirmations != nil {
+		cond.Confirmations = uint64(*args.Confirmations)
+	}
+	return cond
+}
+
+// RPCScheduledTransaction represents a transaction waiting for its schedule.
+type RPCScheduledTransaction struct {
+	Transaction *RPCTransaction `json:"transaction"`
+	Condition   ScheduleArgs    `json:"condition"`
+	Added       hexutil.Uint64  `json:"addedAtBlock"`
+}
+
+func newRPCScheduledTransaction(stx *core.ScheduledTx) *RPCScheduledTransaction {
+	var (
+		minBlock      = hexutil.Uint64(stx.Condition.MinBlock)
+		minTimestamp  = hexutil.Uint64(stx.Condition.MinTimestamp)
+		confirmations = hexutil.Uint64(stx.Condition.Confirmations)
+		cond          = ScheduleArgs{MinBlock: &minBlock, MinTimestamp: &minTimestamp, Confirmations: &confirmations}
+	)
+	if stx.Condition.AfterTx != (common.Hash{}) {
+		afterTx := stx.Condition.AfterTx
+		cond.AfterTxHash = &afterTx
+	}
+	return &RPCScheduledTransaction{
+		Transaction: newRPCPendingTransaction(stx.Tx),
+		Condition:   cond,
+		Added:       hexutil.Uint64(stx.Added),
+	}
+}
+
+// ScheduleRawTransaction keeps the signed transaction on the node until all the
+// given conditions hold, and then adds it to the transaction pool.
+func (s *PublicTxPoolAPI) ScheduleRawTransaction(ctx context.Context, encodedTx hexutil.Bytes, args ScheduleArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if !types.SupportTxType(tx.Type()) {
+		return common.Hash{}, types.ErrNotSupportedTxType
+	}
+	signer := types.MakeSigner(s.b.ChainConfig())
+	if _, err := types.Sender(signer, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.b.ScheduleTx(ctx, tx, args.toCondition()); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Scheduled returns the transactions waiting for their schedule.
+func (s *PublicTxPoolAPI) Scheduled() []*RPCScheduledTransaction {
+	scheduled := s.b.ScheduledTxs()
+	result := make([]*RPCScheduledTransaction, len(scheduled))
+	for i, stx := range scheduled {
+		result[i] = newRPCScheduledTransaction(stx)
+	}
+	return result
+}
+
+// CancelScheduledTransaction drops a transaction waiting for its schedule,
+// reporting whether it was found.
+func (s *PublicTxPoolAPI) CancelScheduledTransaction(hash common.Hash) bool {
+	return s.b.CancelScheduledTx(hash)
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -625,6 +720,104 @@ func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, blockHash comm
 	return nil, err
 }
 
+// resolveBlockRange converts the given range into absolute block numbers and
+// clamps its end to the current head. The returned range is empty if from is
+// beyond the head.
+func (s *PublicBlockChainAPI) resolveBlockRange(from, to rpc.BlockNumber) (uint64, uint64, error) {
+	head := s.b.CurrentBlock().NumberU64()
+	resolve := func(number rpc.BlockNumber) (uint64, error) {
+		switch number {
+		case rpc.PendingBlockNumber:
+			return 0, PendingBlockRangeErr
+		case rpc.LatestBlockNumber:
+			return head, nil
+		}
+		if number < 0 {
+			return 0, InvalidBlockRangeErr
+		}
+		return uint64(number), nil
+	}
+	start, err := resolve(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := resolve(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, InvalidBlockRangeErr
+	}
+	if end-start >= maxBlockRangeSpan {
+		return 0, 0, fmt.Errorf("block range too large: have %d, max %d", end-start+1, maxBlockRangeSpan)
+	}
+	if end > head {
+		end = head
+	}
+	return start, end, nil
+}
+
+// GetBlocksByRange returns the canonical blocks from fromBlock to toBlock
+// inclusive. At most maxBlockRangeSpan blocks are served per request, and the
+// result stops at the current head.
+func (s *PublicBlockChainAPI) GetBlocksByRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber, fullTx bool) ([]map[string]interface{}, error) {
+	start, end, err := s.resolveBlockRange(fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]map[string]interface{}, 0, maxBlockRangeSpan)
+	for number := start; number <= end; number++ {
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		fields, err := s.rpcOutputBlock(block, true, fullTx)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, fields)
+	}
+	return blocks, nil
+}
+
+// GetReceiptsByRange returns the receipts of the canonical blocks from
+// fromBlock to toBlock inclusive, one list per block. At most
+// maxBlockRangeSpan blocks are served per request, and the result stops at
+// the current head.
+func (s *PublicBlockChainAPI) GetReceiptsByRange(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) ([][]map[string]interface{}, error) {
+	start, end, err := s.resolveBlockRange(fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]map[string]interface{}, 0, maxBlockRangeSpan)
+	for number := start; number <= end; number++ {
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		receipts, err := s.b.GetReceipts(ctx, block.Hash())
+		if err != nil {
+			return nil, err
+		}
+		txs := block.Transactions()
+		if len(receipts) != len(txs) {
+			return nil, fmt.Errorf("receipts of block %d not found", number)
+		}
+		fields := make([]map[string]interface{}, len(receipts))
+		for i, receipt := range receipts {
+			fields[i] = rpcMarshalReceipt(txs[i], receipt, block.Hash(), number, uint64(i))
+		}
+		result = append(result, fields)
+	}
+	return result, nil
+}
+
 // GetProposerByBlockNumber returns the requested Proposers.
 func (s *PublicBlockChainAPI) GetProposersByBlockNumber(ctx context.Context, blockNr rpc.BlockNumber) ([]common.Address, error) {
 	proposers, err := s.b.Proposers(blockNr)
@@ -700,6 +893,60 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// GetProof returns the merkle proof of the given account and of the given
+// storage slots of it, against the state root of the given block.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*proof.AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr, false)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	storageTrie := state.StorageTrie(address)
+	storageHash := types.EmptyRootHash
+	codeHash := state.GetCodeHash(address)
+	if storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	} else {
+		// Missing accounts are proven empty
+		codeHash = crypto.Keccak256Hash(nil)
+	}
+	storageProof := make([]proof.StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		slot := common.HexToHash(key)
+		storageProof[i] = proof.StorageResult{Key: slot, Value: new(hexutil.Big), Proof: []hexutil.Bytes{}}
+		if storageTrie == nil {
+			continue
+		}
+		nodes, err := state.GetStorageProof(address, slot)
+		if err != nil {
+			return nil, err
+		}
+		storageProof[i].Value = (*hexutil.Big)(state.GetState(address, slot).Big())
+		storageProof[i].Proof = toHexSlice(nodes)
+	}
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	return &proof.AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     codeHash,
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, state.Error()
+}
+
+// toHexSlice converts the nodes of a merkle proof for the RPC output.
+func toHexSlice(nodes [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(nodes))
+	for i, node := range nodes {
+		result[i] = node
+	}
+	return result
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From      common.Address  `json:"from"`
@@ -838,6 +1085,8 @@ type ExecutionResult struct {
 	Failed      bool           `json:"failed"`
 	ReturnValue string         `json:"returnValue"`
 	StructLogs  []StructLogRes `json:"structLogs"`
+	// Proxies are the calls of proxy contracts redirected to their logic contracts
+	Proxies []vm.ProxyRedirect `json:"proxies,omitempty"`
 }
 
 // StructLogRes stores a structured log emitted by the EVM while replaying a
@@ -1307,6 +1556,44 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 		receipt = receipts[index]
 	}
 
+	return rpcMarshalReceipt(tx, receipt, blockHash, blockNumber, index), nil
+}
+
+// GetReceiptProof returns the receipt of the given transaction along its merkle
+// proof against the receipts root of its block.
+func (s *PublicTransactionPoolAPI) GetReceiptProof(ctx context.Context, hash common.Hash) (*proof.ReceiptProof, error) {
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, nil
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) <= int(index) {
+		return nil, nil
+	}
+	nodes, err := proof.ProveReceipt(receipts, index)
+	if err != nil {
+		return nil, err
+	}
+	return &proof.ReceiptProof{
+		BlockHash:        blockHash,
+		BlockNumber:      hexutil.Uint64(blockNumber),
+		TransactionIndex: hexutil.Uint64(index),
+		ReceiptsRoot:     block.ReceiptsRoot(),
+		Receipt:          receipts.GetRlp(int(index)),
+		Proof:            nodes,
+	}, nil
+}
+
+// rpcMarshalReceipt converts the receipt of the index-th transaction of a block
+// into the RPC representation.
+func rpcMarshalReceipt(tx *types.Transaction, receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, index uint64) map[string]interface{} {
 	var signer types.Signer = types.FrontierSigner{}
 	if tx.Protected() {
 		signer = types.NewCep1Signer(tx.ChainId())
@@ -1316,7 +1603,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	fields := map[string]interface{}{
 		"blockHash":         blockHash,
 		"blockNumber":       hexutil.Uint64(blockNumber),
-		"transactionHash":   hash,
+		"transactionHash":   tx.Hash(),
 		"transactionIndex":  hexutil.Uint64(index),
 		"from":              from,
 		"to":                tx.To(),
@@ -1340,7 +1627,11 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
-	return fields, nil
+	// The logic contract is only reported if the recipient is a proxy contract
+	if receipt.LogicAddress != (common.Address{}) {
+		fields["logicAddress"] = receipt.LogicAddress
+	}
+	return fields
 }
 
 // sign is a helper function that signs a transaction with the private key of the given address.
@@ -63,6 +63,9 @@ type Backend interface {
 
 	// TxPool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	ScheduleTx(ctx context.Context, signedTx *types.Transaction, cond core.TxCondition) error
+	ScheduledTxs() []*core.ScheduledTx
+	CancelScheduledTx(hash common.Hash) bool
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
@@ -68,6 +68,8 @@ type Work struct {
 	pubReceipts  []*types.Receipt
 	privReceipts []*types.Receipt
 
+	proxies *vm.ProxyCache // proxy lookups cached across the transactions of the block
+
 	accm      *accounts.Manager
 	createdAt time.Time
 }
@@ -282,6 +284,9 @@ func (e *engine) update() {
 				e.currentWork.commitTransactions(e.mux, txset, e.chain, e.coinbase, time.Now().Add(time.Second*10))
 				e.updateSnapshot()
 				e.currentMu.Unlock()
+			} else if e.isZeroPeriod() {
+				// 0-period chains seal blocks on transactions only, now is the time
+				e.commitNewWork()
 			}
 		// System stopped
 		case err := <-e.txsSub.Err():
@@ -308,6 +313,11 @@ func (e *engine) update() {
 
 }
 
+// isZeroPeriod returns if blocks are sealed only when there are transactions.
+func (e *engine) isZeroPeriod() bool {
+	return e.config.Dpor != nil && e.config.Dpor.Period == 0
+}
+
 // wait handles mined blocks.
 func (e *engine) wait() {
 	for {
@@ -371,6 +381,7 @@ func (e *engine) makeCurrentWork(parent *types.Block, header *types.Header) erro
 		createdAt: time.Now(),
 		remoteDB:  e.chain.RemoteDB(),
 		accm:      e.backend.AccountManager(),
+		proxies:   vm.NewProxyCache(),
 	}
 
 	// Keep track of transactions which return errors so they can be removed
@@ -444,6 +455,10 @@ func (e *engine) commitNewWork() {
 	if delay > 0 {
 		commitTxsBreakTime = header.Timestamp().Add(-delay)
 	}
+	// 0-period chains have no slot to wait for, the block is due right away
+	if e.isZeroPeriod() {
+		commitTxsBreakTime = time.Now().Add(time.Second * 10)
+	}
 
 	log.Debug("timelog before commit txs", "header.timestamp", header.Timestamp(), "now", time.Now(), "delay", header.Timestamp().Sub(time.Now()), "commitTxsBreakTime", commitTxsBreakTime)
 
@@ -578,7 +593,7 @@ func (w *Work) commitTransaction(tx *types.Transaction, bc *core.BlockChain, coi
 	snapPriv := w.privState.Snapshot()
 
 	pubReceipt, privReceipt, _, err := core.ApplyTransaction(w.config, bc, &coinbase, gp, w.pubState, w.privState, w.remoteDB,
-		w.header, tx, &w.header.GasUsed, vm.Config{}, w.accm)
+		w.header, tx, &w.header.GasUsed, vm.Config{ProxyCache: w.proxies}, w.accm)
 	if err != nil {
 		w.pubState.RevertToSnapshot(snap)
 		w.privState.RevertToSnapshot(snapPriv)
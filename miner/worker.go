@@ -109,6 +109,8 @@ func (nw *NativeWorker) mine(work *Work, quitCh <-chan struct{}) {
 				log.Info("Not your turn", "err", err, "number", work.Block.Number())
 			} else if err == consensus.ErrNotInProposerCommittee {
 				log.Info("Not in proposer committee", "err", err, "number", work.Block.Number())
+			} else if err == consensus.ErrWaitTransactions {
+				log.Debug("No transaction to seal", "number", work.Block.Number())
 			} else {
 				log.Warn("Block sealing failed", "err", err)
 			}
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/external"
 	"bitbucket.org/cpchain/chain/accounts/keystore"
 	"bitbucket.org/cpchain/chain/configs"
 	"github.com/ethereum/go-ethereum/common"
@@ -74,6 +75,16 @@ type Config struct {
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
+	// ExternalSigner is the IPC path or HTTP URL of an external signer. If set,
+	// the accounts exposed by the signer are available next to the keystore
+	// accounts, with all their signing requests forwarded to the signer.
+	ExternalSigner string `toml:",omitempty"`
+
+	// DatabaseBackend is the key-value store backend of the databases, one of
+	// database.Backends. If empty, existing databases keep their backend and new
+	// ones use LevelDB.
+	DatabaseBackend string `toml:",omitempty"`
+
 	// IPCPath is the requested location to place the IPC endpoint. If the path is
 	// a simple file name, it is placed inside the data directory (or on the root
 	// pipe path on Windows), whereas if it's a resolvable path name (absolute or
@@ -383,6 +394,13 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	backends := []accounts.Backend{
 		keystore.NewKeyStore(keydir, scryptN, scryptP),
 	}
+	if conf.ExternalSigner != "" {
+		signer, err := external.NewExternalBackend(conf.ExternalSigner)
+		if err != nil {
+			return nil, "", fmt.Errorf("error connecting to external signer: %v", err)
+		}
+		backends = append(backends, signer)
+	}
 	return accounts.NewManager(backends...), ephemeral, nil
 }
 
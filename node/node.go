@@ -578,7 +578,7 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (database.Database,
 	if n.config.DataDir == "" {
 		return database.NewMemDatabase(), nil
 	}
-	return database.NewLDBDatabase(n.config.resolvePath(name), cache, handles)
+	return database.Open(n.config.DatabaseBackend, n.config.resolvePath(name), cache, handles)
 }
 
 // ResolvePath returns the absolute path of a resource in the instance directory.
@@ -29,7 +29,7 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (da
 	if ctx.config.DataDir == "" {
 		return database.NewMemDatabase(), nil
 	}
-	db, err := database.NewLDBDatabase(ctx.config.resolvePath(name), cache, handles)
+	db, err := database.Open(ctx.config.DatabaseBackend, ctx.config.resolvePath(name), cache, handles)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,156 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abiregistry
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	errNoMethod = errors.New("no method matching the call")
+	errNoEvent  = errors.New("no event matching the log")
+)
+
+// Argument is a decoded argument of a call or an event.
+type Argument struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Call is a decoded contract call.
+type Call struct {
+	Contract  string      `json:"contract"`
+	Method    string      `json:"method"`
+	Signature string      `json:"signature"`
+	Args      []*Argument `json:"args"`
+}
+
+// Log is a decoded contract event.
+type Log struct {
+	Address  common.Address `json:"address"`
+	Index    hexutil.Uint   `json:"logIndex"`
+	Contract string         `json:"contract"`
+	Event    string         `json:"event"`
+	Args     []*Argument    `json:"args"`
+}
+
+// DecodeCall decodes the input of a call of the contract.
+func (c *Contract) DecodeCall(input []byte) (*Call, error) {
+	if len(input) < 4 {
+		return nil, errNoMethod
+	}
+	method, err := c.ABI.MethodById(input)
+	if err != nil {
+		return nil, errNoMethod
+	}
+	values, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	call := &Call{Contract: c.Name, Method: method.Name, Signature: method.Sig(), Args: []*Argument{}}
+	for i, arg := range method.Inputs {
+		call.Args = append(call.Args, &Argument{Name: arg.Name, Type: arg.Type.String(), Value: formatValue(values[i])})
+	}
+	return call, nil
+}
+
+// DecodeLog decodes an event of the contract. Indexed arguments of dynamic
+// types are only known by the hash in their topic.
+func (c *Contract) DecodeLog(log *types.Log) (*Log, error) {
+	if len(log.Topics) == 0 {
+		return nil, errNoEvent
+	}
+	for _, event := range c.ABI.Events {
+		if event.Anonymous || event.Id() != log.Topics[0] {
+			continue
+		}
+		values, err := event.Inputs.NonIndexed().UnpackValues(log.Data)
+		if err != nil {
+			return nil, err
+		}
+		decoded := &Log{Address: log.Address, Index: hexutil.Uint(log.Index), Contract: c.Name, Event: event.Name, Args: []*Argument{}}
+		topics := log.Topics[1:]
+		for _, arg := range event.Inputs {
+			var value interface{}
+			if arg.Indexed {
+				if len(topics) == 0 {
+					return nil, errNoEvent
+				}
+				value, topics = decodeTopic(arg, topics[0]), topics[1:]
+			} else {
+				value, values = formatValue(values[0]), values[1:]
+			}
+			decoded.Args = append(decoded.Args, &Argument{Name: arg.Name, Type: arg.Type.String(), Value: value})
+		}
+		return decoded, nil
+	}
+	return nil, errNoEvent
+}
+
+// decodeTopic decodes an indexed event argument, the topic itself if its type
+// is dynamic.
+func decodeTopic(arg abi.Argument, topic common.Hash) interface{} {
+	switch arg.Type.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return topic
+	}
+	values, err := abi.Arguments{{Name: arg.Name, Type: arg.Type}}.UnpackValues(topic.Bytes())
+	if err != nil {
+		return topic
+	}
+	return formatValue(values[0])
+}
+
+// formatValue converts a decoded value to its RPC representation, numbers and
+// byte arrays in hex.
+func formatValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return (*hexutil.Big)(v)
+	case common.Address, common.Hash, string, bool:
+		return v
+	case []byte:
+		return hexutil.Bytes(v)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return hexutil.Uint64(rv.Uint())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return (*hexutil.Big)(big.NewInt(rv.Int()))
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Bytes(b)
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = formatValue(rv.Index(i).Interface())
+		}
+		return list
+	}
+	return value
+}
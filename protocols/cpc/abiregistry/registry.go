@@ -0,0 +1,167 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package abiregistry holds the ABIs of the contracts known to a node, the
+// built-in system contracts and the ones registered by the user, to decode
+// their calls and events.
+package abiregistry
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/admission"
+	"bitbucket.org/cpchain/chain/contracts/dpor/campaign"
+	"bitbucket.org/cpchain/chain/contracts/dpor/network"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	rpt "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	pdash "bitbucket.org/cpchain/chain/contracts/pdash/pdash_contract"
+	proxy "bitbucket.org/cpchain/chain/contracts/proxy/proxy_contract"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of the built-in contracts not named by the chain config.
+const (
+	ContractProxyRegister = "proxyRegister"
+	ContractPdash         = "pdash"
+	ContractPdashProxy    = "pdashProxy"
+	ContractPdashRegister = "pdashRegister"
+)
+
+var errBuiltinContract = errors.New("ABI of a built-in contract cannot be changed")
+
+// BuiltinABIs are the ABIs of the contracts shipped with the chain, by name.
+var BuiltinABIs = map[string]string{
+	configs.ContractCampaign:   campaign.CampaignABI,
+	configs.ContractRpt:        rpt.RptABI,
+	configs.ContractAdmission:  admission.AdmissionABI,
+	configs.ContractRnode:      rnode.RnodeABI,
+	configs.ContractNetwork:    network.NetworkABI,
+	configs.ContractReward:     reward.RewardABI,
+	configs.ContractValidators: validators.ValidatorRegistryABI,
+	ContractProxyRegister:      proxy.ProxyContractRegisterABI,
+	ContractPdash:              pdash.PdashABI,
+	ContractPdashProxy:         pdash.PdashProxyABI,
+	ContractPdashRegister:      pdash.RegisterABI,
+}
+
+// Contract is the ABI of a contract.
+type Contract struct {
+	Name    string
+	ABI     abi.ABI
+	JSON    string
+	Builtin bool
+}
+
+// Registry holds the ABIs of the built-in contracts and of the contracts
+// registered by the user, the latter persisted in the database.
+type Registry struct {
+	db      database.Database
+	builtin map[common.Address]*Contract
+
+	mu    sync.RWMutex
+	cache map[common.Address]*Contract // Registered contracts read so far, misses are not kept
+}
+
+// NewRegistry returns a registry of the built-in contracts at the given
+// addresses, by name, and of the contracts registered in db.
+func NewRegistry(db database.Database, builtins map[string]common.Address) *Registry {
+	r := &Registry{
+		db:      db,
+		builtin: make(map[common.Address]*Contract),
+		cache:   make(map[common.Address]*Contract),
+	}
+	for name, addr := range builtins {
+		json, ok := BuiltinABIs[name]
+		if !ok || addr == (common.Address{}) {
+			continue
+		}
+		parsed, err := abi.JSON(strings.NewReader(json))
+		if err != nil {
+			log.Error("Invalid built-in contract ABI", "name", name, "err", err)
+			continue
+		}
+		r.builtin[addr] = &Contract{Name: name, ABI: parsed, JSON: json, Builtin: true}
+	}
+	return r
+}
+
+// Lookup returns the ABI of the contract at addr, nil if unknown.
+func (r *Registry) Lookup(addr common.Address) *Contract {
+	if contract, ok := r.builtin[addr]; ok {
+		return contract
+	}
+	r.mu.RLock()
+	contract, ok := r.cache[addr]
+	r.mu.RUnlock()
+	if ok {
+		return contract
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := rawdb.ReadContractABI(r.db, addr)
+	if entry == nil {
+		return nil
+	}
+	parsed, err := abi.JSON(strings.NewReader(entry.ABI))
+	if err != nil {
+		return nil
+	}
+	contract = &Contract{Name: entry.Name, ABI: parsed, JSON: entry.ABI}
+	r.cache[addr] = contract
+	return contract
+}
+
+// Register registers the ABI of the contract at addr, replacing the one
+// registered before if any. The ABIs of the built-in contracts are fixed.
+func (r *Registry) Register(addr common.Address, name string, json string) error {
+	if _, ok := r.builtin[addr]; ok {
+		return errBuiltinContract
+	}
+	parsed, err := abi.JSON(strings.NewReader(json))
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rawdb.WriteContractABI(r.db, addr, &rawdb.ContractABI{Name: name, ABI: json})
+	r.cache[addr] = &Contract{Name: name, ABI: parsed, JSON: json}
+	return nil
+}
+
+// Unregister removes the ABI registered for the contract at addr.
+func (r *Registry) Unregister(addr common.Address) error {
+	if _, ok := r.builtin[addr]; ok {
+		return errBuiltinContract
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rawdb.DeleteContractABI(r.db, addr)
+	delete(r.cache, addr)
+	return nil
+}
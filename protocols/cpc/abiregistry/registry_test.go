@@ -0,0 +1,134 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package abiregistry
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const tokenABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"memo","type":"string","indexed":true},{"name":"amount","type":"uint256","indexed":false}]}
+]`
+
+var (
+	campaignAddr = common.HexToAddress("0x01")
+	tokenAddr    = common.HexToAddress("0x02")
+)
+
+func TestRegistry(t *testing.T) {
+	db := database.NewMemDatabase()
+	registry := NewRegistry(db, map[string]common.Address{configs.ContractCampaign: campaignAddr})
+
+	if contract := registry.Lookup(campaignAddr); contract == nil || contract.Name != configs.ContractCampaign || !contract.Builtin {
+		t.Fatalf("built-in contract mismatch: %+v", contract)
+	}
+	if contract := registry.Lookup(tokenAddr); contract != nil {
+		t.Fatalf("unregistered contract found: %+v", contract)
+	}
+	for i := 0; i < 16; i++ {
+		registry.Lookup(common.BigToAddress(big.NewInt(int64(0x100 + i))))
+	}
+	if n := len(registry.cache); n != 0 {
+		t.Fatalf("unknown contracts cached: %d", n)
+	}
+	if err := registry.Register(campaignAddr, "token", tokenABI); err != errBuiltinContract {
+		t.Fatalf("built-in registration error mismatch: have %v, want %v", err, errBuiltinContract)
+	}
+	if err := registry.Unregister(campaignAddr); err != errBuiltinContract {
+		t.Fatalf("built-in unregistration error mismatch: have %v, want %v", err, errBuiltinContract)
+	}
+	if err := registry.Register(tokenAddr, "token", "[{"); err == nil {
+		t.Fatalf("invalid ABI registered")
+	}
+	if err := registry.Register(tokenAddr, "token", tokenABI); err != nil {
+		t.Fatalf("failed to register ABI: %v", err)
+	}
+
+	// Registered ABIs are persisted
+	reopened := NewRegistry(db, nil)
+	if contract := reopened.Lookup(tokenAddr); contract == nil || contract.Name != "token" || contract.Builtin {
+		t.Fatalf("registered contract mismatch: %+v", contract)
+	}
+	if err := reopened.Unregister(tokenAddr); err != nil {
+		t.Fatalf("failed to unregister ABI: %v", err)
+	}
+	if _, ok := reopened.cache[tokenAddr]; ok {
+		t.Fatalf("unregistered contract still cached")
+	}
+	if contract := NewRegistry(db, nil).Lookup(tokenAddr); contract != nil {
+		t.Fatalf("unregistered contract found: %+v", contract)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	registry := NewRegistry(database.NewMemDatabase(), nil)
+	if err := registry.Register(tokenAddr, "token", tokenABI); err != nil {
+		t.Fatalf("failed to register ABI: %v", err)
+	}
+	contract := registry.Lookup(tokenAddr)
+
+	to := common.HexToAddress("0xabc")
+	input, err := contract.ABI.Pack("transfer", to, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := contract.DecodeCall(input)
+	if err != nil {
+		t.Fatalf("failed to decode call: %v", err)
+	}
+	if call.Contract != "token" || call.Method != "transfer" || call.Signature != "transfer(address,uint256)" || len(call.Args) != 2 ||
+		call.Args[0].Value != to || call.Args[1].Value.(*hexutil.Big).ToInt().Int64() != 42 {
+		t.Fatalf("call mismatch: %+v", call)
+	}
+	if _, err := contract.DecodeCall([]byte{0x01, 0x02, 0x03, 0x04}); err != errNoMethod {
+		t.Fatalf("unknown method error mismatch: have %v, want %v", err, errNoMethod)
+	}
+
+	from := common.HexToAddress("0xdef")
+	data, err := contract.ABI.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	memo := crypto.Keccak256Hash([]byte("rent"))
+	log := &types.Log{
+		Address: tokenAddr,
+		Topics:  []common.Hash{contract.ABI.Events["Transfer"].Id(), common.BytesToHash(from.Bytes()), memo},
+		Data:    data,
+		Index:   3,
+	}
+	event, err := contract.DecodeLog(log)
+	if err != nil {
+		t.Fatalf("failed to decode log: %v", err)
+	}
+	if event.Event != "Transfer" || event.Index != 3 || len(event.Args) != 3 || event.Args[0].Value != from ||
+		event.Args[1].Value != memo || event.Args[2].Value.(*hexutil.Big).ToInt().Int64() != 7 {
+		t.Fatalf("event mismatch: %+v", event)
+	}
+	log.Topics = []common.Hash{{0x01}}
+	if _, err := contract.DecodeLog(log); err != errNoEvent {
+		t.Fatalf("unknown event error mismatch: have %v, want %v", err, errNoEvent)
+	}
+}
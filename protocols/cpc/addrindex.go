@@ -0,0 +1,248 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// addrIndexSectionSize is the number of blocks in a section of the address
+	// index.
+	addrIndexSectionSize = 512
+
+	// addrIndexConfirms is the number of confirmation blocks before a section of
+	// the address index is processed.
+	addrIndexConfirms = 64
+
+	// addrIndexThrottling is the time to wait between processing two consecutive
+	// sections of the address index.
+	addrIndexThrottling = 100 * time.Millisecond
+
+	// addrIndexMaxScan is the maximum number of unindexed blocks scanned to answer
+	// a query, bounding the work done while the index catches up.
+	addrIndexMaxScan = 4 * addrIndexSectionSize
+
+	// addrTxPageSize is the number of transactions returned per page.
+	addrTxPageSize = 100
+)
+
+var errAddrIndexSyncing = errors.New("address index is still being generated, retry with a lower block range")
+
+// AddrIndexer is a chain indexer backend maintaining the address to transaction
+// history index. A reorganised section is reprocessed by the chain indexer, in
+// which case the entries of its previous version are replaced on commit.
+type AddrIndexer struct {
+	db     database.Database
+	signer types.Signer
+
+	section uint64
+	entries map[common.Address][]rawdb.AddrTxEntry
+}
+
+// NewAddrIndexer returns a chain indexer maintaining the address index.
+func NewAddrIndexer(db database.Database, signer types.Signer) *core.ChainIndexer {
+	backend := &AddrIndexer{
+		db:     db,
+		signer: signer,
+	}
+	table := database.NewTable(db, string(rawdb.AddrTxIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, addrIndexSectionSize, addrIndexConfirms, addrIndexThrottling, "addrindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new section.
+func (b *AddrIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	b.section, b.entries = section, make(map[common.Address][]rawdb.AddrTxEntry)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the transactions of a
+// block to the section.
+func (b *AddrIndexer) Process(header *types.Header) {
+	hash, number := header.Hash(), header.Number.Uint64()
+	body := rawdb.ReadBody(b.db, hash, number)
+	if body == nil {
+		log.Error("Address index missing block body", "number", number, "hash", hash)
+		return
+	}
+	receipts := rawdb.ReadReceipts(b.db, hash, number)
+	for addr, list := range addrTxEntries(b.signer, number, body.Transactions, receipts) {
+		b.entries[addr] = append(b.entries[addr], list...)
+	}
+}
+
+// Commit implements core.ChainIndexerBackend, replacing the stored entries of
+// the section.
+func (b *AddrIndexer) Commit() error {
+	batch := b.db.NewBatch()
+	rawdb.DeleteAddrTxSection(batch, b.section, rawdb.ReadAddrTxSectionAddresses(b.db, b.section))
+	rawdb.WriteAddrTxSection(batch, b.section, b.entries)
+	return batch.Write()
+}
+
+// addrTxEntries returns the index entries of the transactions of a block, per
+// involved address.
+func addrTxEntries(signer types.Signer, number uint64, txs types.Transactions, receipts types.Receipts) map[common.Address][]rawdb.AddrTxEntry {
+	entries := make(map[common.Address][]rawdb.AddrTxEntry)
+	add := func(addr common.Address, index int, tx *types.Transaction, direction uint8) {
+		list := entries[addr]
+		if n := len(list); n > 0 && list[n-1].TxIndex == uint64(index) {
+			list[n-1].Direction |= direction
+			return
+		}
+		entries[addr] = append(list, rawdb.AddrTxEntry{
+			BlockNumber: number,
+			TxIndex:     uint64(index),
+			TxHash:      tx.Hash(),
+			Direction:   direction,
+		})
+	}
+	for i, tx := range txs {
+		if from, err := types.Sender(signer, tx); err == nil {
+			add(from, i, tx, rawdb.AddrTxSent)
+		}
+		if to := tx.To(); to != nil {
+			add(*to, i, tx, rawdb.AddrTxReceived)
+		} else if i < len(receipts) && receipts[i].ContractAddress != (common.Address{}) {
+			add(receipts[i].ContractAddress, i, tx, rawdb.AddrTxCreated)
+		}
+	}
+	return entries
+}
+
+// RPCAddressTransaction is a transaction involving a queried address.
+type RPCAddressTransaction struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Hash             common.Hash    `json:"hash"`
+	Direction        string         `json:"direction"` // "sent", "received", "self" or "created"
+}
+
+// RPCAddressTransactions is a page of the transaction history of an address.
+type RPCAddressTransactions struct {
+	Transactions []*RPCAddressTransaction `json:"transactions"`
+	Page         hexutil.Uint             `json:"page"`
+	HasMore      bool                     `json:"hasMore"`
+}
+
+// directionName returns the RPC representation of entry directions.
+func directionName(direction uint8) string {
+	switch {
+	case direction&rawdb.AddrTxCreated != 0:
+		return "created"
+	case direction == rawdb.AddrTxSent|rawdb.AddrTxReceived:
+		return "self"
+	case direction&rawdb.AddrTxSent != 0:
+		return "sent"
+	default:
+		return "received"
+	}
+}
+
+// PublicAddressIndexAPI serves the address to transaction history index.
+type PublicAddressIndexAPI struct {
+	c *CpchainService
+}
+
+// NewPublicAddressIndexAPI creates a new address index API.
+func NewPublicAddressIndexAPI(c *CpchainService) *PublicAddressIndexAPI {
+	return &PublicAddressIndexAPI{c}
+}
+
+// GetTransactionsByAddress returns a page of the canonical transactions sent
+// or received by addr, or creating the contract at addr, between fromBlock and
+// toBlock inclusive, oldest first. Pages hold up to 100 transactions and are
+// numbered from zero.
+func (api *PublicAddressIndexAPI) GetTransactionsByAddress(ctx context.Context, addr common.Address, fromBlock, toBlock rpc.BlockNumber, page hexutil.Uint) (*RPCAddressTransactions, error) {
+	head := api.c.blockchain.CurrentBlock().NumberU64()
+	resolve := func(number rpc.BlockNumber) uint64 {
+		if number < 0 || uint64(number) > head {
+			return head
+		}
+		return uint64(number)
+	}
+	from, to := resolve(fromBlock), resolve(toBlock)
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+	var (
+		skip   = uint64(page) * addrTxPageSize
+		want   = skip + addrTxPageSize + 1 // One extra to know whether more follow
+		found  []rawdb.AddrTxEntry
+		db     = api.c.chainDb
+		signer = types.MakeSigner(api.c.chainConfig)
+	)
+	collect := func(entries []rawdb.AddrTxEntry) {
+		for _, entry := range entries {
+			if entry.BlockNumber >= from && entry.BlockNumber <= to && uint64(len(found)) < want {
+				found = append(found, entry)
+			}
+		}
+	}
+	// Serve the indexed sections from the index and scan the remaining blocks
+	sections, _, _ := api.c.addrIndexer.Sections()
+	number := from
+	for ; number <= to && number/addrIndexSectionSize < sections && uint64(len(found)) < want; number = (number/addrIndexSectionSize + 1) * addrIndexSectionSize {
+		collect(rawdb.ReadAddrTxEntries(db, addr, number/addrIndexSectionSize))
+	}
+	if number <= to && uint64(len(found)) < want && to-number >= addrIndexMaxScan {
+		return nil, errAddrIndexSyncing
+	}
+	for ; number <= to && uint64(len(found)) < want; number++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		block := api.c.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			break
+		}
+		receipts := rawdb.ReadReceipts(db, block.Hash(), number)
+		collect(addrTxEntries(signer, number, block.Transactions(), receipts)[addr])
+	}
+	// Cut the requested page out of the collected entries
+	result := &RPCAddressTransactions{Transactions: []*RPCAddressTransaction{}, Page: page}
+	if uint64(len(found)) == want {
+		result.HasMore = true
+		found = found[:want-1]
+	}
+	if uint64(len(found)) <= skip {
+		return result, nil
+	}
+	for _, entry := range found[skip:] {
+		result.Transactions = append(result.Transactions, &RPCAddressTransaction{
+			BlockNumber:      hexutil.Uint64(entry.BlockNumber),
+			BlockHash:        rawdb.ReadCanonicalHash(db, entry.BlockNumber),
+			TransactionIndex: hexutil.Uint64(entry.TxIndex),
+			Hash:             entry.TxHash,
+			Direction:        directionName(entry.Direction),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAddrIndexerSections(t *testing.T) {
+	var (
+		db       = database.NewMemDatabase()
+		signer   = types.NewCep1Signer(big.NewInt(1))
+		key, _   = crypto.GenerateKey()
+		sender   = crypto.PubkeyToAddress(key.PublicKey)
+		receiver = common.Address{0x01}
+		contract = common.Address{0x02}
+		other    = common.Address{0x03}
+		backend  = &AddrIndexer{db: db, signer: signer}
+	)
+	sign := func(tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	// Index a section of two blocks: a transfer, a self transfer and a creation
+	process := func(blocks [][]*types.Transaction, receipts []types.Receipts) {
+		backend.Reset(0, common.Hash{})
+		for i, txs := range blocks {
+			header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte{byte(len(txs))}}
+			rawdb.WriteBody(db, header.Hash(), uint64(i), &types.Body{Transactions: txs})
+			rawdb.WriteReceipts(db, header.Hash(), uint64(i), receipts[i])
+			backend.Process(header)
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("failed to commit section: %v", err)
+		}
+	}
+	transfer := sign(types.NewTransaction(0, receiver, big.NewInt(1), 21000, big.NewInt(1), nil))
+	self := sign(types.NewTransaction(1, sender, big.NewInt(1), 21000, big.NewInt(1), nil))
+	create := sign(types.NewContractCreation(2, big.NewInt(0), 100000, big.NewInt(1), nil))
+	process(
+		[][]*types.Transaction{{transfer}, {self, create}},
+		[]types.Receipts{{{}}, {{}, {ContractAddress: contract}}},
+	)
+	entries := rawdb.ReadAddrTxEntries(db, sender, 0)
+	if len(entries) != 3 {
+		t.Fatalf("sender entry count mismatch: have %d, want 3", len(entries))
+	}
+	if entries[1].BlockNumber != 1 || entries[1].TxIndex != 0 || directionName(entries[1].Direction) != "self" {
+		t.Fatalf("self transfer entry mismatch: %+v", entries[1])
+	}
+	if entries := rawdb.ReadAddrTxEntries(db, contract, 0); len(entries) != 1 || entries[0].TxHash != create.Hash() || directionName(entries[0].Direction) != "created" {
+		t.Fatalf("contract entries mismatch: %+v", entries)
+	}
+	if entries := rawdb.ReadAddrTxEntries(db, receiver, 0); len(entries) != 1 || directionName(entries[0].Direction) != "received" {
+		t.Fatalf("receiver entries mismatch: %+v", entries)
+	}
+	// Reprocess the section after a reorganisation, the old entries must go
+	process(
+		[][]*types.Transaction{{sign(types.NewTransaction(0, other, big.NewInt(1), 21000, big.NewInt(1), nil))}},
+		[]types.Receipts{{{}}},
+	)
+	if entries := rawdb.ReadAddrTxEntries(db, receiver, 0); len(entries) != 0 {
+		t.Fatalf("stale receiver entries left: %+v", entries)
+	}
+	if entries := rawdb.ReadAddrTxEntries(db, sender, 0); len(entries) != 1 || directionName(entries[0].Direction) != "sent" {
+		t.Fatalf("sender entries mismatch after reorg: %+v", entries)
+	}
+}
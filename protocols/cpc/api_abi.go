@@ -0,0 +1,229 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/protocols/cpc/abiregistry"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var errUnknownABI = errors.New("no ABI known for the contract")
+
+// RPCContractABI is the ABI of a contract known to the node.
+type RPCContractABI struct {
+	Address common.Address  `json:"address"`
+	Name    string          `json:"name"`
+	ABI     json.RawMessage `json:"abi"`
+	Builtin bool            `json:"builtin"`
+}
+
+// RPCDecodedTransaction is a transaction and its receipt decoded with the ABIs
+// of the contracts involved.
+type RPCDecodedTransaction struct {
+	Hash         common.Hash        `json:"hash"`
+	BlockNumber  hexutil.Uint64     `json:"blockNumber"`
+	To           *common.Address    `json:"to"`
+	LogicAddress *common.Address    `json:"logicAddress,omitempty"` // Logic contract run behind the proxy called, if any
+	Status       hexutil.Uint64     `json:"status"`
+	Call         *abiregistry.Call  `json:"call"`                   // Decoded call, nil if unknown
+	RevertReason string             `json:"revertReason,omitempty"` // Reason of a reverted call, if given
+	Logs         []*abiregistry.Log `json:"logs"`                   // Decoded logs, the unknown ones left out
+}
+
+// RPCDecodedReceipt is a receipt decoded with the ABIs of the contracts
+// involved.
+type RPCDecodedReceipt struct {
+	TransactionHash common.Hash        `json:"transactionHash"`
+	BlockNumber     hexutil.Uint64     `json:"blockNumber"`
+	ContractAddress *common.Address    `json:"contractAddress"`        // Contract created, if any
+	LogicAddress    *common.Address    `json:"logicAddress,omitempty"` // Logic contract run behind the proxy called, if any
+	Status          hexutil.Uint64     `json:"status"`
+	GasUsed         hexutil.Uint64     `json:"gasUsed"`
+	RevertReason    string             `json:"revertReason,omitempty"` // Reason of a reverted call, if given
+	Logs            []*abiregistry.Log `json:"logs"`                   // Decoded logs, the unknown ones left out
+}
+
+// PublicABIAPI decodes calls and events with the ABIs known to the node.
+type PublicABIAPI struct {
+	c *CpchainService
+}
+
+// NewPublicABIAPI creates a new ABI decoding API.
+func NewPublicABIAPI(c *CpchainService) *PublicABIAPI {
+	return &PublicABIAPI{c}
+}
+
+// GetABI returns the ABI known for the contract at addr.
+func (api *PublicABIAPI) GetABI(addr common.Address) (*RPCContractABI, error) {
+	contract := api.c.abiRegistry.Lookup(addr)
+	if contract == nil {
+		return nil, errUnknownABI
+	}
+	return &RPCContractABI{
+		Address: addr,
+		Name:    contract.Name,
+		ABI:     json.RawMessage(contract.JSON),
+		Builtin: contract.Builtin,
+	}, nil
+}
+
+// DecodeCall decodes the input of a call of the contract at to.
+func (api *PublicABIAPI) DecodeCall(to common.Address, input hexutil.Bytes) (*abiregistry.Call, error) {
+	contract := api.c.abiRegistry.Lookup(to)
+	if contract == nil {
+		return nil, errUnknownABI
+	}
+	return contract.DecodeCall(input)
+}
+
+// DecodeTransaction decodes the call, the logs and the revert reason of a
+// mined transaction. The revert reason needs the transaction to be replayed,
+// on top of the state of its parent block.
+func (api *PublicABIAPI) DecodeTransaction(ctx context.Context, hash common.Hash) (*RPCDecodedTransaction, error) {
+	tx, receipt, err := api.decodeReceipt(hash)
+	if err != nil {
+		return nil, err
+	}
+	decoded := &RPCDecodedTransaction{
+		Hash:         hash,
+		BlockNumber:  receipt.BlockNumber,
+		To:           tx.To(),
+		LogicAddress: receipt.LogicAddress,
+		Status:       receipt.Status,
+		RevertReason: receipt.RevertReason,
+		Logs:         receipt.Logs,
+	}
+	if contract := api.callee(decoded); contract != nil {
+		decoded.Call, _ = contract.DecodeCall(tx.Data())
+	}
+	return decoded, nil
+}
+
+// DecodeReceipt decodes the logs and the revert reason of the receipt of a
+// mined transaction, as DecodeTransaction without the call.
+func (api *PublicABIAPI) DecodeReceipt(ctx context.Context, hash common.Hash) (*RPCDecodedReceipt, error) {
+	_, decoded, err := api.decodeReceipt(hash)
+	return decoded, err
+}
+
+// decodeReceipt decodes the receipt of a mined transaction, returned along
+// with it.
+func (api *PublicABIAPI) decodeReceipt(hash common.Hash) (*types.Transaction, *RPCDecodedReceipt, error) {
+	tx, blockHash, number, index := rawdb.ReadTransaction(api.c.chainDb, hash)
+	if tx == nil {
+		return nil, nil, fmt.Errorf("transaction %x not found", hash)
+	}
+	receipt, _, _, _ := rawdb.ReadReceipt(api.c.chainDb, hash)
+	if receipt == nil {
+		return nil, nil, fmt.Errorf("receipt of transaction %x not found", hash)
+	}
+	decoded := &RPCDecodedReceipt{
+		TransactionHash: hash,
+		BlockNumber:     hexutil.Uint64(number),
+		Status:          hexutil.Uint64(receipt.Status),
+		GasUsed:         hexutil.Uint64(receipt.GasUsed),
+		Logs:            api.DecodeLogs(receipt.Logs),
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		created := receipt.ContractAddress
+		decoded.ContractAddress = &created
+	}
+	if receipt.LogicAddress != (common.Address{}) {
+		logic := receipt.LogicAddress
+		decoded.LogicAddress = &logic
+	}
+	if receipt.Status == types.ReceiptStatusFailed && tx.To() != nil && !tx.IsPrivate() {
+		ret, err := api.replay(blockHash, int(index))
+		if err != nil {
+			return nil, nil, err
+		}
+		decoded.RevertReason = unpackRevertReason(ret)
+	}
+	return tx, decoded, nil
+}
+
+// DecodeLogs decodes the logs of the contracts with a known ABI, such as the
+// ones returned by the log filters, leaving the others out.
+func (api *PublicABIAPI) DecodeLogs(logs []*types.Log) []*abiregistry.Log {
+	decoded := []*abiregistry.Log{}
+	for _, log := range logs {
+		if contract := api.c.abiRegistry.Lookup(log.Address); contract != nil {
+			if event, err := contract.DecodeLog(log); err == nil {
+				decoded = append(decoded, event)
+			}
+		}
+	}
+	return decoded
+}
+
+// callee returns the ABI of the contract called by a transaction, that of the
+// logic contract if the proxy called is unknown.
+func (api *PublicABIAPI) callee(tx *RPCDecodedTransaction) *abiregistry.Contract {
+	if tx.To == nil {
+		return nil
+	}
+	if contract := api.c.abiRegistry.Lookup(*tx.To); contract != nil {
+		return contract
+	}
+	if tx.LogicAddress != nil {
+		return api.c.abiRegistry.Lookup(*tx.LogicAddress)
+	}
+	return nil
+}
+
+// replay executes a transaction again on top of the state it was mined on and
+// returns its return data.
+func (api *PublicABIAPI) replay(blockHash common.Hash, index int) ([]byte, error) {
+	msg, vmctx, statedb, err := NewPrivateDebugAPI(api.c.chainConfig, api.c).computeTxEnv(blockHash, index, defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	vmenv := vm.NewEVM(vmctx, statedb, api.c.chainConfig, vm.Config{})
+	ret, _, _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+	return ret, err
+}
+
+// PrivateABIAPI registers the ABIs of the user contracts.
+type PrivateABIAPI struct {
+	c *CpchainService
+}
+
+// NewPrivateABIAPI creates a new ABI registration API.
+func NewPrivateABIAPI(c *CpchainService) *PrivateABIAPI {
+	return &PrivateABIAPI{c}
+}
+
+// RegisterABI registers the JSON ABI of the contract at addr under name,
+// replacing the one registered before if any.
+func (api *PrivateABIAPI) RegisterABI(addr common.Address, name string, abi string) error {
+	return api.c.abiRegistry.Register(addr, name, abi)
+}
+
+// UnregisterABI removes the ABI registered for the contract at addr.
+func (api *PrivateABIAPI) UnregisterABI(addr common.Address) error {
+	return api.c.abiRegistry.Unregister(addr)
+}
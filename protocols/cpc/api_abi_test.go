@@ -0,0 +1,102 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/protocols/cpc/abiregistry"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const transferABI = `[
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"to","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false}]}
+]`
+
+func TestDecodeReceipt(t *testing.T) {
+	var (
+		db       = database.NewMemDatabase()
+		registry = abiregistry.NewRegistry(db, nil)
+		api      = NewPublicABIAPI(&CpchainService{chainDb: db, abiRegistry: registry})
+		token    = common.Address{0x01}
+		created  = common.Address{0x02}
+		to       = common.Address{0x03}
+	)
+	if err := registry.Register(token, "token", transferABI); err != nil {
+		t.Fatalf("failed to register ABI: %v", err)
+	}
+	contract := registry.Lookup(token)
+	input, err := contract.ABI.Pack("transfer", to, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := contract.ABI.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A block with a token transfer and a contract creation
+	txs := []*types.Transaction{
+		types.NewTransaction(0, token, new(big.Int), 100000, new(big.Int), input),
+		types.NewContractCreation(1, new(big.Int), 100000, new(big.Int), nil),
+	}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful, GasUsed: 30000, Logs: []*types.Log{
+			{Address: token, Topics: []common.Hash{contract.ABI.Events["Transfer"].Id(), common.BytesToHash(to.Bytes())}, Data: data},
+			{Address: to, Topics: []common.Hash{{0x01}}},
+		}},
+		{Status: types.ReceiptStatusSuccessful, GasUsed: 50000, ContractAddress: created},
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(5)}, txs, receipts)
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteTxLookupEntries(db, block)
+	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts)
+
+	receipt, err := api.DecodeReceipt(context.Background(), txs[0].Hash())
+	if err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if receipt.BlockNumber != 5 || receipt.GasUsed != 30000 || receipt.ContractAddress != nil || len(receipt.Logs) != 1 ||
+		receipt.Logs[0].Event != "Transfer" || receipt.Logs[0].Args[1].Value.(*hexutil.Big).ToInt().Int64() != 42 {
+		t.Fatalf("decoded receipt mismatch: %+v", receipt)
+	}
+	tx, err := api.DecodeTransaction(context.Background(), txs[0].Hash())
+	if err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if tx.Call == nil || tx.Call.Method != "transfer" || len(tx.Logs) != 1 {
+		t.Fatalf("decoded transaction mismatch: %+v", tx)
+	}
+
+	receipt, err = api.DecodeReceipt(context.Background(), txs[1].Hash())
+	if err != nil {
+		t.Fatalf("failed to decode receipt: %v", err)
+	}
+	if receipt.ContractAddress == nil || *receipt.ContractAddress != created || len(receipt.Logs) != 0 {
+		t.Fatalf("decoded creation receipt mismatch: %+v", receipt)
+	}
+	if _, err := api.DecodeReceipt(context.Background(), common.Hash{0x01}); err == nil {
+		t.Fatalf("unknown transaction decoded")
+	}
+}
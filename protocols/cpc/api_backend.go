@@ -42,6 +42,7 @@ import (
 var (
 	errNilBlock             = errors.New("nil block")
 	errInvalidProposersList = errors.New("invalid proposers list")
+	errSchedulerDisabled    = errors.New("transaction scheduling is disabled")
 )
 
 // APIBackend implements cpcapi.Backend for full nodes
@@ -176,6 +177,27 @@ func (b *APIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) er
 	return b.cpc.txPool.AddLocal(signedTx)
 }
 
+func (b *APIBackend) ScheduleTx(ctx context.Context, signedTx *types.Transaction, cond core.TxCondition) error {
+	if b.cpc.txScheduler == nil {
+		return errSchedulerDisabled
+	}
+	return b.cpc.txScheduler.Schedule(signedTx, cond)
+}
+
+func (b *APIBackend) ScheduledTxs() []*core.ScheduledTx {
+	if b.cpc.txScheduler == nil {
+		return nil
+	}
+	return b.cpc.txScheduler.Scheduled()
+}
+
+func (b *APIBackend) CancelScheduledTx(hash common.Hash) bool {
+	if b.cpc.txScheduler == nil {
+		return false
+	}
+	return b.cpc.txScheduler.Cancel(hash)
+}
+
 func (b *APIBackend) GetPoolTransactions() (types.Transactions, error) {
 	pending, err := b.cpc.txPool.Pending()
 	if err != nil {
@@ -237,6 +259,14 @@ func (b *APIBackend) BloomStatus() (uint64, uint64) {
 	return configs.BloomBitsBlocks, sections
 }
 
+func (b *APIBackend) LogIndexStatus() (uint64, uint64) {
+	if b.cpc.logIndexer == nil {
+		return logIndexSectionSize, 0
+	}
+	sections, _, _ := b.cpc.logIndexer.Sections()
+	return logIndexSectionSize, sections
+}
+
 func (b *APIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
 	for i := 0; i < bloomFilterThreads; i++ {
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.cpc.bloomRequests)
@@ -0,0 +1,345 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// maxBundleSize is the maximum number of transactions simulated at once.
+	maxBundleSize = 256
+
+	// defaultSimulateTimeout is the amount of time a bundle can execute before
+	// being forcefully aborted.
+	defaultSimulateTimeout = 5 * time.Second
+)
+
+var (
+	errEmptyBundle    = errors.New("empty bundle")
+	errBundleTooLarge = fmt.Errorf("bundle exceeds %d transactions", maxBundleSize)
+)
+
+// revertSelector is the selector of the Error(string) revert reason.
+var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// BundleTxArgs is a transaction of a simulated bundle. If Raw is set, it holds
+// a signed transaction and all the other fields are ignored. Otherwise the
+// transaction is executed unsigned on behalf of From, using the sender's
+// current nonce and, if Gas is not set, the remaining block gas capped by what
+// the sender can pay for.
+type BundleTxArgs struct {
+	Raw      hexutil.Bytes   `json:"raw"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+}
+
+// OverrideAccount specifies the fields of an account replaced before a bundle
+// is simulated. Storage slots not listed keep their values.
+type OverrideAccount struct {
+	Nonce   *hexutil.Uint64             `json:"nonce"`
+	Code    *hexutil.Bytes              `json:"code"`
+	Balance *hexutil.Big                `json:"balance"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// StateOverride is the set of accounts overridden before a bundle is simulated.
+type StateOverride map[common.Address]OverrideAccount
+
+// apply writes the overrides into the state.
+func (overrides StateOverride) apply(statedb *state.StateDB) {
+	for addr, account := range overrides {
+		if account.Nonce != nil {
+			statedb.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			statedb.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			statedb.SetBalance(addr, account.Balance.ToInt())
+		}
+		for key, value := range account.Storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+}
+
+// SimulatedTx is the outcome of one transaction of a simulated bundle.
+type SimulatedTx struct {
+	Hash            *common.Hash    `json:"hash,omitempty"` // Only set for signed transactions
+	From            common.Address  `json:"from"`
+	To              *common.Address `json:"to"`
+	ContractAddress *common.Address `json:"contractAddress,omitempty"`
+	GasUsed         hexutil.Uint64  `json:"gasUsed"`
+	Status          hexutil.Uint    `json:"status"`
+	ReturnValue     hexutil.Bytes   `json:"returnValue"`
+	RevertReason    string          `json:"revertReason,omitempty"`
+	Error           string          `json:"error,omitempty"` // Set if the transaction could not be applied at all
+	Logs            []*types.Log    `json:"logs"`
+}
+
+// ValueDiff is the change of an account balance.
+type ValueDiff struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+// NonceDiff is the change of an account nonce.
+type NonceDiff struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// CodeDiff is the change of an account code.
+type CodeDiff struct {
+	From hexutil.Bytes `json:"from"`
+	To   hexutil.Bytes `json:"to"`
+}
+
+// StorageDiff is the change of a storage slot.
+type StorageDiff struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// AccountDiff lists the changed fields of an account. Unchanged fields are nil.
+type AccountDiff struct {
+	Balance *ValueDiff                  `json:"balance,omitempty"`
+	Nonce   *NonceDiff                  `json:"nonce,omitempty"`
+	Code    *CodeDiff                   `json:"code,omitempty"`
+	Storage map[common.Hash]StorageDiff `json:"storage,omitempty"`
+}
+
+// BundleResult is the outcome of a simulated bundle.
+type BundleResult struct {
+	BlockNumber  hexutil.Uint64                  `json:"blockNumber"`
+	BlockHash    common.Hash                     `json:"blockHash"`
+	GasUsed      hexutil.Uint64                  `json:"gasUsed"`
+	Transactions []*SimulatedTx                  `json:"transactions"`
+	StateDiff    map[common.Address]*AccountDiff `json:"stateDiff"`
+}
+
+// PublicSimulationAPI executes transaction bundles against the local state
+// without submitting them.
+type PublicSimulationAPI struct {
+	c *CpchainService
+}
+
+// NewPublicSimulationAPI creates a new simulation API.
+func NewPublicSimulationAPI(c *CpchainService) *PublicSimulationAPI {
+	return &PublicSimulationAPI{c}
+}
+
+// SimulateBundle executes the transactions in order on top of the state of the
+// given block, after applying the optional state overrides. A transaction that
+// cannot be applied, e.g. because of a bad nonce, is reported and skipped; the
+// following ones still run. The resulting state diff is relative to the state
+// after the overrides.
+func (api *PublicSimulationAPI) SimulateBundle(ctx context.Context, txs []BundleTxArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (*BundleResult, error) {
+	if len(txs) == 0 {
+		return nil, errEmptyBundle
+	}
+	if len(txs) > maxBundleSize {
+		return nil, errBundleTooLarge
+	}
+	statedb, header, err := api.c.APIBackend.StateAndHeaderByNumber(ctx, blockNr, false)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if overrides != nil {
+		overrides.apply(statedb)
+	}
+	statedb.Finalise(true)
+	base := statedb.Copy()
+
+	ctx, cancel := context.WithTimeout(ctx, defaultSimulateTimeout)
+	defer cancel()
+
+	var (
+		signer = types.MakeSigner(api.c.chainConfig)
+		gp     = new(core.GasPool).AddGas(header.GasLimit)
+		result = &BundleResult{
+			BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+			BlockHash:   header.Hash(),
+		}
+	)
+	for i, args := range txs {
+		msg, hash, err := api.bundleMessage(args, signer, statedb, gp)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %v", i, err)
+		}
+		simulated := &SimulatedTx{From: msg.From(), To: msg.To(), Logs: []*types.Log{}}
+		if hash != (common.Hash{}) {
+			simulated.Hash = &hash
+		}
+		// Logs are recorded under the real hash, or a per-index placeholder
+		logKey := hash
+		if logKey == (common.Hash{}) {
+			binary.BigEndian.PutUint64(logKey[common.HashLength-8:], uint64(i+1))
+		}
+		statedb.Prepare(logKey, common.Hash{}, i)
+
+		vmctx := core.NewEVMContext(msg, header, api.c.blockchain, nil)
+		vmenv := vm.NewEVM(vmctx, statedb, api.c.chainConfig, vm.Config{})
+		stop := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				vmenv.Cancel()
+			case <-stop:
+			}
+		}()
+		ret, gas, failed, err := core.ApplyMessage(vmenv, msg, gp)
+		close(stop)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("simulation aborted (timeout = %v)", defaultSimulateTimeout)
+		}
+		if err != nil {
+			simulated.Error = err.Error()
+			result.Transactions = append(result.Transactions, simulated)
+			continue
+		}
+		statedb.Finalise(true)
+
+		simulated.GasUsed = hexutil.Uint64(gas)
+		simulated.ReturnValue = ret
+		if failed {
+			simulated.Status = hexutil.Uint(types.ReceiptStatusFailed)
+			simulated.RevertReason = unpackRevertReason(ret)
+		} else {
+			simulated.Status = hexutil.Uint(types.ReceiptStatusSuccessful)
+			if msg.To() == nil {
+				addr := crypto.CreateAddress(msg.From(), msg.Nonce())
+				simulated.ContractAddress = &addr
+			}
+		}
+		for _, log := range statedb.GetLogs(logKey) {
+			log.BlockNumber = header.Number.Uint64()
+			simulated.Logs = append(simulated.Logs, log)
+		}
+		result.GasUsed += hexutil.Uint64(gas)
+		result.Transactions = append(result.Transactions, simulated)
+	}
+	result.StateDiff = diffState(base, statedb)
+	return result, nil
+}
+
+// bundleMessage converts a bundle transaction into a message. The hash is
+// only returned for signed transactions.
+func (api *PublicSimulationAPI) bundleMessage(args BundleTxArgs, signer types.Signer, statedb *state.StateDB, gp *core.GasPool) (types.Message, common.Hash, error) {
+	if len(args.Raw) > 0 {
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(args.Raw, tx); err != nil {
+			return types.Message{}, common.Hash{}, err
+		}
+		if tx.IsPrivate() {
+			return types.Message{}, common.Hash{}, types.ErrNotSupportedTxType
+		}
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			return types.Message{}, common.Hash{}, err
+		}
+		return msg, tx.Hash(), nil
+	}
+	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
+	if gasPrice.Sign() == 0 {
+		gasPrice = new(big.Int).Set(api.c.gasPrice)
+	}
+	if gas == 0 {
+		gas = gp.Gas()
+		// Cap the gas to what the sender can pay for, the unused gas going
+		// back to the pool. Senders unable to pay anything fail as usual.
+		funds := new(big.Int).Sub(statedb.GetBalance(args.From), args.Value.ToInt())
+		if gasPrice.Sign() > 0 && funds.Sign() > 0 {
+			if allowance := funds.Div(funds, gasPrice); allowance.IsUint64() && allowance.Uint64() < gas {
+				gas = allowance.Uint64()
+			}
+		}
+	}
+	nonce := statedb.GetNonce(args.From)
+	return types.NewMessage(args.From, args.To, nonce, args.Value.ToInt(), gas, gasPrice, args.Data, false), common.Hash{}, nil
+}
+
+// unpackRevertReason decodes the Error(string) message of reverted return
+// data, returning an empty string if it holds none.
+func unpackRevertReason(ret []byte) string {
+	if len(ret) < 4+64 || !bytes.Equal(ret[:4], revertSelector) {
+		return ""
+	}
+	data := ret[4:]
+	// the bounds are compared without adding, as the contract controls the
+	// offset and size words and may overflow them
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return ""
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[offset.Uint64():start])
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start {
+		return ""
+	}
+	return string(data[start : start+size.Uint64()])
+}
+
+// diffState compares the accounts touched in post with their values in pre.
+func diffState(pre, post *state.StateDB) map[common.Address]*AccountDiff {
+	diffs := make(map[common.Address]*AccountDiff)
+	for addr, keys := range post.Touched() {
+		diff := new(AccountDiff)
+		if from, to := pre.GetBalance(addr), post.GetBalance(addr); from.Cmp(to) != 0 {
+			diff.Balance = &ValueDiff{From: (*hexutil.Big)(from), To: (*hexutil.Big)(to)}
+		}
+		if from, to := pre.GetNonce(addr), post.GetNonce(addr); from != to {
+			diff.Nonce = &NonceDiff{From: hexutil.Uint64(from), To: hexutil.Uint64(to)}
+		}
+		if from, to := pre.GetCode(addr), post.GetCode(addr); !bytes.Equal(from, to) {
+			diff.Code = &CodeDiff{From: from, To: to}
+		}
+		for _, key := range keys {
+			if from, to := pre.GetState(addr, key), post.GetState(addr, key); from != to {
+				if diff.Storage == nil {
+					diff.Storage = make(map[common.Hash]StorageDiff)
+				}
+				diff.Storage[key] = StorageDiff{From: from, To: to}
+			}
+		}
+		if diff.Balance != nil || diff.Nonce != nil || diff.Code != nil || diff.Storage != nil {
+			diffs[addr] = diff
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestSimulationStateDiff(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+		addr       = common.Address{0x01}
+		other      = common.Address{0x02}
+		balance    = hexutil.Big(*big.NewInt(1000))
+	)
+	statedb.SetNonce(addr, 1)
+	statedb.SetState(addr, common.Hash{0x01}, common.Hash{0x01})
+	statedb.SetNonce(other, 3)
+	statedb.Finalise(true)
+
+	StateOverride{addr: {Balance: &balance, Storage: map[common.Hash]common.Hash{{0x02}: {0x02}}}}.apply(statedb)
+	statedb.Finalise(true)
+	if statedb.GetBalance(addr).Int64() != 1000 || statedb.GetState(addr, common.Hash{0x02}) != (common.Hash{0x02}) {
+		t.Fatalf("overrides not applied")
+	}
+	base := statedb.Copy()
+
+	statedb.SubBalance(addr, big.NewInt(10))
+	statedb.SetState(addr, common.Hash{0x01}, common.Hash{0x05})
+	statedb.GetState(addr, common.Hash{0x02}) // read only
+	statedb.SetNonce(other, 3)                // unchanged
+	statedb.Finalise(true)
+
+	diffs := diffState(base, statedb)
+	if len(diffs) != 1 {
+		t.Fatalf("diff count mismatch: have %d, want 1", len(diffs))
+	}
+	diff := diffs[addr]
+	if diff == nil || diff.Balance == nil || diff.Balance.To.ToInt().Int64() != 990 || diff.Nonce != nil || diff.Code != nil {
+		t.Fatalf("account diff mismatch: %+v", diff)
+	}
+	if len(diff.Storage) != 1 || diff.Storage[common.Hash{0x01}] != (StorageDiff{From: common.Hash{0x01}, To: common.Hash{0x05}}) {
+		t.Fatalf("storage diff mismatch: %v", diff.Storage)
+	}
+}
+
+func TestBundleMessageGas(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(database.NewMemDatabase()))
+		api        = &PublicSimulationAPI{&CpchainService{gasPrice: big.NewInt(10)}}
+		rich       = common.Address{0x01}
+		poor       = common.Address{0x02}
+		empty      = common.Address{0x03}
+	)
+	statedb.AddBalance(rich, new(big.Int).Mul(big.NewInt(1000), big.NewInt(100000000)))
+	statedb.AddBalance(poor, big.NewInt(1000000))
+
+	tests := []struct {
+		args BundleTxArgs
+		want uint64
+	}{
+		{BundleTxArgs{From: rich}, 100000000},
+		{BundleTxArgs{From: poor}, 100000},
+		{BundleTxArgs{From: poor, Value: hexutil.Big(*big.NewInt(500000))}, 50000},
+		{BundleTxArgs{From: poor, GasPrice: hexutil.Big(*big.NewInt(1000))}, 1000},
+		{BundleTxArgs{From: poor, Gas: 300000}, 300000},
+		{BundleTxArgs{From: empty}, 100000000},
+	}
+	for i, tt := range tests {
+		msg, _, err := api.bundleMessage(tt.args, nil, statedb, new(core.GasPool).AddGas(100000000))
+		if err != nil {
+			t.Fatalf("test %d: failed to convert transaction: %v", i, err)
+		}
+		if msg.Gas() != tt.want {
+			t.Errorf("test %d: gas mismatch: have %d, want %d", i, msg.Gas(), tt.want)
+		}
+	}
+}
+
+func TestUnpackRevertReason(t *testing.T) {
+	// Error("not allowed")
+	ret := common.FromHex("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000b" +
+		"6e6f7420616c6c6f776564000000000000000000000000000000000000000000")
+	if reason := unpackRevertReason(ret); reason != "not allowed" {
+		t.Fatalf("revert reason mismatch: have %q, want %q", reason, "not allowed")
+	}
+	if reason := unpackRevertReason(ret[:40]); reason != "" {
+		t.Fatalf("truncated revert data decoded: %q", reason)
+	}
+	if reason := unpackRevertReason([]byte{0x01, 0x02}); reason != "" {
+		t.Fatalf("custom revert data decoded: %q", reason)
+	}
+	// Length and offset words wrapping around when added to their position
+	oversized := common.FromHex("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000ffffffffffffffc0" +
+		"6e6f7420616c6c6f776564000000000000000000000000000000000000000000")
+	if reason := unpackRevertReason(oversized); reason != "" {
+		t.Fatalf("oversized revert reason decoded: %q", reason)
+	}
+	misplaced := common.FromHex("0x08c379a0" +
+		"000000000000000000000000000000000000000000000000ffffffffffffffff" +
+		"000000000000000000000000000000000000000000000000000000000000000b" +
+		"6e6f7420616c6c6f776564000000000000000000000000000000000000000000")
+	if reason := unpackRevertReason(misplaced); reason != "" {
+		t.Fatalf("misplaced revert reason decoded: %q", reason)
+	}
+}
@@ -637,6 +637,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			Failed:      failed,
 			ReturnValue: fmt.Sprintf("%x", ret),
 			StructLogs:  cpcapi.FormatLogs(tracer.StructLogs()),
+			Proxies:     tracer.ProxyRedirects(),
 		}, nil
 
 	case *tracers.Tracer:
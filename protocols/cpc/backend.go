@@ -18,13 +18,16 @@
 package cpc
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"sync"
 	"sync/atomic"
 
 	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
 	"bitbucket.org/cpchain/chain/admission"
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/commons/log"
@@ -41,8 +44,11 @@ import (
 	"bitbucket.org/cpchain/chain/miner"
 	"bitbucket.org/cpchain/chain/node"
 	"bitbucket.org/cpchain/chain/private"
+	"bitbucket.org/cpchain/chain/protocols/cpc/abiregistry"
 	"bitbucket.org/cpchain/chain/protocols/cpc/filters"
 	"bitbucket.org/cpchain/chain/protocols/cpc/gasprice"
+	"bitbucket.org/cpchain/chain/protocols/cpc/pdash"
+	"bitbucket.org/cpchain/chain/protocols/cpc/rewards"
 	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -53,6 +59,7 @@ import (
 var (
 	errForbidValidatorMining = errors.New("Validator is forbidden to mine.")
 	errNotAdmissionKey       = errors.New("Admission key is missing, need to run with --mine flag.")
+	errUnknownBlock          = errors.New("unknown block")
 )
 
 type LesServer interface {
@@ -72,6 +79,7 @@ type CpchainService struct {
 
 	// Handlers
 	txPool          *core.TxPool
+	txScheduler     *core.TxScheduler
 	blockchain      *core.BlockChain
 	protocolManager *ProtocolManager
 	lesServer       LesServer
@@ -87,6 +95,12 @@ type CpchainService struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // LogsBloom indexer operating during block imports
+	addrIndexer   *core.ChainIndexer             // Address to transaction history indexer, nil if disabled
+	logIndexer    *core.ChainIndexer             // Exact log indexer, nil if disabled
+	chainFreezer  *chainFreezer                  // Mover of old blocks to the ancient store, nil if disabled
+	rewards       *rewards.Service               // Accounting and scheduling of the reward contract
+	pdashIndexer  *pdash.Indexer                 // Pdash order indexer, nil if disabled
+	abiRegistry   *abiregistry.Registry          // ABIs of the contracts decoded for the RPC output
 
 	// chain service backend
 	APIBackend          *APIBackend
@@ -165,13 +179,21 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 	cpc.APIBackend.gpo = gasprice.NewOracle(cpc.APIBackend, gpoParams)
 
 	contractAddrs := configs.ChainConfigInfo().Dpor.Contracts
+	// a developer chain, or a network bootstrapped from a genesis of its own,
+	// has the system contracts of its own
+	if config.Dev || config.Genesis != nil {
+		contractAddrs = chainConfig.Dpor.Contracts
+	}
 
 	contractClient := cpcapi.NewPublicBlockChainAPI(cpc.APIBackend)
 	primitive_backend.GetApiBackendHolderInstance().Init(cpc.APIBackend, contractClient)
 	if dpor, ok := cpc.engine.(*dpor.Dpor); ok {
 		dpor.SetCampaignBackend(contractAddrs[configs.ContractCampaign], primitive_backend.GetChainClient())
-		dpor.SetRptBackend(contractAddrs[configs.ContractRpt], primitive_backend.GetChainClient())
+		dpor.SetRptBackend(chainConfig, contractAddrs[configs.ContractRpt], primitive_backend.GetChainClient())
 		dpor.SetRNodeBackend(contractAddrs[configs.ContractRnode], primitive_backend.GetChainClient())
+		if registry := contractAddrs[configs.ContractValidators]; registry != (common.Address{}) {
+			dpor.SetValidatorBackend(registry, primitive_backend.GetChainClient(), cpc.stateCaller)
+		}
 	}
 
 	log.Info("Initialising cpchain protocol", "versions", ProtocolVersions, "network", config.NetworkId)
@@ -201,6 +223,16 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 		contractAddrs[configs.ContractCampaign],
 		contractAddrs[configs.ContractRnode],
 		contractAddrs[configs.ContractNetwork])
+	cpc.rewards = rewards.NewService(contractAddrs[configs.ContractReward])
+
+	builtins := map[string]common.Address{abiregistry.ContractPdash: config.PdashContract}
+	for name, addr := range contractAddrs {
+		builtins[name] = addr
+	}
+	if chainConfig.Dpor != nil {
+		builtins[abiregistry.ContractProxyRegister] = chainConfig.Dpor.ProxyContractRegister
+	}
+	cpc.abiRegistry = abiregistry.NewRegistry(chainDb, builtins)
 
 	if dpor, ok := cpc.engine.(*dpor.Dpor); ok {
 		dpor.SetupAdmission(cpc.AdmissionApiBackend)
@@ -214,12 +246,33 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
 	cpc.bloomIndexer.Start(cpc.blockchain)
+	if config.AddressIndex {
+		cpc.addrIndexer = NewAddrIndexer(chainDb, types.MakeSigner(chainConfig))
+		cpc.addrIndexer.Start(cpc.blockchain)
+	}
+	if config.LogIndex {
+		cpc.logIndexer = NewLogIndexer(chainDb)
+		cpc.logIndexer.Start(cpc.blockchain)
+	}
+	if config.PdashContract != (common.Address{}) {
+		cpc.pdashIndexer = pdash.NewIndexer(config.PdashContract, cpc.blockchain)
+		cpc.pdashIndexer.Start()
+	}
+	if config.AncientDepth > 0 && rawdb.AncientStore(chainDb) != nil {
+		cpc.chainFreezer = newChainFreezer(chainDb, cpc.blockchain, config.AncientDepth)
+		cpc.chainFreezer.Start()
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
 	cpc.txPool = core.NewTxPool(config.TxPool, cpc.chainConfig, cpc.blockchain)
 
+	if !config.TxPool.NoLocals && config.TxPool.Schedule != "" {
+		config.TxPool.Schedule = ctx.ResolvePath(config.TxPool.Schedule)
+		cpc.txScheduler = core.NewTxScheduler(config.TxPool.Schedule, cpc.blockchain, chainDb, cpc.txPool.AddLocal)
+	}
+
 	if cpc.protocolManager, err = NewProtocolManager(cpc.chainConfig, config.NetworkId, cpc.eventMux, cpc.txPool, cpc.engine, cpc.blockchain, chainDb, cpc.coinbase, config.SyncMode); err != nil {
 		return nil, err
 	}
@@ -229,13 +282,27 @@ func New(ctx *node.ServiceContext, config *Config) (*CpchainService, error) {
 	return cpc, nil
 }
 
-// CreateDB creates the chain database.
+// CreateDB creates the chain database. The ancient store is opened along if
+// blocks are moved there or were moved there before.
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) (database.Database, error) {
 	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
 	if err != nil {
 		return nil, err
 	}
-	return db, nil
+	path := ctx.ResolvePath(name)
+	if path == "" {
+		return db, nil
+	}
+	ancients := rawdb.AncientPath(path)
+	if _, err := os.Stat(ancients); config.AncientDepth == 0 && os.IsNotExist(err) {
+		return db, nil
+	}
+	fdb, err := rawdb.NewDatabaseWithFreezer(db, ancients)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return fdb, nil
 }
 
 // SetAsMiner sets dpor engine as miner
@@ -262,14 +329,18 @@ func (s *CpchainService) CreateConsensusEngine(ctx *node.ServiceContext, chainCo
 	}
 	// If Dpor is requested, set it up
 	if chainConfig.Dpor != nil {
-		dpor := dpor.New(chainConfig.Dpor, db)
+		newDpor := dpor.New
+		if s.config.Dev {
+			newDpor = dpor.NewDevSealer
+		}
+		dpor := newDpor(chainConfig.Dpor, db)
 		if eb != (common.Address{}) {
 			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
 			if wallet == nil || err != nil {
 				log.Error("Coinbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpor.Authorize(eb, wallet.SignHash)
+			dpor.AuthorizeWallet(eb, wallet)
 		}
 		return dpor
 	}
@@ -287,6 +358,37 @@ func (s *CpchainService) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the admission control
 	apis = append(apis, s.AdmissionApiBackend.Apis()...)
 
+	// Append the reward accounting API
+	apis = append(apis, s.rewards.APIs()...)
+
+	// Append the pdash order API if the orders are indexed
+	if s.pdashIndexer != nil {
+		apis = append(apis, s.pdashIndexer.APIs()...)
+	}
+
+	// Append the contract ABI APIs
+	apis = append(apis, rpc.API{
+		Namespace: "abi",
+		Version:   "1.0",
+		Service:   NewPublicABIAPI(s),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "abi",
+		Version:   "1.0",
+		Service:   NewPrivateABIAPI(s),
+		Public:    false,
+	})
+
+	// Append the address index API if the index is maintained
+	if s.addrIndexer != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicAddressIndexAPI(s),
+			Public:    true,
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -301,6 +403,12 @@ func (s *CpchainService) APIs() []rpc.API {
 			Service:   NewPublicMinerAPI(s),
 			Public:    true,
 		},
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicSimulationAPI(s),
+			Public:    true,
+		},
 		{
 			Namespace: "eth",
 			Version:   "1.0",
@@ -401,7 +509,7 @@ func (s *CpchainService) StartMining(local bool) error {
 				log.Error("Etherbase account unavailable locally", "err", err)
 				return nil
 			}
-			dpor.Authorize(coinbase, wallet.SignHash)
+			dpor.AuthorizeWallet(coinbase, wallet)
 		}
 
 		log.Debug("server.nodeid", "enode", s.server.NodeInfo().Enode)
@@ -444,6 +552,29 @@ func (s *CpchainService) SetupValidator() error {
 	return nil
 }
 
+// StartRewards starts serving the accounting of the reward contract from
+// backend, driving its period transitions with key as well if configured to.
+func (s *CpchainService) StartRewards(backend rewards.Backend, key *ecdsa.PrivateKey) error {
+	if !s.config.RewardSchedule {
+		key = nil
+	}
+	return s.rewards.Start(backend, key)
+}
+
+// stateCaller returns a contract caller executing calls on the state of the
+// block with given hash and number.
+func (s *CpchainService) stateCaller(hash common.Hash, number uint64) (bind.ContractCaller, error) {
+	header := s.blockchain.GetHeader(hash, number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	statedb, err := s.blockchain.StateAt(header.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return core.NewStateCaller(s.blockchain, s.blockchain.Config(), header, statedb), nil
+}
+
 func (s *CpchainService) StopMining() {
 	if !s.IsMining() {
 		return
@@ -472,6 +603,7 @@ func (s *CpchainService) Miner() *miner.Miner { return s.miner }
 func (s *CpchainService) AccountManager() *accounts.Manager { return s.accountManager }
 func (s *CpchainService) BlockChain() *core.BlockChain      { return s.blockchain }
 func (s *CpchainService) TxPool() *core.TxPool              { return s.txPool }
+func (s *CpchainService) TxScheduler() *core.TxScheduler    { return s.txScheduler }
 func (s *CpchainService) EventMux() *event.TypeMux          { return s.eventMux }
 func (s *CpchainService) Engine() consensus.Engine          { return s.engine }
 func (s *CpchainService) ChainDb() database.Database        { return s.chainDb }
@@ -521,12 +653,28 @@ func (s *CpchainService) Start(srvr *p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // cpchain protocol.
 func (s *CpchainService) Stop() error {
+	if s.chainFreezer != nil {
+		s.chainFreezer.Stop()
+	}
 	s.bloomIndexer.Close()
+	if s.addrIndexer != nil {
+		s.addrIndexer.Close()
+	}
+	if s.logIndexer != nil {
+		s.logIndexer.Close()
+	}
+	if s.pdashIndexer != nil {
+		s.pdashIndexer.Stop()
+	}
+	s.rewards.Stop()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
 		s.lesServer.Stop()
 	}
+	if s.txScheduler != nil {
+		s.txScheduler.Stop()
+	}
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.eventMux.Stop()
@@ -103,6 +103,12 @@ func (pm *ProtocolManager) minedBroadcastLoop() {
 
 				// broadcast mined block with dpor handler
 				pm.engine.(*dpor.Dpor).HandleMinedBlock(ev.Block)
+			} else if pm.chainconfig.Dpor != nil && pm.engine.(*dpor.Dpor).Mode() == dpor.DevMode {
+
+				// the dev sealer has signed the block as the validators committee, insert it directly
+				if _, err := pm.blockchain.InsertChain(types.Blocks{ev.Block}); err != nil {
+					log.Warn("failed to insert dev sealed block", "number", ev.Block.NumberU64(), "hash", ev.Block.Hash().Hex(), "err", err)
+				}
 			} else {
 				pm.BroadcastBlock(ev.Block, true)
 			}
@@ -74,12 +74,16 @@ type Config struct {
 	DatabaseCache      int
 	TrieCache          int
 	TrieTimeout        time.Duration
+	AddressIndex       bool   // Whether to maintain the address to transaction history index
+	LogIndex           bool   // Whether to maintain the exact log index by address and first topic
+	AncientDepth       uint64 // Depth beyond which blocks are moved to the ancient store, 0 to disable
 
 	// Mining-related options
 	Cpcbase      common.Address `toml:",omitempty"`
 	MinerThreads int            `toml:",omitempty"`
 	ExtraData    []byte         `toml:",omitempty"`
 	GasPrice     *big.Int
+	Dev          bool `toml:"-"` // Whether to seal all blocks alone as a developer chain
 
 	// Transaction pool options
 	TxPool core.TxPoolConfig
@@ -96,7 +100,15 @@ type Config struct {
 	// Private Tx related configuration
 	PrivateTx private.Config
 
-	SyncMode syncer.SyncMode `toml:"-"`
+	SyncMode  syncer.SyncMode `toml:"-"`
+	LightServ bool            // Whether to serve the light clients
+
+	// Whether to drive the period transitions of the reward contract with the
+	// unlocked account, which must own the contract
+	RewardSchedule bool
+
+	// Address of the pdash contract whose orders to index, none if zero
+	PdashContract common.Address `toml:",omitempty"`
 }
 
 type configMarshaling struct {
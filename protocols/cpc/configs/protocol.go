@@ -3,4 +3,5 @@ package configs
 // Constants to match up protocol versions and messages
 const (
 	Cpc1 = 1
+	Cpc2 = 2 // adds the state range messages of snap sync
 )
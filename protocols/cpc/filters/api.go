@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -341,6 +342,84 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	return returnLogs(logs), err
 }
 
+const (
+	// defaultLogsPageSize is the number of logs in a page if none is requested.
+	defaultLogsPageSize = 1000
+
+	// maxLogsPageSize is the maximum number of logs in a page.
+	maxLogsPageSize = 10000
+)
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// LogsPage is a page of the logs matching a query.
+type LogsPage struct {
+	Logs []*types.Log `json:"logs"`
+	Next string       `json:"next,omitempty"` // Token of the next page, empty on the last page
+}
+
+// GetLogsPage returns up to limit logs matching the given argument, resuming
+// from the token of the previous page if any. Pages hold 1000 logs if no limit
+// is given and up to 10000.
+func (api *PublicFilterAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, limit hexutil.Uint, token *string) (*LogsPage, error) {
+	// Convert the RPC block numbers into internal representations
+	if crit.FromBlock == nil {
+		crit.FromBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
+	}
+	if crit.ToBlock == nil {
+		crit.ToBlock = big.NewInt(rpc.LatestBlockNumber.Int64())
+	}
+	size := int(limit)
+	if size == 0 {
+		size = defaultLogsPageSize
+	}
+	if size > maxLogsPageSize {
+		size = maxLogsPageSize
+	}
+	// Resume from the first log left out of the previous page
+	begin, skip := crit.FromBlock.Int64(), uint(0)
+	if token != nil && *token != "" {
+		number, index, err := decodePageToken(*token)
+		if err != nil {
+			return nil, err
+		}
+		if (begin >= 0 && int64(number) < begin) || (crit.ToBlock.Int64() >= 0 && int64(number) > crit.ToBlock.Int64()) {
+			return nil, errInvalidPageToken
+		}
+		begin, skip = int64(number), index
+	}
+	// Gather one log more than the page holds to know whether more follow
+	filter := New(api.backend, begin, crit.ToBlock.Int64(), crit.Addresses, crit.Topics)
+	filter.Paginate(size+1, skip)
+
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := &LogsPage{Logs: returnLogs(logs)}
+	if len(logs) > size {
+		page.Logs, page.Next = page.Logs[:size], encodePageToken(logs[size].BlockNumber, logs[size].Index)
+	}
+	return page, nil
+}
+
+// encodePageToken encodes the position of the first log of a page.
+func encodePageToken(number uint64, index uint) string {
+	token := make([]byte, 16)
+	binary.BigEndian.PutUint64(token, number)
+	binary.BigEndian.PutUint64(token[8:], uint64(index))
+	return hexutil.Encode(token)
+}
+
+// decodePageToken decodes the position of the first log of a page.
+func decodePageToken(token string) (uint64, uint, error) {
+	data, err := hexutil.Decode(token)
+	if err != nil || len(data) != 16 {
+		return 0, 0, errInvalidPageToken
+	}
+	return binary.BigEndian.Uint64(data), uint(binary.BigEndian.Uint64(data[8:])), nil
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_uninstallfilter
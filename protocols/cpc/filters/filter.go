@@ -19,10 +19,12 @@ package filters
 import (
 	"context"
 	"math/big"
+	"sort"
 
 	"bitbucket.org/cpchain/chain/api/rpc"
 	"bitbucket.org/cpchain/chain/core"
 	"bitbucket.org/cpchain/chain/core/bloombits"
+	"bitbucket.org/cpchain/chain/core/rawdb"
 	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -45,6 +47,13 @@ type Backend interface {
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
 }
 
+// LogIndexBackend is implemented by the backends maintaining the exact log
+// index, which filters on both addresses and first topics prefer over the
+// bloom bits.
+type LogIndexBackend interface {
+	LogIndexStatus() (uint64, uint64)
+}
+
 // Filter can be used to retrieve and filter logs.
 type Filter struct {
 	backend Backend
@@ -54,6 +63,10 @@ type Filter struct {
 	addresses  []common.Address
 	topics     [][]common.Hash
 
+	limit int    // Maximum number of logs to return, zero for no limit
+	skip  uint   // Index of the first log to return within the first block
+	first uint64 // First block of the filter range
+
 	matcher *bloombits.Matcher
 }
 
@@ -92,6 +105,12 @@ func New(backend Backend, begin, end int64, addresses []common.Address, topics [
 	}
 }
 
+// Paginate limits the logs returned by Logs to the given number, leaving out
+// the logs of the first block before the given index within the block.
+func (f *Filter) Paginate(limit int, skip uint) {
+	f.limit, f.skip = limit, skip
+}
+
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
@@ -105,34 +124,121 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.begin == -1 {
 		f.begin = int64(head)
 	}
+	f.first = uint64(f.begin)
+
 	end := uint64(f.end)
 	if f.end == -1 {
 		end = head
 	}
-	// Gather all indexed logs, and finish with non indexed ones
+	// Gather all indexed logs, exactly indexed first, and finish with non
+	// indexed ones
 	var (
 		logs []*types.Log
 		err  error
 	)
+	if backend, ok := f.backend.(LogIndexBackend); ok && f.exactlyIndexable() {
+		size, sections := backend.LogIndexStatus()
+		if indexed := sections * size; indexed > uint64(f.begin) {
+			if indexed > end {
+				logs, err = f.exactLogs(ctx, end, size)
+			} else {
+				logs, err = f.exactLogs(ctx, indexed-1, size)
+			}
+			if err != nil || f.full(len(logs)) {
+				return f.truncate(logs), err
+			}
+		}
+	}
 	size, sections := f.backend.BloomStatus()
-	if indexed := sections * size; indexed > uint64(f.begin) {
+	if indexed := sections * size; indexed > uint64(f.begin) && f.begin <= int64(end) {
+		var found []*types.Log
 		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
+			found, err = f.indexedLogs(ctx, end, len(logs))
 		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+			found, err = f.indexedLogs(ctx, indexed-1, len(logs))
 		}
-		if err != nil {
-			return logs, err
+		logs = append(logs, found...)
+		if err != nil || f.full(len(logs)) {
+			return f.truncate(logs), err
 		}
 	}
-	rest, err := f.unindexedLogs(ctx, end)
+	rest, err := f.unindexedLogs(ctx, end, len(logs))
 	logs = append(logs, rest...)
-	return logs, err
+	return f.truncate(logs), err
+}
+
+// exactlyIndexable returns whether the filter restricts both the addresses and
+// the first topics, the keys of the exact log index.
+func (f *Filter) exactlyIndexable() bool {
+	return len(f.addresses) > 0 && len(f.topics) > 0 && len(f.topics[0]) > 0
+}
+
+// full returns whether the given number of gathered logs reaches the limit of
+// the filter.
+func (f *Filter) full(count int) bool {
+	return f.limit > 0 && count >= f.limit
+}
+
+// truncate cuts the logs down to the limit of the filter.
+func (f *Filter) truncate(logs []*types.Log) []*types.Log {
+	if f.limit > 0 && len(logs) > f.limit {
+		return logs[:f.limit]
+	}
+	return logs
+}
+
+// exactLogs returns the logs matching the filter criteria based on the exact
+// log index, retrieving the blocks holding matching logs only.
+func (f *Filter) exactLogs(ctx context.Context, end uint64, size uint64) ([]*types.Log, error) {
+	var logs []*types.Log
+
+	for section := uint64(f.begin) / size; section <= end/size; section++ {
+		// Collect the blocks of the section holding logs of any queried key
+		blocks := make(map[uint64]bool)
+		for _, addr := range f.addresses {
+			for _, topic := range f.topics[0] {
+				for _, entry := range rawdb.ReadLogIndexEntries(f.db, rawdb.LogIndexKey{Address: addr, Topic: topic}, section) {
+					if entry.BlockNumber >= uint64(f.begin) && entry.BlockNumber <= end {
+						blocks[entry.BlockNumber] = true
+					}
+				}
+			}
+		}
+		numbers := make([]uint64, 0, len(blocks))
+		for number := range blocks {
+			numbers = append(numbers, number)
+		}
+		sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+		// Pull the matching logs, the index only telling where to look
+		for _, number := range numbers {
+			if err := ctx.Err(); err != nil {
+				return logs, err
+			}
+			header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+			if header == nil || err != nil {
+				return logs, err
+			}
+			found, err := f.checkMatches(ctx, header)
+			if err != nil {
+				return logs, err
+			}
+			logs = append(logs, found...)
+			f.begin = int64(number) + 1
+
+			if f.full(len(logs)) {
+				return logs, nil
+			}
+		}
+		f.begin = int64((section + 1) * size)
+	}
+	f.begin = int64(end) + 1
+	return logs, nil
 }
 
 // indexedLogs returns the logs matching the filter criteria based on the bloom
 // bits indexed available locally or via the network.
-func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+func (f *Filter) indexedLogs(ctx context.Context, end uint64, gathered int) ([]*types.Log, error) {
 	// Create a matcher session and request servicing from the backend
 	matches := make(chan uint64, 64)
 
@@ -171,6 +277,10 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 			}
 			logs = append(logs, found...)
 
+			if f.full(len(logs) + gathered) {
+				return logs, nil
+			}
+
 		case <-ctx.Done():
 			return logs, ctx.Err()
 		}
@@ -179,10 +289,10 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*types.Log, err
 
 // indexedLogs returns the logs matching the filter criteria based on raw block
 // iteration and bloom matching.
-func (f *Filter) unindexedLogs(ctx context.Context, end uint64) ([]*types.Log, error) {
+func (f *Filter) unindexedLogs(ctx context.Context, end uint64, gathered int) ([]*types.Log, error) {
 	var logs []*types.Log
 
-	for ; f.begin <= int64(end); f.begin++ {
+	for ; f.begin <= int64(end) && !f.full(len(logs)+gathered); f.begin++ {
 		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.begin))
 		if header == nil || err != nil {
 			return logs, err
@@ -224,11 +334,26 @@ func (f *Filter) checkMatches(ctx context.Context, header *types.Header) (logs [
 			}
 			logs = filterLogs(unfiltered, nil, nil, f.addresses, f.topics)
 		}
-		return logs, nil
+		return f.skipLogs(header, logs), nil
 	}
 	return nil, nil
 }
 
+// skipLogs leaves out the logs of the first block before the index the filter
+// resumes from.
+func (f *Filter) skipLogs(header *types.Header, logs []*types.Log) []*types.Log {
+	if f.skip == 0 || header.Number.Uint64() != f.first {
+		return logs
+	}
+	var kept []*types.Log
+	for _, log := range logs {
+		if log.Index >= f.skip {
+			kept = append(kept, log)
+		}
+	}
+	return kept
+}
+
 func includes(addresses []common.Address, a common.Address) bool {
 	for _, addr := range addresses {
 		if addr == a {
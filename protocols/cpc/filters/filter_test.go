@@ -30,6 +30,7 @@ import (
 	"bitbucket.org/cpchain/chain/database"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 )
@@ -239,3 +240,117 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// logIndexBackend is a test backend maintaining the exact log index.
+type logIndexBackend struct {
+	*testBackend
+	size, sections uint64
+}
+
+func (b *logIndexBackend) LogIndexStatus() (uint64, uint64) {
+	return b.size, b.sections
+}
+
+func TestLogIndexFilters(t *testing.T) {
+	var (
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		backend  = &logIndexBackend{
+			testBackend: &testBackend{new(event.TypeMux), db, 0, new(event.Feed), new(event.Feed), new(event.Feed), new(event.Feed)},
+			size:        16,
+			sections:    2,
+		}
+		addr  = common.BytesToAddress([]byte("pdash"))
+		hot   = common.BytesToHash([]byte("hot"))
+		other = common.BytesToHash([]byte("other"))
+	)
+	genesis := core.GenesisBlockForTesting(db, addr, big.NewInt(1000000))
+
+	// Hot logs in blocks 3 (twice), 10, 20 and past the indexed sections in 33
+	hotBlocks := map[int]int{3: 2, 10: 1, 20: 1, 33: 1}
+	d := dpor.NewFaker(configs.ChainConfigInfo().Dpor, db)
+	chain, receipts := core.GenerateChain(configs.TestChainConfig, genesis, d, db, remoteDB, 40, func(i int, gen *core.BlockGen) {
+		number := uint64(i + 1)
+		receipt := types.NewReceipt(nil, false, 0)
+		for j := 0; j < hotBlocks[int(number)]; j++ {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr, Topics: []common.Hash{hot}, BlockNumber: number, Index: uint(j)})
+		}
+		if number == 5 {
+			receipt.Logs = append(receipt.Logs, &types.Log{Address: addr, Topics: []common.Hash{other}, BlockNumber: number})
+		}
+		if len(receipt.Logs) > 0 {
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+		}
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	// Index the first two sections, with a stale entry left by a reorg in block 7
+	key := rawdb.LogIndexKey{Address: addr, Topic: hot}
+	rawdb.WriteLogIndexSection(db, 0, map[rawdb.LogIndexKey][]rawdb.LogIndexEntry{key: {{BlockNumber: 3, LogIndex: 0}, {BlockNumber: 3, LogIndex: 1}, {BlockNumber: 7, LogIndex: 0}, {BlockNumber: 10, LogIndex: 0}}})
+	rawdb.WriteLogIndexSection(db, 1, map[rawdb.LogIndexKey][]rawdb.LogIndexEntry{key: {{BlockNumber: 20, LogIndex: 0}}})
+
+	want := []uint64{3, 3, 10, 20, 33}
+	logs, err := New(backend, 0, -1, []common.Address{addr}, [][]common.Hash{{hot}}).Logs(context.Background())
+	if err != nil {
+		t.Fatalf("failed to filter logs: %v", err)
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("log count mismatch: have %d, want %d", len(logs), len(want))
+	}
+	for i, log := range logs {
+		if log.BlockNumber != want[i] {
+			t.Errorf("log %d: block mismatch: have %d, want %d", i, log.BlockNumber, want[i])
+		}
+	}
+	// Queries without first topics are served by the blooms
+	logs, _ = New(backend, 0, -1, []common.Address{addr}, nil).Logs(context.Background())
+	if len(logs) != len(want)+1 {
+		t.Fatalf("address log count mismatch: have %d, want %d", len(logs), len(want)+1)
+	}
+
+	// Pages are cut within blocks and resume where the previous page stopped
+	api := NewPublicFilterAPI(backend, false)
+	crit := FilterCriteria{FromBlock: big.NewInt(0), Addresses: []common.Address{addr}, Topics: [][]common.Hash{{hot}}}
+	for _, limit := range []int{1, 2, 5, 10} {
+		var (
+			found []*types.Log
+			token *string
+			pages int
+		)
+		for {
+			page, err := api.GetLogsPage(context.Background(), crit, hexutil.Uint(limit), token)
+			if err != nil {
+				t.Fatalf("limit %d: failed to get page: %v", limit, err)
+			}
+			if len(page.Logs) > limit {
+				t.Fatalf("limit %d: page too large: %d logs", limit, len(page.Logs))
+			}
+			found, pages = append(found, page.Logs...), pages+1
+			if page.Next == "" {
+				break
+			}
+			token = &page.Next
+		}
+		if len(found) != len(want) {
+			t.Fatalf("limit %d: log count mismatch: have %d, want %d", limit, len(found), len(want))
+		}
+		for i, log := range found {
+			if log.BlockNumber != want[i] || (i == 1 && log.Index != 1) {
+				t.Errorf("limit %d: log %d mismatch: block %d index %d", limit, i, log.BlockNumber, log.Index)
+			}
+		}
+		if wantPages := (len(want) + limit - 1) / limit; pages != wantPages {
+			t.Errorf("limit %d: page count mismatch: have %d, want %d", limit, pages, wantPages)
+		}
+	}
+	invalid := encodePageToken(50, 0)
+	crit.ToBlock = big.NewInt(40)
+	if _, err := api.GetLogsPage(context.Background(), crit, 1, &invalid); err != errInvalidPageToken {
+		t.Errorf("token error mismatch: have %v, want %v", err, errInvalidPageToken)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+)
+
+const (
+	// freezerRecheckInterval is the time between two attempts to move blocks to
+	// the ancient store.
+	freezerRecheckInterval = time.Minute
+
+	// minAncientDepth is the minimum number of recent blocks kept in the
+	// key-value store, far beyond any reorganisation.
+	minAncientDepth = 1024
+
+	// freezerChunkSize is the maximum number of blocks moved at once.
+	freezerChunkSize = 30000
+)
+
+// chainFreezer periodically moves the canonical blocks deeper than a given
+// depth from the key-value store to the ancient store.
+type chainFreezer struct {
+	db    database.Database
+	chain *core.BlockChain
+	depth uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChainFreezer creates a chain freezer keeping the depth most recent blocks
+// in the key-value store of db.
+func newChainFreezer(db database.Database, chain *core.BlockChain, depth uint64) *chainFreezer {
+	if depth < minAncientDepth {
+		log.Warn("Ancient depth too low, raising it", "provided", depth, "updated", minAncientDepth)
+		depth = minAncientDepth
+	}
+	return &chainFreezer{
+		db:    db,
+		chain: chain,
+		depth: depth,
+		quit:  make(chan struct{}),
+	}
+}
+
+// Start starts moving blocks in the background.
+func (f *chainFreezer) Start() {
+	f.wg.Add(1)
+	go f.loop()
+}
+
+// Stop stops moving blocks, waiting for the batch being moved.
+func (f *chainFreezer) Stop() {
+	close(f.quit)
+	f.wg.Wait()
+}
+
+func (f *chainFreezer) loop() {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(freezerRecheckInterval)
+	defer ticker.Stop()
+
+	for {
+		// Move the blocks in chunks to return quickly on shutdown
+		head := f.chain.CurrentBlock().NumberU64()
+		for head >= f.depth {
+			limit := head + 1 - f.depth
+			if ancients := rawdb.AncientStore(f.db).Ancients(); limit > ancients+freezerChunkSize {
+				limit = ancients + freezerChunkSize
+			}
+			start := time.Now()
+			moved, err := rawdb.FreezeBlocks(f.db, limit)
+			if err != nil {
+				log.Error("Failed to move blocks to the ancient store", "err", err)
+				break
+			}
+			if moved == 0 {
+				break
+			}
+			log.Info("Moved blocks to the ancient store", "count", moved, "ancients", limit, "elapsed", time.Since(start))
+
+			select {
+			case <-f.quit:
+				return
+			default:
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-f.quit:
+			return
+		}
+	}
+}
@@ -27,16 +27,23 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DatabaseCache           int
 		TrieCache               int
 		TrieTimeout             time.Duration
+		AddressIndex            bool
+		LogIndex                bool
+		AncientDepth            uint64
 		Cpcbase                 common.Address `toml:",omitempty"`
 		MinerThreads            int            `toml:",omitempty"`
 		ExtraData               hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		Dev                     bool `toml:"-"`
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
 		DocRoot                 string `toml:"-"`
 		PrivateTx               private.Config
 		SyncMode                syncer.SyncMode `toml:"-"`
+		LightServ               bool
+		RewardSchedule          bool
+		PdashContract           common.Address `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -47,16 +54,23 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseCache = c.DatabaseCache
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
+	enc.AddressIndex = c.AddressIndex
+	enc.LogIndex = c.LogIndex
+	enc.AncientDepth = c.AncientDepth
 	enc.Cpcbase = c.Cpcbase
 	enc.MinerThreads = c.MinerThreads
 	enc.ExtraData = c.ExtraData
 	enc.GasPrice = c.GasPrice
+	enc.Dev = c.Dev
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.DocRoot = c.DocRoot
 	enc.PrivateTx = c.PrivateTx
 	enc.SyncMode = c.SyncMode
+	enc.LightServ = c.LightServ
+	enc.RewardSchedule = c.RewardSchedule
+	enc.PdashContract = c.PdashContract
 	return &enc, nil
 }
 
@@ -71,16 +85,23 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DatabaseCache           *int
 		TrieCache               *int
 		TrieTimeout             *time.Duration
+		AddressIndex            *bool
+		LogIndex                *bool
+		AncientDepth            *uint64
 		Cpcbase                 *common.Address `toml:",omitempty"`
 		MinerThreads            *int            `toml:",omitempty"`
 		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		Dev                     *bool `toml:"-"`
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
 		DocRoot                 *string `toml:"-"`
 		PrivateTx               *private.Config
 		SyncMode                *syncer.SyncMode `toml:"-"`
+		LightServ               *bool
+		RewardSchedule          *bool
+		PdashContract           *common.Address `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -110,6 +131,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieTimeout != nil {
 		c.TrieTimeout = *dec.TrieTimeout
 	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
+	if dec.LogIndex != nil {
+		c.LogIndex = *dec.LogIndex
+	}
+	if dec.AncientDepth != nil {
+		c.AncientDepth = *dec.AncientDepth
+	}
 	if dec.Cpcbase != nil {
 		c.Cpcbase = *dec.Cpcbase
 	}
@@ -122,6 +152,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.GasPrice != nil {
 		c.GasPrice = dec.GasPrice
 	}
+	if dec.Dev != nil {
+		c.Dev = *dec.Dev
+	}
 	if dec.TxPool != nil {
 		c.TxPool = *dec.TxPool
 	}
@@ -140,5 +173,14 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.SyncMode != nil {
 		c.SyncMode = *dec.SyncMode
 	}
+	if dec.LightServ != nil {
+		c.LightServ = *dec.LightServ
+	}
+	if dec.RewardSchedule != nil {
+		c.RewardSchedule = *dec.RewardSchedule
+	}
+	if dec.PdashContract != nil {
+		c.PdashContract = *dec.PdashContract
+	}
 	return nil
 }
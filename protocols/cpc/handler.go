@@ -417,6 +417,10 @@ func (pm *ProtocolManager) handleMsg(p *peer, id string, handleTxs bool, handleD
 
 func (pm *ProtocolManager) handleSyncMsg(msg p2p.Msg, p *peer) error {
 	// Handle the message depending on its contents
+	// State ranges are not part of the protocol before cpc/2
+	if msg.Code >= GetAccountRangeMsg && msg.Code <= StorageRangeMsg && p.version < snapVersion {
+		return errResp(ErrInvalidMsgCode, "%v on cpc/%d", msg.Code, p.version)
+	}
 	switch {
 	case msg.Code == StatusMsg:
 		// Status messages should never arrive after the handshake
@@ -636,6 +640,57 @@ func (pm *ProtocolManager) handleSyncMsg(msg p2p.Msg, p *peer) error {
 			log.Debug("Failed to deliver node state data", "err", err)
 		}
 
+	case msg.Code == GetAccountRangeMsg:
+		var query getAccountRangeData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if query.Bytes > softResponseLimit {
+			query.Bytes = softResponseLimit
+		}
+		rng, err := syncer.ServeAccountRange(pm.blockchain.StateCache().TrieDB(), query.Root, query.Origin, query.Limit, query.Bytes)
+		if err != nil {
+			// An empty range without proof tells the state is not available
+			log.Debug("Failed to serve account range", "root", query.Root, "origin", query.Origin, "err", err)
+			rng = new(syncer.StateRange)
+		}
+		return p.SendAccountRange(rng)
+
+	case msg.Code == AccountRangeMsg:
+		// A range of accounts arrived to one of our previous requests
+		var rng syncer.StateRange
+		if err := msg.Decode(&rng); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := pm.syncer.DeliverAccountRange(p.id, &rng); err != nil {
+			log.Debug("Failed to deliver account range", "err", err)
+		}
+
+	case msg.Code == GetStorageRangeMsg:
+		var query getStorageRangeData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if query.Bytes > softResponseLimit {
+			query.Bytes = softResponseLimit
+		}
+		rng, err := syncer.ServeStorageRange(pm.blockchain.StateCache().TrieDB(), query.Root, query.Account, query.Origin, query.Limit, query.Bytes)
+		if err != nil {
+			log.Debug("Failed to serve storage range", "root", query.Root, "account", query.Account, "origin", query.Origin, "err", err)
+			rng = new(syncer.StateRange)
+		}
+		return p.SendStorageRange(rng)
+
+	case msg.Code == StorageRangeMsg:
+		// A range of storage slots arrived to one of our previous requests
+		var rng syncer.StateRange
+		if err := msg.Decode(&rng); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := pm.syncer.DeliverStorageRange(p.id, &rng); err != nil {
+			log.Debug("Failed to deliver storage range", "err", err)
+		}
+
 	case msg.Code == GetReceiptsMsg:
 		// Decode the retrieval message
 		msgStream := rlp.NewStream(msg.Payload, uint64(msg.Size))
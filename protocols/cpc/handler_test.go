@@ -17,10 +17,12 @@
 package cpc
 
 import (
+	"bytes"
 	"math"
 	"math/big"
 	"math/rand"
 	"testing"
+	"time"
 
 	"bitbucket.org/cpchain/chain/configs"
 	"bitbucket.org/cpchain/chain/core"
@@ -31,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
 )
 
 // Tests that protocol versions and modes of operations are matched up properly.
@@ -372,6 +375,116 @@ func testGetNodeData(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that the accounts of a state can be retrieved as proven ranges.
+func TestGetAccountRange(t *testing.T) {
+	generator := func(i int, block *core.BlockGen) {
+		// Fund a few accounts to have a range to split
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(testBank), common.Address{byte(i + 1)}, big.NewInt(1000), configs.TxGas, nil, nil), types.HomesteadSigner{}, testBankKey)
+		block.AddTx(tx)
+	}
+	pm, _ := newTestProtocolManagerMust(t, 4, generator, nil)
+	peer, _ := newTestPeer("peer", 64, pm, true)
+	defer peer.close()
+
+	var (
+		root   = pm.blockchain.CurrentBlock().StateRoot()
+		limit  = common.BytesToHash(bytes.Repeat([]byte{0xff}, common.HashLength))
+		origin common.Hash
+		seen   = make(map[common.Hash]bool)
+	)
+	for {
+		p2p.Send(peer.app, GetAccountRangeMsg, &getAccountRangeData{Root: root, Origin: origin, Limit: limit, Bytes: 1})
+		msg, err := peer.app.ReadMsg()
+		if err != nil {
+			t.Fatalf("failed to read account range response: %v", err)
+		}
+		if msg.Code != AccountRangeMsg {
+			t.Fatalf("response packet code mismatch: have %x, want %x", msg.Code, AccountRangeMsg)
+		}
+		var rng syncer.StateRange
+		if err := msg.Decode(&rng); err != nil {
+			t.Fatalf("failed to decode account range: %v", err)
+		}
+		more, err := syncer.VerifyStateRange(root, origin, &rng)
+		if err != nil {
+			t.Fatalf("invalid account range from %x: %v", origin, err)
+		}
+		if len(rng.Keys) != 1 {
+			t.Fatalf("account count mismatch: have %d, want 1", len(rng.Keys))
+		}
+		seen[rng.Keys[0]] = true
+		if !more {
+			break
+		}
+		origin = rng.Keys[0]
+		origin[common.HashLength-1]++
+	}
+	statedb, _ := pm.blockchain.State()
+	for _, addr := range []common.Address{testBank, {1}, {2}, {3}, {4}} {
+		if !seen[crypto.Keccak256Hash(addr[:])] || !statedb.Exist(addr) {
+			t.Errorf("account %x missing from the ranges", addr)
+		}
+	}
+	// Ranges of unknown states come without proof
+	p2p.Send(peer.app, GetAccountRangeMsg, &getAccountRangeData{Root: common.Hash{0x01}, Limit: limit, Bytes: 1024})
+	msg, err := peer.app.ReadMsg()
+	if err != nil {
+		t.Fatalf("failed to read account range response: %v", err)
+	}
+	var rng syncer.StateRange
+	if err := msg.Decode(&rng); err != nil {
+		t.Fatalf("failed to decode account range: %v", err)
+	}
+	if len(rng.Keys) != 0 || len(rng.Proof) != 0 {
+		t.Fatalf("range served for unknown state: %d accounts, %d proof nodes", len(rng.Keys), len(rng.Proof))
+	}
+}
+
+// Tests that state ranges are neither served to nor requested from cpc/1 peers.
+func TestGetAccountRangeCpc1(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, 4, nil, nil)
+	tp, errc := newTestPeer("peer", 1, pm, true)
+	defer tp.close()
+
+	go p2p.Send(tp.app, GetAccountRangeMsg, &getAccountRangeData{Root: pm.blockchain.CurrentBlock().StateRoot(), Bytes: 1024})
+	select {
+	case err := <-errc:
+		if want := errResp(ErrInvalidMsgCode, "%v on cpc/%d", GetAccountRangeMsg, 1); err == nil || err.Error() != want.Error() {
+			t.Errorf("wrong error: have %v, want %v", err, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("account range served on cpc/1")
+	}
+
+	// Snap sync only picks the peers serving state ranges
+	makePeer := func(version int, ht int64) *peer {
+		var id discover.NodeID
+		rand.Read(id[:])
+		p := newPeer(version, p2p.NewPeer(id, "peer", nil), nil)
+		p.ht = big.NewInt(ht)
+		return p
+	}
+	var (
+		peers     = newPeerSet()
+		snapSync  = &ProtocolManager{peers: peers, syncMode: syncer.SnapSync}
+		fastSync  = &ProtocolManager{peers: peers, syncMode: syncer.FastSync}
+		old, snap = makePeer(1, 100), makePeer(2, 50)
+	)
+	defer peers.Close()
+
+	peers.Register(old)
+	if p := snapSync.bestSyncPeer(); p != old {
+		t.Errorf("sync peer mismatch without snap peers: have %v, want %v", p, old)
+	}
+	peers.Register(snap)
+	if p := snapSync.bestSyncPeer(); p != snap {
+		t.Errorf("sync peer mismatch with snap peers: have %v, want %v", p, snap)
+	}
+	if p := fastSync.bestSyncPeer(); p != old {
+		t.Errorf("sync peer mismatch on fast sync: have %v, want %v", p, old)
+	}
+}
+
 // Tests that the transaction receipts can be retrieved based on hashes.
 func TestGetReceipt64(t *testing.T) { testGetReceipt(t, 64) }
 
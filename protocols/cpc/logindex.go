@@ -0,0 +1,111 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// logIndexSectionSize is the number of blocks in a section of the log index.
+	logIndexSectionSize = 512
+
+	// logIndexConfirms is the number of confirmation blocks before a section of
+	// the log index is processed.
+	logIndexConfirms = 64
+
+	// logIndexThrottling is the time to wait between processing two consecutive
+	// sections of the log index.
+	logIndexThrottling = 100 * time.Millisecond
+)
+
+// LogIndexer is a chain indexer backend maintaining the exact log index, from
+// the address and first topic of the logs to their positions. A reorganised
+// section is reprocessed by the chain indexer, in which case the entries of its
+// previous version are replaced on commit.
+type LogIndexer struct {
+	db database.Database
+
+	section uint64
+	entries map[rawdb.LogIndexKey][]rawdb.LogIndexEntry
+}
+
+// NewLogIndexer returns a chain indexer maintaining the log index.
+func NewLogIndexer(db database.Database) *core.ChainIndexer {
+	backend := &LogIndexer{
+		db: db,
+	}
+	table := database.NewTable(db, string(rawdb.LogIndexPrefix))
+
+	return core.NewChainIndexer(db, table, backend, logIndexSectionSize, logIndexConfirms, logIndexThrottling, "logindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new section.
+func (b *LogIndexer) Reset(section uint64, lastSectionHead common.Hash) error {
+	b.section, b.entries = section, make(map[rawdb.LogIndexKey][]rawdb.LogIndexEntry)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the logs of a block to
+// the section.
+func (b *LogIndexer) Process(header *types.Header) {
+	hash, number := header.Hash(), header.Number.Uint64()
+	receipts := rawdb.ReadReceipts(b.db, hash, number)
+	if receipts == nil && header.ReceiptsRoot != types.EmptyRootHash {
+		log.Error("Log index missing block receipts", "number", number, "hash", hash)
+		return
+	}
+	for key, list := range logIndexEntries(number, receipts) {
+		b.entries[key] = append(b.entries[key], list...)
+	}
+}
+
+// Commit implements core.ChainIndexerBackend, replacing the stored entries of
+// the section.
+func (b *LogIndexer) Commit() error {
+	batch := b.db.NewBatch()
+	rawdb.DeleteLogIndexSection(batch, b.section, rawdb.ReadLogIndexSectionKeys(b.db, b.section))
+	rawdb.WriteLogIndexSection(batch, b.section, b.entries)
+	return batch.Write()
+}
+
+// logIndexEntries returns the index entries of the logs of a block, per address
+// and first topic. Logs without topics are left out, as no query on a first
+// topic can match them.
+func logIndexEntries(number uint64, receipts types.Receipts) map[rawdb.LogIndexKey][]rawdb.LogIndexEntry {
+	var (
+		entries = make(map[rawdb.LogIndexKey][]rawdb.LogIndexEntry)
+		index   uint64
+	)
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if len(l.Topics) > 0 {
+				key := rawdb.LogIndexKey{Address: l.Address, Topic: l.Topics[0]}
+				entries[key] = append(entries[key], rawdb.LogIndexEntry{BlockNumber: number, LogIndex: index})
+			}
+			index++
+		}
+	}
+	return entries
+}
@@ -0,0 +1,82 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package cpc
+
+import (
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestLogIndexerSections(t *testing.T) {
+	var (
+		db       = database.NewMemDatabase()
+		contract = common.Address{0x01}
+		transfer = common.Hash{0x01}
+		order    = common.Hash{0x02}
+		backend  = &LogIndexer{db: db}
+	)
+	logs := func(topics ...common.Hash) []*types.Log {
+		var list []*types.Log
+		for _, topic := range topics {
+			l := &types.Log{Address: contract}
+			if topic != (common.Hash{}) {
+				l.Topics = []common.Hash{topic}
+			}
+			list = append(list, l)
+		}
+		return list
+	}
+	process := func(blocks []types.Receipts) {
+		backend.Reset(0, common.Hash{})
+		for i, receipts := range blocks {
+			header := &types.Header{Number: big.NewInt(int64(i)), Extra: []byte{byte(len(receipts))}}
+			rawdb.WriteReceipts(db, header.Hash(), uint64(i), receipts)
+			backend.Process(header)
+		}
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("failed to commit section: %v", err)
+		}
+	}
+	// Index a section of two blocks, positions counting logs across receipts
+	// and anonymous logs
+	process([]types.Receipts{
+		{{Logs: logs(transfer)}},
+		{{Logs: logs(order, common.Hash{})}, {Logs: logs(transfer, order)}},
+	})
+	entries := rawdb.ReadLogIndexEntries(db, rawdb.LogIndexKey{Address: contract, Topic: order}, 0)
+	if len(entries) != 2 || entries[0] != (rawdb.LogIndexEntry{BlockNumber: 1, LogIndex: 0}) || entries[1] != (rawdb.LogIndexEntry{BlockNumber: 1, LogIndex: 3}) {
+		t.Fatalf("order entries mismatch: %+v", entries)
+	}
+	entries = rawdb.ReadLogIndexEntries(db, rawdb.LogIndexKey{Address: contract, Topic: transfer}, 0)
+	if len(entries) != 2 || entries[1] != (rawdb.LogIndexEntry{BlockNumber: 1, LogIndex: 2}) {
+		t.Fatalf("transfer entries mismatch: %+v", entries)
+	}
+	// Reprocess the section after a reorganisation, the old entries must go
+	process([]types.Receipts{{{Logs: logs(transfer)}}})
+
+	if entries := rawdb.ReadLogIndexEntries(db, rawdb.LogIndexKey{Address: contract, Topic: order}, 0); len(entries) != 0 {
+		t.Fatalf("stale order entries left: %+v", entries)
+	}
+	if entries := rawdb.ReadLogIndexEntries(db, rawdb.LogIndexKey{Address: contract, Topic: transfer}, 0); len(entries) != 1 {
+		t.Fatalf("transfer entries mismatch after reorg: %+v", entries)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pdash
+
+import (
+	"context"
+	"errors"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// orderPageSize is the number of orders returned per page.
+const orderPageSize = 100
+
+var errUnknownOrder = errors.New("unknown order")
+
+// OrderPage is a page of the orders matching a filter.
+type OrderPage struct {
+	Orders      []*Order       `json:"orders"`
+	Page        hexutil.Uint   `json:"page"`
+	HasMore     bool           `json:"hasMore"`     // Whether later pages hold more orders
+	BlockNumber hexutil.Uint64 `json:"blockNumber"` // Block the orders are indexed at
+}
+
+// APIs returns the collection of RPC services the index offers.
+func (i *Indexer) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "pdash",
+			Version:   "1.0",
+			Service:   &PublicPdashAPI{i},
+			Public:    true,
+		},
+	}
+}
+
+// PublicPdashAPI serves the index of the pdash orders.
+type PublicPdashAPI struct {
+	i *Indexer
+}
+
+// GetOrders returns a page of the orders matching filter, ordered by id.
+func (api *PublicPdashAPI) GetOrders(ctx context.Context, filter OrderFilter) (*OrderPage, error) {
+	orders, number := api.i.filter(&filter)
+
+	page := &OrderPage{Orders: []*Order{}, Page: filter.Page, BlockNumber: hexutil.Uint64(number)}
+	skip := uint64(filter.Page) * orderPageSize
+	if uint64(len(orders)) <= skip {
+		return page, nil
+	}
+	orders = orders[skip:]
+	if len(orders) > orderPageSize {
+		orders, page.HasMore = orders[:orderPageSize], true
+	}
+	page.Orders = orders
+	return page, nil
+}
+
+// GetOrder returns the order with the given id.
+func (api *PublicPdashAPI) GetOrder(ctx context.Context, id hexutil.Uint64) (*Order, error) {
+	if order := api.i.order(uint64(id)); order != nil {
+		return order, nil
+	}
+	return nil, errUnknownOrder
+}
@@ -0,0 +1,454 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pdash implements the node side index of the orders of the pdash
+// data marketplace contract, keyed by buyer, seller, proxy and status.
+package pdash
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	pdash "bitbucket.org/cpchain/chain/contracts/pdash/pdash_contract"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	// maxReorgDepth is the number of recent blocks whose touched orders are
+	// remembered to be refreshed on a reorg. A deeper reorg reloads all orders.
+	maxReorgDepth = 128
+
+	// reconcileInterval is the number of blocks after which all orders are
+	// reloaded, catching up with the orders changed by the calls of other
+	// contracts, which are not seen in the blocks.
+	reconcileInterval = 1024
+
+	// chainHeadChanSize is the size of the channel listening to the chain
+	// head events.
+	chainHeadChanSize = 16
+)
+
+// pdashABI is the parsed ABI of the pdash contract.
+var pdashABI, _ = abi.JSON(strings.NewReader(pdash.PdashABI))
+
+// Chain is the chain access needed by the index.
+type Chain interface {
+	core.ChainContext
+
+	Config() *configs.ChainConfig
+	CurrentBlock() *types.Block
+	GetBlockByNumber(number uint64) *types.Block
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	StateAt(root common.Hash) (*state.StateDB, error)
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// journalEntry records the orders touched by a recent block.
+type journalEntry struct {
+	number uint64
+	hash   common.Hash
+	ids    []uint64
+}
+
+// Indexer maintains the index of the orders of the pdash contract. It follows
+// the chain head, refreshing the orders touched by the events of each new
+// block and by the transactions calling the contract, directly or through a
+// proxy contract, which also change orders without an event. The calls of
+// other contracts are not seen, so all orders are reloaded every
+// reconcileInterval blocks. The orders are read through the contract getters
+// at the head, so that the index never depends on the storage layout of the
+// contract nor on the state of past blocks.
+type Indexer struct {
+	contract common.Address
+	chain    Chain
+
+	// Only accessed by update
+	journal     []journalEntry // Recent canonical blocks indexed, the head being the last
+	reconcileAt uint64         // Number of the block reloading all orders
+
+	mu       sync.RWMutex
+	orders   map[uint64]*Order
+	buyers   map[common.Address]idSet
+	sellers  map[common.Address]idSet
+	proxies  map[common.Address]idSet
+	statuses map[Status]idSet
+	number   uint64 // Number of the block the orders are indexed at
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewIndexer returns an index of the orders of the pdash contract at address.
+func NewIndexer(contract common.Address, chain Chain) *Indexer {
+	return &Indexer{
+		contract: contract,
+		chain:    chain,
+		orders:   make(map[uint64]*Order),
+		buyers:   make(map[common.Address]idSet),
+		sellers:  make(map[common.Address]idSet),
+		proxies:  make(map[common.Address]idSet),
+		statuses: make(map[Status]idSet),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start starts following the chain head in the background.
+func (i *Indexer) Start() {
+	i.wg.Add(1)
+	go i.loop()
+}
+
+// Stop stops following the chain head.
+func (i *Indexer) Stop() {
+	close(i.quit)
+	i.wg.Wait()
+}
+
+func (i *Indexer) loop() {
+	defer i.wg.Done()
+
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := i.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	for {
+		if err := i.update(); err != nil {
+			log.Warn("Failed to update pdash order index", "err", err)
+		}
+		select {
+		case <-heads:
+		case <-sub.Err():
+			return
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// update brings the index up to the current head, refreshing the orders
+// touched by the blocks reorganised away and by the new canonical blocks.
+// The orders are read from the contract before locking the index, which is
+// only locked to replace them. It is not safe for concurrent use.
+func (i *Indexer) update() error {
+	head := i.chain.CurrentBlock()
+	if head == nil {
+		return nil
+	}
+	var (
+		journal = append([]journalEntry(nil), i.journal...)
+		touched = make(idSet)
+	)
+	// Unwind the blocks no longer canonical
+	for len(journal) > 0 {
+		last := journal[len(journal)-1]
+		if last.number <= head.NumberU64() {
+			if block := i.chain.GetBlockByNumber(last.number); block != nil && block.Hash() == last.hash {
+				break
+			}
+		}
+		for _, id := range last.ids {
+			touched[id] = struct{}{}
+		}
+		journal = journal[:len(journal)-1]
+	}
+	if len(journal) == 0 || head.NumberU64() >= i.reconcileAt {
+		return i.reload(head)
+	}
+
+	// Collect the orders touched by the new canonical blocks
+	for number := journal[len(journal)-1].number + 1; number <= head.NumberU64(); number++ {
+		block := i.chain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("missing canonical block %d", number)
+		}
+		ids := touchedOrders(i.contract, block, i.chain.GetReceiptsByHash(block.Hash()))
+		for _, id := range ids {
+			touched[id] = struct{}{}
+		}
+		journal = append(journal, journalEntry{number: number, hash: block.Hash(), ids: ids})
+	}
+	if n := len(journal); n > maxReorgDepth {
+		journal = journal[n-maxReorgDepth:]
+	}
+
+	caller, err := i.callerAt(head)
+	if err != nil {
+		return err
+	}
+	orders, err := readOrders(caller, touched)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	for id, order := range orders {
+		i.remove(id)
+		if order != nil {
+			i.insert(order)
+		}
+	}
+	i.number = head.NumberU64()
+	i.mu.Unlock()
+
+	i.journal = journal
+	return nil
+}
+
+// reload rebuilds the whole index from the state of head.
+func (i *Indexer) reload(head *types.Block) error {
+	caller, err := i.callerAt(head)
+	if err != nil {
+		return err
+	}
+	count, err := caller.NumOrders(nil)
+	switch {
+	case err == bind.ErrNoCode:
+		// the contract is not deployed yet
+		count = new(big.Int)
+	case err != nil:
+		return err
+	}
+	ids := make(idSet)
+	for id := uint64(1); id <= count.Uint64(); id++ {
+		ids[id] = struct{}{}
+	}
+	orders, err := readOrders(caller, ids)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	for id := range i.orders {
+		i.remove(id)
+	}
+	for _, order := range orders {
+		if order != nil {
+			i.insert(order)
+		}
+	}
+	i.number = head.NumberU64()
+	i.mu.Unlock()
+
+	i.journal = []journalEntry{{number: head.NumberU64(), hash: head.Hash()}}
+	i.reconcileAt = head.NumberU64() + reconcileInterval
+	log.Debug("Reloaded pdash order index", "orders", len(orders), "number", head.NumberU64())
+	return nil
+}
+
+// readOrders reads orders from the contract, mapping the ids of the orders
+// not existing to nil.
+func readOrders(caller *pdash.PdashCaller, ids idSet) (map[uint64]*Order, error) {
+	orders := make(map[uint64]*Order, len(ids))
+	for id := range ids {
+		record, err := caller.OrderRecords(nil, new(big.Int).SetUint64(id))
+		if err != nil && err != bind.ErrNoCode {
+			return nil, err
+		}
+		// orders of reorganised blocks may not exist anymore
+		if err == bind.ErrNoCode || record.BuyerAddress == (common.Address{}) {
+			orders[id] = nil
+			continue
+		}
+		orders[id] = &Order{
+			ID:             hexutil.Uint64(id),
+			DescHash:       record.DescHash,
+			BuyerRSAPubkey: record.BuyerRSAPubkey,
+			Buyer:          record.BuyerAddress,
+			Seller:         record.SellerAddress,
+			Proxy:          record.ProxyAddress,
+			SecondaryProxy: record.SecondaryProxyAddress,
+			OfferedPrice:   (*hexutil.Big)(record.OfferedPrice),
+			ProxyFee:       (*hexutil.Big)(record.ProxyFee),
+			DeliverHash:    record.DeliverHash,
+			EndTime:        hexutil.Uint64(record.EndTime.Uint64()),
+			Status:         Status(record.State),
+			DisputeID:      hexutil.Uint64(record.DisputeId.Uint64()),
+		}
+	}
+	return orders, nil
+}
+
+// insert adds an order and its index entries.
+func (i *Indexer) insert(order *Order) {
+	id := uint64(order.ID)
+	i.orders[id] = order
+	addID(i.buyers, order.Buyer, id)
+	addID(i.sellers, order.Seller, id)
+	addID(i.proxies, order.Proxy, id)
+	addID(i.proxies, order.SecondaryProxy, id)
+	if i.statuses[order.Status] == nil {
+		i.statuses[order.Status] = make(idSet)
+	}
+	i.statuses[order.Status][id] = struct{}{}
+}
+
+// remove removes an order and its index entries.
+func (i *Indexer) remove(id uint64) {
+	order := i.orders[id]
+	if order == nil {
+		return
+	}
+	delete(i.orders, id)
+	removeID(i.buyers, order.Buyer, id)
+	removeID(i.sellers, order.Seller, id)
+	removeID(i.proxies, order.Proxy, id)
+	removeID(i.proxies, order.SecondaryProxy, id)
+	if delete(i.statuses[order.Status], id); len(i.statuses[order.Status]) == 0 {
+		delete(i.statuses, order.Status)
+	}
+}
+
+// order returns the indexed order with the given id, nil if none.
+func (i *Indexer) order(id uint64) *Order {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.orders[id]
+}
+
+// filter returns the indexed orders matching f, ordered by id, along with the
+// number of the block they are indexed at.
+func (i *Indexer) filter(f *OrderFilter) ([]*Order, uint64) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	// Scan the smallest set of orders keyed by the criteria
+	var candidates idSet
+	narrow := func(set idSet) {
+		if candidates == nil || len(set) < len(candidates) {
+			candidates = set
+		}
+	}
+	if f.Buyer != nil {
+		narrow(orNone(i.buyers[*f.Buyer]))
+	}
+	if f.Seller != nil {
+		narrow(orNone(i.sellers[*f.Seller]))
+	}
+	if f.Proxy != nil {
+		narrow(orNone(i.proxies[*f.Proxy]))
+	}
+	if f.Status != nil {
+		narrow(orNone(i.statuses[*f.Status]))
+	}
+	var orders []*Order
+	if candidates == nil {
+		for _, order := range i.orders {
+			if f.matches(order) {
+				orders = append(orders, order)
+			}
+		}
+	} else {
+		for id := range candidates {
+			if order := i.orders[id]; f.matches(order) {
+				orders = append(orders, order)
+			}
+		}
+	}
+	sort.Slice(orders, func(a, b int) bool { return orders[a].ID < orders[b].ID })
+
+	return orders, i.number
+}
+
+// callerAt returns a binding of the pdash contract reading the state of block.
+func (i *Indexer) callerAt(block *types.Block) (*pdash.PdashCaller, error) {
+	statedb, err := i.chain.StateAt(block.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	return pdash.NewPdashCaller(i.contract, core.NewStateCaller(i.chain, i.chain.Config(), block.Header(), statedb))
+}
+
+// touchedOrders returns the ids of the orders touched by a block, from the
+// events of the contract and the arguments of the successful transactions
+// calling it, directly or through a proxy contract redirecting to it.
+func touchedOrders(contract common.Address, block *types.Block, receipts types.Receipts) []uint64 {
+	var (
+		ids  []uint64
+		seen = make(map[uint64]bool)
+	)
+	add := func(args abi.Arguments, data []byte, name string) {
+		values, err := args.UnpackValues(data)
+		if err != nil {
+			return
+		}
+		for k, arg := range args {
+			if id, ok := values[k].(*big.Int); ok && arg.Name == name && id.IsUint64() && !seen[id.Uint64()] {
+				seen[id.Uint64()] = true
+				ids = append(ids, id.Uint64())
+			}
+		}
+	}
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			if log.Address != contract || len(log.Topics) == 0 {
+				continue
+			}
+			for _, event := range pdashABI.Events {
+				if event.Id() == log.Topics[0] {
+					add(event.Inputs.NonIndexed(), log.Data, "orderId")
+				}
+			}
+		}
+	}
+	for k, tx := range block.Transactions() {
+		if tx.To() == nil || len(tx.Data()) < 4 || k >= len(receipts) {
+			continue
+		}
+		if receipt := receipts[k]; receipt.Status != types.ReceiptStatusSuccessful ||
+			(*tx.To() != contract && receipt.LogicAddress != contract) {
+			continue
+		}
+		if method, err := pdashABI.MethodById(tx.Data()); err == nil {
+			add(method.Inputs, tx.Data()[4:], "id")
+		}
+	}
+	return ids
+}
+
+// idSet is a set of order ids.
+type idSet map[uint64]struct{}
+
+func addID(index map[common.Address]idSet, addr common.Address, id uint64) {
+	if index[addr] == nil {
+		index[addr] = make(idSet)
+	}
+	index[addr][id] = struct{}{}
+}
+
+func removeID(index map[common.Address]idSet, addr common.Address, id uint64) {
+	if delete(index[addr], id); len(index[addr]) == 0 {
+		delete(index, addr)
+	}
+}
+
+// orNone returns set, or an empty set if nil.
+func orNone(set idSet) idSet {
+	if set == nil {
+		return idSet{}
+	}
+	return set
+}
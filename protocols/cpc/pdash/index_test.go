@@ -0,0 +1,231 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pdash
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	pdash "bitbucket.org/cpchain/chain/contracts/pdash/pdash_contract"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	buyerKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	sellerKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	proxyKey, _  = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+
+	buyer          = crypto.PubkeyToAddress(buyerKey.PublicKey)
+	seller         = crypto.PubkeyToAddress(sellerKey.PublicKey)
+	proxy          = crypto.PubkeyToAddress(proxyKey.PublicKey)
+	secondaryProxy = common.HexToAddress("0x82104907aa699b2982fc46f38fd8c915d03cdb8d")
+	otherSeller    = common.HexToAddress("0x0000000000000000000000000000000000000abc")
+)
+
+func TestIndexer(t *testing.T) {
+	backend := backends.NewDporSimulatedBackend(core.GenesisAlloc{
+		buyer:  {Balance: big.NewInt(1000000000000000000)},
+		seller: {Balance: big.NewInt(1000000000000000000)},
+		proxy:  {Balance: big.NewInt(1000000000000000000)},
+	})
+	address, _, contract, err := pdash.DeployPdash(bind.NewKeyedTransactor(buyerKey), backend)
+	if err != nil {
+		t.Fatalf("failed to deploy pdash contract: %v", err)
+	}
+	backend.Commit()
+	deployed := backend.Blockchain().CurrentBlock().NumberU64()
+
+	index := NewIndexer(address, backend.Blockchain())
+	if err := index.update(); err != nil {
+		t.Fatalf("failed to load index: %v", err)
+	}
+	api := &PublicPdashAPI{index}
+
+	// send sends a transaction of the pdash contract in a block of its own
+	send := func(key *ecdsa.PrivateKey, value int64, fn func(*bind.TransactOpts) (*types.Transaction, error)) {
+		t.Helper()
+		opts := bind.NewKeyedTransactor(key)
+		opts.Value = big.NewInt(value)
+		if _, err := fn(opts); err != nil {
+			t.Fatalf("failed to send transaction: %v", err)
+		}
+		backend.Commit()
+		if err := index.update(); err != nil {
+			t.Fatalf("failed to update index: %v", err)
+		}
+	}
+	one := big.NewInt(1)
+
+	send(buyerKey, 10, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.PlaceOrder(opts, [32]byte{1}, []byte("key"), seller, proxy, secondaryProxy, one, big.NewInt(1000))
+	})
+	send(buyerKey, 20, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.PlaceOrder(opts, [32]byte{2}, []byte("key"), otherSeller, proxy, secondaryProxy, one, big.NewInt(1000))
+	})
+	send(sellerKey, 10, func(opts *bind.TransactOpts) (*types.Transaction, error) { return contract.SellerConfirm(opts, one) })
+	send(proxyKey, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) { return contract.ProxyFetched(opts, one) })
+	send(proxyKey, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.ProxyDelivered(opts, [32]byte{3}, one)
+	})
+	send(buyerKey, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.BuyerConfirmDeliver(opts, one)
+	})
+	finished := backend.Blockchain().CurrentBlock().NumberU64()
+
+	// Rating the proxies changes the order without an event
+	send(buyerKey, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.BuyerRateProxy(opts, one, big.NewInt(20))
+	})
+
+	order, err := api.GetOrder(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("failed to get order: %v", err)
+	}
+	if order.Buyer != buyer || order.Seller != seller || order.Proxy != proxy || order.SecondaryProxy != secondaryProxy ||
+		order.OfferedPrice.ToInt().Int64() != 10 || order.DeliverHash != (common.Hash{3}) || order.Status != BuyerRated {
+		t.Fatalf("order mismatch: %+v", order)
+	}
+	if _, err := api.GetOrder(context.Background(), 3); err != errUnknownOrder {
+		t.Fatalf("unknown order error mismatch: have %v, want %v", err, errUnknownOrder)
+	}
+
+	// Orders changed without being seen are restored by the reconciliation
+	index.mu.Lock()
+	index.remove(2)
+	index.mu.Unlock()
+	index.reconcileAt = backend.Blockchain().CurrentBlock().NumberU64() + 1
+	backend.Commit()
+	if err := index.update(); err != nil {
+		t.Fatalf("failed to update index: %v", err)
+	}
+	if order, err := api.GetOrder(context.Background(), 2); err != nil || order.Seller != otherSeller {
+		t.Fatalf("reconciled order mismatch: %+v, %v", order, err)
+	}
+
+	status := Created
+	tests := []struct {
+		filter OrderFilter
+		want   []hexutil.Uint64
+	}{
+		{OrderFilter{}, []hexutil.Uint64{1, 2}},
+		{OrderFilter{Buyer: &buyer}, []hexutil.Uint64{1, 2}},
+		{OrderFilter{Seller: &seller}, []hexutil.Uint64{1}},
+		{OrderFilter{Proxy: &secondaryProxy}, []hexutil.Uint64{1, 2}},
+		{OrderFilter{Status: &status}, []hexutil.Uint64{2}},
+		{OrderFilter{Seller: &seller, Status: &status}, nil},
+		{OrderFilter{Seller: &buyer}, nil},
+		{OrderFilter{Page: 1}, nil},
+	}
+	for k, tt := range tests {
+		page, err := api.GetOrders(context.Background(), tt.filter)
+		if err != nil {
+			t.Fatalf("test %d: failed to get orders: %v", k, err)
+		}
+		if len(page.Orders) != len(tt.want) || page.HasMore {
+			t.Fatalf("test %d: orders mismatch: have %d, want %v", k, len(page.Orders), tt.want)
+		}
+		for j, order := range page.Orders {
+			if order.ID != tt.want[j] {
+				t.Fatalf("test %d: order %d mismatch: have %d, want %d", k, j, order.ID, tt.want[j])
+			}
+		}
+	}
+
+	// The orders are served over RPC
+	server := rpc.NewServer()
+	if err := server.RegisterName("pdash", api); err != nil {
+		t.Fatal(err)
+	}
+	client := cpclient.NewClient(rpc.DialInProc(server))
+	orders, err := client.PdashOrders(context.Background(), cpclient.PdashOrderFilter{Proxy: &proxy, Status: "buyerRated"})
+	if err != nil {
+		t.Fatalf("failed to get orders over RPC: %v", err)
+	}
+	if len(orders.Orders) != 1 || orders.Orders[0].ID != 1 || orders.Orders[0].Seller != seller || orders.Orders[0].Status != "buyerRated" {
+		t.Fatalf("RPC orders mismatch: %+v", orders)
+	}
+	if remote, err := client.PdashOrder(context.Background(), 2); err != nil || remote.OfferedPrice.Int64() != 20 {
+		t.Fatalf("RPC order mismatch: %+v, %v", remote, err)
+	}
+	if _, err := client.PdashOrders(context.Background(), cpclient.PdashOrderFilter{Status: "lost"}); err == nil {
+		t.Fatalf("unknown status accepted")
+	}
+
+	// A reorg undoing the rating and withdrawing the other order is followed
+	if err := backend.Blockchain().SetHead(finished); err != nil {
+		t.Fatal(err)
+	}
+	backend.Rollback()
+	send(buyerKey, 0, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return contract.BuyerWithdraw(opts, big.NewInt(2))
+	})
+	if order, _ := api.GetOrder(context.Background(), 1); order.Status != Finished {
+		t.Fatalf("reorganised order status mismatch: have %v, want %v", order.Status, Finished)
+	}
+	if order, _ := api.GetOrder(context.Background(), 2); order.Status != Withdrawn {
+		t.Fatalf("new order status mismatch: have %v, want %v", order.Status, Withdrawn)
+	}
+
+	// A reorg deeper than the placement of the orders removes them
+	if err := backend.Blockchain().SetHead(deployed); err != nil {
+		t.Fatal(err)
+	}
+	backend.Rollback()
+	if err := index.update(); err != nil {
+		t.Fatalf("failed to update index: %v", err)
+	}
+	if page, _ := api.GetOrders(context.Background(), OrderFilter{}); len(page.Orders) != 0 || uint64(page.BlockNumber) != deployed {
+		t.Fatalf("orders left after reorg: %d at %d", len(page.Orders), page.BlockNumber)
+	}
+}
+
+func TestTouchedOrdersProxied(t *testing.T) {
+	var (
+		contract = common.HexToAddress("0x1000")
+		other    = common.HexToAddress("0x2000")
+	)
+	call := func(to common.Address, id int64) *types.Transaction {
+		data, err := pdashABI.Pack("buyerConfirmDeliver", big.NewInt(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return types.NewTransaction(0, to, new(big.Int), 100000, new(big.Int), data)
+	}
+	txs := []*types.Transaction{call(contract, 1), call(proxy, 2), call(proxy, 3), call(other, 4)}
+	receipts := types.Receipts{
+		{Status: types.ReceiptStatusSuccessful},
+		{Status: types.ReceiptStatusSuccessful, LogicAddress: contract},
+		{Status: types.ReceiptStatusSuccessful, LogicAddress: other},
+		{Status: types.ReceiptStatusSuccessful},
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, txs, receipts)
+
+	ids := touchedOrders(contract, block, receipts)
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("touched orders mismatch: have %v, want [1 2]", ids)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package pdash
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var errUnknownStatus = errors.New("unknown order status")
+
+// Status is the state of a pdash order, as numbered by the State enum of the
+// pdash contract.
+type Status uint8
+
+const (
+	Created Status = iota
+	SellerConfirmed
+	ProxyFetched
+	ProxyDelivered
+	BuyerConfirmed
+	Finished
+	SellerRated
+	BuyerRated
+	AllRated
+	Disputed
+	Withdrawn
+)
+
+var statusNames = []string{
+	"created",
+	"sellerConfirmed",
+	"proxyFetched",
+	"proxyDelivered",
+	"buyerConfirmed",
+	"finished",
+	"sellerRated",
+	"buyerRated",
+	"allRated",
+	"disputed",
+	"withdrawn",
+}
+
+func (s Status) String() string {
+	if int(s) < len(statusNames) {
+		return statusNames[s]
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Status) UnmarshalText(text []byte) error {
+	for i, name := range statusNames {
+		if name == string(text) {
+			*s = Status(i)
+			return nil
+		}
+	}
+	return errUnknownStatus
+}
+
+// Order is a pdash order as recorded by the pdash contract.
+type Order struct {
+	ID             hexutil.Uint64 `json:"id"`
+	DescHash       common.Hash    `json:"descHash"`
+	BuyerRSAPubkey hexutil.Bytes  `json:"buyerRSAPubkey"`
+	Buyer          common.Address `json:"buyer"`
+	Seller         common.Address `json:"seller"`
+	Proxy          common.Address `json:"proxy"`
+	SecondaryProxy common.Address `json:"secondaryProxy"`
+	OfferedPrice   *hexutil.Big   `json:"offeredPrice"`
+	ProxyFee       *hexutil.Big   `json:"proxyFee"`
+	DeliverHash    common.Hash    `json:"deliverHash"`
+	EndTime        hexutil.Uint64 `json:"endTime"`
+	Status         Status         `json:"status"`
+	DisputeID      hexutil.Uint64 `json:"disputeId"`
+}
+
+// OrderFilter selects the orders matching all of its set criteria.
+type OrderFilter struct {
+	Buyer  *common.Address `json:"buyer"`
+	Seller *common.Address `json:"seller"`
+	Proxy  *common.Address `json:"proxy"` // Either the proxy or the secondary proxy
+	Status *Status         `json:"status"`
+	Page   hexutil.Uint    `json:"page"`
+}
+
+// matches reports whether o matches all criteria of f.
+func (f *OrderFilter) matches(o *Order) bool {
+	if f.Buyer != nil && *f.Buyer != o.Buyer {
+		return false
+	}
+	if f.Seller != nil && *f.Seller != o.Seller {
+		return false
+	}
+	if f.Proxy != nil && *f.Proxy != o.Proxy && *f.Proxy != o.SecondaryProxy {
+		return false
+	}
+	if f.Status != nil && *f.Status != o.Status {
+		return false
+	}
+	return true
+}
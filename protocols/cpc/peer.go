@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -275,6 +276,16 @@ func (p *peer) SendNodeData(data [][]byte) error {
 	return p2p.Send(p.rw, NodeDataMsg, data)
 }
 
+// SendAccountRange sends a range of accounts with its proof.
+func (p *peer) SendAccountRange(rng *syncer.StateRange) error {
+	return p2p.Send(p.rw, AccountRangeMsg, rng)
+}
+
+// SendStorageRange sends a range of storage slots with its proof.
+func (p *peer) SendStorageRange(rng *syncer.StateRange) error {
+	return p2p.Send(p.rw, StorageRangeMsg, rng)
+}
+
 // SendReceiptsRLP sends a batch of transaction receipts, corresponding to the
 // ones requested from an already RLP encoded format.
 func (p *peer) SendReceiptsRLP(receipts []rlp.RawValue) error {
@@ -316,6 +327,20 @@ func (p *peer) RequestNodeData(hashes []common.Hash) error {
 	return p2p.Send(p.rw, GetNodeDataMsg, hashes)
 }
 
+// RequestAccountRange fetches a range of accounts of the state trie with the
+// given root, along with the proofs of its edges.
+func (p *peer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching range of accounts", "root", root, "origin", origin, "limit", limit, "bytes", bytes)
+	return p2p.Send(p.rw, GetAccountRangeMsg, &getAccountRangeData{Root: root, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
+// RequestStorageRange fetches a range of storage slots of an account of the
+// state trie with the given root, along with the proofs of its edges.
+func (p *peer) RequestStorageRange(root, account, origin, limit common.Hash, bytes uint64) error {
+	p.Log().Debug("Fetching range of storage slots", "root", root, "account", account, "origin", origin, "limit", limit, "bytes", bytes)
+	return p2p.Send(p.rw, GetStorageRangeMsg, &getStorageRangeData{Root: root, Account: account, Origin: origin, Limit: limit, Bytes: bytes})
+}
+
 // RequestReceipts fetches a batch of transaction receipts from a remote node.
 func (p *peer) RequestReceipts(hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
@@ -552,6 +577,27 @@ func (ps *peerSet) BestPeer() *peer {
 	return bestPeer
 }
 
+// BestSnapPeer retrieves the known peer with the currently highest total
+// difficulty among the ones serving the state ranges of snap sync.
+func (ps *peerSet) BestSnapPeer() *peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	var (
+		bestPeer *peer
+		bestHt   *big.Int
+	)
+	for _, p := range ps.peers {
+		if p.version < snapVersion {
+			continue
+		}
+		if _, ht := p.Head(); bestPeer == nil || ht.Cmp(bestHt) > 0 {
+			bestPeer, bestHt = p, ht
+		}
+	}
+	return bestPeer
+}
+
 // Close disconnects all peers.
 // No new peers can be registered after Close has returned.
 func (ps *peerSet) Close() {
@@ -19,13 +19,16 @@ import (
 var ProtocolName = "cpc"
 
 // ProtocolVersions are the versions of the cpchain protocol (first is primary).
-var ProtocolVersions = []uint{configs.Cpc1}
+var ProtocolVersions = []uint{configs.Cpc2, configs.Cpc1}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{100}
+var ProtocolLengths = []uint64{100, 100}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
+// snapVersion is the first protocol version serving the state ranges of snap sync.
+const snapVersion = configs.Cpc2
+
 // eth protocol message codes
 const (
 	// Protocol messages belonging to eth/62
@@ -45,6 +48,12 @@ const (
 	NodeDataMsg    = 0x0e
 	GetReceiptsMsg = 0x0f
 	ReceiptsMsg    = 0x10
+
+	// Protocol messages of the snapshot state sync, belonging to cpc/2
+	GetAccountRangeMsg = 0x11
+	AccountRangeMsg    = 0x12
+	GetStorageRangeMsg = 0x13
+	StorageRangeMsg    = 0x14
 )
 
 type errCode int
@@ -167,6 +176,23 @@ func (hn *hashOrNumber) DecodeRLP(s *rlp.Stream) error {
 	return err
 }
 
+// getAccountRangeData represents an account range query.
+type getAccountRangeData struct {
+	Root   common.Hash // State root of the accounts
+	Origin common.Hash // Hash of the first account to retrieve
+	Limit  common.Hash // Hash of the last account to retrieve
+	Bytes  uint64      // Soft limit of the response size
+}
+
+// getStorageRangeData represents a storage range query of an account.
+type getStorageRangeData struct {
+	Root    common.Hash // State root of the account
+	Account common.Hash // Hash of the account address
+	Origin  common.Hash // Hash of the first storage slot to retrieve
+	Limit   common.Hash // Hash of the last storage slot to retrieve
+	Bytes   uint64      // Soft limit of the response size
+}
+
 // newBlockData is the network packet for the block propagation message.
 type newBlockData struct {
 	Block *types.Block
@@ -0,0 +1,359 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rewards implements the RNode reward service, accounting what the
+// investors of the reward contract earned in each of its periods and driving
+// the period transitions of the contract on schedule.
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// rewardABI is the parsed ABI of the reward contract.
+var rewardABI, _ = abi.JSON(strings.NewReader(reward.RewardABI))
+
+// Period is what happened in a period of the reward contract, from the raise
+// starting it to the raise starting the next one.
+type Period struct {
+	Number         uint64
+	RaiseTime      uint64 // Unix time the raise started
+	LockTime       uint64 // Unix time the deposits were locked, 0 if not yet
+	SettlementTime uint64 // Unix time the settlement started, 0 if not yet
+
+	BonusPool   *big.Int                    // Amount funded to the bonus pool during the period
+	Deposits    map[common.Address]*big.Int // Amount deposited by each investor
+	Withdrawals map[common.Address]*big.Int // Amount withdrawn by each investor
+	Interest    map[common.Address]*big.Int // Interest settled to each investor
+	RNodes      map[common.Address]bool     // Investors with an RNode deposit at the end of the period
+}
+
+func newPeriod(number uint64, raiseTime uint64, rnodes map[common.Address]bool) *Period {
+	p := &Period{
+		Number:      number,
+		RaiseTime:   raiseTime,
+		BonusPool:   new(big.Int),
+		Deposits:    make(map[common.Address]*big.Int),
+		Withdrawals: make(map[common.Address]*big.Int),
+		Interest:    make(map[common.Address]*big.Int),
+		RNodes:      make(map[common.Address]bool, len(rnodes)),
+	}
+	for addr := range rnodes {
+		p.RNodes[addr] = true
+	}
+	return p
+}
+
+// copy returns a deep copy of the period.
+func (p *Period) copy() *Period {
+	cpy := newPeriod(p.Number, p.RaiseTime, p.RNodes)
+	cpy.LockTime, cpy.SettlementTime = p.LockTime, p.SettlementTime
+	cpy.BonusPool.Set(p.BonusPool)
+	for _, m := range []struct{ dst, src map[common.Address]*big.Int }{
+		{cpy.Deposits, p.Deposits}, {cpy.Withdrawals, p.Withdrawals}, {cpy.Interest, p.Interest},
+	} {
+		for addr, amount := range m.src {
+			m.dst[addr] = new(big.Int).Set(amount)
+		}
+	}
+	return cpy
+}
+
+// add adds amount to the entry of addr in m.
+func add(m map[common.Address]*big.Int, addr common.Address, amount *big.Int) {
+	if m[addr] == nil {
+		m[addr] = new(big.Int)
+	}
+	m[addr].Add(m[addr], amount)
+}
+
+// reorgWindow is the number of the latest blocks whose events the accountant
+// keeps apart, to drop them if the blocks get reorged. Older events are folded
+// into the periods for good.
+const reorgWindow = 128
+
+// AccountantBackend is the chain access needed by the accountant.
+type AccountantBackend interface {
+	bind.ContractFilterer
+
+	// HeaderByNumber returns a header of the canonical chain, the latest one if
+	// number is nil.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// blockID identifies a block of the chain.
+type blockID struct {
+	number uint64
+	hash   common.Hash
+}
+
+// Accountant computes the periods of the reward contract from its events. It
+// follows the chain head, only fetching the events of the blocks it has not
+// seen yet, and drops the events of the blocks reorged out of the chain.
+type Accountant struct {
+	contract common.Address
+	backend  AccountantBackend
+
+	mu       sync.Mutex
+	folded   *periodSet  // Periods as of the folded block
+	foldedAt *blockID    // Last block folded into the periods, nil if none
+	recent   []types.Log // Events of the blocks after the folded one, up to the synced one
+	synced   *blockID    // Last block whose events were fetched, nil if none
+}
+
+// NewAccountant returns an accountant of the reward contract at address.
+func NewAccountant(contract common.Address, backend AccountantBackend) *Accountant {
+	return &Accountant{contract: contract, backend: backend, folded: newPeriodSet()}
+}
+
+// Periods returns all periods of the reward contract started so far, the
+// current one being the last.
+func (a *Accountant) Periods(ctx context.Context) ([]*Period, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	head, err := a.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.rewind(ctx); err != nil {
+		return nil, err
+	}
+	if err := a.sync(ctx, head); err != nil {
+		return nil, err
+	}
+	if err := a.fold(ctx, head); err != nil {
+		return nil, err
+	}
+
+	set := a.folded.copy()
+	for _, log := range a.recent {
+		if err := set.apply(log); err != nil {
+			return nil, err
+		}
+	}
+	return set.periods, nil
+}
+
+// rewind drops the events of the blocks no longer in the canonical chain,
+// starting over if even the folded ones were reorged.
+func (a *Accountant) rewind(ctx context.Context) error {
+	if a.synced == nil {
+		return nil
+	}
+	canonical, err := a.canonical(ctx, a.synced)
+	if err != nil || canonical {
+		return err
+	}
+	a.recent, a.synced = nil, a.foldedAt
+	if a.foldedAt == nil {
+		return nil
+	}
+	if canonical, err = a.canonical(ctx, a.foldedAt); err != nil || canonical {
+		return err
+	}
+	log.Warn("Reward contract events reorged beyond the window, accounting again", "number", a.foldedAt.number)
+	a.folded, a.foldedAt, a.synced = newPeriodSet(), nil, nil
+	return nil
+}
+
+// canonical returns whether a block is in the canonical chain.
+func (a *Accountant) canonical(ctx context.Context, id *blockID) (bool, error) {
+	header, err := a.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(id.number))
+	if err != nil {
+		return false, err
+	}
+	return header != nil && header.Hash() == id.hash, nil
+}
+
+// sync fetches the events of the blocks after the synced one up to head.
+func (a *Accountant) sync(ctx context.Context, head *types.Header) error {
+	from := uint64(0)
+	if a.synced != nil {
+		from = a.synced.number + 1
+	}
+	to := head.Number.Uint64()
+	if from > to {
+		return nil
+	}
+	logs, err := a.backend.FilterLogs(ctx, cpchain.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{a.contract},
+	})
+	if err != nil {
+		return err
+	}
+	for _, log := range logs {
+		if !log.Removed {
+			a.recent = append(a.recent, log)
+		}
+	}
+	a.synced = &blockID{number: to, hash: head.Hash()}
+	return nil
+}
+
+// fold folds the events of the blocks out of the reorg window into the periods.
+func (a *Accountant) fold(ctx context.Context, head *types.Header) error {
+	if head.Number.Uint64() <= reorgWindow {
+		return nil
+	}
+	number := head.Number.Uint64() - reorgWindow
+	if a.foldedAt != nil && a.foldedAt.number >= number {
+		return nil
+	}
+	header, err := a.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil || header == nil {
+		return err
+	}
+	n := 0
+	for ; n < len(a.recent) && a.recent[n].BlockNumber <= number; n++ {
+		if err := a.folded.apply(a.recent[n]); err != nil {
+			return err
+		}
+	}
+	a.recent = append([]types.Log(nil), a.recent[n:]...)
+	a.foldedAt = &blockID{number: number, hash: header.Hash()}
+	return nil
+}
+
+// periodSet is the state of the periods as of a block.
+type periodSet struct {
+	periods []*Period
+	current *Period
+}
+
+func newPeriodSet() *periodSet {
+	return &periodSet{current: newPeriod(0, 0, nil)}
+}
+
+// copy returns a deep copy of the periods.
+func (s *periodSet) copy() *periodSet {
+	cpy := &periodSet{periods: make([]*Period, len(s.periods))}
+	for i, p := range s.periods {
+		cpy.periods[i] = p.copy()
+	}
+	if len(s.periods) != 0 {
+		cpy.current = cpy.periods[len(cpy.periods)-1]
+	} else {
+		cpy.current = s.current.copy()
+	}
+	return cpy
+}
+
+// apply accounts an event of the reward contract.
+func (s *periodSet) apply(log types.Log) error {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	event, err := eventByID(log.Topics[0])
+	if err != nil {
+		return nil
+	}
+	current := s.current
+	switch event {
+	case "NewRaise":
+		var ev reward.RewardNewRaise
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		s.current = newPeriod(uint64(len(s.periods))+1, ev.When.Uint64(), current.RNodes)
+		s.periods = append(s.periods, s.current)
+
+	case "NewLock":
+		var ev reward.RewardNewLock
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		current.LockTime = ev.When.Uint64()
+
+	case "NewSettlement":
+		var ev reward.RewardNewSettlement
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		current.SettlementTime = ev.When.Uint64()
+
+	case "FundBonusPool":
+		var ev reward.RewardFundBonusPool
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		current.BonusPool.Add(current.BonusPool, ev.Amount)
+
+	case "AddInvestment":
+		var ev reward.RewardAddInvestment
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		add(current.Deposits, ev.Who, ev.Amount)
+
+	case "SubInvestment":
+		var ev reward.RewardSubInvestment
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		add(current.Withdrawals, ev.Who, ev.Amount)
+
+	case "ApplyForSettlement":
+		var ev reward.RewardApplyForSettlement
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		add(current.Interest, ev.Who, ev.Income)
+
+	case "NewEnode":
+		var ev reward.RewardNewEnode
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		current.RNodes[ev.Who] = true
+
+	case "EnodeQuit":
+		var ev reward.RewardEnodeQuit
+		if err := unpack(&ev, event, log); err != nil {
+			return err
+		}
+		delete(current.RNodes, ev.Who)
+	}
+	return nil
+}
+
+// eventByID returns the name of the reward contract event with the given id.
+func eventByID(id common.Hash) (string, error) {
+	for name, event := range rewardABI.Events {
+		if event.Id() == id {
+			return name, nil
+		}
+	}
+	return "", errUnknownEvent
+}
+
+// unpack unpacks the non indexed fields of a reward contract event.
+func unpack(out interface{}, event string, log types.Log) error {
+	return rewardABI.Unpack(out, event, log.Data)
+}
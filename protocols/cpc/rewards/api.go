@@ -0,0 +1,232 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	errNotStarted     = errors.New("reward service not started")
+	errInvalidPeriods = errors.New("invalid period range")
+	errUnknownPeriod  = errors.New("period not started yet")
+)
+
+// Service is the RNode reward service, serving the accounting of the reward
+// contract and optionally driving its period transitions.
+type Service struct {
+	contract common.Address
+
+	mu         sync.RWMutex
+	accountant *Accountant
+	scheduler  *Scheduler
+}
+
+// NewService returns a reward service of the reward contract at address. It
+// serves nothing until started.
+func NewService(contract common.Address) *Service {
+	return &Service{contract: contract}
+}
+
+// Start starts serving the accounting of the reward contract from backend.
+// If key is not nil, the period transitions are driven with it as well.
+func (s *Service) Start(backend Backend, key *ecdsa.PrivateKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accountant = NewAccountant(s.contract, backend)
+	if key != nil {
+		scheduler, err := NewScheduler(s.contract, backend, key)
+		if err != nil {
+			return err
+		}
+		scheduler.accountant = s.accountant
+		s.scheduler = scheduler
+		s.scheduler.Start()
+		log.Info("Started driving reward contract", "contract", s.contract.Hex())
+	}
+	return nil
+}
+
+// Stop stops driving the period transitions, if started.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+		s.scheduler = nil
+	}
+}
+
+// APIs returns the collection of RPC services the reward service offers.
+func (s *Service) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "reward",
+			Version:   "1.0",
+			Service:   &PublicRewardAPI{s},
+			Public:    true,
+		},
+	}
+}
+
+// periods returns the periods of the reward contract started so far.
+func (s *Service) periods(ctx context.Context) ([]*Period, error) {
+	s.mu.RLock()
+	accountant := s.accountant
+	s.mu.RUnlock()
+
+	if accountant == nil {
+		return nil, errNotStarted
+	}
+	return accountant.Periods(ctx)
+}
+
+// PeriodStatement is what an investor earned in a period.
+type PeriodStatement struct {
+	Period         hexutil.Uint64 `json:"period"`
+	RaiseTime      hexutil.Uint64 `json:"raiseTime"`
+	LockTime       hexutil.Uint64 `json:"lockTime"`
+	SettlementTime hexutil.Uint64 `json:"settlementTime"`
+	Deposited      *hexutil.Big   `json:"deposited"`
+	Withdrawn      *hexutil.Big   `json:"withdrawn"`
+	Interest       *hexutil.Big   `json:"interest"`
+	RNode          bool           `json:"rnode"`
+}
+
+// InvestorStatement is what an investor earned over a range of periods.
+type InvestorStatement struct {
+	Investor common.Address     `json:"investor"`
+	Periods  []*PeriodStatement `json:"periods"`
+	Interest *hexutil.Big       `json:"interest"`
+}
+
+// Earning is what an investor earned in a period.
+type Earning struct {
+	Investor common.Address `json:"investor"`
+	Interest *hexutil.Big   `json:"interest"`
+	RNode    bool           `json:"rnode"`
+}
+
+// PeriodSummary is what all investors earned in a period.
+type PeriodSummary struct {
+	Period         hexutil.Uint64 `json:"period"`
+	RaiseTime      hexutil.Uint64 `json:"raiseTime"`
+	LockTime       hexutil.Uint64 `json:"lockTime"`
+	SettlementTime hexutil.Uint64 `json:"settlementTime"`
+	BonusPool      *hexutil.Big   `json:"bonusPool"`
+	Earnings       []*Earning     `json:"earnings"`
+}
+
+// PublicRewardAPI serves the accounting of the reward contract.
+type PublicRewardAPI struct {
+	s *Service
+}
+
+// GetInvestorStatement returns what investor earned in the periods from
+// fromPeriod to toPeriod inclusive, up to the current period if toPeriod is 0.
+// Periods are numbered from 1, the first raise of the reward contract.
+func (api *PublicRewardAPI) GetInvestorStatement(ctx context.Context, investor common.Address, fromPeriod, toPeriod hexutil.Uint64) (*InvestorStatement, error) {
+	periods, err := api.s.periods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, to := uint64(fromPeriod), uint64(toPeriod)
+	if to == 0 || to > uint64(len(periods)) {
+		to = uint64(len(periods))
+	}
+	if from == 0 || from > to {
+		return nil, errInvalidPeriods
+	}
+
+	statement := &InvestorStatement{Investor: investor, Periods: []*PeriodStatement{}}
+	total := new(big.Int)
+	for _, p := range periods[from-1 : to] {
+		interest := amountOf(p.Interest, investor)
+		total.Add(total, interest)
+		statement.Periods = append(statement.Periods, &PeriodStatement{
+			Period:         hexutil.Uint64(p.Number),
+			RaiseTime:      hexutil.Uint64(p.RaiseTime),
+			LockTime:       hexutil.Uint64(p.LockTime),
+			SettlementTime: hexutil.Uint64(p.SettlementTime),
+			Deposited:      (*hexutil.Big)(amountOf(p.Deposits, investor)),
+			Withdrawn:      (*hexutil.Big)(amountOf(p.Withdrawals, investor)),
+			Interest:       (*hexutil.Big)(interest),
+			RNode:          p.RNodes[investor],
+		})
+	}
+	statement.Interest = (*hexutil.Big)(total)
+	return statement, nil
+}
+
+// GetPeriod returns what every investor and RNode earned in a period.
+func (api *PublicRewardAPI) GetPeriod(ctx context.Context, period hexutil.Uint64) (*PeriodSummary, error) {
+	periods, err := api.s.periods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if period == 0 || uint64(period) > uint64(len(periods)) {
+		return nil, errUnknownPeriod
+	}
+	p := periods[period-1]
+
+	investors := make(map[common.Address]bool)
+	for addr := range p.Interest {
+		investors[addr] = true
+	}
+	for addr := range p.RNodes {
+		investors[addr] = true
+	}
+	summary := &PeriodSummary{
+		Period:         hexutil.Uint64(p.Number),
+		RaiseTime:      hexutil.Uint64(p.RaiseTime),
+		LockTime:       hexutil.Uint64(p.LockTime),
+		SettlementTime: hexutil.Uint64(p.SettlementTime),
+		BonusPool:      (*hexutil.Big)(p.BonusPool),
+		Earnings:       make([]*Earning, 0, len(investors)),
+	}
+	for addr := range investors {
+		summary.Earnings = append(summary.Earnings, &Earning{
+			Investor: addr,
+			Interest: (*hexutil.Big)(amountOf(p.Interest, addr)),
+			RNode:    p.RNodes[addr],
+		})
+	}
+	sort.Slice(summary.Earnings, func(i, j int) bool {
+		return summary.Earnings[i].Investor.Hex() < summary.Earnings[j].Investor.Hex()
+	})
+	return summary, nil
+}
+
+// amountOf returns the amount of addr in m, zero if none.
+func amountOf(m map[common.Address]*big.Int, addr common.Address) *big.Int {
+	if amount := m[addr]; amount != nil {
+		return new(big.Int).Set(amount)
+	}
+	return new(big.Int)
+}
@@ -0,0 +1,286 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	rnodeKey, _    = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+	investorKey, _ = crypto.HexToECDSA("49a7b37aa6f6645917e7b807e9d1c00d4fa71f18343b0d4122a4d2df64dd6fee")
+)
+
+// minedBackend mines every transaction as soon as it is sent.
+type minedBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b minedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.SimulatedBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	b.Commit()
+	return nil
+}
+
+func cpc(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(configs.Cpc))
+}
+
+func TestRewardService(t *testing.T) {
+	var (
+		rnode    = crypto.PubkeyToAddress(rnodeKey.PublicKey)
+		investor = crypto.PubkeyToAddress(investorKey.PublicKey)
+		backend  = minedBackend{backends.NewDporSimulatedBackend(core.GenesisAlloc{
+			crypto.PubkeyToAddress(ownerKey.PublicKey): {Balance: cpc(100000)},
+			rnode:    {Balance: cpc(100000)},
+			investor: {Balance: cpc(100000)},
+		})}
+		owner = bind.NewKeyedTransactor(ownerKey)
+	)
+	address, _, contract, err := reward.DeployReward(owner, backend)
+	if err != nil {
+		t.Fatalf("failed to deploy reward contract: %v", err)
+	}
+	for _, send := range []func(*bind.TransactOpts) (*types.Transaction, error){
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetRaisePeriod(opts, big.NewInt(100))
+		},
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetLockPeriod(opts, big.NewInt(1000))
+		},
+		func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return contract.SetSettlementPeriod(opts, big.NewInt(100))
+		},
+		contract.NewRaise,
+	} {
+		if _, err := send(owner); err != nil {
+			t.Fatalf("failed to set up reward contract: %v", err)
+		}
+	}
+	for _, d := range []struct {
+		opts   *bind.TransactOpts
+		amount *big.Int
+	}{
+		{bind.NewKeyedTransactor(rnodeKey), cpc(25000)},
+		{bind.NewKeyedTransactor(investorKey), cpc(100)},
+	} {
+		d.opts.Value = d.amount
+		if _, err := contract.Deposit(d.opts); err != nil {
+			t.Fatalf("failed to deposit: %v", err)
+		}
+	}
+	owner.Value = cpc(1000)
+	if _, err := (&reward.RewardRaw{Contract: contract}).Transfer(owner); err != nil {
+		t.Fatalf("failed to fund bonus pool: %v", err)
+	}
+	owner.Value = nil
+
+	scheduler, err := NewScheduler(address, backend, ownerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// advance moves the chain time forward and lets the scheduler drive the
+	// contract
+	advance := func(seconds int64) {
+		backend.AdjustTime(time.Duration(seconds) * time.Second)
+		backend.Commit()
+		if err := scheduler.step(); err != nil {
+			t.Fatalf("failed to drive reward contract: %v", err)
+		}
+	}
+	advance(10)
+	if inRaise, _ := contract.InRaise(nil); !inRaise {
+		t.Fatalf("raise ended early")
+	}
+	advance(100)
+	if inLock, _ := contract.InLock(nil); !inLock {
+		t.Fatalf("deposits not locked after the raise")
+	}
+	advance(1000)
+	if inSettlement, _ := contract.InSettlement(nil); !inSettlement {
+		t.Fatalf("settlement not started after the lock")
+	}
+	advance(100)
+	if round, _ := contract.Round(nil); round.Uint64() != 2 {
+		t.Fatalf("next raise not started after the settlement: round %v", round)
+	}
+
+	// The statements account the interest of the settled period
+	service := NewService(address)
+	api := service.APIs()[0].Service.(*PublicRewardAPI)
+	if _, err := api.GetInvestorStatement(context.Background(), rnode, 1, 0); err != errNotStarted {
+		t.Fatalf("statement error mismatch before start: have %v, want %v", err, errNotStarted)
+	}
+	if err := service.Start(backend, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer service.Stop()
+
+	statement, err := api.GetInvestorStatement(context.Background(), rnode, 1, 0)
+	if err != nil {
+		t.Fatalf("failed to get statement: %v", err)
+	}
+	if len(statement.Periods) != 2 {
+		t.Fatalf("statement periods mismatch: have %d, want 2", len(statement.Periods))
+	}
+	first := statement.Periods[0]
+	if first.Deposited.ToInt().Cmp(cpc(25000)) != 0 || !first.RNode || first.LockTime == 0 || first.SettlementTime == 0 {
+		t.Fatalf("first period mismatch: %+v", first)
+	}
+	// the bonus pool is shared by all investments
+	want := new(big.Int).Div(new(big.Int).Mul(cpc(1000), big.NewInt(25000)), big.NewInt(25100))
+	if statement.Interest.ToInt().Cmp(want) != 0 || first.Interest.ToInt().Cmp(want) != 0 {
+		t.Fatalf("interest mismatch: have %v, want %v", statement.Interest.ToInt(), want)
+	}
+	if _, err := api.GetInvestorStatement(context.Background(), rnode, 2, 1); err != errInvalidPeriods {
+		t.Fatalf("reversed range error mismatch: have %v, want %v", err, errInvalidPeriods)
+	}
+
+	// Investors without an RNode deposit are not settled
+	statement, err = api.GetInvestorStatement(context.Background(), investor, 1, 1)
+	if err != nil {
+		t.Fatalf("failed to get statement: %v", err)
+	}
+	if period := statement.Periods[0]; period.RNode || period.Interest.ToInt().Sign() != 0 || period.Deposited.ToInt().Cmp(cpc(100)) != 0 {
+		t.Fatalf("investor period mismatch: %+v", period)
+	}
+
+	summary, err := api.GetPeriod(context.Background(), hexutil.Uint64(1))
+	if err != nil {
+		t.Fatalf("failed to get period: %v", err)
+	}
+	if summary.BonusPool.ToInt().Cmp(cpc(1000)) != 0 || len(summary.Earnings) != 1 || summary.Earnings[0].Investor != rnode {
+		t.Fatalf("period summary mismatch: %+v", summary)
+	}
+	if _, err := api.GetPeriod(context.Background(), hexutil.Uint64(3)); err != errUnknownPeriod {
+		t.Fatalf("future period error mismatch: have %v, want %v", err, errUnknownPeriod)
+	}
+}
+
+// queryBackend records the log queries and can pretend blocks were reorged.
+type queryBackend struct {
+	minedBackend
+	queries []cpchain.FilterQuery
+	reorged map[uint64]bool
+}
+
+func (b *queryBackend) FilterLogs(ctx context.Context, query cpchain.FilterQuery) ([]types.Log, error) {
+	b.queries = append(b.queries, query)
+	return b.minedBackend.FilterLogs(ctx, query)
+}
+
+func (b *queryBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := b.minedBackend.HeaderByNumber(ctx, number)
+	if header != nil && number != nil && b.reorged[number.Uint64()] {
+		header = types.CopyHeader(header)
+		header.Extra = []byte("reorged")
+	}
+	return header, err
+}
+
+func TestAccountantFollowsChain(t *testing.T) {
+	var (
+		rnode   = crypto.PubkeyToAddress(rnodeKey.PublicKey)
+		backend = &queryBackend{minedBackend: minedBackend{backends.NewDporSimulatedBackend(core.GenesisAlloc{
+			crypto.PubkeyToAddress(ownerKey.PublicKey): {Balance: cpc(100000)},
+			rnode: {Balance: cpc(100000)},
+		})}}
+		owner = bind.NewKeyedTransactor(ownerKey)
+	)
+	address, _, contract, err := reward.DeployReward(owner, backend)
+	if err != nil {
+		t.Fatalf("failed to deploy reward contract: %v", err)
+	}
+	if _, err := contract.NewRaise(owner); err != nil {
+		t.Fatalf("failed to start raise: %v", err)
+	}
+	accountant := NewAccountant(address, backend)
+	periods, err := accountant.Periods(context.Background())
+	if err != nil || len(periods) != 1 {
+		t.Fatalf("periods mismatch: have %d, %v, want 1", len(periods), err)
+	}
+
+	// Only the blocks not seen yet are queried
+	head := backend.Blockchain().CurrentBlock().NumberU64()
+	opts := bind.NewKeyedTransactor(rnodeKey)
+	opts.Value = cpc(25000)
+	if _, err := contract.Deposit(opts); err != nil {
+		t.Fatalf("failed to deposit: %v", err)
+	}
+	backend.queries = nil
+	periods, err = accountant.Periods(context.Background())
+	if err != nil || len(periods) != 1 || periods[0].Deposits[rnode].Cmp(cpc(25000)) != 0 {
+		t.Fatalf("periods mismatch after deposit: %v, %v", periods, err)
+	}
+	if len(backend.queries) != 1 || backend.queries[0].FromBlock.Uint64() != head+1 {
+		t.Fatalf("queries mismatch: %+v, want one from block %d", backend.queries, head+1)
+	}
+	backend.queries = nil
+	if _, err := accountant.Periods(context.Background()); err != nil || len(backend.queries) != 0 {
+		t.Fatalf("blocks queried again: %+v, %v", backend.queries, err)
+	}
+	// Returned periods are not shared with the accountant
+	periods[0].Deposits[rnode].SetInt64(0)
+
+	// The events of reorged blocks are fetched again
+	backend.reorged = map[uint64]bool{backend.Blockchain().CurrentBlock().NumberU64(): true}
+	periods, err = accountant.Periods(context.Background())
+	if err != nil || len(periods) != 1 || periods[0].Deposits[rnode].Cmp(cpc(25000)) != 0 {
+		t.Fatalf("periods mismatch after reorg: %v, %v", periods, err)
+	}
+	if len(backend.queries) != 1 || backend.queries[0].FromBlock.Uint64() != 0 {
+		t.Fatalf("queries mismatch after reorg: %+v, want one from genesis", backend.queries)
+	}
+
+	// Events out of the reorg window are kept for good
+	for i := 0; i < reorgWindow+5; i++ {
+		backend.Commit()
+	}
+	backend.reorged = nil
+	if _, err := accountant.Periods(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	folded := accountant.foldedAt
+	if folded == nil || len(accountant.recent) != 0 {
+		t.Fatalf("events not folded: %v, %d recent", folded, len(accountant.recent))
+	}
+	backend.queries = nil
+	backend.reorged = map[uint64]bool{backend.Blockchain().CurrentBlock().NumberU64(): true}
+	periods, err = accountant.Periods(context.Background())
+	if err != nil || len(periods) != 1 || periods[0].Deposits[rnode].Cmp(cpc(25000)) != 0 {
+		t.Fatalf("periods mismatch after folding: %v, %v", periods, err)
+	}
+	if len(backend.queries) != 1 || backend.queries[0].FromBlock.Uint64() != folded.number+1 {
+		t.Fatalf("queries mismatch after reorg: %+v, want one from block %d", backend.queries, folded.number+1)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewards
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// scheduleInterval is the time between two checks of whether a period
+	// transition of the reward contract is due.
+	scheduleInterval = time.Minute
+
+	// transitionTimeout is the maximum time to wait for a transition
+	// transaction to be mined.
+	transitionTimeout = 5 * time.Minute
+)
+
+var (
+	errUnknownEvent     = errors.New("unknown reward contract event")
+	errTransitionFailed = errors.New("reward contract transition failed")
+)
+
+// Backend is the chain access needed by the reward service.
+type Backend interface {
+	bind.ContractBackend
+
+	// HeaderByNumber returns a header of the canonical chain, the latest one if
+	// number is nil.
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+
+	// TransactionReceipt returns the receipt of a mined transaction.
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Scheduler drives the period transitions of the reward contract on schedule:
+// it locks the deposits once the raise is over, starts the settlement once the
+// lock is over, settles the interest of every RNode and starts the next raise
+// once the settlement is over. Its key must own the reward contract.
+type Scheduler struct {
+	contract   *reward.Reward
+	backend    Backend
+	accountant *Accountant
+	opts       *bind.TransactOpts
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewScheduler returns a scheduler of the reward contract at address sending
+// its transitions with key.
+func NewScheduler(address common.Address, backend Backend, key *ecdsa.PrivateKey) (*Scheduler, error) {
+	contract, err := reward.NewReward(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheduler{
+		contract:   contract,
+		backend:    backend,
+		accountant: NewAccountant(address, backend),
+		opts:       bind.NewKeyedTransactor(key),
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// Start starts checking whether a transition is due in the background.
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// Stop stops the scheduler and waits for the transition in progress, if any.
+func (s *Scheduler) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.step(); err != nil {
+			log.Warn("Failed to drive reward contract", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// step sends the transitions due at the time of the latest block.
+func (s *Scheduler) step() error {
+	ctx, cancel := context.WithTimeout(context.Background(), transitionTimeout)
+	defer cancel()
+
+	head, err := s.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// block times are in milliseconds, the contract's in seconds
+	now := new(big.Int).Div(head.Time, big.NewInt(1000))
+	opts := &bind.CallOpts{Context: ctx}
+
+	inRaise, err := s.contract.InRaise(opts)
+	if err != nil {
+		return err
+	}
+	inLock, err := s.contract.InLock(opts)
+	if err != nil {
+		return err
+	}
+	inSettlement, err := s.contract.InSettlement(opts)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case inRaise:
+		next, err := s.contract.NextLockTime(opts)
+		if err != nil || now.Cmp(next) < 0 {
+			return err
+		}
+		return s.transact(ctx, "lock", s.contract.NewLock)
+
+	case inLock:
+		next, err := s.contract.NextSettlementTime(opts)
+		if err != nil || now.Cmp(next) < 0 {
+			return err
+		}
+		return s.transact(ctx, "settlement", s.contract.NewSettlement)
+
+	case inSettlement:
+		if err := s.settle(ctx); err != nil {
+			return err
+		}
+		next, err := s.contract.NextRaiseTime(opts)
+		if err != nil || now.Cmp(next) < 0 {
+			return err
+		}
+		return s.transact(ctx, "raise", s.contract.NewRaise)
+	}
+	// a disabled contract is left alone
+	return nil
+}
+
+// settle distributes the interest of the RNodes not settled yet in the
+// current period.
+func (s *Scheduler) settle(ctx context.Context) error {
+	rnodes, err := s.contract.GetEnodes(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	periods, err := s.accountant.Periods(ctx)
+	if err != nil {
+		return err
+	}
+	if len(periods) == 0 {
+		return nil
+	}
+	current := periods[len(periods)-1]
+	for _, rnode := range rnodes {
+		if current.Interest[rnode] != nil {
+			continue
+		}
+		rnode := rnode
+		err := s.transact(ctx, "interest", func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return s.contract.DistributeInterest(opts, rnode)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transact sends a transition and waits for it to be mined.
+func (s *Scheduler) transact(ctx context.Context, name string, send func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	opts := *s.opts
+	opts.Context = ctx
+	tx, err := send(&opts)
+	if err != nil {
+		return err
+	}
+	receipt, err := bind.WaitMined(ctx, s.backend, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("%v: %v %x", errTransitionFailed, name, tx.Hash())
+	}
+	log.Info("Drove reward contract", "transition", name, "tx", tx.Hash().Hex())
+	return nil
+}
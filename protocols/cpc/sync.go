@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 
 	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
 	"bitbucket.org/cpchain/chain/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/p2p/discover"
@@ -161,11 +162,11 @@ func (pm *ProtocolManager) syncerLoop() {
 			}
 			pm.syncer.AddPeer(peer)
 			// update from peers
-			go pm.synchronize(pm.peers.BestPeer())
+			go pm.synchronize(pm.bestSyncPeer())
 
 		case <-forceSync.C:
 			// Force a sync even if not enough peers are present
-			go pm.synchronize(pm.peers.BestPeer())
+			go pm.synchronize(pm.bestSyncPeer())
 
 		case <-pm.noMorePeers:
 			return
@@ -181,7 +182,18 @@ func (pm *ProtocolManager) SyncFromPeer(p *p2p.Peer) {
 }
 
 func (pm *ProtocolManager) SyncFromBestPeer() {
-	go pm.synchronize(pm.peers.BestPeer())
+	go pm.synchronize(pm.bestSyncPeer())
+}
+
+// bestSyncPeer retrieves the peer to sync with. Snap sync goes to the best of
+// the peers serving state ranges, if there is none the best peer is fast synced.
+func (pm *ProtocolManager) bestSyncPeer() *peer {
+	if pm.syncMode == syncer.SnapSync {
+		if p := pm.peers.BestSnapPeer(); p != nil {
+			return p
+		}
+	}
+	return pm.peers.BestPeer()
 }
 
 // Synchronise tries to sync up our local block chain with a remote peer. It fetches blocks a peer.
@@ -198,8 +210,14 @@ func (pm *ProtocolManager) synchronize(peer *peer) {
 		return
 	}
 
+	// peers before cpc/2 can not serve the state ranges of snap sync
+	mode := pm.syncMode
+	if mode == syncer.SnapSync && peer.version < snapVersion {
+		mode = syncer.FastSync
+	}
+
 	// full sync with the downloader
-	if err := pm.syncer.Synchronise(peer, pHead, pHt, pm.syncMode); err != nil {
+	if err := pm.syncer.Synchronise(peer, pHead, pHt, mode); err != nil {
 		return
 	}
 
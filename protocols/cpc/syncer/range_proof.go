@@ -0,0 +1,601 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package syncer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// The range proof verification rebuilds the part of a trie between two edge
+// proofs. The vendored trie package keeps its nodes private, so the nodes are
+// modelled again here, following its encoding.
+type (
+	rangeNode interface{}
+
+	fullNode struct {
+		children [17]rangeNode // Index 16 holds the value of a key ending here
+	}
+	shortNode struct {
+		key []byte // Hex nibbles, terminated by 16 for a leaf
+		val rangeNode
+	}
+	hashNode  []byte
+	valueNode []byte
+)
+
+var (
+	// errRangeNotMonotonic is returned if the keys of a range are not strictly
+	// increasing.
+	errRangeNotMonotonic = errors.New("range not monotonically increasing")
+
+	// errRangeMismatch is returned if a range and its edge proofs do not
+	// rebuild the trie root.
+	errRangeMismatch = errors.New("range not proven")
+
+	// errRangeIncomplete is returned if an empty range is proven while the
+	// trie holds more entries.
+	errRangeIncomplete = errors.New("more entries available")
+
+	errEmptyRange = errors.New("empty range")
+)
+
+// verifyRange checks that keys and values are all the leaves of the trie with
+// the given root between firstKey and lastKey, without any gap. The proof
+// holds the nodes proving firstKey and lastKey, either of which may be absent
+// from the trie. With a nil proof, the range must be the whole trie. It
+// returns whether the trie holds more leaves past the range.
+func verifyRange(root common.Hash, firstKey []byte, lastKey []byte, keys [][]byte, values [][]byte, proof trie.DatabaseReader) (bool, error) {
+	if len(keys) != len(values) {
+		return false, fmt.Errorf("inconsistent range, keys: %d, values: %d", len(keys), len(values))
+	}
+	for i := 0; i < len(keys)-1; i++ {
+		if bytes.Compare(keys[i], keys[i+1]) >= 0 {
+			return false, errRangeNotMonotonic
+		}
+	}
+	// Without proof, the range is the whole trie
+	if proof == nil {
+		var tr rangeNode
+		for i, key := range keys {
+			var err error
+			if tr, err = insertNode(tr, keybytesToHex(key), valueNode(values[i])); err != nil {
+				return false, err
+			}
+		}
+		if rangeRoot(tr) != root {
+			return false, errRangeMismatch
+		}
+		return false, nil
+	}
+	// An empty range must prove that nothing follows firstKey
+	if len(keys) == 0 {
+		tr, value, err := proofToPath(root, nil, firstKey, proof, true)
+		if err != nil {
+			return false, err
+		}
+		if value != nil || hasRightElement(tr, firstKey) {
+			return false, errRangeIncomplete
+		}
+		return false, nil
+	}
+	// A single leaf proven by itself can't have two distinct edge paths
+	if len(keys) == 1 && bytes.Equal(firstKey, lastKey) {
+		tr, value, err := proofToPath(root, nil, firstKey, proof, false)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(firstKey, keys[0]) || !bytes.Equal(value, values[0]) {
+			return false, errRangeMismatch
+		}
+		return hasRightElement(tr, firstKey), nil
+	}
+	if bytes.Compare(firstKey, lastKey) >= 0 || len(firstKey) != len(lastKey) {
+		return false, errors.New("invalid edge keys")
+	}
+	// Resolve both edge paths, drop everything between them and fill the gap
+	// in with the range: only the complete range gives back the root.
+	tr, _, err := proofToPath(root, nil, firstKey, proof, true)
+	if err != nil {
+		return false, err
+	}
+	if tr, _, err = proofToPath(root, tr, lastKey, proof, true); err != nil {
+		return false, err
+	}
+	empty, err := unsetInternal(tr, firstKey, lastKey)
+	if err != nil {
+		return false, err
+	}
+	if empty {
+		tr = nil
+	}
+	for i, key := range keys {
+		if tr, err = insertNode(tr, keybytesToHex(key), valueNode(values[i])); err != nil {
+			return false, err
+		}
+	}
+	if rangeRoot(tr) != root {
+		return false, errRangeMismatch
+	}
+	return hasRightElement(tr, keys[len(keys)-1]), nil
+}
+
+// proofToPath resolves the path of key from the proof nodes, starting from the
+// root or merging into an already resolved one. Subtries off the path stay
+// hash nodes. It returns the value at the key, if the proof shows it.
+func proofToPath(rootHash common.Hash, root rangeNode, key []byte, proof trie.DatabaseReader, allowAbsent bool) (rangeNode, []byte, error) {
+	resolve := func(hash []byte) (rangeNode, error) {
+		blob, _ := proof.Get(hash)
+		if blob == nil {
+			return nil, fmt.Errorf("proof node %x missing", hash)
+		}
+		return decodeRangeNode(blob)
+	}
+	if root == nil {
+		n, err := resolve(rootHash[:])
+		if err != nil {
+			return nil, nil, err
+		}
+		root = n
+	}
+	var (
+		parent = root
+		hex    = keybytesToHex(key)
+	)
+	for {
+		rest, child := stepNode(parent, hex)
+		switch c := child.(type) {
+		case nil:
+			// The trie doesn't hold the key, still the resolved nodes are
+			// proven and bound the range
+			if allowAbsent {
+				return root, nil, nil
+			}
+			return nil, nil, errors.New("key not in trie")
+		case *shortNode, *fullNode:
+			hex, parent = rest, child
+			continue
+		case hashNode:
+			resolved, err := resolve(c)
+			if err != nil {
+				return nil, nil, err
+			}
+			switch p := parent.(type) {
+			case *shortNode:
+				p.val = resolved
+			case *fullNode:
+				p.children[hex[0]] = resolved
+			}
+			hex, parent = rest, resolved
+		case valueNode:
+			return root, c, nil
+		}
+	}
+}
+
+// stepNode returns the child of n on the path of key and the rest of the key.
+func stepNode(n rangeNode, key []byte) ([]byte, rangeNode) {
+	switch n := n.(type) {
+	case *shortNode:
+		if len(key) < len(n.key) || !bytes.Equal(n.key, key[:len(n.key)]) {
+			return nil, nil
+		}
+		return key[len(n.key):], n.val
+	case *fullNode:
+		return key[1:], n.children[key[0]]
+	case valueNode:
+		return nil, n
+	}
+	return key, nil
+}
+
+// sameNode reports whether two children are the same resolved node.
+func sameNode(a, b rangeNode) bool {
+	switch a := a.(type) {
+	case *fullNode:
+		b, ok := b.(*fullNode)
+		return ok && a == b
+	case *shortNode:
+		b, ok := b.(*shortNode)
+		return ok && a == b
+	}
+	return false
+}
+
+// unsetInternal removes the nodes between the two resolved edge paths, which
+// the range has to fill in again. It reports whether the whole trie is within
+// the range.
+func unsetInternal(n rangeNode, left []byte, right []byte) (bool, error) {
+	left, right = keybytesToHex(left), keybytesToHex(right)
+
+	// Step down to the fork point of the two paths, a short node either path
+	// leaves or a full node they split at
+	var (
+		pos    int
+		parent rangeNode
+
+		forkLeft, forkRight int // Order of the paths against the short node fork point
+	)
+findFork:
+	for {
+		switch rn := n.(type) {
+		case *shortNode:
+			forkLeft = comparePath(left[pos:], rn.key)
+			forkRight = comparePath(right[pos:], rn.key)
+			if forkLeft != 0 || forkRight != 0 {
+				break findFork
+			}
+			parent, n, pos = n, rn.val, pos+len(rn.key)
+		case *fullNode:
+			l, r := rn.children[left[pos]], rn.children[right[pos]]
+			if l == nil || r == nil || !sameNode(l, r) {
+				break findFork
+			}
+			parent, n, pos = n, l, pos+1
+		default:
+			return false, errRangeMismatch
+		}
+	}
+	switch rn := n.(type) {
+	case *shortNode:
+		// Both paths on the same side of the short node leave nothing to prove
+		if forkLeft == forkRight {
+			return false, errEmptyRange
+		}
+		if forkLeft != 0 && forkRight != 0 {
+			// The short node is within the range
+			if parent == nil {
+				return true, nil
+			}
+			parent.(*fullNode).children[left[pos-1]] = nil
+			return false, nil
+		}
+		if forkRight != 0 {
+			// Only the left path goes through the short node
+			if _, ok := rn.val.(valueNode); ok {
+				if parent == nil {
+					return true, nil
+				}
+				parent.(*fullNode).children[left[pos-1]] = nil
+				return false, nil
+			}
+			return false, unset(rn, rn.val, left[pos:], len(rn.key), false)
+		}
+		// Only the right path goes through the short node
+		if _, ok := rn.val.(valueNode); ok {
+			if parent == nil {
+				return true, nil
+			}
+			parent.(*fullNode).children[right[pos-1]] = nil
+			return false, nil
+		}
+		return false, unset(rn, rn.val, right[pos:], len(rn.key), true)
+	case *fullNode:
+		for i := left[pos] + 1; i < right[pos]; i++ {
+			rn.children[i] = nil
+		}
+		if err := unset(rn, rn.children[left[pos]], left[pos:], 1, false); err != nil {
+			return false, err
+		}
+		return false, unset(rn, rn.children[right[pos]], right[pos:], 1, true)
+	default:
+		return false, errRangeMismatch
+	}
+}
+
+// comparePath compares a path with the key of a short node it reaches.
+func comparePath(path, key []byte) int {
+	if len(path) < len(key) {
+		return bytes.Compare(path, key)
+	}
+	return bytes.Compare(path[:len(key)], key)
+}
+
+// unset removes the children on one side of an edge path below the fork point,
+// the right side of the left path or the left side of the right path.
+func unset(parent rangeNode, child rangeNode, key []byte, pos int, removeLeft bool) error {
+	switch c := child.(type) {
+	case *fullNode:
+		if removeLeft {
+			for i := 0; i < int(key[pos]); i++ {
+				c.children[i] = nil
+			}
+		} else {
+			for i := key[pos] + 1; i < 16; i++ {
+				c.children[i] = nil
+			}
+		}
+		return unset(c, c.children[key[pos]], key, pos+1, removeLeft)
+	case *shortNode:
+		if len(key[pos:]) < len(c.key) || !bytes.Equal(c.key, key[pos:pos+len(c.key)]) {
+			// The path leaves the short node, drop it if it is within the range
+			cmp := bytes.Compare(c.key, key[pos:])
+			if (removeLeft && cmp < 0) || (!removeLeft && cmp > 0) {
+				parent.(*fullNode).children[key[pos-1]] = nil
+			}
+			return nil
+		}
+		if _, ok := c.val.(valueNode); ok {
+			parent.(*fullNode).children[key[pos-1]] = nil
+			return nil
+		}
+		return unset(c, c.val, key, pos+len(c.key), removeLeft)
+	case nil:
+		// An absent child of the fork point
+		return nil
+	default:
+		return errRangeMismatch
+	}
+}
+
+// hasRightElement reports whether the trie holds keys past the resolved path
+// of key.
+func hasRightElement(n rangeNode, key []byte) bool {
+	pos, hex := 0, keybytesToHex(key)
+	for n != nil {
+		switch rn := n.(type) {
+		case *fullNode:
+			for i := hex[pos] + 1; i < 16; i++ {
+				if rn.children[i] != nil {
+					return true
+				}
+			}
+			n, pos = rn.children[hex[pos]], pos+1
+		case *shortNode:
+			if len(hex)-pos < len(rn.key) || !bytes.Equal(rn.key, hex[pos:pos+len(rn.key)]) {
+				return bytes.Compare(rn.key, hex[pos:]) > 0
+			}
+			n, pos = rn.val, pos+len(rn.key)
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// insertNode inserts the value at the hex key. Subtries left as hash nodes
+// can't be inserted into, the edge proofs must have resolved them.
+func insertNode(n rangeNode, key []byte, value rangeNode) (rangeNode, error) {
+	if len(key) == 0 {
+		return value, nil
+	}
+	switch n := n.(type) {
+	case nil:
+		return &shortNode{key: common.CopyBytes(key), val: value}, nil
+	case *shortNode:
+		match := prefixLen(key, n.key)
+		if match == len(n.key) {
+			val, err := insertNode(n.val, key[match:], value)
+			if err != nil {
+				return nil, err
+			}
+			n.val = val
+			return n, nil
+		}
+		branch := new(fullNode)
+		var err error
+		if branch.children[n.key[match]], err = insertNode(nil, n.key[match+1:], n.val); err != nil {
+			return nil, err
+		}
+		if branch.children[key[match]], err = insertNode(nil, key[match+1:], value); err != nil {
+			return nil, err
+		}
+		if match == 0 {
+			return branch, nil
+		}
+		return &shortNode{key: common.CopyBytes(key[:match]), val: branch}, nil
+	case *fullNode:
+		child, err := insertNode(n.children[key[0]], key[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n.children[key[0]] = child
+		return n, nil
+	default:
+		return nil, errRangeMismatch
+	}
+}
+
+// rangeRoot returns the root hash of the trie.
+func rangeRoot(n rangeNode) common.Hash {
+	if n == nil {
+		return types.EmptyRootHash
+	}
+	if hash, ok := n.(hashNode); ok {
+		return common.BytesToHash(hash)
+	}
+	return crypto.Keccak256Hash(encodeRangeNode(n))
+}
+
+// encodeRangeNode returns the RLP encoding of a short or full node.
+func encodeRangeNode(n rangeNode) []byte {
+	var list []interface{}
+	switch n := n.(type) {
+	case *shortNode:
+		list = []interface{}{hexToCompact(n.key), nodeRef(n.val)}
+	case *fullNode:
+		for _, child := range n.children {
+			list = append(list, nodeRef(child))
+		}
+	}
+	enc, _ := rlp.EncodeToBytes(list)
+	return enc
+}
+
+// nodeRef returns how a parent refers to the node: by hash, or embedded if
+// its encoding is shorter than a hash.
+func nodeRef(n rangeNode) interface{} {
+	switch n := n.(type) {
+	case nil:
+		return []byte{}
+	case valueNode:
+		return []byte(n)
+	case hashNode:
+		return []byte(n)
+	}
+	enc := encodeRangeNode(n)
+	if len(enc) < 32 {
+		return rlp.RawValue(enc)
+	}
+	return crypto.Keccak256(enc)
+}
+
+// decodeRangeNode decodes the RLP encoding of a trie node.
+func decodeRangeNode(blob []byte) (rangeNode, error) {
+	elems, _, err := rlp.SplitList(blob)
+	if err != nil {
+		return nil, fmt.Errorf("bad proof node: %v", err)
+	}
+	count, err := rlp.CountValues(elems)
+	if err != nil {
+		return nil, fmt.Errorf("bad proof node: %v", err)
+	}
+	switch count {
+	case 2:
+		kbuf, rest, err := rlp.SplitString(elems)
+		if err != nil {
+			return nil, err
+		}
+		key := compactToHex(kbuf)
+		if hasTerm(key) {
+			val, _, err := rlp.SplitString(rest)
+			if err != nil {
+				return nil, err
+			}
+			return &shortNode{key: key, val: valueNode(common.CopyBytes(val))}, nil
+		}
+		val, _, err := decodeRef(rest)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, errors.New("bad proof node: short node without child")
+		}
+		return &shortNode{key: key, val: val}, nil
+	case 17:
+		n := new(fullNode)
+		for i := 0; i < 16; i++ {
+			child, rest, err := decodeRef(elems)
+			if err != nil {
+				return nil, err
+			}
+			n.children[i], elems = child, rest
+		}
+		val, _, err := rlp.SplitString(elems)
+		if err != nil {
+			return nil, err
+		}
+		if len(val) > 0 {
+			n.children[16] = valueNode(common.CopyBytes(val))
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("bad proof node: %d elements", count)
+	}
+}
+
+// decodeRef decodes a child reference: a hash, an embedded node or nothing.
+func decodeRef(buf []byte) (rangeNode, []byte, error) {
+	kind, val, rest, err := rlp.Split(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	switch {
+	case kind == rlp.List:
+		embedded := buf[:len(buf)-len(rest)]
+		if len(embedded) >= 32 {
+			return nil, buf, errors.New("bad proof node: oversized embedded node")
+		}
+		n, err := decodeRangeNode(embedded)
+		return n, rest, err
+	case kind == rlp.String && len(val) == 0:
+		return nil, rest, nil
+	case kind == rlp.String && len(val) == 32:
+		return hashNode(common.CopyBytes(val)), rest, nil
+	default:
+		return nil, nil, fmt.Errorf("bad proof node: invalid reference size %d", len(val))
+	}
+}
+
+// keybytesToHex splits a key into nibbles, terminated by 16.
+func keybytesToHex(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2+1)
+	for i, b := range key {
+		nibbles[i*2] = b / 16
+		nibbles[i*2+1] = b % 16
+	}
+	nibbles[len(nibbles)-1] = 16
+	return nibbles
+}
+
+// hexToCompact packs nibbles in the compact encoding of short node keys.
+func hexToCompact(hex []byte) []byte {
+	terminator := byte(0)
+	if hasTerm(hex) {
+		terminator = 1
+		hex = hex[:len(hex)-1]
+	}
+	buf := make([]byte, len(hex)/2+1)
+	buf[0] = terminator << 5
+	if len(hex)&1 == 1 {
+		buf[0] |= 1 << 4
+		buf[0] |= hex[0]
+		hex = hex[1:]
+	}
+	for bi, ni := 1, 0; ni < len(hex); bi, ni = bi+1, ni+2 {
+		buf[bi] = hex[ni]<<4 | hex[ni+1]
+	}
+	return buf
+}
+
+// compactToHex unpacks the compact encoding of a short node key.
+func compactToHex(compact []byte) []byte {
+	if len(compact) == 0 {
+		return compact
+	}
+	base := keybytesToHex(compact)
+	// Delete the terminator flag unless the key is a leaf
+	if base[0] < 2 {
+		base = base[:len(base)-1]
+	}
+	// Skip the flag nibble and the padding of even keys
+	chop := 2 - base[0]&1
+	return base[chop:]
+}
+
+// hasTerm returns whether the hex key ends with the terminator.
+func hasTerm(s []byte) bool {
+	return len(s) > 0 && s[len(s)-1] == 16
+}
+
+// prefixLen returns the length of the common prefix of a and b.
+func prefixLen(a, b []byte) int {
+	i := 0
+	for ; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			break
+		}
+	}
+	return i
+}
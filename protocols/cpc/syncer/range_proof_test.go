@@ -0,0 +1,112 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package syncer
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+func TestVerifyRange(t *testing.T) {
+	tr, _ := trie.New(common.Hash{}, trie.NewDatabase(database.NewMemDatabase()))
+	var keys [][]byte
+	for i := 0; i < 200; i++ {
+		key := crypto.Keccak256([]byte{byte(i)})
+		keys = append(keys, key)
+		tr.Update(key, []byte{byte(i), 0x01})
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	root := tr.Hash()
+
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = tr.Get(key)
+	}
+	prove := func(edges ...[]byte) *database.MemDatabase {
+		db := database.NewMemDatabase()
+		for _, edge := range edges {
+			if err := tr.Prove(edge, 0, db); err != nil {
+				t.Fatalf("failed to prove: %v", err)
+			}
+		}
+		return db
+	}
+	// The whole trie needs no proof
+	if more, err := verifyRange(root, nil, nil, keys, values, nil); err != nil || more {
+		t.Fatalf("whole trie: more %v, err %v", more, err)
+	}
+	if _, err := verifyRange(root, nil, nil, keys[1:], values[1:], nil); err == nil {
+		t.Fatalf("partial trie proven as whole")
+	}
+	// Ranges proven by existing and absent edges
+	before := common.CopyBytes(keys[10])
+	before[31]--
+	for _, tt := range []struct {
+		first      []byte
+		start, end int
+	}{
+		{keys[0], 0, 50},
+		{keys[10], 10, 11},
+		{before, 10, 100},
+		{keys[150], 150, len(keys)},
+		{make([]byte, 32), 0, 30},
+	} {
+		last := keys[tt.end-1]
+		nodes := prove(tt.first, last)
+		if tt.end-tt.start == 1 && bytes.Equal(tt.first, last) {
+			nodes = prove(last)
+		}
+		more, err := verifyRange(root, tt.first, last, keys[tt.start:tt.end], values[tt.start:tt.end], nodes)
+		if err != nil {
+			t.Fatalf("range %d-%d: %v", tt.start, tt.end, err)
+		}
+		if more != (tt.end < len(keys)) {
+			t.Fatalf("range %d-%d: more mismatch: have %v", tt.start, tt.end, more)
+		}
+		if tt.end-tt.start < 3 {
+			continue
+		}
+		// Dropping or altering a leaf inside the range must fail
+		gapKeys := append(append([][]byte{}, keys[tt.start:tt.start+1]...), keys[tt.start+2:tt.end]...)
+		gapValues := append(append([][]byte{}, values[tt.start:tt.start+1]...), values[tt.start+2:tt.end]...)
+		if _, err := verifyRange(root, tt.first, last, gapKeys, gapValues, prove(tt.first, last)); err == nil {
+			t.Fatalf("range %d-%d: gap not detected", tt.start, tt.end)
+		}
+		altered := append([][]byte{{0xff}}, values[tt.start+1:tt.end]...)
+		if _, err := verifyRange(root, tt.first, last, keys[tt.start:tt.end], altered, prove(tt.first, last)); err == nil {
+			t.Fatalf("range %d-%d: altered value not detected", tt.start, tt.end)
+		}
+	}
+	// An empty range is only valid past the last key
+	after := common.CopyBytes(keys[len(keys)-1])
+	after[31]++
+	if _, err := verifyRange(root, after, after, nil, nil, prove(after)); err != nil {
+		t.Fatalf("empty range past the end: %v", err)
+	}
+	if _, err := verifyRange(root, before, before, nil, nil, prove(before)); err != errRangeIncomplete {
+		t.Fatalf("empty range error mismatch: have %v, want %v", err, errRangeIncomplete)
+	}
+	if _, err := verifyRange(root, keys[0], keys[2], [][]byte{keys[1], keys[0]}, values[:2], prove(keys[0], keys[2])); err != errRangeNotMonotonic {
+		t.Fatalf("unordered range error mismatch: have %v, want %v", err, errRangeNotMonotonic)
+	}
+}
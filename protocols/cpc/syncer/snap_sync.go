@@ -0,0 +1,306 @@
+package syncer
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+	maxHash       = common.HexToHash("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	errStateDataMismatch = errors.New("received state data not requested")
+)
+
+// processSnapSyncContent downloads the state with the given root from the peer
+// as ranges of accounts and storage slots, verifying each range against the
+// root with its edge proofs, then heals the trie nodes the ranges could not
+// rebuild.
+//
+// Every range is stored as a standalone trie, whose inner nodes match the ones
+// of the state trie wherever the range spans their whole subtrie. The nodes of
+// an account range are only stored after the storage and code of its accounts,
+// so that a state node present in the database always has its whole subtrie
+// there, which is what the healing relies on to skip them.
+func (s *Synchronizer) processSnapSyncContent(p SyncPeer, root common.Hash) error {
+	var (
+		db     = s.blockchain.Database()
+		triedb = trie.NewDatabase(db)
+		origin common.Hash
+		start  = time.Now()
+		stats  struct{ accounts, slots, codes int }
+	)
+	for root != types.EmptyRootHash {
+		if has, _ := db.Has(root[:]); has {
+			break
+		}
+		go p.RequestAccountRange(root, origin, maxHash, MaxRangeBytes)
+		rng, err := s.waitStateRange(s.syncAccountRangeCh)
+		if err != nil {
+			return err
+		}
+		more, err := VerifyStateRange(root, origin, rng)
+		if err != nil {
+			log.Warn("Invalid account range", "peer", p.IDString(), "origin", origin.Hex(), "err", err)
+			return err
+		}
+		var codes []common.Hash
+		for i, value := range rng.Values {
+			var acc state.Account
+			if err := rlp.DecodeBytes(value, &acc); err != nil {
+				return err
+			}
+			if acc.Root != types.EmptyRootHash {
+				if has, _ := db.Has(acc.Root[:]); !has {
+					slots, err := s.syncStorage(p, triedb, root, rng.Keys[i], acc.Root)
+					if err != nil {
+						return err
+					}
+					stats.slots += slots
+				}
+			}
+			if codeHash := common.BytesToHash(acc.CodeHash); codeHash != emptyCodeHash {
+				if has, _ := db.Has(codeHash[:]); !has {
+					codes = append(codes, codeHash)
+				}
+			}
+		}
+		if err := s.syncCodes(db, codes); err != nil {
+			return err
+		}
+		if err := commitStateRange(triedb, rng); err != nil {
+			return err
+		}
+		stats.accounts += len(rng.Keys)
+		stats.codes += len(codes)
+
+		log.Info("Downloaded account range", "accounts", stats.accounts, "slots", stats.slots, "codes", stats.codes,
+			"elapsed", common.PrettyDuration(time.Since(start)))
+		if !more {
+			break
+		}
+		origin = incHash(rng.Keys[len(rng.Keys)-1])
+	}
+	// Fetch the state nodes joining the ranges
+	var sched *trie.Sync
+	callback := func(leaf []byte, parent common.Hash) error {
+		var acc state.Account
+		if err := rlp.Decode(bytes.NewReader(leaf), &acc); err != nil {
+			return err
+		}
+		sched.AddSubTrie(acc.Root, 64, parent, nil)
+		sched.AddRawEntry(common.BytesToHash(acc.CodeHash), 64, parent)
+		return nil
+	}
+	sched = trie.NewSync(root, db, callback)
+	healed, err := s.healTrie(db, sched)
+	if err != nil {
+		return err
+	}
+	log.Info("Snapshot state sync finished", "accounts", stats.accounts, "slots", stats.slots, "codes", stats.codes,
+		"healed", healed, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// syncStorage downloads the storage trie of an account, returning the number
+// of slots fetched.
+func (s *Synchronizer) syncStorage(p SyncPeer, triedb *trie.Database, root, account, storageRoot common.Hash) (int, error) {
+	var (
+		origin common.Hash
+		slots  int
+	)
+	for {
+		go p.RequestStorageRange(root, account, origin, maxHash, MaxRangeBytes)
+		rng, err := s.waitStateRange(s.syncStorageRangeCh)
+		if err != nil {
+			return slots, err
+		}
+		more, err := VerifyStateRange(storageRoot, origin, rng)
+		if err != nil {
+			log.Warn("Invalid storage range", "peer", p.IDString(), "account", account.Hex(), "origin", origin.Hex(), "err", err)
+			return slots, err
+		}
+		if err := commitStateRange(triedb, rng); err != nil {
+			return slots, err
+		}
+		slots += len(rng.Keys)
+		if !more {
+			break
+		}
+		origin = incHash(rng.Keys[len(rng.Keys)-1])
+	}
+	// Storage downloaded in several ranges misses the nodes joining them
+	db := s.blockchain.Database()
+	_, err := s.healTrie(db, trie.NewSync(storageRoot, db, nil))
+	return slots, err
+}
+
+// syncCodes downloads the contract codes with the given hashes.
+func (s *Synchronizer) syncCodes(db database.Database, hashes []common.Hash) error {
+	for len(hashes) > 0 {
+		batch := hashes
+		if len(batch) > MaxStateFetch {
+			batch = batch[:MaxStateFetch]
+		}
+		data, err := s.fetchNodeData(batch)
+		if err != nil {
+			return err
+		}
+		wanted := make(map[common.Hash]bool, len(batch))
+		for _, hash := range batch {
+			wanted[hash] = true
+		}
+		for _, code := range data {
+			hash := crypto.Keccak256Hash(code)
+			if !wanted[hash] {
+				return errStateDataMismatch
+			}
+			if err := db.Put(hash[:], code); err != nil {
+				return err
+			}
+			delete(wanted, hash)
+		}
+		if len(wanted) == len(batch) {
+			return errStateUnavailable
+		}
+		// Request the codes left out of the response again
+		rest := hashes[len(batch):]
+		for hash := range wanted {
+			rest = append(rest, hash)
+		}
+		hashes = rest
+	}
+	return nil
+}
+
+// healTrie fetches the nodes missing in the trie scheduled by sched, returning
+// the number of nodes fetched.
+func (s *Synchronizer) healTrie(db database.Database, sched *trie.Sync) (int, error) {
+	var (
+		healed int
+		queue  = sched.Missing(MaxStateFetch)
+	)
+	for len(queue) > 0 {
+		data, err := s.fetchNodeData(queue)
+		if err != nil {
+			return healed, err
+		}
+		if len(data) == 0 {
+			return healed, errStateUnavailable
+		}
+		if len(data) > len(queue) {
+			return healed, errStateDataMismatch
+		}
+		results := make([]trie.SyncResult, len(data))
+		for i, item := range data {
+			results[i] = trie.SyncResult{Hash: queue[i], Data: item}
+		}
+		if _, _, err := sched.Process(results); err != nil {
+			return healed, err
+		}
+		if _, err := sched.Commit(db); err != nil {
+			return healed, err
+		}
+		healed += len(results)
+
+		// Request the nodes left out of the response again
+		queue = append(queue[len(results):], sched.Missing(MaxStateFetch-len(queue)+len(results))...)
+	}
+	return healed, nil
+}
+
+// fetchNodeData requests the state entries with the given hashes from the
+// current peer and waits for the response.
+func (s *Synchronizer) fetchNodeData(hashes []common.Hash) ([][]byte, error) {
+	s.syncRequestStateDataCh <- hashes
+
+	timer := time.NewTimer(SyncStateTimeout)
+	defer timer.Stop()
+
+	select {
+	case data := <-s.syncStateDataCh:
+		return data, nil
+	case <-timer.C:
+		return nil, ErrTimeout
+	case <-s.cancelCh:
+		s.processFastSyncContentCh <- struct{}{}
+		return nil, errCanceled
+	case <-s.quitCh:
+		return nil, errQuitSync
+	}
+}
+
+// waitStateRange waits for the response to a range request.
+func (s *Synchronizer) waitStateRange(ch chan *StateRange) (*StateRange, error) {
+	timer := time.NewTimer(SyncStateTimeout)
+	defer timer.Stop()
+
+	select {
+	case rng := <-ch:
+		return rng, nil
+	case <-timer.C:
+		return nil, ErrTimeout
+	case <-s.cancelCh:
+		s.processFastSyncContentCh <- struct{}{}
+		return nil, errCanceled
+	case <-s.quitCh:
+		return nil, errQuitSync
+	}
+}
+
+// commitStateRange stores the leaves of a verified range as a trie.
+func commitStateRange(triedb *trie.Database, rng *StateRange) error {
+	tr, err := trie.New(common.Hash{}, triedb)
+	if err != nil {
+		return err
+	}
+	for i, key := range rng.Keys {
+		if err := tr.TryUpdate(key[:], rng.Values[i]); err != nil {
+			return err
+		}
+	}
+	root, err := tr.Commit(nil)
+	if err != nil {
+		return err
+	}
+	return triedb.Commit(root, false)
+}
+
+// DeliverAccountRange injects a range of accounts received from a remote node.
+func (s *Synchronizer) DeliverAccountRange(id string, rng *StateRange) error {
+	return s.deliverStateRange(id, s.syncAccountRangeCh, rng)
+}
+
+// DeliverStorageRange injects a range of storage slots received from a remote node.
+func (s *Synchronizer) DeliverStorageRange(id string, rng *StateRange) error {
+	return s.deliverStateRange(id, s.syncStorageRangeCh, rng)
+}
+
+func (s *Synchronizer) deliverStateRange(id string, ch chan *StateRange, rng *StateRange) error {
+	if !s.Synchronising() {
+		return errCanceled
+	}
+	s.currentPeerMutex.RLock()
+	current := s.currentPeer
+	s.currentPeerMutex.RUnlock()
+	if current == nil || current.IDString() != id {
+		return ErrUnknownPeer
+	}
+	// Drop the responses nobody waits for anymore
+	select {
+	case ch <- rng:
+		return nil
+	default:
+		return errCanceled
+	}
+}
@@ -0,0 +1,164 @@
+package syncer_test
+
+import (
+	"bytes"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Tests that a trie served as ranges verifies chunk by chunk and that altered
+// ranges are rejected.
+func TestStateRangeProof(t *testing.T) {
+	triedb, tr, content := makeTestTrie(database.NewMemDatabase())
+	root := tr.Hash()
+	limit := common.BytesToHash(bytes.Repeat([]byte{0xff}, common.HashLength))
+
+	var (
+		origin common.Hash
+		leaves int
+		ranges int
+	)
+	for {
+		rng, err := syncer.ServeAccountRange(triedb, root, origin, limit, 1024)
+		if err != nil {
+			t.Fatalf("failed to serve range from %x: %v", origin, err)
+		}
+		ranges++
+		more, err := syncer.VerifyStateRange(root, origin, rng)
+		if err != nil {
+			t.Fatalf("range %d from %x: verification failed: %v", ranges, origin, err)
+		}
+		for i, key := range rng.Keys {
+			if value := tr.Get(key[:]); string(value) != string(rng.Values[i]) {
+				t.Fatalf("range %d: value mismatch for %x", ranges, key)
+			}
+		}
+		leaves += len(rng.Keys)
+
+		if len(rng.Keys) > 2 {
+			// A range with a leaf left out
+			gap := &syncer.StateRange{
+				Keys:   append(append([]common.Hash{}, rng.Keys[:1]...), rng.Keys[2:]...),
+				Values: append(append([][]byte{}, rng.Values[:1]...), rng.Values[2:]...),
+				Proof:  rng.Proof,
+			}
+			if _, err := syncer.VerifyStateRange(root, origin, gap); err == nil {
+				t.Fatalf("range %d: gap not detected", ranges)
+			}
+			// A range with an altered value
+			altered := &syncer.StateRange{
+				Keys:   rng.Keys,
+				Values: append([][]byte{{0xff}}, rng.Values[1:]...),
+				Proof:  rng.Proof,
+			}
+			if _, err := syncer.VerifyStateRange(root, origin, altered); err == nil {
+				t.Fatalf("range %d: altered value not detected", ranges)
+			}
+		}
+		if !more {
+			break
+		}
+		next := rng.Keys[len(rng.Keys)-1]
+		for i := len(next) - 1; i >= 0; i-- {
+			if next[i]++; next[i] != 0 {
+				break
+			}
+		}
+		origin = next
+	}
+	if leaves != len(content) {
+		t.Fatalf("leaf count mismatch: have %d, want %d", leaves, len(content))
+	}
+	if ranges < 2 {
+		t.Fatalf("trie served in %d range, want several", ranges)
+	}
+	// Proofless ranges tell the state is missing
+	if _, err := syncer.VerifyStateRange(root, common.Hash{}, &syncer.StateRange{}); err == nil {
+		t.Fatalf("empty range without proof accepted")
+	}
+}
+
+// Tests that the pivot state downloaded as ranges matches the remote one,
+// including contract storage and code.
+func TestSnapSync(t *testing.T) {
+	// Sync the state right after the contract deployments, in tiny ranges
+	syncer.MinFullBlocks = 1
+	syncer.MaxRangeBytes = 64
+	defer func() {
+		syncer.MinFullBlocks = 1024
+		syncer.MaxRangeBytes = 512 * 1024
+	}()
+
+	var (
+		p      = NewFakePeer(200, 0, true)
+		remote = p.blockchain.(*core.BlockChain)
+	)
+	blocks, _ := core.GenerateChain(remote.Config(), remote.CurrentBlock(), remote.Engine(), p.db,
+		database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter()), 2, func(int, *core.BlockGen) {})
+	if _, err := remote.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to extend remote chain: %v", err)
+	}
+	head, height := p.Head()
+
+	localchain, _ := newBlockchainWithDB(0, false)
+	localchain.SetSyncMode(syncer.SnapSync)
+
+	localSyncer := syncer.New(localchain, nil, new(event.TypeMux))
+	localSyncer.AddPeer(p)
+	defer localSyncer.Terminate()
+
+	go p.returnBlocksLoop()
+	defer p.quit()
+	go func() {
+		for {
+			select {
+			case blocks := <-p.returnCh:
+				localSyncer.DeliverBlocks(p.IDString(), blocks)
+			case receipts := <-p.returnReceiptsCh:
+				localSyncer.DeliverReceipts(p.IDString(), receipts)
+			case data := <-p.returnStateDataCh:
+				localSyncer.DeliverNodeData(p.IDString(), data)
+			case headers := <-p.returnHeadersCh:
+				localSyncer.DeliverHeaders(p.IDString(), headers)
+			case bodies := <-p.returnBodiesCh:
+				localSyncer.DeliverBodies(p.IDString(), bodies)
+			case rng := <-p.returnAccountsCh:
+				localSyncer.DeliverAccountRange(p.IDString(), rng)
+			case rng := <-p.returnStorageCh:
+				localSyncer.DeliverStorageRange(p.IDString(), rng)
+			case <-p.quitCh:
+				return
+			}
+		}
+	}()
+	if err := localSyncer.Synchronise(p, head, height, syncer.SnapSync); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	log.Info("snapshot sync successful")
+
+	if have, want := localchain.CurrentBlock().NumberU64(), height.Uint64(); have != want {
+		t.Fatalf("head mismatch: have %d, want %d", have, want)
+	}
+	local, err := localchain.State()
+	if err != nil {
+		t.Fatalf("failed to open synced state: %v", err)
+	}
+	state, _ := remote.State()
+	if have, want := local.GetBalance(testBank), state.GetBalance(testBank); have.Cmp(want) != 0 {
+		t.Errorf("bank balance mismatch: have %v, want %v", have, want)
+	}
+	for _, addr := range []common.Address{rewardAddr, campaignAddr, acAddr} {
+		if have, want := len(local.GetCode(addr)), len(state.GetCode(addr)); have != want || want == 0 {
+			t.Errorf("contract %x: code size mismatch: have %d, want %d", addr, have, want)
+		}
+	}
+	if have, want := local.IntermediateRoot(false), state.IntermediateRoot(false); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+}
@@ -0,0 +1,135 @@
+package syncer
+
+import (
+	"bytes"
+	"errors"
+
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	errStateUnavailable = errors.New("state not available at the remote peer")
+	errUnknownAccount   = errors.New("unknown account")
+)
+
+// StateRange is a run of consecutive leaves of a state or storage trie, keyed
+// by their hashed keys, along with the Merkle proofs of the first requested
+// key and of the last returned one.
+type StateRange struct {
+	Keys   []common.Hash
+	Values [][]byte
+	Proof  [][]byte
+}
+
+// ServeAccountRange returns the accounts of the state trie with the given root
+// from origin on, stopping at the first account past limit or once the values
+// reach maxBytes.
+func ServeAccountRange(triedb *trie.Database, root, origin, limit common.Hash, maxBytes uint64) (*StateRange, error) {
+	return serveTrieRange(triedb, root, origin, limit, maxBytes)
+}
+
+// ServeStorageRange returns the storage slots of the account with the given
+// hashed address in the state trie with the given root, from origin on,
+// stopping at the first slot past limit or once the values reach maxBytes.
+func ServeStorageRange(triedb *trie.Database, root, account, origin, limit common.Hash, maxBytes uint64) (*StateRange, error) {
+	accTrie, err := trie.New(root, triedb)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := accTrie.TryGet(account[:])
+	if err != nil {
+		return nil, err
+	}
+	if blob == nil {
+		return nil, errUnknownAccount
+	}
+	var acc state.Account
+	if err := rlp.DecodeBytes(blob, &acc); err != nil {
+		return nil, err
+	}
+	return serveTrieRange(triedb, acc.Root, origin, limit, maxBytes)
+}
+
+func serveTrieRange(triedb *trie.Database, root, origin, limit common.Hash, maxBytes uint64) (*StateRange, error) {
+	tr, err := trie.New(root, triedb)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rng  = new(StateRange)
+		size uint64
+		it   = trie.NewIterator(tr.NodeIterator(origin[:]))
+	)
+	for it.Next() {
+		key := common.BytesToHash(it.Key)
+		rng.Keys = append(rng.Keys, key)
+		rng.Values = append(rng.Values, common.CopyBytes(it.Value))
+
+		// The first leaf past the limit is included so that the proof covers
+		// the whole requested range
+		if size += uint64(common.HashLength + len(it.Value)); size >= maxBytes || bytes.Compare(key[:], limit[:]) >= 0 {
+			break
+		}
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	// Prove both edges of the range, the nodes shared by the proofs being sent once
+	proof := database.NewMemDatabase()
+	if err := tr.Prove(origin[:], 0, proof); err != nil {
+		return nil, err
+	}
+	if len(rng.Keys) > 0 {
+		if err := tr.Prove(rng.Keys[len(rng.Keys)-1][:], 0, proof); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range proof.Keys() {
+		node, _ := proof.Get(key)
+		rng.Proof = append(rng.Proof, node)
+	}
+	return rng, nil
+}
+
+// VerifyStateRange checks that the range holds all the leaves of the trie with
+// the given root from origin to its last key, without any gap. It returns
+// whether the trie has more leaves past the range.
+func VerifyStateRange(root, origin common.Hash, rng *StateRange) (bool, error) {
+	if len(rng.Proof) == 0 {
+		return false, errStateUnavailable
+	}
+	nodes := database.NewMemDatabase()
+	for _, node := range rng.Proof {
+		nodes.Put(crypto.Keccak256(node), node)
+	}
+	var (
+		last = origin
+		keys = make([][]byte, len(rng.Keys))
+	)
+	for i := range rng.Keys {
+		keys[i] = rng.Keys[i][:]
+	}
+	if len(keys) > 0 {
+		last = rng.Keys[len(keys)-1]
+		if bytes.Compare(keys[0], origin[:]) < 0 {
+			return false, errors.New("range starting before its origin")
+		}
+	}
+	return verifyRange(root, origin[:], last[:], keys, rng.Values, nodes)
+}
+
+// incHash returns the hash following h, wrapping around to the zero hash.
+func incHash(h common.Hash) common.Hash {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			break
+		}
+	}
+	return h
+}
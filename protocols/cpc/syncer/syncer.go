@@ -37,15 +37,18 @@ const (
 var (
 	MaxQueueSize  = 200 // Max size of blocks queue
 	MinFullBlocks = configs.DefaultFullSyncPivot
+	MaxRangeBytes = uint64(512 * 1024) // Soft size limit of the account or storage ranges requested at once
 )
 
-// SyncMode : Full, Fast
+// SyncMode : Full, Fast, Snap, Light
 type SyncMode int
 
-// FullSync, FastSync
+// FullSync, FastSync, SnapSync, LightSync
 const (
-	FullSync SyncMode = iota // Synchronise the entire blockchain history from full blocks
-	FastSync                 // Quickly download the headers, full sync only at the chain head
+	FullSync  SyncMode = iota // Synchronise the entire blockchain history from full blocks
+	FastSync                  // Quickly download the headers, full sync only at the chain head
+	SnapSync                  // Download the headers and the pivot state as account and storage ranges, full sync only at the chain head
+	LightSync                 // Download the headers only, verified by the quorum of validators, see protocols/lcpc
 )
 
 var (
@@ -77,6 +80,14 @@ type SyncPeer interface {
 
 	RequestNodeData(hashes []common.Hash) error
 
+	// RequestAccountRange fetches the accounts of the state trie with the
+	// given root from origin to limit, up to about bytes.
+	RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error
+
+	// RequestStorageRange fetches the storage slots of an account of the state
+	// trie with the given root from origin to limit, up to about bytes.
+	RequestStorageRange(root, account, origin, limit common.Hash, bytes uint64) error
+
 	RequestHeadersByNumber(origin uint64, amount int, skip int, reverse bool) error
 
 	RequestBodies([]common.Hash) error
@@ -111,6 +122,12 @@ type Syncer interface {
 	// DeliverNodeData injects a new batch of node state data received from a remote node.
 	DeliverNodeData(id string, data [][]byte) error
 
+	// DeliverAccountRange injects a range of accounts received from a remote node.
+	DeliverAccountRange(id string, rng *StateRange) error
+
+	// DeliverStorageRange injects a range of storage slots received from a remote node.
+	DeliverStorageRange(id string, rng *StateRange) error
+
 	// DeliverHeaders injects a new batch of block headers received from a remote
 	// node into the download schedule.
 	DeliverHeaders(id string, headers []*types.Header) error
@@ -204,6 +221,8 @@ type Synchronizer struct {
 	syncHeadersCh          chan []*types.Header
 	syncBodiesCh           chan [][]*types.Transaction
 	syncStateDataCh        chan [][]byte
+	syncAccountRangeCh     chan *StateRange
+	syncStorageRangeCh     chan *StateRange
 	syncRequestsCh         chan uint64
 	syncRequestReceiptsCh  chan []common.Hash
 	syncRequestBodiesCh    chan []common.Hash
@@ -243,6 +262,8 @@ func New(chain BlockChain, dropPeer DropPeer, mux *event.TypeMux) *Synchronizer
 		syncBlocksCh:               make(chan types.Blocks, 1), // there is only one peer to synchronise with.
 		syncReceiptsCh:             make(chan []types.Receipts, 1),
 		syncStateDataCh:            make(chan [][]byte, 1),
+		syncAccountRangeCh:         make(chan *StateRange, 1),
+		syncStorageRangeCh:         make(chan *StateRange, 1),
 		syncHeadersCh:              make(chan []*types.Header, 1),
 		syncBodiesCh:               make(chan [][]*types.Transaction, 1),
 		syncRequestsCh:             make(chan uint64, 1),
@@ -294,7 +315,7 @@ func (s *Synchronizer) Synchronise(p SyncPeer, head common.Hash, height *big.Int
 
 	s.blockchain.SetKnownHead(head, height.Uint64())
 
-	if mode == FastSync {
+	if mode == FastSync || mode == SnapSync {
 		current := s.blockchain.CurrentFastBlock().NumberU64()
 		if height.Uint64()-current <= uint64(MinFullBlocks) {
 			err = s.synchronise(p, head, height.Uint64(), FullSync)
@@ -356,7 +377,7 @@ func (s *Synchronizer) blocksHandler(errCh chan error, successCh chan struct{},
 			return
 		}
 		s.progressLock.Lock()
-		if mode == FastSync {
+		if mode != FullSync {
 			s.progress.CurrentBlock = s.blockchain.CurrentFastBlock().NumberU64()
 		} else {
 			s.progress.CurrentBlock = s.blockchain.CurrentBlock().NumberU64()
@@ -416,7 +437,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 		currentNumber = currentHeader.Number.Uint64()
 	)
 
-	if mode == FastSync {
+	if mode != FullSync {
 		currentHeader = s.blockchain.CurrentFastBlock().Header()
 		currentNumber = currentHeader.Number.Uint64()
 	}
@@ -475,7 +496,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 
 	// sync state data
 	// get the latest block
-	if mode == FastSync {
+	if mode != FullSync {
 		atomic.StoreInt32(&s.headerIdle, 0)
 		atomic.StoreInt32(&s.blockIdle, 0)
 		if atomic.CompareAndSwapInt32(&s.processFastSyncContentIdle, 0, 1) {
@@ -488,7 +509,12 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 				return ErrTimeout
 			}
 			go func(root common.Hash) {
-				err := s.processFastSyncContent(root)
+				var err error
+				if mode == SnapSync {
+					err = s.processSnapSyncContent(p, root)
+				} else {
+					err = s.processFastSyncContent(root)
+				}
 				if err != nil {
 					errCh <- err
 				}
@@ -517,7 +543,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 
 		prepare := make(chan bool, 2)
 
-		if mode == FastSync {
+		if mode != FullSync {
 			if MaxBlockFetch < height-i {
 				go s.FetchHeaders(i, MaxBlockFetch)
 			} else {
@@ -551,7 +577,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 				elapsed := common.PrettyDuration(time.Since(stats.fetchHeaderStart))
 				log.Debug("fetch headers", "elapsed", elapsed)
 
-				if mode == FastSync {
+				if mode != FullSync {
 					hashes := make([]common.Hash, len(headers))
 					for i, header := range headers {
 						hashes[i] = header.Hash()
@@ -618,7 +644,7 @@ func (s *Synchronizer) synchronise(p SyncPeer, head common.Hash, height uint64,
 	select {
 	case <-successCh:
 		// wait blocks handler be success
-		if mode == FastSync {
+		if mode != FullSync {
 			// wait state sync be finished
 			<-s.stateSyncFinishCh
 			// commit the pivot point
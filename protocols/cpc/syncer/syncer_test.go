@@ -806,6 +806,8 @@ type FakePeer struct {
 	returnStateDataCh chan [][]byte
 	returnHeadersCh   chan []*types.Header
 	returnBodiesCh    chan [][]*types.Transaction
+	returnAccountsCh  chan *syncer.StateRange
+	returnStorageCh   chan *syncer.StateRange
 	quitCh            chan struct{}
 	id                int
 	errorPeer         bool
@@ -832,6 +834,8 @@ func NewFakePeer(n int, badIdx int, deployContract bool) *FakePeer {
 		returnStateDataCh: make(chan [][]byte),
 		returnHeadersCh:   make(chan []*types.Header),
 		returnBodiesCh:    make(chan [][]*types.Transaction),
+		returnAccountsCh:  make(chan *syncer.StateRange),
+		returnStorageCh:   make(chan *syncer.StateRange),
 		quitCh:            make(chan struct{}),
 		id:                0,
 	}
@@ -876,6 +880,26 @@ func (fp *FakePeer) RequestNodeData(hashes []common.Hash) error {
 	return nil
 }
 
+func (fp *FakePeer) RequestAccountRange(root, origin, limit common.Hash, bytes uint64) error {
+	triedb := fp.blockchain.(*core.BlockChain).StateCache().TrieDB()
+	rng, err := syncer.ServeAccountRange(triedb, root, origin, limit, bytes)
+	if err != nil {
+		return err
+	}
+	fp.returnAccountsCh <- rng
+	return nil
+}
+
+func (fp *FakePeer) RequestStorageRange(root, account, origin, limit common.Hash, bytes uint64) error {
+	triedb := fp.blockchain.(*core.BlockChain).StateCache().TrieDB()
+	rng, err := syncer.ServeStorageRange(triedb, root, account, origin, limit, bytes)
+	if err != nil {
+		return err
+	}
+	fp.returnStorageCh <- rng
+	return nil
+}
+
 func (fp *FakePeer) returnBlocksLoop() {
 	for {
 		select {
@@ -277,7 +277,8 @@ func (cw *contractWrapper) pushObject(vm *duktape.Context) {
 // Tracer provides an implementation of Tracer that evaluates a Javascript
 // function for each VM execution step.
 type Tracer struct {
-	inited bool // Flag whether the context was already inited from the EVM
+	inited bool    // Flag whether the context was already inited from the EVM
+	env    *vm.EVM // EVM of the traced transaction, set on the first step
 
 	vm *duktape.Context // Javascript VM instance
 
@@ -366,8 +367,21 @@ func New(code string) (*Tracer, error) {
 		return 1
 	})
 	tracer.vm.PushGlobalGoFunction("isPrecompiled", func(ctx *duktape.Context) int {
-		_, ok := vm.PrimitiveContracts[common.BytesToAddress(popSlice(ctx))]
-		ctx.PushBoolean(ok)
+		addr := common.BytesToAddress(popSlice(ctx))
+		if tracer.env != nil {
+			ctx.PushBoolean(tracer.env.IsPrimitive(addr))
+		} else {
+			_, ok := vm.PrimitiveContracts[addr]
+			ctx.PushBoolean(ok)
+		}
+		return 1
+	})
+	tracer.vm.PushGlobalGoFunction("resolveProxy", func(ctx *duktape.Context) int {
+		addr := common.BytesToAddress(popSlice(ctx))
+		if tracer.env != nil {
+			addr = tracer.env.ResolveProxy(addr)
+		}
+		copy(makeSlice(ctx.PushFixedBuffer(20), 20), addr[:])
 		return 1
 	})
 	tracer.vm.PushGlobalGoFunction("slice", func(ctx *duktape.Context) int {
@@ -509,12 +523,21 @@ func (jst *Tracer) CaptureStart(from common.Address, to common.Address, create b
 	return nil
 }
 
+// CaptureProxy implements the ProxyTracer interface, reporting the logic
+// contract the recipient of the transaction was redirected to in the context.
+func (jst *Tracer) CaptureProxy(proxy common.Address, logic common.Address, depth int) {
+	if depth == 1 {
+		jst.ctx["logic"] = logic
+	}
+}
+
 // CaptureState implements the Tracer interface to trace a single step of VM execution.
 func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
 	if jst.err == nil {
 		// Initialize the context if it wasn't done yet
 		if !jst.inited {
 			jst.ctx["block"] = env.BlockNumber.Uint64()
+			jst.env = env
 			jst.inited = true
 		}
 		// If tracing was interrupted, set the error and stop
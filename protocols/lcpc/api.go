@@ -0,0 +1,63 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"context"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PublicLightAPI provides the light client specific RPC methods.
+type PublicLightAPI struct {
+	lcpc *LightCpchain
+}
+
+// NewPublicLightAPI creates a new light client API.
+func NewPublicLightAPI(lcpc *LightCpchain) *PublicLightAPI {
+	return &PublicLightAPI{lcpc: lcpc}
+}
+
+// LightStatus is the state of a light client.
+type LightStatus struct {
+	Head       common.Hash      `json:"head"`
+	Number     hexutil.Uint64   `json:"number"`
+	Servers    int              `json:"servers"`
+	Validators []common.Address `json:"validators"`
+}
+
+// Status returns the head of the light chain, the number of connected light
+// servers and the validators the next headers are verified with.
+func (api *PublicLightAPI) Status() *LightStatus {
+	head := api.lcpc.chain.CurrentHeader()
+	number := head.Number.Uint64()
+	return &LightStatus{
+		Head:       head.Hash(),
+		Number:     hexutil.Uint64(number),
+		Servers:    len(api.lcpc.peers.ServingPeers()),
+		Validators: api.lcpc.chain.ValidatorsOf(number + 1),
+	}
+}
+
+// GetProvenTransaction returns the transaction with the given index in the
+// block with the given hash, checked against the transaction root of the
+// block rather than trusted from the server.
+func (api *PublicLightAPI) GetProvenTransaction(ctx context.Context, blockHash common.Hash, index hexutil.Uint64) (*types.Transaction, error) {
+	return api.lcpc.odr.GetTransaction(ctx, blockHash, uint64(index))
+}
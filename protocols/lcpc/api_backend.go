@@ -0,0 +1,332 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/bloombits"
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	errUnknownBlock    = errors.New("unknown block")
+	errNoServers       = errors.New("no light server to relay the transaction to")
+	errPrivateTxsLight = errors.New("private transactions are not supported by light clients")
+)
+
+// LightAPIBackend implements cpcapi.Backend and filters.Backend for light
+// clients.
+type LightAPIBackend struct {
+	lcpc *LightCpchain
+
+	// Light clients execute no block, these feeds never fire
+	logsFeed   event.Feed
+	rmLogsFeed event.Feed
+	sideFeed   event.Feed
+	newTxsFeed event.Feed
+	scope      event.SubscriptionScope
+}
+
+func (b *LightAPIBackend) ChainConfig() *configs.ChainConfig {
+	return b.lcpc.chainConfig
+}
+
+func (b *LightAPIBackend) CurrentBlock() *types.Block {
+	return types.NewBlockWithHeader(b.lcpc.chain.CurrentHeader())
+}
+
+// SetHead is a no-op, the headers of the light chain being final.
+func (b *LightAPIBackend) SetHead(number uint64) {}
+
+func (b *LightAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
+	// Light clients have no pending block, the latest one stands for it
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return b.lcpc.chain.CurrentHeader(), nil
+	}
+	return b.lcpc.chain.GetHeaderByNumber(uint64(blockNr)), nil
+}
+
+func (b *LightAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	header, err := b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return b.GetBlock(ctx, header.Hash())
+}
+
+func (b *LightAPIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber, isPrivate bool) (*state.StateDB, *types.Header, error) {
+	if isPrivate {
+		return nil, nil, errPrivateTxsLight
+	}
+	header, err := b.HeaderByNumber(ctx, blockNr)
+	if header == nil || err != nil {
+		return nil, nil, err
+	}
+	stateDb, err := NewState(ctx, header, b.lcpc.odr)
+	return stateDb, header, err
+}
+
+func (b *LightAPIBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.lcpc.odr.GetBlock(ctx, hash)
+}
+
+func (b *LightAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.lcpc.odr.GetReceipts(ctx, hash)
+}
+
+func (b *LightAPIBackend) GetPrivateReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return nil, errPrivateTxsLight
+}
+
+func (b *LightAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
+	receipts, err := b.GetReceipts(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, nil
+}
+
+func (b *LightAPIBackend) GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header, vmCfg vm.Config) (*vm.EVM, func() error, error) {
+	state.SetBalance(msg.From(), math.MaxBig256)
+	vmError := func() error { return nil }
+
+	// The coinbase of the header is the author, no engine is needed to find it
+	context := core.NewEVMContext(msg, header, &chainContext{b.lcpc.chain}, &header.Coinbase)
+	return vm.NewEVM(context, state, b.lcpc.chainConfig, vmCfg), vmError, nil
+}
+
+func (b *LightAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return b.scope.Track(b.rmLogsFeed.Subscribe(ch))
+}
+
+func (b *LightAPIBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.lcpc.chain.SubscribeChainEvent(ch)
+}
+
+func (b *LightAPIBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.lcpc.chain.SubscribeChainHeadEvent(ch)
+}
+
+func (b *LightAPIBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription {
+	return b.scope.Track(b.sideFeed.Subscribe(ch))
+}
+
+func (b *LightAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.scope.Track(b.logsFeed.Subscribe(ch))
+}
+
+// SendTx relays the transaction to the light servers, light clients keeping
+// no pool of their own.
+func (b *LightAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	peers := b.lcpc.peers.ServingPeers()
+	if len(peers) == 0 {
+		return errNoServers
+	}
+	for _, p := range peers {
+		if err := p.SendTxs(types.Transactions{signedTx}); err != nil {
+			p.Log().Debug("Failed to relay transaction", "hash", signedTx.Hash(), "err", err)
+		}
+	}
+	return nil
+}
+
+func (b *LightAPIBackend) ScheduleTx(ctx context.Context, signedTx *types.Transaction, cond core.TxCondition) error {
+	return errNotSupported
+}
+
+func (b *LightAPIBackend) ScheduledTxs() []*core.ScheduledTx {
+	return nil
+}
+
+func (b *LightAPIBackend) CancelScheduledTx(hash common.Hash) bool {
+	return false
+}
+
+func (b *LightAPIBackend) GetPoolTransactions() (types.Transactions, error) {
+	return nil, nil
+}
+
+func (b *LightAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	return nil
+}
+
+// GetPoolNonce returns the nonce of the account in the state of the head.
+func (b *LightAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	stateDb, err := NewState(ctx, b.lcpc.chain.CurrentHeader(), b.lcpc.odr)
+	if err != nil {
+		return 0, err
+	}
+	nonce := stateDb.GetNonce(addr)
+	return nonce, stateDb.Error()
+}
+
+func (b *LightAPIBackend) Stats() (pending int, queued int) {
+	return 0, 0
+}
+
+func (b *LightAPIBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return make(map[common.Address]types.Transactions), make(map[common.Address]types.Transactions)
+}
+
+func (b *LightAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
+	return b.scope.Track(b.newTxsFeed.Subscribe(ch))
+}
+
+func (b *LightAPIBackend) Downloader() syncer.Syncer {
+	return &lightSyncer{b.lcpc}
+}
+
+func (b *LightAPIBackend) ProtocolVersion() int {
+	return int(ProtocolVersions[0])
+}
+
+func (b *LightAPIBackend) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.lcpc.config.GasPrice), nil
+}
+
+func (b *LightAPIBackend) ChainDb() database.Database {
+	return b.lcpc.chainDb
+}
+
+func (b *LightAPIBackend) EventMux() *event.TypeMux {
+	return b.lcpc.eventMux
+}
+
+func (b *LightAPIBackend) AccountManager() *accounts.Manager {
+	return b.lcpc.accountManager
+}
+
+// BloomStatus reports no bloom bits section, the filters checking the bloom
+// of each header instead.
+func (b *LightAPIBackend) BloomStatus() (uint64, uint64) {
+	return configs.BloomBitsBlocks, 0
+}
+
+func (b *LightAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
+
+func (b *LightAPIBackend) RemoteDB() database.RemoteDatabase {
+	return nil
+}
+
+func (b *LightAPIBackend) RNode() ([]common.Address, uint64) {
+	return nil, b.lcpc.chain.CurrentHeader().Number.Uint64()
+}
+
+// CurrentProposerIndex return current proposer index, (0,1,...,11)
+func (b *LightAPIBackend) CurrentProposerIndex() uint64 {
+	bn := b.lcpc.chain.CurrentHeader().Number.Uint64()
+	vl, tl := b.ViewLen(), b.TermLen()
+	return ((bn - 1) % (vl * tl)) % tl
+}
+
+// CurrentView return current view, (0,1,2)
+func (b *LightAPIBackend) CurrentView() uint64 {
+	bn := b.lcpc.chain.CurrentHeader().Number.Uint64()
+	vl, tl := b.ViewLen(), b.TermLen()
+	return ((bn - 1) % (vl * tl)) / tl
+}
+
+// CurrentTerm return current term
+func (b *LightAPIBackend) CurrentTerm() uint64 {
+	bn := b.lcpc.chain.CurrentHeader().Number.Uint64()
+	vl, tl := b.ViewLen(), b.TermLen()
+	return (bn - 1) / (vl * tl)
+}
+
+func (b *LightAPIBackend) ViewLen() uint64 {
+	return b.lcpc.chainConfig.Dpor.ViewLen
+}
+
+func (b *LightAPIBackend) TermLen() uint64 {
+	return b.lcpc.chainConfig.Dpor.TermLen
+}
+
+func (b *LightAPIBackend) CommitteMember() []common.Address {
+	return b.lcpc.chain.CurrentHeader().Dpor.Proposers
+}
+
+// CalcRptInfo is not available to light clients, reputations being computed
+// from the contracts.
+func (b *LightAPIBackend) CalcRptInfo(address common.Address, addresses []common.Address, blockNum uint64) int64 {
+	return 0
+}
+
+func (b *LightAPIBackend) BlockReward(blockNr rpc.BlockNumber) *big.Int {
+	return new(big.Int)
+}
+
+func (b *LightAPIBackend) ProposerOf(blockNr rpc.BlockNumber) (common.Address, error) {
+	proposers, err := b.Proposers(blockNr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	vl, tl := b.ViewLen(), b.TermLen()
+	view := ((uint64(blockNr) - 1) % (vl * tl)) % tl
+	if len(proposers) > int(view) {
+		return proposers[int(view)], nil
+	}
+	return common.Address{}, errUnknownBlock
+}
+
+// Proposers returns the proposers listed in the header of the block.
+func (b *LightAPIBackend) Proposers(blockNr rpc.BlockNumber) ([]common.Address, error) {
+	header, _ := b.HeaderByNumber(context.Background(), blockNr)
+	if header == nil {
+		return []common.Address{}, errUnknownBlock
+	}
+	return header.Dpor.Proposers, nil
+}
+
+// Validators returns the validators the light chain verified the block with.
+func (b *LightAPIBackend) Validators(blockNr rpc.BlockNumber) ([]common.Address, error) {
+	header, _ := b.HeaderByNumber(context.Background(), blockNr)
+	if header == nil {
+		return []common.Address{}, errUnknownBlock
+	}
+	return b.lcpc.chain.ValidatorsOf(header.Number.Uint64()), nil
+}
+
+func (b *LightAPIBackend) SupportPrivateTx(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// chainContext lets the EVM resolve the hashes of the light chain headers.
+type chainContext struct {
+	*LightChain
+}
+
+// Engine returns no engine, light clients verifying headers by quorum only.
+func (c *chainContext) Engine() consensus.Engine { return nil }
@@ -0,0 +1,166 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"sync"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/internal/cpcapi"
+	"bitbucket.org/cpchain/chain/node"
+	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/protocols/cpc/filters"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// LightCpchain implements the light client service, following the chain with
+// its headers and retrieving the rest from the light servers.
+type LightCpchain struct {
+	config      *cpc.Config
+	chainConfig *configs.ChainConfig
+
+	chainDb   database.Database
+	chain     *LightChain
+	peers     *peerSet
+	retriever *retriever
+	odr       *LightOdr
+
+	eventMux       *event.TypeMux
+	accountManager *accounts.Manager
+
+	ApiBackend *LightAPIBackend
+
+	networkID     uint64
+	netRPCService *cpcapi.PublicNetAPI
+
+	// Header sync progress
+	synchronising int32
+	startingBlock uint64
+	highestBlock  uint64
+
+	syncCh chan struct{}
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a new light client service.
+func New(ctx *node.ServiceContext, config *cpc.Config) (*LightCpchain, error) {
+	chainDb, err := cpc.CreateDB(ctx, config, "lightchaindata")
+	if err != nil {
+		return nil, err
+	}
+	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
+	if _, ok := genesisErr.(*configs.ConfigCompatError); genesisErr != nil && !ok {
+		return nil, genesisErr
+	}
+	log.Info("Initialised chain configuration", "config", chainConfig)
+
+	chain, err := NewLightChain(chainDb, chainConfig, dpor.NormalMode)
+	if err != nil {
+		return nil, err
+	}
+	lcpc := newLightCpchain(config, chainConfig, chainDb, chain)
+	lcpc.eventMux = ctx.EventMux
+	lcpc.accountManager = ctx.AccountManager
+
+	log.Info("Initialising light cpchain protocol", "versions", ProtocolVersions, "network", config.NetworkId)
+	return lcpc, nil
+}
+
+func newLightCpchain(config *cpc.Config, chainConfig *configs.ChainConfig, chainDb database.Database, chain *LightChain) *LightCpchain {
+	peers := newPeerSet()
+	retriever := newRetriever(peers)
+
+	lcpc := &LightCpchain{
+		config:      config,
+		chainConfig: chainConfig,
+		chainDb:     chainDb,
+		chain:       chain,
+		peers:       peers,
+		retriever:   retriever,
+		odr:         newLightOdr(chain, retriever),
+		networkID:   config.NetworkId,
+		syncCh:      make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+	lcpc.ApiBackend = &LightAPIBackend{lcpc: lcpc}
+	return lcpc
+}
+
+// Chain returns the header chain of the light client.
+func (s *LightCpchain) Chain() *LightChain { return s.chain }
+
+// Odr returns the retriever of the data missing in the light chain.
+func (s *LightCpchain) Odr() *LightOdr { return s.odr }
+
+// Protocols implements node.Service, returning the light protocols.
+func (s *LightCpchain) Protocols() []p2p.Protocol {
+	return s.clientProtocols()
+}
+
+// APIs implements node.Service, returning the RPC services of the light client.
+func (s *LightCpchain) APIs() []rpc.API {
+	return append(cpcapi.GetAPIs(s.ApiBackend), []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   filters.NewPublicFilterAPI(s.ApiBackend, true),
+			Public:    true,
+		}, {
+			Namespace: "net",
+			Version:   "1.0",
+			Service:   s.netRPCService,
+			Public:    true,
+		}, {
+			Namespace: "lcpc",
+			Version:   "1.0",
+			Service:   NewPublicLightAPI(s),
+			Public:    true,
+		},
+	}...)
+}
+
+// Start implements node.Service, starting the header sync.
+func (s *LightCpchain) Start(srvr *p2p.Server) error {
+	s.netRPCService = cpcapi.NewPublicNetAPI(srvr, s.networkID)
+
+	s.wg.Add(1)
+	go s.syncLoop()
+
+	log.Info("Light client started")
+	return nil
+}
+
+// Stop implements node.Service, terminating the header sync and disconnecting
+// the light servers.
+func (s *LightCpchain) Stop() error {
+	close(s.quit)
+	s.peers.Close()
+	s.wg.Wait()
+
+	s.chain.Stop()
+	s.eventMux.Stop()
+	s.chainDb.Close()
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// clientProtocols returns the light protocols run by the client.
+func (s *LightCpchain) clientProtocols() []p2p.Protocol {
+	var protocols []p2p.Protocol
+	for i, version := range ProtocolVersions {
+		version := version
+		protocols = append(protocols, p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  ProtocolLengths[i],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return s.handlePeer(p, rw, version)
+			},
+			PeerInfo: func(id discover.NodeID) interface{} {
+				if p := s.peers.Peer(fmt.Sprintf("%x", id[:8])); p != nil {
+					return p.Info()
+				}
+				return nil
+			},
+		})
+	}
+	return protocols
+}
+
+func (s *LightCpchain) handlePeer(p *p2p.Peer, rw p2p.MsgReadWriter, version uint) error {
+	peer := newPeer(int(version), p, rw)
+
+	head := s.chain.CurrentHeader()
+	if err := peer.Handshake(s.networkID, head.Number, head.Hash(), s.chain.Genesis().Hash(), false); err != nil {
+		peer.Log().Debug("Light server handshake failed", "err", err)
+		return err
+	}
+	if !peer.serve {
+		return p2p.DiscUselessPeer
+	}
+	if err := s.peers.Register(peer); err != nil {
+		return err
+	}
+	defer s.peers.Unregister(peer.id)
+
+	peer.Log().Debug("Light server connected")
+	s.triggerSync()
+
+	for {
+		if err := s.handleMsg(peer); err != nil {
+			peer.Log().Debug("Light server message handling failed", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMsg is invoked whenever an inbound message is received from a light
+// server. The remote connection is torn down upon returning any error.
+func (s *LightCpchain) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	var (
+		reqID uint64
+		resp  interface{}
+	)
+	switch msg.Code {
+	case StatusMsg:
+		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
+
+	case AnnounceMsg:
+		var ann announceData
+		if err := msg.Decode(&ann); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.SetHead(ann.Hash, new(big.Int).SetUint64(ann.Number))
+		s.triggerSync()
+		return nil
+
+	case HeadersMsg:
+		var data headersData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqID, resp = data.ReqID, data.Headers
+
+	case BodiesMsg:
+		var data bodiesData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqID, resp = data.ReqID, data.Bodies
+
+	case ReceiptsMsg:
+		var data receiptsData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqID, resp = data.ReqID, data.Receipts
+
+	case ProofsMsg, CodeMsg:
+		var data nodesData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqID, resp = data.ReqID, data.Data
+
+	case TxProofsMsg:
+		var data txProofsData
+		if err := msg.Decode(&data); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		reqID, resp = data.ReqID, data.Proofs
+
+	default:
+		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+	}
+	// Late responses to timed out requests are dropped
+	if err := s.retriever.deliver(p, msg.Code, reqID, resp); err != nil {
+		p.Log().Debug("Dropped light response", "code", msg.Code, "err", err)
+	}
+	return nil
+}
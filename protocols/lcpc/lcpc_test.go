@@ -0,0 +1,213 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/vm"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/protocols/cpc"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+var (
+	testBankKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testBank       = crypto.PubkeyToAddress(testBankKey.PublicKey)
+	testAccount    = common.Address{0x01}
+)
+
+// testTxPool records the transactions relayed by the clients.
+type testTxPool struct {
+	added chan []*types.Transaction
+}
+
+func (p *testTxPool) AddRemotes(txs []*types.Transaction) []error {
+	p.added <- txs
+	return make([]error, len(txs))
+}
+
+// newTestServer creates a light server for a chain of the given length, whose
+// odd blocks transfer funds to the test account.
+func newTestServer(t *testing.T, blocks int) (*LightServer, *core.BlockChain, *core.Genesis) {
+	var (
+		db       = database.NewMemDatabase()
+		remoteDB = database.NewIpfsDbWithAdapter(database.NewFakeIpfsAdapter())
+		signer   = types.HomesteadSigner{}
+	)
+	gspec := core.DefaultGenesisBlock()
+	gspec.Alloc = core.GenesisAlloc{testBank: {Balance: big.NewInt(1000000000)}}
+	genesis := gspec.MustCommit(db)
+
+	engine := dpor.NewFaker(gspec.Config.Dpor, db)
+	chain, _ := core.GenerateChain(gspec.Config, genesis, engine, db, remoteDB, blocks, func(i int, block *core.BlockGen) {
+		if i%2 == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(testBank), testAccount, big.NewInt(1000), configs.TxGas, nil, nil), signer, testBankKey)
+			block.AddTx(tx)
+		}
+	})
+	blockchain, err := core.NewBlockChain(db, nil, gspec.Config, engine, vm.Config{}, remoteDB, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	pool := &testTxPool{added: make(chan []*types.Transaction, 1)}
+	return NewLightServer(blockchain, pool, cpc.DefaultConfig.NetworkId), blockchain, gspec
+}
+
+// newTestClient creates a light client starting from the given genesis, whose
+// headers are trusted as the fake dpor engine signs none.
+func newTestClient(t *testing.T, gspec *core.Genesis) *LightCpchain {
+	db := database.NewMemDatabase()
+	gspec.MustCommit(db)
+
+	chain, err := NewLightChain(db, gspec.Config, dpor.FakeMode)
+	if err != nil {
+		t.Fatalf("failed to create light chain: %v", err)
+	}
+	config := cpc.DefaultConfig
+	return newLightCpchain(&config, gspec.Config, db, chain)
+}
+
+// connect runs the light protocol between the server and the client.
+func connect(t *testing.T, server *LightServer, client *LightCpchain) {
+	app, net := p2p.MsgPipe()
+
+	var serverID, clientID discover.NodeID
+	rand.Read(serverID[:])
+	rand.Read(clientID[:])
+
+	go server.handlePeer(p2p.NewPeer(clientID, "client", nil), app, ProtocolVersions[0])
+	go client.handlePeer(p2p.NewPeer(serverID, "server", nil), net, ProtocolVersions[0])
+
+	for i := 0; client.peers.Len() == 0 || server.peers.Len() == 0; i++ {
+		if i == 100 {
+			t.Fatalf("handshake timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLightSync(t *testing.T) {
+	server, blockchain, gspec := newTestServer(t, 8)
+	client := newTestClient(t, gspec)
+	connect(t, server, client)
+
+	if err := client.synchronise(); err != nil {
+		t.Fatalf("failed to synchronise: %v", err)
+	}
+	head := client.chain.CurrentHeader()
+	if head.Hash() != blockchain.CurrentBlock().Hash() {
+		t.Fatalf("head mismatch: have %d, want %d", head.Number, blockchain.CurrentBlock().Number())
+	}
+	if progress := client.ApiBackend.Downloader().Progress(); progress.CurrentBlock != 8 || progress.HighestBlock != 8 {
+		t.Fatalf("progress mismatch: %+v", progress)
+	}
+	// Headers past a gap are refused
+	if _, err := client.chain.InsertHeaderChain([]*types.Header{blockchain.CurrentBlock().Header()}); err == nil {
+		t.Fatalf("reinserted head accepted")
+	}
+}
+
+func TestLightRetrieval(t *testing.T) {
+	server, blockchain, gspec := newTestServer(t, 4)
+	client := newTestClient(t, gspec)
+	connect(t, server, client)
+
+	if err := client.synchronise(); err != nil {
+		t.Fatalf("failed to synchronise: %v", err)
+	}
+	ctx := context.Background()
+
+	// The state is retrieved along the paths of the accounts read
+	statedb, err := NewState(ctx, client.chain.CurrentHeader(), client.odr)
+	if err != nil {
+		t.Fatalf("failed to open state: %v", err)
+	}
+	full, _ := blockchain.State()
+	for _, addr := range []common.Address{testBank, testAccount} {
+		if have, want := statedb.GetBalance(addr), full.GetBalance(addr); have.Cmp(want) != 0 {
+			t.Fatalf("balance mismatch of %x: have %v, want %v", addr, have, want)
+		}
+	}
+	if err := statedb.Error(); err != nil {
+		t.Fatalf("state retrieval failed: %v", err)
+	}
+
+	// Blocks and receipts are checked against the roots of the headers
+	block := blockchain.GetBlockByNumber(1)
+	body, err := client.odr.GetBlock(ctx, block.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve block: %v", err)
+	}
+	if body.Transactions().Len() != 1 || body.Transactions()[0].Hash() != block.Transactions()[0].Hash() {
+		t.Fatalf("block transactions mismatch")
+	}
+	receipts, err := client.odr.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipts: %v", err)
+	}
+	if len(receipts) != 1 || receipts[0].TxHash != block.Transactions()[0].Hash() {
+		t.Fatalf("receipts mismatch: %v", receipts)
+	}
+
+	// Single transactions come along the proof of their index
+	tx, err := client.odr.GetTransaction(ctx, block.Hash(), 0)
+	if err != nil {
+		t.Fatalf("failed to retrieve transaction: %v", err)
+	}
+	if tx.Hash() != block.Transactions()[0].Hash() {
+		t.Fatalf("transaction mismatch")
+	}
+	proof, err := proveTransaction(block.Body(), 0)
+	if err != nil {
+		t.Fatalf("failed to prove transaction: %v", err)
+	}
+	if err := verifyTxProof(block.TxsRoot(), 1, proof); err == nil {
+		t.Fatalf("proof accepted for another index")
+	}
+	proof.Tx = blockchain.GetBlockByNumber(3).Transactions()[0]
+	if err := verifyTxProof(block.TxsRoot(), 0, proof); err == nil {
+		t.Fatalf("proof accepted for another transaction")
+	}
+
+	// Transactions are relayed to the pool of the server
+	if err := client.ApiBackend.SendTx(ctx, tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	select {
+	case txs := <-server.txpool.(*testTxPool).added:
+		if len(txs) != 1 || txs[0].Hash() != tx.Hash() {
+			t.Fatalf("relayed transactions mismatch")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("transaction not relayed")
+	}
+}
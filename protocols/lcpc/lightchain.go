@@ -0,0 +1,202 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"errors"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/consensus/dpor"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var (
+	errNoGenesis = errors.New("genesis not found in chain")
+
+	// lightSnapshotKey tracks the snapshot of the light verifier, whose head is
+	// the head of the light chain.
+	lightSnapshotKey = []byte("LightSnapshot")
+)
+
+// LightChain is a chain of headers, each final once it holds a quorum of
+// signatures of the validators of its term. Finality rules reorganisations
+// out, so headers are only ever appended on top of the head.
+type LightChain struct {
+	db       database.Database
+	config   *configs.ChainConfig
+	genesis  *types.Header
+	verifier *dpor.LightVerifier
+	current  *types.Header
+
+	chainFeed     event.Feed
+	chainHeadFeed event.Feed
+	scope         event.SubscriptionScope
+
+	mu sync.RWMutex
+}
+
+// NewLightChain opens the header chain stored in db, which must hold the
+// genesis already.
+func NewLightChain(db database.Database, config *configs.ChainConfig, mode dpor.Mode) (*LightChain, error) {
+	genesis := rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, 0), 0)
+	if genesis == nil {
+		return nil, errNoGenesis
+	}
+	lc := &LightChain{
+		db:      db,
+		config:  config,
+		genesis: genesis,
+	}
+	if blob, err := db.Get(lightSnapshotKey); err == nil && len(blob) > 0 {
+		if lc.verifier, err = dpor.LoadLightVerifier(config.Dpor, blob); err != nil {
+			log.Warn("Failed to load light snapshot, restarting from genesis", "err", err)
+		}
+	}
+	if lc.verifier != nil {
+		number, hash := lc.verifier.Head()
+		if lc.current = rawdb.ReadHeader(db, hash, number); lc.current == nil {
+			log.Warn("Light chain head missing, restarting from genesis", "number", number, "hash", hash.Hex())
+		}
+	}
+	if lc.current == nil {
+		lc.verifier = dpor.NewLightVerifier(config.Dpor, genesis, mode)
+		lc.current = genesis
+	}
+	log.Info("Loaded light chain", "number", lc.current.Number, "hash", lc.current.Hash().Hex())
+	return lc, nil
+}
+
+// InsertHeaderChain verifies and appends a batch of consecutive headers on top
+// of the head. It returns the number of headers inserted, headers past an
+// invalid one being left out.
+func (lc *LightChain) InsertHeaderChain(headers []*types.Header) (int, error) {
+	lc.mu.Lock()
+	var (
+		inserted []*types.Header
+		err      error
+	)
+	for _, header := range headers {
+		if err = lc.verifier.VerifyHeader(header); err != nil {
+			break
+		}
+		if err = lc.verifier.Apply(header); err != nil {
+			break
+		}
+		rawdb.WriteHeader(lc.db, header)
+		rawdb.WriteCanonicalHash(lc.db, header.Hash(), header.Number.Uint64())
+		inserted = append(inserted, header)
+	}
+	if len(inserted) > 0 {
+		lc.current = inserted[len(inserted)-1]
+		rawdb.WriteHeadHeaderHash(lc.db, lc.current.Hash())
+
+		blob, encErr := lc.verifier.Encode()
+		if encErr == nil {
+			encErr = lc.db.Put(lightSnapshotKey, blob)
+		}
+		if encErr != nil {
+			log.Error("Failed to store light snapshot", "err", encErr)
+		}
+	}
+	head := lc.current
+	lc.mu.Unlock()
+
+	for _, header := range inserted {
+		lc.chainFeed.Send(core.ChainEvent{Block: types.NewBlockWithHeader(header), Hash: header.Hash()})
+	}
+	if len(inserted) > 0 {
+		lc.chainHeadFeed.Send(core.ChainHeadEvent{Block: types.NewBlockWithHeader(head)})
+	}
+	return len(inserted), err
+}
+
+// CurrentHeader returns the head of the chain.
+func (lc *LightChain) CurrentHeader() *types.Header {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+
+	return lc.current
+}
+
+// Genesis returns the genesis header of the chain.
+func (lc *LightChain) Genesis() *types.Header {
+	return lc.genesis
+}
+
+// Config returns the chain configuration.
+func (lc *LightChain) Config() *configs.ChainConfig {
+	return lc.config
+}
+
+// Database returns the database of the chain.
+func (lc *LightChain) Database() database.Database {
+	return lc.db
+}
+
+// ValidatorsOf returns the validator committee of the given block number.
+func (lc *LightChain) ValidatorsOf(number uint64) []common.Address {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+
+	return lc.verifier.ValidatorsOf(number)
+}
+
+// GetHeader retrieves a header by hash and number.
+func (lc *LightChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return rawdb.ReadHeader(lc.db, hash, number)
+}
+
+// GetHeaderByHash retrieves a header by hash.
+func (lc *LightChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	number := rawdb.ReadHeaderNumber(lc.db, hash)
+	if number == nil {
+		return nil
+	}
+	return lc.GetHeader(hash, *number)
+}
+
+// GetHeaderByNumber retrieves the canonical header with the given number.
+func (lc *LightChain) GetHeaderByNumber(number uint64) *types.Header {
+	hash := rawdb.ReadCanonicalHash(lc.db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return lc.GetHeader(hash, number)
+}
+
+// SubscribeChainEvent registers a subscription of ChainEvent, sent for every
+// header inserted.
+func (lc *LightChain) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return lc.scope.Track(lc.chainFeed.Subscribe(ch))
+}
+
+// SubscribeChainHeadEvent registers a subscription of ChainHeadEvent.
+func (lc *LightChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return lc.scope.Track(lc.chainHeadFeed.Subscribe(ch))
+}
+
+// Stop closes the subscriptions of the chain.
+func (lc *LightChain) Stop() {
+	lc.scope.Close()
+}
@@ -0,0 +1,388 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core/rawdb"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// retrieveTimeout is the time a peer is given to answer a request before the
+// next one is asked.
+var retrieveTimeout = 5 * time.Second
+
+var (
+	errNoPeers       = errors.New("no light server connected")
+	errUnavailable   = errors.New("data not retrievable from any light server")
+	errUnknownHeader = errors.New("header not in the light chain")
+)
+
+// pendingReq is a request waiting for its response.
+type pendingReq struct {
+	peer *peer
+	code uint64           // Code of the expected response message
+	ch   chan interface{} // Channel the response is delivered to
+}
+
+// retriever sends the requests of on demand retrievals to the light servers
+// and matches the responses to them.
+type retriever struct {
+	peers *peerSet
+
+	reqID   uint64
+	pending map[uint64]*pendingReq
+	lock    sync.Mutex
+}
+
+func newRetriever(peers *peerSet) *retriever {
+	return &retriever{
+		peers:   peers,
+		pending: make(map[uint64]*pendingReq),
+	}
+}
+
+// retrieve sends a request built by send to the serving peers in turn, best
+// ones first, until one answers with a response accepted by validate. Peers
+// answering with invalid data are dropped, the ones without the data being
+// told by validate returning errUnavailable.
+func (r *retriever) retrieve(ctx context.Context, code uint64, send func(p *peer, reqID uint64) error, validate func(resp interface{}) error) error {
+	peers := r.peers.ServingPeers()
+	if len(peers) == 0 {
+		return errNoPeers
+	}
+	for _, p := range peers {
+		req := &pendingReq{peer: p, code: code, ch: make(chan interface{}, 1)}
+
+		r.lock.Lock()
+		r.reqID++
+		reqID := r.reqID
+		r.pending[reqID] = req
+		r.lock.Unlock()
+
+		resp, err := r.wait(ctx, req, func() error { return send(p, reqID) })
+
+		r.lock.Lock()
+		delete(r.pending, reqID)
+		r.lock.Unlock()
+
+		switch {
+		case err == context.Canceled || err == context.DeadlineExceeded:
+			return err
+		case err != nil:
+			p.Log().Debug("Light request failed", "code", code, "err", err)
+		default:
+			switch err := validate(resp); err {
+			case nil:
+				return nil
+			case errUnavailable:
+				p.Log().Debug("Light server missing requested data", "code", code)
+			default:
+				p.Log().Warn("Invalid light response, dropping server", "code", code, "err", err)
+				p.Disconnect(p2p.DiscUselessPeer)
+			}
+		}
+	}
+	return errUnavailable
+}
+
+func (r *retriever) wait(ctx context.Context, req *pendingReq, send func() error) (interface{}, error) {
+	if err := send(); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(retrieveTimeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-req.ch:
+		return resp, nil
+	case <-timer.C:
+		return nil, errResp(ErrRequestRejected, "timeout")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// deliver hands a response over to the request it answers.
+func (r *retriever) deliver(p *peer, code uint64, reqID uint64, resp interface{}) error {
+	r.lock.Lock()
+	req, ok := r.pending[reqID]
+	r.lock.Unlock()
+
+	if !ok || req.peer != p || req.code != code {
+		return errResp(ErrUnexpectedResponse, "reqID %d", reqID)
+	}
+	select {
+	case req.ch <- resp:
+	default:
+	}
+	return nil
+}
+
+// LightOdr retrieves the data missing in a light chain on demand, checking it
+// against the headers of the chain, and caches it in the chain database.
+type LightOdr struct {
+	chain     *LightChain
+	retriever *retriever
+}
+
+func newLightOdr(chain *LightChain, retriever *retriever) *LightOdr {
+	return &LightOdr{chain: chain, retriever: retriever}
+}
+
+// header returns the header of the light chain with the given hash.
+func (odr *LightOdr) header(hash common.Hash) (*types.Header, error) {
+	header := odr.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownHeader
+	}
+	return header, nil
+}
+
+// GetBody retrieves the body of the block with the given hash.
+func (odr *LightOdr) GetBody(ctx context.Context, hash common.Hash) (*types.Body, error) {
+	header, err := odr.header(hash)
+	if err != nil {
+		return nil, err
+	}
+	db, number := odr.chain.Database(), header.Number.Uint64()
+	if body := rawdb.ReadBody(db, hash, number); body != nil {
+		return body, nil
+	}
+	var body *types.Body
+	err = odr.retriever.retrieve(ctx, BodiesMsg, func(p *peer, reqID uint64) error {
+		return p.RequestBodies(reqID, []common.Hash{hash})
+	}, func(resp interface{}) error {
+		bodies := resp.([]*types.Body)
+		if len(bodies) != 1 {
+			return errUnavailable
+		}
+		if root := types.DeriveSha(types.Transactions(bodies[0].Transactions)); root != header.TxsRoot {
+			return fmt.Errorf("transaction root mismatch: have %x, want %x", root, header.TxsRoot)
+		}
+		body = bodies[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rawdb.WriteBody(db, hash, number, body)
+	return body, nil
+}
+
+// GetBlock retrieves the block with the given hash.
+func (odr *LightOdr) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	header, err := odr.header(hash)
+	if err != nil {
+		return nil, err
+	}
+	body, err := odr.GetBody(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewBlockWithHeader(header).WithBody(body.Transactions), nil
+}
+
+// GetReceipts retrieves the receipts of the block with the given hash, along
+// with their fields derived from the block.
+func (odr *LightOdr) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	header, err := odr.header(hash)
+	if err != nil {
+		return nil, err
+	}
+	db, number := odr.chain.Database(), header.Number.Uint64()
+	if receipts := rawdb.ReadReceipts(db, hash, number); receipts != nil {
+		return receipts, nil
+	}
+	var receipts types.Receipts
+	err = odr.retriever.retrieve(ctx, ReceiptsMsg, func(p *peer, reqID uint64) error {
+		return p.RequestReceipts(reqID, []common.Hash{hash})
+	}, func(resp interface{}) error {
+		results := resp.([]types.Receipts)
+		if len(results) != 1 {
+			return errUnavailable
+		}
+		if root := types.DeriveSha(results[0]); root != header.ReceiptsRoot {
+			return fmt.Errorf("receipt root mismatch: have %x, want %x", root, header.ReceiptsRoot)
+		}
+		receipts = results[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	block, err := odr.GetBlock(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := deriveReceiptFields(receipts, block, types.MakeSigner(odr.chain.Config())); err != nil {
+		return nil, err
+	}
+	rawdb.WriteReceipts(db, hash, number, receipts)
+	return receipts, nil
+}
+
+// GetCode retrieves the contract code with the given hash.
+func (odr *LightOdr) GetCode(ctx context.Context, hash common.Hash) ([]byte, error) {
+	db := odr.chain.Database()
+	if code, err := db.Get(hash[:]); err == nil {
+		return code, nil
+	}
+	var code []byte
+	err := odr.retriever.retrieve(ctx, CodeMsg, func(p *peer, reqID uint64) error {
+		return p.RequestCode(reqID, []common.Hash{hash})
+	}, func(resp interface{}) error {
+		codes := resp.([][]byte)
+		if len(codes) != 1 {
+			return errUnavailable
+		}
+		if have := crypto.Keccak256Hash(codes[0]); have != hash {
+			return fmt.Errorf("code hash mismatch: have %x, want %x", have, hash)
+		}
+		code = codes[0]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return code, db.Put(hash[:], code)
+}
+
+// GetProof retrieves the merkle proof of the given hashed key in the secure
+// trie with the given root, storing its nodes in the chain database so that
+// the trie can be read along the path of the key.
+func (odr *LightOdr) GetProof(ctx context.Context, root common.Hash, key []byte) error {
+	var nodes [][]byte
+	err := odr.retriever.retrieve(ctx, ProofsMsg, func(p *peer, reqID uint64) error {
+		return p.RequestProofs(reqID, []ProofReq{{Root: root, Key: key}})
+	}, func(resp interface{}) error {
+		proof := database.NewMemDatabase()
+		for _, node := range resp.([][]byte) {
+			proof.Put(crypto.Keccak256(node), node)
+		}
+		if _, _, err := trie.VerifyProof(root, key, proof); err != nil {
+			return err
+		}
+		nodes = resp.([][]byte)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	db := odr.chain.Database()
+	for _, node := range nodes {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTransaction retrieves the transaction with the given index in the block
+// with the given hash, proven against the transaction root of the block.
+func (odr *LightOdr) GetTransaction(ctx context.Context, hash common.Hash, index uint64) (*types.Transaction, error) {
+	header, err := odr.header(hash)
+	if err != nil {
+		return nil, err
+	}
+	var tx *types.Transaction
+	err = odr.retriever.retrieve(ctx, TxProofsMsg, func(p *peer, reqID uint64) error {
+		return p.RequestTxProofs(reqID, []TxProofReq{{BlockHash: hash, Index: index}})
+	}, func(resp interface{}) error {
+		proofs := resp.([]TxProof)
+		if len(proofs) != 1 || proofs[0].Tx == nil {
+			return errUnavailable
+		}
+		if err := verifyTxProof(header.TxsRoot, index, &proofs[0]); err != nil {
+			return err
+		}
+		tx = proofs[0].Tx
+		return nil
+	})
+	return tx, err
+}
+
+// verifyTxProof checks that the proven transaction has the given index in the
+// transaction trie with the given root.
+func verifyTxProof(root common.Hash, index uint64, proof *TxProof) error {
+	db := database.NewMemDatabase()
+	for _, node := range proof.Proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	key, _ := rlp.EncodeToBytes(uint(index))
+	value, _, err := trie.VerifyProof(root, key, db)
+	if err != nil {
+		return err
+	}
+	want, err := rlp.EncodeToBytes(proof.Tx)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, want) {
+		return errors.New("transaction not proven at its index")
+	}
+	return nil
+}
+
+// deriveReceiptFields fills in the receipt and log fields which are not part
+// of the consensus encoding of the receipts.
+func deriveReceiptFields(receipts types.Receipts, block *types.Block, signer types.Signer) error {
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(receipts), len(txs))
+	}
+	var (
+		logIdx  uint
+		gasUsed uint64
+	)
+	for i, receipt := range receipts {
+		receipt.TxHash = txs[i].Hash()
+		receipt.GasUsed = receipt.CumulativeGasUsed - gasUsed
+		gasUsed = receipt.CumulativeGasUsed
+
+		if txs[i].To() == nil {
+			from, err := types.Sender(signer, txs[i])
+			if err != nil {
+				log.Debug("Failed to recover sender of receipt", "tx", txs[i].Hash(), "err", err)
+			} else {
+				receipt.ContractAddress = crypto.CreateAddress(from, txs[i].Nonce())
+			}
+		}
+		for _, l := range receipt.Logs {
+			l.BlockNumber = block.NumberU64()
+			l.BlockHash = block.Hash()
+			l.TxHash = receipt.TxHash
+			l.TxIndex = uint(i)
+			l.Index = logIdx
+			logIdx++
+		}
+	}
+	return nil
+}
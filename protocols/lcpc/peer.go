@@ -0,0 +1,362 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+var (
+	errClosed            = errors.New("peer set is closed")
+	errAlreadyRegistered = errors.New("peer is already registered")
+	errNotRegistered     = errors.New("peer is not registered")
+)
+
+const handshakeTimeout = 5 * time.Second
+
+// PeerInfo represents a short summary of the light sub-protocol metadata known
+// about a connected peer.
+type PeerInfo struct {
+	Version int      `json:"version"` // light protocol version negotiated
+	Height  *big.Int `json:"height"`  // height of the peer's blockchain
+	Head    string   `json:"head"`    // hash of the peer's best owned block
+	Serve   bool     `json:"serve"`   // whether the peer serves light clients
+}
+
+type peer struct {
+	id string
+
+	*p2p.Peer
+	rw p2p.MsgReadWriter
+
+	version int  // Protocol version negotiated
+	serve   bool // Whether the peer serves light clients
+
+	head common.Hash
+	ht   *big.Int
+	lock sync.RWMutex
+}
+
+func newPeer(version int, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
+	return &peer{
+		Peer:    p,
+		rw:      rw,
+		version: version,
+		id:      fmt.Sprintf("%x", p.ID().Bytes()[:8]),
+		ht:      new(big.Int),
+	}
+}
+
+// Info gathers and returns a collection of metadata known about a peer.
+func (p *peer) Info() *PeerInfo {
+	hash, ht := p.Head()
+
+	return &PeerInfo{
+		Version: p.version,
+		Height:  ht,
+		Head:    hash.Hex(),
+		Serve:   p.serve,
+	}
+}
+
+// Head retrieves a copy of the current head hash and height of the peer.
+func (p *peer) Head() (hash common.Hash, ht *big.Int) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	copy(hash[:], p.head[:])
+	return hash, new(big.Int).Set(p.ht)
+}
+
+// SetHead updates the head hash and height of the peer.
+func (p *peer) SetHead(hash common.Hash, ht *big.Int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	copy(p.head[:], hash[:])
+	p.ht.Set(ht)
+}
+
+// SendAnnounce announces a new head to the peer.
+func (p *peer) SendAnnounce(hash common.Hash, number uint64) error {
+	return p2p.Send(p.rw, AnnounceMsg, &announceData{Hash: hash, Number: number})
+}
+
+// SendHeaders sends a batch of block headers to the remote peer.
+func (p *peer) SendHeaders(reqID uint64, headers []*types.Header) error {
+	return p2p.Send(p.rw, HeadersMsg, &headersData{ReqID: reqID, Headers: headers})
+}
+
+// SendBodies sends a batch of block bodies to the remote peer.
+func (p *peer) SendBodies(reqID uint64, bodies []*types.Body) error {
+	return p2p.Send(p.rw, BodiesMsg, &bodiesData{ReqID: reqID, Bodies: bodies})
+}
+
+// SendReceipts sends a batch of block receipts to the remote peer.
+func (p *peer) SendReceipts(reqID uint64, receipts []types.Receipts) error {
+	return p2p.Send(p.rw, ReceiptsMsg, &receiptsData{ReqID: reqID, Receipts: receipts})
+}
+
+// SendProofs sends the nodes of a batch of merkle proofs to the remote peer.
+func (p *peer) SendProofs(reqID uint64, nodes [][]byte) error {
+	return p2p.Send(p.rw, ProofsMsg, &nodesData{ReqID: reqID, Data: nodes})
+}
+
+// SendCode sends a batch of contract codes to the remote peer.
+func (p *peer) SendCode(reqID uint64, codes [][]byte) error {
+	return p2p.Send(p.rw, CodeMsg, &nodesData{ReqID: reqID, Data: codes})
+}
+
+// SendTxProofs sends a batch of proven transactions to the remote peer.
+func (p *peer) SendTxProofs(reqID uint64, proofs []TxProof) error {
+	return p2p.Send(p.rw, TxProofsMsg, &txProofsData{ReqID: reqID, Proofs: proofs})
+}
+
+// SendTxs relays transactions to the remote peer.
+func (p *peer) SendTxs(txs types.Transactions) error {
+	return p2p.Send(p.rw, SendTxMsg, txs)
+}
+
+// RequestHeaders fetches a batch of consecutive canonical headers starting at
+// the given number.
+func (p *peer) RequestHeaders(reqID, origin, amount uint64) error {
+	p.Log().Debug("Fetching batch of headers", "count", amount, "from", origin)
+	return p2p.Send(p.rw, GetHeadersMsg, &getHeadersData{ReqID: reqID, Origin: origin, Amount: amount})
+}
+
+// RequestBodies fetches a batch of block bodies by block hash.
+func (p *peer) RequestBodies(reqID uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of block bodies", "count", len(hashes))
+	return p2p.Send(p.rw, GetBodiesMsg, &hashesData{ReqID: reqID, Hashes: hashes})
+}
+
+// RequestReceipts fetches a batch of block receipts by block hash.
+func (p *peer) RequestReceipts(reqID uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
+	return p2p.Send(p.rw, GetReceiptsMsg, &hashesData{ReqID: reqID, Hashes: hashes})
+}
+
+// RequestProofs fetches a batch of merkle proofs of state or storage keys.
+func (p *peer) RequestProofs(reqID uint64, reqs []ProofReq) error {
+	p.Log().Debug("Fetching batch of proofs", "count", len(reqs))
+	return p2p.Send(p.rw, GetProofsMsg, &getProofsData{ReqID: reqID, Reqs: reqs})
+}
+
+// RequestCode fetches a batch of contract codes by code hash.
+func (p *peer) RequestCode(reqID uint64, hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of codes", "count", len(hashes))
+	return p2p.Send(p.rw, GetCodeMsg, &hashesData{ReqID: reqID, Hashes: hashes})
+}
+
+// RequestTxProofs fetches a batch of transactions along with their proofs.
+func (p *peer) RequestTxProofs(reqID uint64, reqs []TxProofReq) error {
+	p.Log().Debug("Fetching batch of transaction proofs", "count", len(reqs))
+	return p2p.Send(p.rw, GetTxProofsMsg, &getTxProofsData{ReqID: reqID, Reqs: reqs})
+}
+
+// Handshake executes the light protocol handshake, negotiating version number,
+// network IDs, head and genesis blocks.
+func (p *peer) Handshake(network uint64, ht *big.Int, head common.Hash, genesis common.Hash, serve bool) error {
+	errc := make(chan error, 2)
+	var status statusData // safe to read after two values have been received from errc
+
+	go func() {
+		errc <- p2p.Send(p.rw, StatusMsg, &statusData{
+			ProtocolVersion: uint32(p.version),
+			NetworkId:       network,
+			Height:          ht,
+			CurrentBlock:    head,
+			GenesisBlock:    genesis,
+			Serve:           serve,
+		})
+	}()
+	go func() {
+		errc <- p.readStatus(network, &status, genesis)
+	}()
+	timeout := time.NewTimer(handshakeTimeout)
+	defer timeout.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return err
+			}
+		case <-timeout.C:
+			return p2p.DiscReadTimeout
+		}
+	}
+	if status.Height == nil {
+		return errResp(ErrDecode, "missing height")
+	}
+	p.SetHead(status.CurrentBlock, status.Height)
+	p.serve = status.Serve
+	return nil
+}
+
+func (p *peer) readStatus(network uint64, status *statusData, genesis common.Hash) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	defer msg.Discard()
+
+	if msg.Code != StatusMsg {
+		return errResp(ErrNoStatusMsg, "first msg has code %x (!= %x)", msg.Code, StatusMsg)
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	// Decode the handshake and make sure everything matches
+	if err := msg.Decode(status); err != nil {
+		return errResp(ErrDecode, "msg %v: %v", msg, err)
+	}
+	if status.GenesisBlock != genesis {
+		return errResp(ErrGenesisBlockMismatch, "%x (!= %x)", status.GenesisBlock[:8], genesis[:8])
+	}
+	if status.NetworkId != network {
+		return errResp(ErrNetworkIdMismatch, "%d (!= %d)", status.NetworkId, network)
+	}
+	if int(status.ProtocolVersion) != p.version {
+		return errResp(ErrProtocolVersionMismatch, "%d (!= %d)", status.ProtocolVersion, p.version)
+	}
+	return nil
+}
+
+// String implements fmt.Stringer.
+func (p *peer) String() string {
+	return fmt.Sprintf("Peer %s [%s]", p.id, fmt.Sprintf("lcpc/%2d", p.version))
+}
+
+// peerSet represents the collection of active peers currently participating in
+// the light sub-protocol.
+type peerSet struct {
+	peers  map[string]*peer
+	lock   sync.RWMutex
+	closed bool
+}
+
+// newPeerSet creates a new peer set to track the active participants.
+func newPeerSet() *peerSet {
+	return &peerSet{
+		peers: make(map[string]*peer),
+	}
+}
+
+// Register injects a new peer into the working set, or returns an error if the
+// peer is already known.
+func (ps *peerSet) Register(p *peer) error {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if ps.closed {
+		return errClosed
+	}
+	if _, ok := ps.peers[p.id]; ok {
+		return errAlreadyRegistered
+	}
+	ps.peers[p.id] = p
+	return nil
+}
+
+// Unregister removes a remote peer from the active set.
+func (ps *peerSet) Unregister(id string) error {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if _, ok := ps.peers[id]; !ok {
+		return errNotRegistered
+	}
+	delete(ps.peers, id)
+	return nil
+}
+
+// Peer retrieves the registered peer with the given id.
+func (ps *peerSet) Peer(id string) *peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	return ps.peers[id]
+}
+
+// Len returns if the current number of peers in the set.
+func (ps *peerSet) Len() int {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	return len(ps.peers)
+}
+
+// AllPeers retrieves a list of peers.
+func (ps *peerSet) AllPeers() []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		list = append(list, p)
+	}
+	return list
+}
+
+// ServingPeers retrieves the peers serving light clients, best ones first.
+func (ps *peerSet) ServingPeers() []*peer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*peer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.serve {
+			list = append(list, p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		_, a := list[i].Head()
+		_, b := list[j].Head()
+		return a.Cmp(b) > 0
+	})
+	return list
+}
+
+// BestPeer retrieves the serving peer with the highest head.
+func (ps *peerSet) BestPeer() *peer {
+	if peers := ps.ServingPeers(); len(peers) > 0 {
+		return peers[0]
+	}
+	return nil
+}
+
+// Close disconnects all peers.
+// No new peers can be registered after Close has returned.
+func (ps *peerSet) Close() {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	for _, p := range ps.peers {
+		p.Disconnect(p2p.DiscQuitting)
+	}
+	ps.closed = true
+}
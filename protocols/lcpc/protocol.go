@@ -0,0 +1,195 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package lcpc implements the light cpchain protocol, letting clients follow
+// the chain with its headers only and retrieve the rest on demand.
+package lcpc
+
+import (
+	"fmt"
+	"math/big"
+
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ProtocolName is the official short name of the protocol used during capability negotiation.
+var ProtocolName = "lcpc"
+
+// ProtocolVersions are the versions of the light protocol (first is primary).
+var ProtocolVersions = []uint{1}
+
+// ProtocolLengths are the number of implemented message corresponding to different protocol versions.
+var ProtocolLengths = []uint64{15}
+
+const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
+
+// lcpc protocol message codes
+const (
+	StatusMsg      = 0x00
+	AnnounceMsg    = 0x01
+	GetHeadersMsg  = 0x02
+	HeadersMsg     = 0x03
+	GetBodiesMsg   = 0x04
+	BodiesMsg      = 0x05
+	GetReceiptsMsg = 0x06
+	ReceiptsMsg    = 0x07
+	GetProofsMsg   = 0x08
+	ProofsMsg      = 0x09
+	GetCodeMsg     = 0x0a
+	CodeMsg        = 0x0b
+	GetTxProofsMsg = 0x0c
+	TxProofsMsg    = 0x0d
+	SendTxMsg      = 0x0e
+)
+
+// Limits of the items served in a single response.
+const (
+	MaxHeaderFetch  = 192 // Amount of block headers to be fetched per request
+	MaxBodyFetch    = 32  // Amount of block bodies to be fetched per request
+	MaxReceiptFetch = 128 // Amount of transaction receipts to allow fetching per request
+	MaxProofsFetch  = 64  // Amount of merkle proofs to be fetched per request
+	MaxCodeFetch    = 64  // Amount of contract codes to allow fetching per request
+	MaxTxSend       = 64  // Amount of transactions to be relayed per message
+)
+
+type errCode int
+
+const (
+	ErrMsgTooLarge = iota
+	ErrDecode
+	ErrInvalidMsgCode
+	ErrProtocolVersionMismatch
+	ErrNetworkIdMismatch
+	ErrGenesisBlockMismatch
+	ErrNoStatusMsg
+	ErrExtraStatusMsg
+	ErrRequestRejected
+	ErrUnexpectedResponse
+)
+
+func (e errCode) String() string {
+	return errorToString[int(e)]
+}
+
+var errorToString = map[int]string{
+	ErrMsgTooLarge:             "Message too long",
+	ErrDecode:                  "Invalid message",
+	ErrInvalidMsgCode:          "Invalid message code",
+	ErrProtocolVersionMismatch: "Protocol version mismatch",
+	ErrNetworkIdMismatch:       "NetworkId mismatch",
+	ErrGenesisBlockMismatch:    "Genesis block mismatch",
+	ErrNoStatusMsg:             "No status message",
+	ErrExtraStatusMsg:          "Extra status message",
+	ErrRequestRejected:         "Request rejected",
+	ErrUnexpectedResponse:      "Unexpected response",
+}
+
+func errResp(code errCode, format string, v ...interface{}) error {
+	return fmt.Errorf("%v - %v", code, fmt.Sprintf(format, v...))
+}
+
+// statusData is the network packet for the status message.
+type statusData struct {
+	ProtocolVersion uint32
+	NetworkId       uint64
+	Height          *big.Int
+	CurrentBlock    common.Hash
+	GenesisBlock    common.Hash
+	Serve           bool // Whether the peer serves light clients
+}
+
+// announceData is the network packet announcing a new head.
+type announceData struct {
+	Hash   common.Hash
+	Number uint64
+}
+
+// getHeadersData represents a query of consecutive canonical headers.
+type getHeadersData struct {
+	ReqID  uint64
+	Origin uint64 // Number of the first header to retrieve
+	Amount uint64 // Maximum number of headers to retrieve
+}
+
+// hashesData represents a query of items by block or code hash.
+type hashesData struct {
+	ReqID  uint64
+	Hashes []common.Hash
+}
+
+// ProofReq asks for the merkle proof of a key in the trie with the given root,
+// be it the state trie or the storage trie of an account.
+type ProofReq struct {
+	Root common.Hash
+	Key  []byte // Hashed key, the tries of the state being secure ones
+}
+
+// getProofsData represents a query of merkle proofs.
+type getProofsData struct {
+	ReqID uint64
+	Reqs  []ProofReq
+}
+
+// TxProofReq asks for a transaction of a block along with the merkle proof of
+// its index in the transaction trie of the block.
+type TxProofReq struct {
+	BlockHash common.Hash
+	Index     uint64
+}
+
+// TxProof is a transaction proven against the transaction root of its block.
+type TxProof struct {
+	Tx    *types.Transaction
+	Proof [][]byte
+}
+
+// getTxProofsData represents a query of transaction proofs.
+type getTxProofsData struct {
+	ReqID uint64
+	Reqs  []TxProofReq
+}
+
+// headersData is the response to a headers query.
+type headersData struct {
+	ReqID   uint64
+	Headers []*types.Header
+}
+
+// bodiesData is the response to a bodies query.
+type bodiesData struct {
+	ReqID  uint64
+	Bodies []*types.Body
+}
+
+// receiptsData is the response to a receipts query.
+type receiptsData struct {
+	ReqID    uint64
+	Receipts []types.Receipts
+}
+
+// nodesData is the response to a proofs or code query, the proof nodes shared
+// by several proofs being sent once.
+type nodesData struct {
+	ReqID uint64
+	Data  [][]byte
+}
+
+// txProofsData is the response to a transaction proofs query.
+type txProofsData struct {
+	ReqID  uint64
+	Proofs []TxProof
+}
@@ -0,0 +1,356 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"fmt"
+	"sync"
+
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	softResponseLimit = 2 * 1024 * 1024 // Target maximum size of returned codes
+	chainHeadChanSize = 10              // Size of the channel listening to ChainHeadEvent
+)
+
+// txPool is the part of the transaction pool the server relays the
+// transactions of the light clients to.
+type txPool interface {
+	AddRemotes([]*types.Transaction) []error
+}
+
+// LightServer serves the headers, bodies, receipts, proofs and codes of the
+// local chain to light clients. It implements cpc.LesServer.
+type LightServer struct {
+	networkID  uint64
+	blockchain *core.BlockChain
+	txpool     txPool
+	peers      *peerSet
+
+	bloomIndexer *core.ChainIndexer
+
+	headCh  chan core.ChainHeadEvent
+	headSub event.Subscription
+
+	protocols []p2p.Protocol
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewLightServer creates a light server for the given chain, relaying the
+// transactions of the clients to txpool.
+func NewLightServer(blockchain *core.BlockChain, txpool txPool, networkID uint64) *LightServer {
+	s := &LightServer{
+		networkID:  networkID,
+		blockchain: blockchain,
+		txpool:     txpool,
+		peers:      newPeerSet(),
+		quit:       make(chan struct{}),
+	}
+	for i, version := range ProtocolVersions {
+		version := version
+		s.protocols = append(s.protocols, p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  ProtocolLengths[i],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return s.handlePeer(p, rw, version)
+			},
+			PeerInfo: func(id discover.NodeID) interface{} {
+				if p := s.peers.Peer(fmt.Sprintf("%x", id[:8])); p != nil {
+					return p.Info()
+				}
+				return nil
+			},
+		})
+	}
+	return s
+}
+
+// Protocols returns the light protocols served.
+func (s *LightServer) Protocols() []p2p.Protocol {
+	return s.protocols
+}
+
+// SetBloomBitsIndexer sets the bloom bits indexer of the chain.
+func (s *LightServer) SetBloomBitsIndexer(bbIndexer *core.ChainIndexer) {
+	s.bloomIndexer = bbIndexer
+}
+
+// Start starts announcing the new heads to the clients.
+func (s *LightServer) Start(srvr *p2p.Server) {
+	s.headCh = make(chan core.ChainHeadEvent, chainHeadChanSize)
+	s.headSub = s.blockchain.SubscribeChainHeadEvent(s.headCh)
+
+	s.wg.Add(1)
+	go s.announceLoop()
+}
+
+// Stop disconnects the clients and stops serving them.
+func (s *LightServer) Stop() {
+	if s.headSub != nil {
+		s.headSub.Unsubscribe()
+	}
+	s.peers.Close()
+	close(s.quit)
+	s.wg.Wait()
+
+	log.Info("Light server stopped")
+}
+
+// announceLoop announces the new heads of the chain to all clients.
+func (s *LightServer) announceLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case ev := <-s.headCh:
+			for _, p := range s.peers.AllPeers() {
+				if err := p.SendAnnounce(ev.Block.Hash(), ev.Block.NumberU64()); err != nil {
+					p.Log().Debug("Failed to announce head", "err", err)
+				}
+			}
+		case <-s.headSub.Err():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *LightServer) handlePeer(p *p2p.Peer, rw p2p.MsgReadWriter, version uint) error {
+	peer := newPeer(int(version), p, rw)
+
+	head := s.blockchain.CurrentBlock()
+	if err := peer.Handshake(s.networkID, head.Number(), head.Hash(), s.blockchain.Genesis().Hash(), true); err != nil {
+		peer.Log().Debug("Light client handshake failed", "err", err)
+		return err
+	}
+	if err := s.peers.Register(peer); err != nil {
+		return err
+	}
+	defer s.peers.Unregister(peer.id)
+
+	peer.Log().Debug("Light client connected")
+	for {
+		if err := s.handleMsg(peer); err != nil {
+			peer.Log().Debug("Light client message handling failed", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMsg is invoked whenever an inbound message is received from a light
+// client. The remote connection is torn down upon returning any error.
+func (s *LightServer) handleMsg(p *peer) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > ProtocolMaxMsgSize {
+		return errResp(ErrMsgTooLarge, "%v > %v", msg.Size, ProtocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case StatusMsg:
+		return errResp(ErrExtraStatusMsg, "uncontrolled status message")
+
+	case GetHeadersMsg:
+		var query getHeadersData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if query.Amount > MaxHeaderFetch {
+			query.Amount = MaxHeaderFetch
+		}
+		var headers []*types.Header
+		for number := query.Origin; number < query.Origin+query.Amount; number++ {
+			header := s.blockchain.GetHeaderByNumber(number)
+			if header == nil {
+				break
+			}
+			headers = append(headers, header)
+		}
+		return p.SendHeaders(query.ReqID, headers)
+
+	case GetBodiesMsg:
+		var query hashesData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		var bodies []*types.Body
+		for _, hash := range limitHashes(query.Hashes, MaxBodyFetch) {
+			body := s.blockchain.GetBody(hash)
+			if body == nil {
+				break
+			}
+			bodies = append(bodies, body)
+		}
+		return p.SendBodies(query.ReqID, bodies)
+
+	case GetReceiptsMsg:
+		var query hashesData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		var receipts []types.Receipts
+		for _, hash := range limitHashes(query.Hashes, MaxReceiptFetch) {
+			if s.blockchain.GetHeaderByHash(hash) == nil {
+				break
+			}
+			results := s.blockchain.GetReceiptsByHash(hash)
+			if results == nil {
+				// Only the blocks without transactions may miss their receipts
+				if body := s.blockchain.GetBody(hash); body == nil || len(body.Transactions) > 0 {
+					break
+				}
+				results = types.Receipts{}
+			}
+			receipts = append(receipts, results)
+		}
+		return p.SendReceipts(query.ReqID, receipts)
+
+	case GetProofsMsg:
+		var query getProofsData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(query.Reqs) > MaxProofsFetch {
+			query.Reqs = query.Reqs[:MaxProofsFetch]
+		}
+		var (
+			triedb = s.blockchain.StateCache().TrieDB()
+			proofs = database.NewMemDatabase()
+		)
+		for _, req := range query.Reqs {
+			tr, err := trie.NewSecure(req.Root, triedb, 0)
+			if err != nil {
+				break
+			}
+			if err := tr.Prove(req.Key, 0, proofs); err != nil {
+				break
+			}
+		}
+		var nodes [][]byte
+		for _, key := range proofs.Keys() {
+			node, _ := proofs.Get(key)
+			nodes = append(nodes, node)
+		}
+		return p.SendProofs(query.ReqID, nodes)
+
+	case GetCodeMsg:
+		var query hashesData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		var (
+			codes [][]byte
+			size  int
+		)
+		for _, hash := range limitHashes(query.Hashes, MaxCodeFetch) {
+			code, err := s.blockchain.StateCache().ContractCode(common.Hash{}, hash)
+			if err != nil {
+				break
+			}
+			codes = append(codes, code)
+			if size += len(code); size >= softResponseLimit {
+				break
+			}
+		}
+		return p.SendCode(query.ReqID, codes)
+
+	case GetTxProofsMsg:
+		var query getTxProofsData
+		if err := msg.Decode(&query); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(query.Reqs) > MaxProofsFetch {
+			query.Reqs = query.Reqs[:MaxProofsFetch]
+		}
+		var proofs []TxProof
+		for _, req := range query.Reqs {
+			proof, err := proveTransaction(s.blockchain.GetBody(req.BlockHash), req.Index)
+			if err != nil {
+				break
+			}
+			proofs = append(proofs, *proof)
+		}
+		return p.SendTxProofs(query.ReqID, proofs)
+
+	case SendTxMsg:
+		var txs []*types.Transaction
+		if err := msg.Decode(&txs); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if len(txs) > MaxTxSend {
+			return errResp(ErrRequestRejected, "%d transactions relayed at once", len(txs))
+		}
+		for i, err := range s.txpool.AddRemotes(txs) {
+			if err != nil {
+				p.Log().Debug("Relayed transaction rejected", "hash", txs[i].Hash(), "err", err)
+			}
+		}
+		return nil
+
+	default:
+		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
+	}
+}
+
+// proveTransaction proves the transaction with the given index in the body
+// against the transaction root of the block.
+func proveTransaction(body *types.Body, index uint64) (*TxProof, error) {
+	if body == nil || index >= uint64(len(body.Transactions)) {
+		return nil, errResp(ErrRequestRejected, "unknown transaction")
+	}
+	tr := new(trie.Trie)
+	for i, tx := range body.Transactions {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		value, _ := rlp.EncodeToBytes(tx)
+		tr.Update(key, value)
+	}
+	key, _ := rlp.EncodeToBytes(uint(index))
+	proof := database.NewMemDatabase()
+	if err := tr.Prove(key, 0, proof); err != nil {
+		return nil, err
+	}
+	result := &TxProof{Tx: body.Transactions[index]}
+	for _, hash := range proof.Keys() {
+		node, _ := proof.Get(hash)
+		result.Proof = append(result.Proof, node)
+	}
+	return result, nil
+}
+
+// limitHashes caps the number of items queried at once.
+func limitHashes(hashes []common.Hash, limit int) []common.Hash {
+	if len(hashes) > limit {
+		return hashes[:limit]
+	}
+	return hashes
+}
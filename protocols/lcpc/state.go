@@ -0,0 +1,172 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"context"
+	"fmt"
+
+	"bitbucket.org/cpchain/chain/core/state"
+	"bitbucket.org/cpchain/chain/database"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var emptyCodeHash = crypto.Keccak256Hash(nil)
+
+// NewState returns the state of the given header, whose tries are retrieved
+// on demand along the paths of the keys read or written.
+func NewState(ctx context.Context, header *types.Header, odr *LightOdr) (*state.StateDB, error) {
+	return state.New(header.StateRoot, newOdrDatabase(ctx, odr))
+}
+
+// odrDatabase is a state database fetching the missing trie nodes and codes
+// from the light servers.
+type odrDatabase struct {
+	ctx    context.Context
+	odr    *LightOdr
+	triedb *trie.Database
+}
+
+func newOdrDatabase(ctx context.Context, odr *LightOdr) *odrDatabase {
+	return &odrDatabase{
+		ctx:    ctx,
+		odr:    odr,
+		triedb: trie.NewDatabase(odr.chain.Database()),
+	}
+}
+
+func (db *odrDatabase) OpenTrie(root common.Hash) (state.Trie, error) {
+	return &odrTrie{db: db, root: root}, nil
+}
+
+func (db *odrDatabase) OpenStorageTrie(addrHash, root common.Hash) (state.Trie, error) {
+	return &odrTrie{db: db, root: root}, nil
+}
+
+func (db *odrDatabase) CopyTrie(t state.Trie) state.Trie {
+	switch t := t.(type) {
+	case *odrTrie:
+		cpy := &odrTrie{db: t.db, root: t.root}
+		if t.trie != nil {
+			cpy.trie = t.trie.Copy()
+		}
+		return cpy
+	default:
+		panic(fmt.Errorf("unknown trie type %T", t))
+	}
+}
+
+func (db *odrDatabase) ContractCode(addrHash, codeHash common.Hash) ([]byte, error) {
+	if codeHash == emptyCodeHash {
+		return nil, nil
+	}
+	return db.odr.GetCode(db.ctx, codeHash)
+}
+
+func (db *odrDatabase) ContractCodeSize(addrHash, codeHash common.Hash) (int, error) {
+	code, err := db.ContractCode(addrHash, codeHash)
+	return len(code), err
+}
+
+func (db *odrDatabase) TrieDB() *trie.Database {
+	return db.triedb
+}
+
+// odrTrie is a secure trie retrieving the proof of a key whenever a node on
+// its path is missing.
+type odrTrie struct {
+	db   *odrDatabase
+	root common.Hash
+	trie *trie.SecureTrie // Opened on first access, the root node being missing at first
+}
+
+func (t *odrTrie) TryGet(key []byte) (value []byte, err error) {
+	err = t.do(crypto.Keccak256(key), func() (err error) {
+		value, err = t.trie.TryGet(key)
+		return err
+	})
+	return value, err
+}
+
+func (t *odrTrie) TryUpdate(key, value []byte) error {
+	return t.do(crypto.Keccak256(key), func() error {
+		return t.trie.TryUpdate(key, value)
+	})
+}
+
+func (t *odrTrie) TryDelete(key []byte) error {
+	return t.do(crypto.Keccak256(key), func() error {
+		return t.trie.TryDelete(key)
+	})
+}
+
+func (t *odrTrie) Commit(onleaf trie.LeafCallback) (common.Hash, error) {
+	if t.trie == nil {
+		return t.root, nil
+	}
+	return t.trie.Commit(onleaf)
+}
+
+func (t *odrTrie) Hash() common.Hash {
+	if t.trie == nil {
+		return t.root
+	}
+	return t.trie.Hash()
+}
+
+func (t *odrTrie) NodeIterator(startKey []byte) trie.NodeIterator {
+	// Only the nodes retrieved already can be iterated
+	if t.trie == nil {
+		tr, _ := trie.NewSecure(common.Hash{}, t.db.triedb, 0)
+		return tr.NodeIterator(startKey)
+	}
+	return t.trie.NodeIterator(startKey)
+}
+
+func (t *odrTrie) GetKey(sha []byte) []byte {
+	return nil
+}
+
+// Prove takes the hashed key, like the one of the secure trie.
+func (t *odrTrie) Prove(key []byte, fromLevel uint, proofDb database.Putter) error {
+	return t.do(key, func() error {
+		return t.trie.Prove(key, fromLevel, proofDb)
+	})
+}
+
+// do runs fn on the trie, retrieving the proof of the hashed key and running
+// it again if a node was missing.
+func (t *odrTrie) do(hashedKey []byte, fn func() error) error {
+	var err error
+	for retrieved := false; ; retrieved = true {
+		if t.trie == nil {
+			t.trie, err = trie.NewSecure(t.root, t.db.triedb, 0)
+		}
+		if t.trie != nil {
+			err = fn()
+		}
+		if _, ok := err.(*trie.MissingNodeError); !ok || retrieved {
+			return err
+		}
+		if err := t.db.odr.GetProof(t.db.ctx, t.root, hashedKey); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package lcpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/cpchain/chain"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/protocols/cpc/syncer"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// forceSyncCycle is the time between header syncs without announcements.
+const forceSyncCycle = 10 * time.Second
+
+var errNotSupported = errors.New("not supported by light clients")
+
+// triggerSync asks the sync loop to catch up with the servers.
+func (s *LightCpchain) triggerSync() {
+	select {
+	case s.syncCh <- struct{}{}:
+	default:
+	}
+}
+
+// syncLoop downloads the headers announced by the servers.
+func (s *LightCpchain) syncLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(forceSyncCycle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.syncCh:
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+		if err := s.synchronise(); err != nil {
+			log.Debug("Light header sync failed", "err", err)
+		}
+	}
+}
+
+// synchronise downloads the headers up to the highest head of the servers,
+// verifying and inserting them batch by batch.
+func (s *LightCpchain) synchronise() error {
+	best := s.peers.BestPeer()
+	if best == nil {
+		return nil
+	}
+	_, height := best.Head()
+	if height.Uint64() <= s.chain.CurrentHeader().Number.Uint64() {
+		return nil
+	}
+	atomic.StoreInt32(&s.synchronising, 1)
+	defer atomic.StoreInt32(&s.synchronising, 0)
+
+	atomic.StoreUint64(&s.startingBlock, s.chain.CurrentHeader().Number.Uint64())
+	atomic.StoreUint64(&s.highestBlock, height.Uint64())
+
+	for {
+		origin := s.chain.CurrentHeader().Number.Uint64() + 1
+		if origin > height.Uint64() {
+			return nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-s.quit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		err := s.retriever.retrieve(ctx, HeadersMsg, func(p *peer, reqID uint64) error {
+			return p.RequestHeaders(reqID, origin, MaxHeaderFetch)
+		}, func(resp interface{}) error {
+			headers := resp.([]*types.Header)
+			if len(headers) == 0 {
+				return errUnavailable
+			}
+			_, err := s.chain.InsertHeaderChain(headers)
+			return err
+		})
+		cancel()
+		if err != nil {
+			return err
+		}
+		head := s.chain.CurrentHeader()
+		log.Info("Imported new light headers", "number", head.Number, "hash", head.Hash().Hex())
+	}
+}
+
+// lightSyncer reports the progress of the header sync through the syncer
+// interface of the full nodes.
+type lightSyncer struct {
+	s *LightCpchain
+}
+
+func (ls *lightSyncer) Synchronise(p syncer.SyncPeer, head common.Hash, height *big.Int, mode syncer.SyncMode) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) Cancel(id string) {}
+
+func (ls *lightSyncer) Progress() cpchain.SyncProgress {
+	return cpchain.SyncProgress{
+		StartingBlock: atomic.LoadUint64(&ls.s.startingBlock),
+		CurrentBlock:  ls.s.chain.CurrentHeader().Number.Uint64(),
+		HighestBlock:  atomic.LoadUint64(&ls.s.highestBlock),
+	}
+}
+
+func (ls *lightSyncer) Synchronising() bool {
+	return atomic.LoadInt32(&ls.s.synchronising) == 1
+}
+
+func (ls *lightSyncer) Terminate() {}
+
+func (ls *lightSyncer) DeliverBlocks(id string, blocks types.Blocks) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverReceipts(id string, receipts []types.Receipts) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverNodeData(id string, data [][]byte) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverAccountRange(id string, rng *syncer.StateRange) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverStorageRange(id string, rng *syncer.StateRange) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverHeaders(id string, headers []*types.Header) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) DeliverBodies(id string, transactions [][]*types.Transaction) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) AddPeer(p syncer.SyncPeer) error {
+	return errNotSupported
+}
+
+func (ls *lightSyncer) RemovePeer(peer string) error {
+	return errNotSupported
+}
@@ -179,58 +179,32 @@ func setVersion(ctx *cli.Context) error {
 }
 
 func showConfigs(ctx *cli.Context) error {
-	cmp, _, _, err := createContractInstanceAndTransactor(ctx, false)
+	cmp, _, client, err := createContractInstanceAndTransactor(ctx, false)
 	if err != nil {
-
 		return err
 	}
-	termIdx, err := cmp.TermIdx(nil)
-	if err != nil {
-		log.Fatal("Failed to get term index", "err", err)
-	}
-	log.Info("term index", "value", termIdx.Int64())
-
-	termLen, err := cmp.TermLen(nil)
-	if err != nil {
-		log.Fatal("Failed to get term len", "err", err)
-	}
-	log.Info("term len", "value", termLen.Int64())
-
-	viewLen, err := cmp.ViewLen(nil)
-	if err != nil {
-		log.Fatal("Failed to get view len", "err", err)
-	}
-	log.Info("view len", "value", viewLen.Int64())
-
-	npm, err := cmp.NumPerRound(nil)
-	if err != nil {
-		log.Fatal("Failed to get num per round", "err", err)
-	}
-	log.Info("num per round", "value", npm.Int64())
-
-	minNoc, err := cmp.MinNoc(nil)
-	if err != nil {
-		log.Fatal("Failed to get min noc", "err", err)
-	}
-	log.Info("min noc", "value", minNoc.Int64())
-
-	maxNoc, err := cmp.MaxNoc(nil)
-	if err != nil {
-		log.Fatal("Failed to get max noc", "err", err)
-	}
-	log.Info("max noc", "value", maxNoc.Int64())
-
-	acceptableBlocks, err := cmp.AcceptableBlocks(nil)
-	if err != nil {
-		log.Fatal("Failed to get acceptable blocks", "err", err)
-	}
-	log.Info("acceptable blocks", "value", acceptableBlocks.Int64())
 
-	supportedVersion, err := cmp.SupportedVersion(nil)
-	if err != nil {
-		log.Fatal("Failed to get supported version", "err", err)
-	}
-	log.Info("supported version", "value", supportedVersion.Int64())
+	// Read all the configs in a single request, adding calls without arguments cannot fail
+	batch := bind.NewBatchCall(nil, client)
+	termIdx, _ := cmp.BatchTermIdx(batch)
+	termLen, _ := cmp.BatchTermLen(batch)
+	viewLen, _ := cmp.BatchViewLen(batch)
+	npm, _ := cmp.BatchNumPerRound(batch)
+	minNoc, _ := cmp.BatchMinNoc(batch)
+	maxNoc, _ := cmp.BatchMaxNoc(batch)
+	acceptableBlocks, _ := cmp.BatchAcceptableBlocks(batch)
+	supportedVersion, _ := cmp.BatchSupportedVersion(batch)
+	if err := batch.Execute(); err != nil {
+		log.Fatal("Failed to get configs", "err", err)
+	}
+	log.Info("term index", "value", (*termIdx).Int64())
+	log.Info("term len", "value", (*termLen).Int64())
+	log.Info("view len", "value", (*viewLen).Int64())
+	log.Info("num per round", "value", (*npm).Int64())
+	log.Info("min noc", "value", (*minNoc).Int64())
+	log.Info("max noc", "value", (*maxNoc).Int64())
+	log.Info("acceptable blocks", "value", (*acceptableBlocks).Int64())
+	log.Info("supported version", "value", (*supportedVersion).Int64())
 
 	return nil
 }
@@ -0,0 +1,192 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/api/cpclient"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/tools/contract-admin/flags"
+	"bitbucket.org/cpchain/chain/tools/contract-admin/utils"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/naoina/toml"
+	"github.com/urfave/cli"
+)
+
+var (
+	deployFlags = append(append([]cli.Flag{}, flags.GeneralFlags...), flags.DeployFlags...)
+
+	DeployCommand = cli.Command{
+		Name:  "deploy",
+		Usage: "Deploy and upgrade contracts behind the proxy register",
+		Description: `
+		Deploy and upgrade contracts behind the proxy register, keeping their history in a file
+		`,
+		Flags: deployFlags,
+		Subcommands: []cli.Command{
+			{
+				Name:      "apply",
+				Usage:     "deploy the contracts of a manifest that changed",
+				Action:    apply,
+				Flags:     deployFlags,
+				ArgsUsage: "manifest.toml",
+				Description: `deploy the contracts of a manifest that changed since their live deployment:
+
+    register = "0x..."           # proxy register, that of the chain config if empty
+
+    [[contract]]
+    name = "campaign"            # name the contract is tracked by
+    contract = "campaign"        # built-in contract, or abi and bin paths of another
+    proxy = "0x..."              # proxy to register at, a new one if empty
+    args = ["@admission", "@rnode"]  # constructor arguments, "@name" is the proxy of a contract listed before`,
+			},
+			{
+				Name:        "rollback",
+				Usage:       "register a previous implementation of a contract",
+				Action:      rollback,
+				Flags:       deployFlags,
+				ArgsUsage:   "name [version]",
+				Description: `register a previous implementation of a contract, the one live before the current one by default`,
+			},
+			{
+				Name:        "verify",
+				Usage:       "verify the live contracts are registered and have their bytecode",
+				Action:      verify,
+				Flags:       deployFlags,
+				Description: `verify the live contracts are registered and have their bytecode`,
+			},
+			{
+				Name:        "history",
+				Usage:       "show the deployment history",
+				Action:      showHistory,
+				Flags:       flags.DeployFlags,
+				Description: `show the deployment history`,
+			},
+		},
+	}
+)
+
+func apply(ctx *cli.Context) error {
+	manifestPath := utils.GetFirstStringArgument(ctx)
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	manifest := new(Manifest)
+	if err := toml.NewDecoder(file).Decode(manifest); err != nil {
+		return err
+	}
+
+	manager, err := createManager(ctx, manifest.Register, true)
+	if err != nil {
+		return err
+	}
+	return manager.Apply(manifest)
+}
+
+func rollback(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		log.Fatal("Invalid length of arguments", "want", "1 or 2", "got", ctx.NArg())
+	}
+	var version uint64
+	if ctx.NArg() == 2 {
+		v, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			log.Fatal("Failed to parse value", "value", ctx.Args().Get(1), "err", err)
+		}
+		version = v
+	}
+
+	manager, err := createManager(ctx, common.Address{}, true)
+	if err != nil {
+		return err
+	}
+	return manager.Rollback(ctx.Args().Get(0), version)
+}
+
+func verify(ctx *cli.Context) error {
+	manager, err := createManager(ctx, common.Address{}, false)
+	if err != nil {
+		return err
+	}
+	if err := manager.Verify(); err != nil {
+		return err
+	}
+	log.Info("All live contracts verified", "count", len(manager.history.Contracts))
+	return nil
+}
+
+func showHistory(ctx *cli.Context) error {
+	history, err := LoadHistory(ctx.String(flags.HistoryPath))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("proxy register: %v\n", history.Register.Hex())
+	for name, record := range history.Contracts {
+		fmt.Printf("%v (proxy %v)\n", name, record.Proxy.Hex())
+		for _, d := range record.Deployments {
+			rollback := ""
+			if d.RollbackOf != 0 {
+				rollback = fmt.Sprintf(" rollback of %d", d.RollbackOf)
+			}
+			fmt.Printf("  v%d %v code %v%v\n", d.Version, d.Address.Hex(), d.CodeHash.Hex(), rollback)
+		}
+	}
+	return nil
+}
+
+// createManager creates a manager of the proxy register given, or the one in
+// the history, or the one of the chain config, in that order.
+func createManager(ctx *cli.Context, register common.Address, withTransactor bool) (*Manager, error) {
+	historyPath := ctx.String(flags.HistoryPath)
+	history, err := LoadHistory(historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := flags.GetEndpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := utils.PrepareCpclient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if register == (common.Address{}) {
+		register = history.Register
+	}
+	if register == (common.Address{}) {
+		chainConfig, err := client.ChainConfig()
+		if err != nil {
+			return nil, err
+		}
+		register = chainConfig.Dpor.ProxyContractRegister
+	}
+
+	opts := &bind.TransactOpts{}
+	if withTransactor {
+		keystoreFile, err := flags.GetKeystorePath(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_, key := utils.GetAddressAndKey(keystoreFile, utils.GetPassword())
+		opts = bind.NewKeyedTransactor(key.PrivateKey)
+	}
+
+	return NewManager(client, opts, register, history, waitMined(client), func(h *History) error {
+		return h.Save(historyPath)
+	})
+}
+
+func waitMined(client *cpclient.Client) WaitFunc {
+	return func(tx *types.Transaction) (*types.Receipt, error) {
+		log.Info("Transaction sent", "hash", tx.Hash().Hex())
+		return bind.WaitMined(context.Background(), client, tx)
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2018 The cpchain authors
+// This file is part of cpchain.
+//
+// cpchain is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// cpchain is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with cpchain. If not, see <http://www.gnu.org/licenses/>.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"bitbucket.org/cpchain/chain/accounts/abi"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/dpor/admission"
+	"bitbucket.org/cpchain/chain/contracts/dpor/campaign"
+	"bitbucket.org/cpchain/chain/contracts/dpor/network"
+	"bitbucket.org/cpchain/chain/contracts/dpor/rnode"
+	rptContract "bitbucket.org/cpchain/chain/contracts/dpor/rpt"
+	"bitbucket.org/cpchain/chain/contracts/dpor/validators"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	proxyContract "bitbucket.org/cpchain/chain/contracts/proxy/proxy_contract"
+	"bitbucket.org/cpchain/chain/contracts/reward"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrNotRegistered is returned when the proxy register ignored a registration,
+	// it only accepts those of its owner
+	ErrNotRegistered = errors.New("proxy register ignored the registration, not its owner?")
+
+	// ErrBytecodeMismatch is returned when the code deployed differs from the
+	// one of the contract
+	ErrBytecodeMismatch = errors.New("deployed bytecode mismatch")
+
+	errUnknownContract = errors.New("unknown contract")
+	errNoRollback      = errors.New("no implementation to roll back to")
+	errNoDeployment    = errors.New("no live deployment")
+)
+
+// builtinContracts are the contracts a manifest can refer to by name
+var builtinContracts = map[string]struct{ abi, bin string }{
+	configs.ContractRnode:      {rnode.RnodeABI, rnode.RnodeBin},
+	configs.ContractAdmission:  {admission.AdmissionABI, admission.AdmissionBin},
+	configs.ContractCampaign:   {campaign.CampaignABI, campaign.CampaignBin},
+	configs.ContractRpt:        {rptContract.RptABI, rptContract.RptBin},
+	configs.ContractNetwork:    {network.NetworkABI, network.NetworkBin},
+	configs.ContractReward:     {reward.RewardABI, reward.RewardBin},
+	configs.ContractValidators: {validators.ValidatorRegistryABI, validators.ValidatorRegistryBin},
+}
+
+// Manifest lists the contracts to deploy behind the proxy register
+type Manifest struct {
+	Register  common.Address `toml:"register"` // proxy register, that of the chain config if empty
+	Contracts []ContractSpec `toml:"contract"`
+}
+
+// ContractSpec describes a contract of a manifest. Either Contract names a
+// built-in contract, or ABI and Bin are paths to the solc outputs of another.
+type ContractSpec struct {
+	Name     string         `toml:"name"`     // name the contract is tracked by
+	Contract string         `toml:"contract"` // built-in contract
+	ABI      string         `toml:"abi"`      // path to the abi
+	Bin      string         `toml:"bin"`      // path to the bytecode
+	Proxy    common.Address `toml:"proxy"`    // proxy to register at, a new one if empty
+	Args     []string       `toml:"args"`     // constructor arguments, "@name" is the proxy of a contract listed before
+}
+
+// Deployment is an implementation registered behind a proxy
+type Deployment struct {
+	Version    uint64         `json:"version"` // version in the proxy register
+	Address    common.Address `json:"address"`
+	InitHash   common.Hash    `json:"initHash"` // hash of the creation code and constructor arguments
+	CodeHash   common.Hash    `json:"codeHash"`
+	Tx         common.Hash    `json:"tx"`
+	Time       int64          `json:"time"`
+	RollbackOf uint64         `json:"rollbackOf,omitempty"` // version rolled back to
+}
+
+// ContractHistory is the deployment history of a contract, the last deployment
+// is the live one.
+type ContractHistory struct {
+	Proxy       common.Address `json:"proxy"`
+	Deployments []Deployment   `json:"deployments"`
+}
+
+// Live returns the live deployment, false if there is none
+func (h *ContractHistory) Live() (Deployment, bool) {
+	if len(h.Deployments) == 0 {
+		return Deployment{}, false
+	}
+	return h.Deployments[len(h.Deployments)-1], true
+}
+
+// Version returns the deployment of given version
+func (h *ContractHistory) Version(version uint64) (Deployment, bool) {
+	for _, d := range h.Deployments {
+		if d.Version == version {
+			return d, true
+		}
+	}
+	return Deployment{}, false
+}
+
+// History is the deployment history of the contracts behind a proxy register
+type History struct {
+	Register  common.Address              `json:"register"`
+	Contracts map[string]*ContractHistory `json:"contracts"`
+}
+
+// LoadHistory loads the deployment history from a file, an empty history if
+// there is none yet
+func LoadHistory(path string) (*History, error) {
+	history := new(History)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Save writes the history to a file, replacing the previous one at once
+func (h *History) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// WaitFunc waits for a transaction to be mined
+type WaitFunc func(tx *types.Transaction) (*types.Receipt, error)
+
+// Manager deploys contracts behind a proxy register and keeps their history
+type Manager struct {
+	backend  proxy.Backend
+	opts     *bind.TransactOpts
+	register *proxy.ProxyContractRegister
+	history  *History
+	wait     WaitFunc
+
+	// save is called whenever the history changes
+	save func(*History) error
+}
+
+// NewManager creates a manager of the contracts behind the proxy register at
+// given address.
+func NewManager(backend proxy.Backend, opts *bind.TransactOpts, register common.Address, history *History, wait WaitFunc, save func(*History) error) (*Manager, error) {
+	if history.Register != (common.Address{}) && history.Register != register {
+		return nil, fmt.Errorf("history is of proxy register %v, not %v", history.Register.Hex(), register.Hex())
+	}
+	history.Register = register
+	if history.Contracts == nil {
+		history.Contracts = make(map[string]*ContractHistory)
+	}
+
+	r, err := proxy.NewProxyContractRegister(opts, register, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		backend:  backend,
+		opts:     opts,
+		register: r,
+		history:  history,
+		wait:     wait,
+		save:     save,
+	}, nil
+}
+
+// Apply deploys the contracts of the manifest whose creation code or arguments
+// changed since their live deployment, and registers them behind their proxies.
+func (m *Manager) Apply(manifest *Manifest) error {
+	for _, spec := range manifest.Contracts {
+		if err := m.apply(spec); err != nil {
+			return fmt.Errorf("%v: %v", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) apply(spec ContractSpec) error {
+	parsed, bin, err := m.load(spec)
+	if err != nil {
+		return err
+	}
+	args, err := m.arguments(parsed.Constructor.Inputs, spec.Args)
+	if err != nil {
+		return err
+	}
+	packed, err := parsed.Pack("", args...)
+	if err != nil {
+		return err
+	}
+	initHash := crypto.Keccak256Hash(bin, packed)
+
+	record := m.history.Contracts[spec.Name]
+	if record != nil {
+		if live, ok := record.Live(); ok && live.InitHash == initHash {
+			log.Info("Contract is up to date", "name", spec.Name, "version", live.Version, "address", live.Address.Hex())
+			return nil
+		}
+	}
+
+	addr, tx, _, err := bind.DeployContract(m.opts, parsed, bin, m.backend, args...)
+	if err = m.mined(tx, err); err != nil {
+		return err
+	}
+	codeHash, err := m.verify(addr, bin)
+	if err != nil {
+		return err
+	}
+	log.Info("Deployed contract", "name", spec.Name, "address", addr.Hex())
+
+	if record == nil {
+		record = &ContractHistory{Proxy: spec.Proxy}
+		if record.Proxy == (common.Address{}) {
+			proxyAddr, tx, _, err := proxyContract.DeployProxy(m.opts, m.backend)
+			if err = m.mined(tx, err); err != nil {
+				return err
+			}
+			record.Proxy = proxyAddr
+			log.Info("Deployed proxy", "name", spec.Name, "proxy", proxyAddr.Hex())
+		}
+	} else if spec.Proxy != (common.Address{}) && spec.Proxy != record.Proxy {
+		return fmt.Errorf("tracked behind proxy %v, not %v", record.Proxy.Hex(), spec.Proxy.Hex())
+	}
+
+	deployment := Deployment{
+		Address:  addr,
+		InitHash: initHash,
+		CodeHash: codeHash,
+		Tx:       tx.Hash(),
+	}
+	return m.registerDeployment(spec.Name, record, deployment)
+}
+
+// Rollback registers a previous implementation behind the proxy of the named
+// contract. Version 0 is the implementation live before the current one.
+func (m *Manager) Rollback(name string, version uint64) error {
+	record := m.history.Contracts[name]
+	if record == nil {
+		return errUnknownContract
+	}
+	live, ok := record.Live()
+	if !ok {
+		return errNoRollback
+	}
+	if version == 0 {
+		version = live.Version - 1
+	}
+	target, ok := record.Version(version)
+	if !ok || version >= live.Version {
+		return errNoRollback
+	}
+	if _, err := m.verify(target.Address, nil); err != nil {
+		return err
+	}
+
+	target.RollbackOf = target.Version
+	return m.registerDeployment(name, record, target)
+}
+
+// Verify checks the live deployments are those registered behind the proxies
+// and still have their bytecode.
+func (m *Manager) Verify() error {
+	for name, record := range m.history.Contracts {
+		live, ok := record.Live()
+		if !ok {
+			return fmt.Errorf("%v: %v", name, errNoDeployment)
+		}
+		impl, err := m.register.GetRealContract(record.Proxy)
+		if err != nil {
+			return err
+		}
+		if impl != live.Address {
+			return fmt.Errorf("%v: proxy %v points to %v, not %v", name, record.Proxy.Hex(), impl.Hex(), live.Address.Hex())
+		}
+		version, err := m.register.GetContractVersion(record.Proxy)
+		if err != nil {
+			return err
+		}
+		if version.Uint64() != live.Version {
+			return fmt.Errorf("%v: proxy %v is at version %v, not %v", name, record.Proxy.Hex(), version, live.Version)
+		}
+		codeHash, err := m.verify(live.Address, nil)
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+		if codeHash != live.CodeHash {
+			return fmt.Errorf("%v: %v", name, ErrBytecodeMismatch)
+		}
+	}
+	return nil
+}
+
+// registerDeployment registers the implementation of a deployment behind the
+// proxy and records it as live
+func (m *Manager) registerDeployment(name string, record *ContractHistory, deployment Deployment) error {
+	tx, err := m.register.RegisterProxyContract(record.Proxy, deployment.Address)
+	if err = m.mined(tx, err); err != nil {
+		return err
+	}
+	impl, err := m.register.GetRealContract(record.Proxy)
+	if err != nil {
+		return err
+	}
+	if impl != deployment.Address {
+		return ErrNotRegistered
+	}
+	version, err := m.register.GetContractVersion(record.Proxy)
+	if err != nil {
+		return err
+	}
+
+	deployment.Version = version.Uint64()
+	deployment.Time = time.Now().Unix()
+	record.Deployments = append(record.Deployments, deployment)
+	m.history.Contracts[name] = record
+	log.Info("Registered contract", "name", name, "proxy", record.Proxy.Hex(), "address", deployment.Address.Hex(), "version", deployment.Version)
+
+	if m.save != nil {
+		return m.save(m.history)
+	}
+	return nil
+}
+
+// verify checks there is code at given address, part of the creation code if
+// given, and returns its hash
+func (m *Manager) verify(addr common.Address, bin []byte) (common.Hash, error) {
+	code, err := m.backend.CodeAt(context.Background(), addr, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(code) == 0 || (bin != nil && !bytes.Contains(bin, code)) {
+		return common.Hash{}, ErrBytecodeMismatch
+	}
+	return crypto.Keccak256Hash(code), nil
+}
+
+func (m *Manager) mined(tx *types.Transaction, err error) error {
+	if err != nil {
+		return err
+	}
+	receipt, err := m.wait(tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %v failed", tx.Hash().Hex())
+	}
+	return nil
+}
+
+// load returns the abi and creation code of a contract
+func (m *Manager) load(spec ContractSpec) (abi.ABI, []byte, error) {
+	abiJSON, bin := "", ""
+	if spec.Contract != "" {
+		builtin, ok := builtinContracts[spec.Contract]
+		if !ok {
+			return abi.ABI{}, nil, errUnknownContract
+		}
+		abiJSON, bin = builtin.abi, builtin.bin
+	} else {
+		abiFile, err := ioutil.ReadFile(spec.ABI)
+		if err != nil {
+			return abi.ABI{}, nil, err
+		}
+		binFile, err := ioutil.ReadFile(spec.Bin)
+		if err != nil {
+			return abi.ABI{}, nil, err
+		}
+		abiJSON, bin = string(abiFile), strings.TrimSpace(string(binFile))
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	if !strings.HasPrefix(bin, "0x") {
+		bin = "0x" + bin
+	}
+	code, err := hexutil.Decode(bin)
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+	return parsed, code, nil
+}
+
+// arguments converts the constructor arguments of a manifest to the types of
+// the constructor inputs
+func (m *Manager) arguments(inputs abi.Arguments, args []string) ([]interface{}, error) {
+	if len(inputs) != len(args) {
+		return nil, fmt.Errorf("constructor takes %d arguments, got %d", len(inputs), len(args))
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch typ := inputs[i].Type; typ.T {
+		case abi.AddressTy:
+			if strings.HasPrefix(arg, "@") {
+				record := m.history.Contracts[arg[1:]]
+				if record == nil {
+					return nil, fmt.Errorf("argument %d: %v", i, errUnknownContract)
+				}
+				values[i] = record.Proxy
+			} else if common.IsHexAddress(arg) {
+				values[i] = common.HexToAddress(arg)
+			} else {
+				return nil, fmt.Errorf("argument %d: invalid address %v", i, arg)
+			}
+		case abi.IntTy, abi.UintTy:
+			n, ok := new(big.Int).SetString(arg, 0)
+			if !ok {
+				return nil, fmt.Errorf("argument %d: invalid integer %v", i, arg)
+			}
+			if typ.Type == reflect.TypeOf(n) {
+				values[i] = n
+			} else if typ.T == abi.IntTy {
+				values[i] = reflect.ValueOf(n.Int64()).Convert(typ.Type).Interface()
+			} else {
+				values[i] = reflect.ValueOf(n.Uint64()).Convert(typ.Type).Interface()
+			}
+		case abi.BoolTy:
+			b, err := strconv.ParseBool(arg)
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %v", i, err)
+			}
+			values[i] = b
+		case abi.StringTy:
+			values[i] = arg
+		default:
+			return nil, fmt.Errorf("argument %d: unsupported type %v", i, typ)
+		}
+	}
+	return values, nil
+}
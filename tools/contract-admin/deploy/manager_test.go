@@ -0,0 +1,139 @@
+package deploy
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bitbucket.org/cpchain/chain/accounts/abi/bind"
+	"bitbucket.org/cpchain/chain/accounts/abi/bind/backends"
+	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/contracts/proxy"
+	"bitbucket.org/cpchain/chain/core"
+	"bitbucket.org/cpchain/chain/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ownerKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	otherKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+)
+
+func newTestManager(t *testing.T, backend *backends.SimulatedBackend, opts *bind.TransactOpts, register common.Address, history *History) *Manager {
+	wait := func(tx *types.Transaction) (*types.Receipt, error) {
+		backend.Commit()
+		return backend.TransactionReceipt(context.Background(), tx.Hash())
+	}
+	m, err := NewManager(backend, opts, register, history, wait, nil)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	return m
+}
+
+func TestManager(t *testing.T) {
+	backend := backends.NewDporSimulatedBackend(core.GenesisAlloc{
+		crypto.PubkeyToAddress(ownerKey.PublicKey): {Balance: big.NewInt(configs.Cpc)},
+		crypto.PubkeyToAddress(otherKey.PublicKey): {Balance: big.NewInt(configs.Cpc)},
+	})
+	owner := bind.NewKeyedTransactor(ownerKey)
+	register, _, _, err := proxy.DeployProxyContractRegister(owner, backend)
+	if err != nil {
+		t.Fatalf("failed to deploy proxy register: %v", err)
+	}
+	backend.Commit()
+
+	manifest := &Manifest{Contracts: []ContractSpec{
+		{Name: "rnode", Contract: configs.ContractRnode},
+		{Name: "admission", Contract: configs.ContractAdmission, Args: []string{"12", "6", "5", "5"}},
+		{Name: "campaign", Contract: configs.ContractCampaign, Args: []string{"@admission", "@rnode"}},
+	}}
+	history := new(History)
+	m := newTestManager(t, backend, owner, register, history)
+	if err := m.Apply(manifest); err != nil {
+		t.Fatalf("failed to apply manifest: %v", err)
+	}
+	if len(history.Contracts) != 3 {
+		t.Fatalf("tracked contracts mismatch: have %d, want 3", len(history.Contracts))
+	}
+	first, _ := history.Contracts["admission"].Live()
+	if first.Version != 1 {
+		t.Fatalf("first version mismatch: have %d, want 1", first.Version)
+	}
+
+	// Unchanged contracts are left alone, changed ones upgraded
+	manifest.Contracts[1].Args[0] = "13"
+	if err := m.Apply(manifest); err != nil {
+		t.Fatalf("failed to apply changed manifest: %v", err)
+	}
+	if n := len(history.Contracts["rnode"].Deployments); n != 1 {
+		t.Fatalf("unchanged contract redeployed: %d deployments", n)
+	}
+	upgraded, _ := history.Contracts["admission"].Live()
+	if upgraded.Version != 2 || upgraded.Address == first.Address {
+		t.Fatalf("contract not upgraded: %+v", upgraded)
+	}
+	if err := m.Verify(); err != nil {
+		t.Fatalf("failed to verify upgrade: %v", err)
+	}
+
+	// Rolling back registers the previous implementation as a new version
+	if err := m.Rollback("admission", 0); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	live, _ := history.Contracts["admission"].Live()
+	if live.Version != 3 || live.Address != first.Address || live.RollbackOf != 1 {
+		t.Fatalf("rollback mismatch: %+v", live)
+	}
+	if err := m.Verify(); err != nil {
+		t.Fatalf("failed to verify rollback: %v", err)
+	}
+	if err := m.Rollback("admission", 3); err != errNoRollback {
+		t.Fatalf("rollback to live version error mismatch: have %v, want %v", err, errNoRollback)
+	}
+
+	// History survives a round trip through its file
+	dir, err := ioutil.TempDir("", "deployments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "deployments.json")
+	if err := history.Save(path); err != nil {
+		t.Fatalf("failed to save history: %v", err)
+	}
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("failed to load history: %v", err)
+	}
+	if loadedLive, _ := loaded.Contracts["admission"].Live(); loaded.Register != register || loadedLive != live {
+		t.Fatalf("loaded history mismatch")
+	}
+
+	// Only the owner of the register can upgrade
+	manifest.Contracts[1].Args[0] = "14"
+	other := newTestManager(t, backend, bind.NewKeyedTransactor(otherKey), register, loaded)
+	if err := other.Apply(manifest); err == nil {
+		t.Fatalf("upgrade by other account accepted")
+	}
+	if err := newTestManager(t, backend, owner, register, loaded).Verify(); err != nil {
+		t.Fatalf("failed to verify after refused upgrade: %v", err)
+	}
+
+	// A contract tracked without deployments, as in an edited file, has none live
+	loaded.Contracts["empty"] = &ContractHistory{Proxy: loaded.Contracts["admission"].Proxy}
+	if _, ok := loaded.Contracts["empty"].Live(); ok {
+		t.Fatalf("live deployment of contract without deployments")
+	}
+	m = newTestManager(t, backend, owner, register, loaded)
+	if err := m.Rollback("empty", 0); err != errNoRollback {
+		t.Fatalf("rollback without deployments error mismatch: have %v, want %v", err, errNoRollback)
+	}
+	if err := m.Verify(); err == nil {
+		t.Fatalf("contract without deployments verified")
+	}
+}
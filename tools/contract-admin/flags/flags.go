@@ -38,6 +38,7 @@ func init() {
 	}
 
 	Register(GeneralFlags...)
+	Register(DeployFlags...)
 }
 
 func Register(flags ...cli.Flag) {
@@ -63,6 +64,7 @@ const (
 	KeystorePath = "keystore"
 	Endpoint     = "endpoint"
 	ContractAddr = "contractaddr"
+	HistoryPath  = "history"
 )
 
 var GeneralFlags = []cli.Flag{
@@ -80,6 +82,14 @@ var GeneralFlags = []cli.Flag{
 	},
 }
 
+var DeployFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  HistoryPath,
+		Usage: "Deployment history file",
+		Value: "deployments.json",
+	},
+}
+
 func GetContractAddress(ctx *cli.Context) (common.Address, error) {
 	if !ctx.IsSet(ContractAddr) {
 		return common.Address{}, errors.New("contract address must be provided!")
@@ -138,45 +138,27 @@ func setOpen(ctx *cli.Context) error {
 }
 
 func showConfigs(ctx *cli.Context) error {
-	ntw, _, _, err := createContractInstanceAndTransactor(ctx, false)
+	ntw, _, client, err := createContractInstanceAndTransactor(ctx, false)
 	if err != nil {
 		return err
 	}
 
-	host, err := ntw.Host(nil)
-	if err != nil {
-		log.Info("Failed to get host", "err", err)
-		return err
-	}
-	log.Info("host", "value", host)
-
-	count, err := ntw.Count(nil)
-	if err != nil {
-		log.Info("Failed to get count", "err", err)
-		return err
-	}
-	log.Info("count", "value", count.Int64())
-
-	timeout, err := ntw.Timeout(nil)
-	if err != nil {
-		log.Info("Failed to get timeout", "err", err)
-		return err
-	}
-	log.Info("timeout", "value", timeout.Int64())
-
-	gap, err := ntw.Gap(nil)
-	if err != nil {
-		log.Info("Failed to get gap", "err", err)
-		return err
-	}
-	log.Info("gap", "value", gap.Int64())
-
-	open, err := ntw.Open(nil)
-	if err != nil {
-		log.Info("Failed to get open", "err", err)
+	// Read all the configs in a single request, adding calls without arguments cannot fail
+	batch := bind.NewBatchCall(nil, client)
+	host, _ := ntw.BatchHost(batch)
+	count, _ := ntw.BatchCount(batch)
+	timeout, _ := ntw.BatchTimeout(batch)
+	gap, _ := ntw.BatchGap(batch)
+	open, _ := ntw.BatchOpen(batch)
+	if err := batch.Execute(); err != nil {
+		log.Info("Failed to get configs", "err", err)
 		return err
 	}
-	log.Info("open", "value", open)
+	log.Info("host", "value", *host)
+	log.Info("count", "value", (*count).Int64())
+	log.Info("timeout", "value", (*timeout).Int64())
+	log.Info("gap", "value", (*gap).Int64())
+	log.Info("open", "value", *open)
 
 	return nil
 }
@@ -195,30 +195,24 @@ func isRnode(ctx *cli.Context) error {
 }
 
 func showConfigs(ctx *cli.Context) error {
-	rnd, _, _, err := createContractInstanceAndTransactor(ctx, false)
-	if err != nil {
-		return err
-	}
-	period, err := rnd.Period(nil)
+	rnd, _, client, err := createContractInstanceAndTransactor(ctx, false)
 	if err != nil {
-		log.Info("Failed to get period", "err", err)
 		return err
 	}
-	log.Info("period", "value", period.Int64())
 
-	version, err := rnd.SupportedVersion(nil)
-	if err != nil {
-		log.Info("Failed to get version", "err", err)
+	// Read all the configs in a single request, adding calls without arguments cannot fail
+	batch := bind.NewBatchCall(nil, client)
+	period, _ := rnd.BatchPeriod(batch)
+	version, _ := rnd.BatchSupportedVersion(batch)
+	threshold, _ := rnd.BatchRnodeThreshold(batch)
+	if err := batch.Execute(); err != nil {
+		log.Info("Failed to get configs", "err", err)
 		return err
 	}
-	log.Info("version", "value", version.Int64())
+	log.Info("period", "value", (*period).Int64())
+	log.Info("version", "value", (*version).Int64())
+	log.Info("threshold", "value", *threshold)
 
-	threshold, err := rnd.RnodeThreshold(nil)
-	if err != nil {
-		log.Info("Failed to get threshold", "err", err)
-		return err
-	}
-	log.Info("threshold", "value", threshold)
 	return nil
 }
 
@@ -232,64 +232,34 @@ func setOmega(ctx *cli.Context) error {
 }
 
 func showConfigs(ctx *cli.Context) error {
-	rpt, _, _, err := createContractInstanceAndTransactor(ctx, false)
+	rpt, _, client, err := createContractInstanceAndTransactor(ctx, false)
 	if err != nil {
 		return err
 	}
 
-	lowPct, err := rpt.LowRptPercentage(nil)
-	if err != nil {
-		log.Fatal("Failed to get low rpt percentage", "err", err)
-	}
-	log.Info("low rpt percentage", "value", lowPct.Int64())
-
-	lowSeats, err := rpt.LowRptSeats(nil)
-	if err != nil {
-		log.Fatal("Failed to get low rpt seats", "err", err)
-	}
-	log.Info("low rpt seats", "value", lowSeats.Int64())
-
-	totalSeats, err := rpt.TotalSeats(nil)
-	if err != nil {
-		log.Fatal("Failed to get total seats", "err", err)
-	}
-	log.Info("total seats", "value", totalSeats.Int64())
-
-	window, err := rpt.Window(nil)
-	if err != nil {
-		log.Fatal("Failed to get window len", "err", err)
-	}
-	log.Info("window len", "value", window.Int64())
-
-	alpha, err := rpt.Alpha(nil)
-	if err != nil {
-		log.Fatal("Failed to get alpha", "err", err)
-	}
-	log.Info("alpha", "value", alpha.Int64())
-
-	beta, err := rpt.Beta(nil)
-	if err != nil {
-		log.Fatal("Failed to get beta", "err", err)
-	}
-	log.Info("beta", "value", beta.Int64())
-
-	gamma, err := rpt.Gamma(nil)
-	if err != nil {
-		log.Fatal("Failed to get gamma", "err", err)
-	}
-	log.Info("gamma", "value", gamma.Int64())
-
-	psi, err := rpt.Psi(nil)
-	if err != nil {
-		log.Fatal("Failed to get psi", "err", err)
-	}
-	log.Info("psi", "value", psi.Int64())
-
-	omega, err := rpt.Omega(nil)
-	if err != nil {
-		log.Fatal("Failed to get omega", "err", err)
-	}
-	log.Info("omega", "value", omega.Int64())
+	// Read all the configs in a single request, adding calls without arguments cannot fail
+	batch := bind.NewBatchCall(nil, client)
+	lowPct, _ := rpt.BatchLowRptPercentage(batch)
+	lowSeats, _ := rpt.BatchLowRptSeats(batch)
+	totalSeats, _ := rpt.BatchTotalSeats(batch)
+	window, _ := rpt.BatchWindow(batch)
+	alpha, _ := rpt.BatchAlpha(batch)
+	beta, _ := rpt.BatchBeta(batch)
+	gamma, _ := rpt.BatchGamma(batch)
+	psi, _ := rpt.BatchPsi(batch)
+	omega, _ := rpt.BatchOmega(batch)
+	if err := batch.Execute(); err != nil {
+		log.Fatal("Failed to get configs", "err", err)
+	}
+	log.Info("low rpt percentage", "value", (*lowPct).Int64())
+	log.Info("low rpt seats", "value", (*lowSeats).Int64())
+	log.Info("total seats", "value", (*totalSeats).Int64())
+	log.Info("window len", "value", (*window).Int64())
+	log.Info("alpha", "value", (*alpha).Int64())
+	log.Info("beta", "value", (*beta).Int64())
+	log.Info("gamma", "value", (*gamma).Int64())
+	log.Info("psi", "value", (*psi).Int64())
+	log.Info("omega", "value", (*omega).Int64())
 
 	return nil
 }
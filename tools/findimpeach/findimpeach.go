@@ -4,12 +4,12 @@ package main
 
 import (
 	"context"
-	"math/big"
 	"os"
 
 	"bitbucket.org/cpchain/chain/api/cpclient"
 	"bitbucket.org/cpchain/chain/commons/log"
 	"bitbucket.org/cpchain/chain/configs"
+	"bitbucket.org/cpchain/chain/types"
 )
 
 // print impeach block and validators for debug
@@ -42,41 +42,55 @@ func main() {
 	log.Infof("latest number:%v", number)
 
 	impeachCounter := 0
-	for i := 0; i < int(number.Int64()); i++ {
-		blockNr := big.NewInt(int64(i))
-		block, err := client.BlockByNumber(context.Background(), blockNr)
+	const chunk = 4096
+	for start := uint64(0); start < number.Uint64(); start += chunk {
+		end := start + chunk - 1
+		if end >= number.Uint64() {
+			end = number.Uint64() - 1
+		}
+		blocks, err := client.BlocksByRange(context.Background(), start, end, nil)
 		if err != nil {
-			log.Infof("BlockByNumber Error:%v", i)
+			log.Infof("BlocksByRange Error:%v-%v", start, end)
 			break
 		}
+		for _, block := range blocks {
+			impeachCounter += inspectBlock(client, block, showValidator)
+		}
+	}
+	log.Info("--------------------------------------")
+	log.Infof("impeachCounter is %d", impeachCounter)
+}
 
-		if block.Impeachment() {
-			log.Info("=======================================================")
-			proposer, err := client.GetProposerByBlock(context.Background(), blockNr)
-			if err != nil {
-				log.Errorf("get proposer for block %v error", i)
-			} else {
-				log.Infof("timestamp=%v,number=%v,proposer=%x", block.Timestamp(), i, proposer)
-			}
+// inspectBlock prints the proposers of an impeach block, returning 1 if the
+// block is an impeach block.
+func inspectBlock(client *cpclient.Client, block *types.Block, showValidator bool) int {
+	if !block.Impeachment() {
+		return 0
+	}
+	i, blockNr := block.NumberU64(), block.Number()
 
-			proposersOfTheTerm, err := client.GetProposersByBlockNumber(context.Background(), blockNr)
-			if err != nil {
-				log.Errorf("get proposersOfTheTerm for block %v error", i)
-			} else {
-				log.Infof("proposersOfTheTerm=%x", proposersOfTheTerm)
-			}
+	log.Info("=======================================================")
+	proposer, err := client.GetProposerByBlock(context.Background(), blockNr)
+	if err != nil {
+		log.Errorf("get proposer for block %v error", i)
+	} else {
+		log.Infof("timestamp=%v,number=%v,proposer=%x", block.Timestamp(), i, proposer)
+	}
+
+	proposersOfTheTerm, err := client.GetProposersByBlockNumber(context.Background(), blockNr)
+	if err != nil {
+		log.Errorf("get proposersOfTheTerm for block %v error", i)
+	} else {
+		log.Infof("proposersOfTheTerm=%x", proposersOfTheTerm)
+	}
 
-			if showValidator {
-				validators, err := client.GetValidatorsByBlockNumber(context.Background(), blockNr)
-				if err != nil {
-					log.Errorf("get validators for block %v error", i)
-				} else {
-					log.Infof("validators=%x", validators)
-				}
-			}
-			impeachCounter++
+	if showValidator {
+		validators, err := client.GetValidatorsByBlockNumber(context.Background(), blockNr)
+		if err != nil {
+			log.Errorf("get validators for block %v error", i)
+		} else {
+			log.Infof("validators=%x", validators)
 		}
 	}
-	log.Info("--------------------------------------")
-	log.Infof("impeachCounter is %d", impeachCounter)
+	return 1
 }
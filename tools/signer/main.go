@@ -0,0 +1,201 @@
+// Copyright 2018 The cpchain authors
+// This file is part of the cpchain library.
+//
+// The cpchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The cpchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the cpchain library. If not, see <http://www.gnu.org/licenses/>.
+
+// signer is a reference external signer. It holds keystore accounts and signs
+// the requests of a node started with --signer, subject to approval rules.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"bitbucket.org/cpchain/chain/accounts"
+	"bitbucket.org/cpchain/chain/accounts/external"
+	"bitbucket.org/cpchain/chain/accounts/keystore"
+	"bitbucket.org/cpchain/chain/api/rpc"
+	"bitbucket.org/cpchain/chain/cmd/cpchain/commons"
+	"bitbucket.org/cpchain/chain/commons/log"
+	"bitbucket.org/cpchain/chain/configs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "signer"
+	app.Usage = "Sign transactions, hashes and DPoR seals for a cpchain node.\n\t\tExample: ./signer --keystore /secure/keystore --unlock 0x... --rules rules.json"
+	app.Version = configs.Version
+	app.Copyright = "LGPL"
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "keystore",
+			Usage: "Directory of the keystore holding the accounts",
+		},
+		cli.StringFlag{
+			Name:  "unlock",
+			Usage: "Comma separated list of accounts to unlock",
+		},
+		cli.StringFlag{
+			Name:  "password",
+			Usage: "Password file, one password per unlocked account",
+		},
+		cli.StringFlag{
+			Name:  "rules",
+			Usage: "JSON file with the approval rules; nothing is signed by default, set allowSeal for mining",
+		},
+		cli.StringFlag{
+			Name:  "ipcpath",
+			Usage: "Path of the IPC endpoint",
+			Value: "signer.ipc",
+		},
+		cli.StringFlag{
+			Name:  "http",
+			Usage: "Also listen for HTTP requests on this address, e.g. localhost:8550",
+		},
+		cli.BoolFlag{
+			Name:  "confirm",
+			Usage: "Ask for confirmation on the terminal before signing anything but seals and macs",
+		},
+	}
+	app.Action = run
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal("run application failed", "err", err)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	if !ctx.IsSet("keystore") {
+		cli.ShowAppHelp(ctx)
+		return nil
+	}
+	ks := keystore.NewKeyStore(ctx.String("keystore"), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err := unlock(ctx, ks); err != nil {
+		return err
+	}
+	rules := new(external.Rules)
+	if ctx.IsSet("rules") {
+		var err error
+		if rules, err = external.LoadRules(ctx.String("rules")); err != nil {
+			return fmt.Errorf("failed to load rules: %v", err)
+		}
+	}
+	var approve external.ApproveFn
+	if ctx.Bool("confirm") {
+		approve = confirm()
+	}
+	apis := []rpc.API{{
+		Namespace: "account",
+		Version:   "1.0",
+		Service:   external.NewSignerAPI(ks, rules, approve),
+		Public:    true,
+	}}
+
+	listener, _, err := rpc.StartIPCEndpoint(ctx.String("ipcpath"), apis)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	log.Info("IPC endpoint opened", "path", ctx.String("ipcpath"))
+
+	if ctx.IsSet("http") {
+		listener, _, err := rpc.StartHTTPEndpoint(ctx.String("http"), apis, []string{"account"}, nil, []string{"localhost"})
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		log.Info("HTTP endpoint opened", "url", "http://"+ctx.String("http"))
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Shutting down...")
+	return nil
+}
+
+// unlock unlocks the requested accounts with the passwords from the password
+// file, prompting for the missing ones.
+func unlock(ctx *cli.Context, ks *keystore.KeyStore) error {
+	var passwords []string
+	if ctx.IsSet("password") {
+		blob, err := ioutil.ReadFile(ctx.String("password"))
+		if err != nil {
+			return fmt.Errorf("failed to read password file: %v", err)
+		}
+		for _, line := range strings.Split(string(blob), "\n") {
+			passwords = append(passwords, strings.TrimRight(line, "\r"))
+		}
+	}
+	unlocks := strings.FieldsFunc(ctx.String("unlock"), func(c rune) bool { return c == ',' })
+	for i, addr := range unlocks {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid account address %q", addr)
+		}
+		account, err := ks.Find(accounts.Account{Address: common.HexToAddress(addr)})
+		if err != nil {
+			return fmt.Errorf("account %s: %v", addr, err)
+		}
+		var password string
+		if i < len(passwords) {
+			password = passwords[i]
+		} else if password, err = commons.ReadPassword(fmt.Sprintf("Unlocking account %s", addr), false); err != nil {
+			return err
+		}
+		if err := ks.Unlock(account, password); err != nil {
+			return fmt.Errorf("failed to unlock account %s: %v", addr, err)
+		}
+		log.Info("Unlocked account", "address", account.Address.Hex())
+	}
+	return nil
+}
+
+// confirm returns an approval callback asking on the terminal for every
+// request but seals and handshake macs. Those must not wait for an operator
+// and are checked by the signer itself.
+func confirm() external.ApproveFn {
+	var (
+		lock   sync.Mutex
+		reader = bufio.NewReader(os.Stdin)
+	)
+	return func(req *external.Request) bool {
+		if req.Kind == external.RequestSeal || req.Kind == external.RequestMac {
+			return true
+		}
+		lock.Lock()
+		defer lock.Unlock()
+
+		switch req.Kind {
+		case external.RequestTransaction:
+			to := "contract creation"
+			if req.Tx.To() != nil {
+				to = req.Tx.To().Hex()
+			}
+			fmt.Printf("Sign transaction from %s to %s, value %v, gas %d, nonce %d? [y/N] ",
+				req.Account.Hex(), to, req.Tx.Value(), req.Tx.Gas(), req.Tx.Nonce())
+		default:
+			fmt.Printf("Sign hash %x with %s? [y/N] ", req.Hash, req.Account.Hex())
+		}
+		answer, _ := reader.ReadString('\n')
+		return strings.ToLower(strings.TrimSpace(answer)) == "y"
+	}
+}
@@ -55,6 +55,7 @@ type Receipt struct {
 	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
+	LogicAddress    common.Address `json:"logicAddress"`
 }
 
 type receiptMarshaling struct {
@@ -80,6 +81,9 @@ type receiptStorageRLP struct {
 	ContractAddress   common.Address
 	Logs              []*LogForStorage
 	GasUsed           uint64
+	// LogicAddress is empty unless the recipient is a proxy contract, as a
+	// tail receipts stored without it still decode
+	LogicAddress []common.Address `rlp:"tail"`
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -168,6 +172,9 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 	for i, log := range r.Logs {
 		enc.Logs[i] = (*LogForStorage)(log)
 	}
+	if r.LogicAddress != (common.Address{}) {
+		enc.LogicAddress = []common.Address{r.LogicAddress}
+	}
 	return rlp.Encode(w, enc)
 }
 
@@ -189,6 +196,9 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	}
 	// Assign the implementation fields
 	r.TxHash, r.ContractAddress, r.GasUsed = dec.TxHash, dec.ContractAddress, dec.GasUsed
+	if len(dec.LogicAddress) > 0 {
+		r.LogicAddress = dec.LogicAddress[0]
+	}
 	return nil
 }
 